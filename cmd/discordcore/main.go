@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"os"
 
 	"github.com/small-frappuccino/discordcore/pkg/app"
@@ -9,6 +10,18 @@ import (
 
 // main is the entry point of the Discord bot.
 func main() {
+	validate := flag.String("validate", "", "validate the given config file (dry run, no Discord connection) and exit")
+	flag.Parse()
+
+	if *validate != "" {
+		report := app.Validate(*validate)
+		report.Write(os.Stdout)
+		if !report.OK() {
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := app.Run("discordcore", "ALICE_BOT_DEVELOPMENT_TOKEN"); err != nil {
 		log.Error().Errorf("Fatal: %v", err)
 		os.Exit(1)
@@ -0,0 +1,561 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/admin"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/discord/session"
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/service"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+	"github.com/small-frappuccino/discordcore/pkg/task"
+	"github.com/small-frappuccino/discordcore/pkg/util"
+)
+
+// Bootstrap holds the core components built at startup (session, config, store, service
+// manager and the built-in monitoring/automod services) so library consumers can extend
+// the bot before it starts:
+//
+//	b, err := app.NewBootstrap("mybot", "MYBOT_TOKEN")
+//	if err != nil { ... }
+//	if err := b.Register(myService); err != nil { ... }
+//	b.RegisterCommands(func(r *core.CommandRouter) { r.RegisterCommand(myCommand) })
+//	return b.Run() // blocks until interrupt, then shuts everything down
+//
+// Reusable bundles of services, commands and event handlers can instead be packaged as an
+// Extension and added with RegisterExtension; NewBootstrap registers them automatically.
+//
+// Run is what app.Run uses internally; calling NewBootstrap + Run with no extra
+// registrations is equivalent.
+type Bootstrap struct {
+	AppName    string
+	Session    *discordgo.Session
+	Config     *files.ConfigManager
+	Store      *storage.Store
+	Services   *service.ServiceManager
+	Monitoring *logging.MonitoringService
+	// ScheduledTasks runs durable tasks (scheduled_tasks); register handlers before Run
+	ScheduledTasks *task.Dispatcher
+	// Outage tracks Discord outages; check Degraded to pause non-essential work
+	Outage *session.OutageMonitor
+
+	started         time.Time
+	shutdownTimeout time.Duration
+	commandHandler  *commands.CommandHandler
+	commandRegistry []func(router *core.CommandRouter)
+	automodRouter   *task.TaskRouter
+	opsNotifier     *errors.OpsNotifier
+	outageDetach    func()
+	configWatchStop func()
+	lateGuildsStop  func()             // stops waiting for guilds that arrive after startup
+	commandsCancel  context.CancelFunc // cancels in-flight interactions on shutdown
+	cleanupStop     chan struct{}
+	persistStop     chan struct{}
+	closeOnce       sync.Once
+
+	readyMu      sync.Mutex
+	readyCh      chan struct{}
+	readyHooks   []func(ReadySummary)
+	readySummary *ReadySummary
+	warnings     []string
+}
+
+// NewBootstrap performs the startup flow up to (but not including) starting services:
+// logger, theme, error handling, Discord session, config, SQLite store, cache warmup and
+// registration of the built-in services. tokenEnv is read from the process environment
+// first, with a $HOME/.local/bin/.env fallback.
+// On error, every component created so far is closed.
+func NewBootstrap(appName, tokenEnv string) (*Bootstrap, error) {
+	b := &Bootstrap{AppName: appName, started: time.Now(), readyCh: make(chan struct{})}
+	if err := b.init(tokenEnv); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Bootstrap) init(tokenEnv string) error {
+	// App name first (affects paths)
+	util.SetAppName(b.AppName)
+
+	// Load env (with $HOME/.local/bin fallback)
+	token, loadErr := util.LoadEnvWithLocalBinFallback(tokenEnv)
+	if loadErr != nil {
+		fmt.Printf("Warning: %v\n", loadErr)
+	}
+
+	// Logger first so subsequent steps can log meaningfully
+	if err := log.SetupLogger(); err != nil {
+		return fmt.Errorf("configure logger: %w", err)
+	}
+
+	// Theme configuration
+	if err := util.ConfigureThemeFromEnv(); err != nil {
+		b.warnf("Failed to set theme from %s: %v", "ALICE_BOT_THEME", err)
+	}
+	if os.Getenv("ALICE_BOT_THEME") == "" {
+		if err := util.SetTheme(""); err != nil {
+			b.warnf("Failed to apply default theme: %v", err)
+		} else {
+			log.Info().Applicationf("🌈 Default theme applied")
+		}
+	}
+
+	// Global error handler
+	if err := errutil.InitializeGlobalErrorHandler(log.GlobalLogger); err != nil {
+		return fmt.Errorf("initialize global error handler: %w", err)
+	}
+
+	// Error handler for service manager
+	errorHandler := errors.NewErrorHandler()
+
+	log.Info().Applicationf("🚀 Starting %s...", b.AppName)
+	log.Info().Applicationf("🌍 Environment: %s (set %s to dev/staging/prod)", strings.ToUpper(util.CurrentEnvironment()), util.EnvironmentEnv)
+	if util.IsDevMode() {
+		log.Info().Applicationf("🧪 Development mode: in-memory store, settings from %s, temp dir %s", util.GetSettingsFilePath(), util.DevTempDir())
+	}
+
+	// Token must be present
+	if token == "" {
+		return fmt.Errorf("%s not set in environment or .env file", tokenEnv)
+	}
+
+	// Discord session
+	log.Info().Discordf("🔑 Attempting to authenticate with Discord API...")
+	log.Info().Discordf("Using bot token (value redacted)")
+	discordSession, err := session.NewDiscordSessionWithOptions(token, session.SessionOptionsFromEnv())
+	if err != nil {
+		if session.IsAuthError(err) {
+			return fmt.Errorf("create discord session: bot token rejected, check %s: %w", tokenEnv, err)
+		}
+		return fmt.Errorf("create discord session: %w", err)
+	}
+	b.Session = discordSession
+	if discordSession.State == nil || discordSession.State.User == nil {
+		return fmt.Errorf("discord session state not properly initialized")
+	}
+	log.Info().Discordf("✅ Authenticated as %s#%s", discordSession.State.User.Username, discordSession.State.User.Discriminator)
+
+	// Minimal on-disk structure
+	if err := util.EnsureCacheInitialized(); err != nil {
+		b.warnf("Failed to initialize cache structure: %v", err)
+	}
+	if err := util.EnsureCacheDirs(); err != nil {
+		return fmt.Errorf("create cache directories: %w", err)
+	}
+	if err := files.EnsureConfigFiles(); err != nil {
+		return fmt.Errorf("ensure config files: %w", err)
+	}
+
+	// Config manager
+	b.Config = files.NewConfigManager()
+	if err := b.Config.LoadConfig(); err != nil {
+		log.Error().Errorf("Failed to load settings file: %v", err)
+		b.recordWarning(fmt.Sprintf("Failed to load settings file: %v", err))
+	}
+
+	// Ops alerts: forward errors above the configured severity to a channel/webhook
+	b.configureErrorNotifications(errorHandler)
+
+	// Outage mode: back off and pause background work during Discord incidents
+	b.configureOutageMode(errorHandler)
+
+	// Shutdown timeout (env/config, validated up front so a typo fails at startup)
+	shutdownTimeout, err := b.Config.ShutdownTimeout()
+	if err != nil {
+		return fmt.Errorf("shutdown timeout: %w", err)
+	}
+	b.shutdownTimeout = shutdownTimeout
+
+	// Guild availability wait (same validation; used by logConfiguredGuilds below)
+	if _, err := b.Config.GuildAvailabilityTimeout(); err != nil {
+		return fmt.Errorf("guild availability timeout: %w", err)
+	}
+
+	// Bot operators (env/config, validated up front like the shutdown timeout)
+	operators, err := b.Config.BotOperators()
+	if err != nil {
+		return fmt.Errorf("bot operators: %w", err)
+	}
+	if len(operators) > 0 {
+		log.Info().Applicationf("🔑 Bot operators configured: %s (owner-gated commands allowed in every guild)", strings.Join(operators, ", "))
+	}
+
+	// SQLite store
+	storeOpts := storage.Options{Namespace: storage.NamespaceFromEnv()}
+	if archive, ok := b.Config.MessageArchive(); ok {
+		storeOpts.MessageArchiveDir, storeOpts.MessageArchiveGuilds = archive.Dir, archive.GuildIDs
+		log.Info().Databasef("🗄️ Expired messages are archived to %s before pruning", archive.Dir)
+	}
+	store := storage.NewStoreWithOptions(util.GetMessageDBPath(), storeOpts)
+	if err := store.Init(); err != nil {
+		return fmt.Errorf("initialize SQLite store: %w", err)
+	}
+	b.Store = store
+	if ns := storage.NamespaceFromEnv(); ns != "" {
+		log.Info().Databasef("🏷️ Store namespace %q: tables are prefixed with %s_", ns, ns)
+	}
+
+	// Log configured guilds
+	if err := b.logConfiguredGuilds(); err != nil {
+		log.Error().Errorf("Some configured guilds could not be accessed: %v", err)
+		b.recordWarning(fmt.Sprintf("Some configured guilds could not be accessed: %v", err))
+	}
+
+	// Periodic cleanup (every 6 hours)
+	b.cleanupStop = cache.SchedulePeriodicCleanup(store, 6*time.Hour)
+
+	// Service manager
+	b.Services = service.NewServiceManager(errorHandler)
+	b.Services.SetShutdownTimeout(b.shutdownTimeout)
+
+	// Monitoring service (central orchestration + unified cache)
+	monitoringService, err := logging.NewMonitoringService(discordSession, b.Config, store)
+	if err != nil {
+		return fmt.Errorf("create monitoring service: %w", err)
+	}
+	b.Monitoring = monitoringService
+	monitoringService.SetOutageMonitor(b.Outage)
+
+	// Cache warmup (persisted + fetch missing)
+	// NOTE: Warmup responsibility is consolidated in the app bootstrap.
+	// MonitoringService does not perform its own warmup to avoid duplicate work during startup.
+	unifiedCache := monitoringService.GetUnifiedCache()
+	if unifiedCache != nil && unifiedCache.WasWarmedUpRecently(10*time.Minute) {
+		log.Info().Applicationf("Skipping cache warmup (recently warmed up)")
+	} else {
+		warmupConfig := cache.DefaultWarmupConfig()
+		warmupConfig.MaxMembersPerGuild = 500 // mitigate initial load
+		if err := cache.IntelligentWarmup(discordSession, unifiedCache, store, warmupConfig); err != nil {
+			b.warnf("Intelligent warmup failed (continuing): %v", err)
+		}
+	}
+
+	// Periodic cache persistence
+	b.persistStop = unifiedCache.SetPersistInterval(30 * time.Minute)
+
+	// Wrap monitoring
+	monitoringWrapper := service.NewServiceWrapper(
+		"monitoring",
+		service.TypeMonitoring,
+		service.PriorityHigh,
+		[]string{},
+		func() error { return monitoringService.Start() },
+		func() error { return monitoringService.Stop() },
+		b.storeHealthy,
+	)
+	monitoringWrapper.SetReloadFunc(monitoringService.Reload)
+
+	// Automod service with TaskRouter adapters
+	automodService := logging.NewAutomodService(discordSession, b.Config)
+	b.automodRouter = task.NewRouter(task.Defaults())
+	automodAdapters := task.NewNotificationAdapters(b.automodRouter, discordSession, b.Config, store, monitoringService.Notifier())
+	automodService.SetAdapters(automodAdapters)
+	automodService.SetStore(store)
+
+	automodWrapper := service.NewServiceWrapper(
+		"automod",
+		service.TypeAutomod,
+		service.PriorityNormal,
+		[]string{},
+		func() error { automodService.Start(); return nil },
+		func() error { automodService.Stop(); return nil },
+		b.storeHealthy,
+	)
+	automodWrapper.SetReloadFunc(automodService.Reload)
+
+	// Durable scheduled tasks (started with the other services, after handlers are registered)
+	b.ScheduledTasks = task.NewDispatcher(store)
+	scheduledTasksWrapper := service.NewServiceWrapper(
+		"scheduled_tasks",
+		service.TypeScheduler,
+		service.PriorityLow,
+		[]string{},
+		b.ScheduledTasks.Start,
+		b.ScheduledTasks.Stop,
+		b.storeHealthy,
+	)
+
+	// Periodic activity reports (durable recurring tasks per guild)
+	activityReports := logging.NewActivityReportService(discordSession, b.Config, store, b.ScheduledTasks, monitoringService.Notifier())
+	activityReportsWrapper := service.NewServiceWrapper(
+		"activity_reports",
+		service.TypeScheduler,
+		service.PriorityLow,
+		[]string{"scheduled_tasks"},
+		activityReports.Start,
+		activityReports.Stop,
+		b.storeHealthy,
+	)
+	activityReportsWrapper.SetReloadFunc(activityReports.Reload)
+
+	// Register services
+	if err := b.Register(monitoringWrapper); err != nil {
+		return fmt.Errorf("register monitoring service: %w", err)
+	}
+	if err := b.Register(automodWrapper); err != nil {
+		return fmt.Errorf("register automod service: %w", err)
+	}
+	if err := b.Register(scheduledTasksWrapper); err != nil {
+		return fmt.Errorf("register scheduled tasks service: %w", err)
+	}
+	if err := b.Register(activityReportsWrapper); err != nil {
+		return fmt.Errorf("register activity reports service: %w", err)
+	}
+	presenceService := NewPresenceRotationService(discordSession, b.Config, monitoringService)
+	presenceService.SetOutageMonitor(b.Outage)
+	if err := b.Register(presenceService); err != nil {
+		return fmt.Errorf("register presence service: %w", err)
+	}
+
+	// Admin commands (registered before the Discord sync in Run)
+	adminCommands := admin.NewAdminCommands(b.Services)
+	adminCommands.SetStore(store)
+	adminCommands.SetActivityReports(activityReports)
+	b.RegisterCommands(adminCommands.RegisterCommands)
+
+	// Downstream extensions (app.RegisterExtension), after everything built in
+	return b.registerExtensions()
+}
+
+// configureErrorNotifications registers the ops notifier on the global error handler (and on
+// the errutil helpers) when error_notifications is configured. Misconfiguration is a warning.
+func (b *Bootstrap) configureErrorNotifications(eh *errors.ErrorHandler) {
+	cfg, ok := b.Config.ErrorNotifications()
+	if !ok {
+		return
+	}
+	minSeverity := errors.SeverityHigh
+	if cfg.MinSeverity != "" {
+		sev, valid := errors.ParseSeverity(cfg.MinSeverity)
+		if !valid {
+			b.warnf("Invalid error_notifications.min_severity %q; using %q", cfg.MinSeverity, minSeverity)
+		} else {
+			minSeverity = sev
+		}
+	}
+	dedup, _ := time.ParseDuration(cfg.DedupWindow)
+	notifier, err := errors.NewOpsNotifier(b.Session, errors.OpsNotifierConfig{
+		ChannelID:   cfg.ChannelID,
+		WebhookURL:  cfg.WebhookURL,
+		DedupWindow: dedup,
+		MaxPerHour:  cfg.MaxPerHour,
+	})
+	if err != nil {
+		b.warnf("Error notifications disabled: %v", err)
+		return
+	}
+	eh.AddSeverityNotifier(notifier, minSeverity)
+	b.opsNotifier = notifier
+	errutil.SetErrorHandler(eh)
+	log.Info().Applicationf("🚨 Forwarding errors with severity >= %s to the ops channel", minSeverity)
+}
+
+// configureOutageMode creates the OutageMonitor on the session. While degraded, Discord/network
+// errors are counted by the ErrorHandler instead of being logged and forwarded one by one, and
+// a single alert goes to the ops channel, if configured.
+func (b *Bootstrap) configureOutageMode(eh *errors.ErrorHandler) {
+	b.Outage = session.NewOutageMonitor(session.OutageConfig{})
+	b.outageDetach = b.Outage.Attach(b.Session)
+	eh.SetSuppressFilter(func(err *errors.ServiceError) bool {
+		return (err.Category == errors.CategoryDiscord || err.Category == errors.CategoryNetwork) && b.Outage.Degraded()
+	})
+
+	var suppressedAtStart atomic.Uint64
+	b.Outage.OnChange(func(state session.OutageState) {
+		if !state.Degraded {
+			log.Info().Applicationf("Outage mode ended; %d Discord/network errors were suppressed", eh.SuppressedCount()-suppressedAtStart.Load())
+			return
+		}
+		suppressedAtStart.Store(eh.SuppressedCount())
+		if b.opsNotifier == nil {
+			return
+		}
+		_ = b.opsNotifier.NotifyError(context.Background(), &errors.ServiceError{
+			Category:  errors.CategoryNetwork,
+			Severity:  errors.SeverityHigh,
+			Message:   fmt.Sprintf("Discord outage detected (%d failures, last: %s). Running in degraded mode: REST calls are backed off and background refreshes are paused until connectivity returns.", state.Failures, state.LastError),
+			Component: "session",
+			Operation: "outage_mode",
+			Timestamp: state.Since,
+		})
+	})
+}
+
+// Register adds a custom service to the service manager. It must be called before Run;
+// services start in priority/dependency order together with the built-in ones, and
+// may depend on "monitoring" or "automod" by name.
+func (b *Bootstrap) Register(svc service.Service) error {
+	if svc == nil {
+		return fmt.Errorf("service is nil")
+	}
+	return b.Services.Register(svc)
+}
+
+// RegisterCommands adds a function that registers slash commands on the router.
+// Registrars run before the commands are synced with Discord, so they must be added before Run.
+func (b *Bootstrap) RegisterCommands(fn func(router *core.CommandRouter)) {
+	if fn == nil {
+		return
+	}
+	b.commandRegistry = append(b.commandRegistry, fn)
+}
+
+// Run starts every registered service, syncs slash commands and blocks until an
+// interrupt signal, then shuts down gracefully and closes the bootstrap.
+func (b *Bootstrap) Run() error {
+	defer b.Close()
+
+	// Start services
+	log.Info().Applicationf("🚀 Starting all services...")
+	if err := b.Services.StartAll(); err != nil {
+		return fmt.Errorf("start services: %w", err)
+	}
+
+	// Hot reload: external edits to the config file are applied to the running services
+	b.Config.OnReload(func(cfg *files.BotConfig) {
+		if err := b.Services.ReloadAll(cfg); err != nil {
+			log.Error().Errorf("Some services failed to apply the reloaded configuration: %v", err)
+		}
+	})
+	b.configWatchStop = b.Config.Watch(files.DefaultConfigWatchInterval)
+
+	// Commands
+	b.commandHandler = commands.NewCommandHandler(b.Session, b.Config)
+	commandsCtx, commandsCancel := context.WithCancel(context.Background())
+	b.commandsCancel = commandsCancel
+	b.commandHandler.AddRegistrar(func(r *core.CommandRouter) { r.SetBaseContext(commandsCtx) })
+	for _, fn := range b.commandRegistry {
+		b.commandHandler.AddRegistrar(fn)
+	}
+	if err := b.commandHandler.SetupCommands(); err != nil {
+		return fmt.Errorf("configure slash commands: %w", err)
+	}
+
+	// Inject store and unified cache into command router
+	if cm := b.commandHandler.GetCommandManager(); cm != nil {
+		if router := cm.GetRouter(); router != nil {
+			router.SetStore(b.Store)
+			if b.Monitoring != nil {
+				router.SetCache(b.Monitoring.GetUnifiedCache())
+			}
+		}
+		// disabled_commands changed in the file: resync those guilds' commands in the background
+		disabled := newDisabledCommandsTracker(b.Config.Guilds())
+		b.Config.OnReload(func(cfg *files.BotConfig) {
+			changed := disabled.update(cfg.Guilds)
+			if len(changed) == 0 {
+				return
+			}
+			go func() {
+				if err := cm.ResyncGuildCommands(changed...); err != nil {
+					log.Error().Errorf("Failed to resync slash commands after config reload: %v", err)
+				}
+			}()
+		})
+	}
+
+	log.Info().Applicationf("🔗 Slash commands sync completed")
+
+	// Self-test before declaring ready
+	if err := b.runSelfTest(); err != nil {
+		b.shutdown()
+		return fmt.Errorf("startup self-test: %w", err)
+	}
+
+	log.Info().Applicationf("🎯 %s initialized successfully in %s", b.AppName, time.Since(b.started).Round(time.Millisecond))
+	b.fireReady()
+	log.Info().Applicationf("🤖 %s running. Press Ctrl+C to stop...", b.AppName)
+
+	// Wait for shutdown signal
+	util.WaitForInterrupt()
+	log.Info().Applicationf("🛑 Stopping %s...", b.AppName)
+
+	b.shutdown()
+	return nil
+}
+
+// shutdown stops every service and drains the automod task router, bounded by
+// shutdownTimeout as a whole. Whatever is still running when it expires is abandoned.
+func (b *Bootstrap) shutdown() {
+	if b.commandsCancel != nil {
+		b.commandsCancel()
+	}
+	if b.configWatchStop != nil {
+		b.configWatchStop()
+	}
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), b.shutdownTimeout)
+	defer shutdownCancel()
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- b.Services.StopAll() }()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			log.Error().Errorf("Some services failed to stop cleanly: %v", err)
+		}
+	case <-shutdownCtx.Done():
+		log.Error().Errorf("Services did not stop within the %s shutdown timeout", b.shutdownTimeout)
+	}
+
+	if b.automodRouter != nil {
+		if err := b.automodRouter.Shutdown(shutdownCtx); err != nil {
+			log.Error().Errorf("Automod task router did not drain within the %s shutdown timeout: %v", b.shutdownTimeout, err)
+		}
+		// Either closed or abandoned; Close must not wait on it again
+		b.automodRouter = nil
+	}
+
+	// Allow services to finish final writes before closing store
+	time.Sleep(100 * time.Millisecond)
+}
+
+// Close releases the store, the Discord session and background schedulers. It is
+// called by Run and is safe to call more than once.
+func (b *Bootstrap) Close() {
+	b.closeOnce.Do(func() {
+		if b.configWatchStop != nil {
+			b.configWatchStop()
+		}
+		if b.lateGuildsStop != nil {
+			b.lateGuildsStop()
+		}
+		if b.cleanupStop != nil {
+			close(b.cleanupStop)
+		}
+		if b.persistStop != nil {
+			close(b.persistStop)
+		}
+		if b.automodRouter != nil {
+			b.automodRouter.Close()
+		}
+		if b.Store != nil {
+			_ = b.Store.Close()
+		}
+		// Alerts use the session being closed
+		errutil.SetErrorHandler(nil)
+		if b.outageDetach != nil {
+			b.outageDetach()
+		}
+		if b.Session != nil {
+			_ = session.Close(b.Session)
+		}
+		if err := util.CleanupDevMode(); err != nil {
+			log.Warn().Applicationf("Failed to remove development temp directory %s: %v", util.DevTempDir(), err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// disabledCommandsTracker remembers each active guild's disabled_commands so a config reload
+// only resyncs the guilds whose set changed.
+type disabledCommandsTracker struct {
+	mu   sync.Mutex
+	sets map[string]string
+}
+
+func newDisabledCommandsTracker(guilds []files.GuildConfig) *disabledCommandsTracker {
+	return &disabledCommandsTracker{sets: disabledCommandSets(guilds)}
+}
+
+// update records the sets of guilds and returns the IDs of the guilds whose set changed,
+// including guilds added, removed or deactivated with commands disabled.
+func (t *disabledCommandsTracker) update(guilds []files.GuildConfig) []string {
+	next := disabledCommandSets(guilds)
+	t.mu.Lock()
+	prev := t.sets
+	t.sets = next
+	t.mu.Unlock()
+
+	var changed []string
+	for id, set := range next {
+		if prev[id] != set {
+			changed = append(changed, id)
+		}
+	}
+	for id, set := range prev {
+		if _, ok := next[id]; !ok && set != "" {
+			changed = append(changed, id)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// disabledCommandSets maps every active guild to its normalized, sorted disabled_commands.
+func disabledCommandSets(guilds []files.GuildConfig) map[string]string {
+	sets := make(map[string]string, len(guilds))
+	for _, gcfg := range guilds {
+		if gcfg.Inactive {
+			continue
+		}
+		paths := make([]string, 0, len(gcfg.DisabledCommands))
+		for _, path := range gcfg.DisabledCommands {
+			paths = append(paths, files.NormalizeCommandPath(path))
+		}
+		slices.Sort(paths)
+		sets[gcfg.GuildID] = strings.Join(slices.Compact(paths), ",")
+	}
+	return sets
+}
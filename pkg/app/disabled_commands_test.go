@@ -0,0 +1,36 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestDisabledCommandsTrackerReportsChangedGuilds(t *testing.T) {
+	tracker := newDisabledCommandsTracker([]files.GuildConfig{
+		{GuildID: "g1", DisabledCommands: []string{"ping"}},
+		{GuildID: "g2", DisabledCommands: []string{"config automod"}},
+		{GuildID: "g3"},
+		{GuildID: "g4", DisabledCommands: []string{"ping"}},
+	})
+
+	changed := tracker.update([]files.GuildConfig{
+		{GuildID: "g1", DisabledCommands: []string{"/ping"}},                 // same set, different spelling
+		{GuildID: "g2", DisabledCommands: []string{"config automod", "ping"}}, // grew
+		{GuildID: "g3"},
+		{GuildID: "g5"}, // new guild without disabled commands
+		// g4 removed
+	})
+	if want := []string{"g2", "g4"}; !slices.Equal(changed, want) {
+		t.Fatalf("changed = %v, want %v", changed, want)
+	}
+	if changed := tracker.update([]files.GuildConfig{
+		{GuildID: "g1", DisabledCommands: []string{"ping"}},
+		{GuildID: "g2", DisabledCommands: []string{"ping", "config automod"}},
+		{GuildID: "g3"},
+		{GuildID: "g5"},
+	}); len(changed) != 0 {
+		t.Fatalf("unchanged reload reported %v", changed)
+	}
+}
@@ -0,0 +1,110 @@
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/app"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/service"
+)
+
+// uptimeReporterService periodically logs how long the bot has been up. It embeds BaseService
+// (name, state, health, stats) and only defines the start/stop hooks.
+type uptimeReporterService struct {
+	*service.BaseService
+	interval time.Duration
+	stop     chan struct{}
+}
+
+// newUptimeReporterService depends on "monitoring" so it starts after it.
+func newUptimeReporterService(interval time.Duration) *uptimeReporterService {
+	s := &uptimeReporterService{
+		BaseService: service.NewBaseService("uptime-reporter", service.TypeMonitoring, service.PriorityLow, []string{"monitoring"}),
+		interval:    interval,
+	}
+	s.SetStartHook(func(ctx context.Context) error {
+		s.stop = make(chan struct{})
+		started := time.Now()
+		go func(stop chan struct{}) {
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					log.Info().Applicationf("⏱️ Bot up for %s", time.Since(started).Round(time.Minute))
+				case <-stop:
+					return
+				}
+			}
+		}(s.stop)
+		return nil
+	})
+	s.SetStopHook(func(ctx context.Context) error {
+		if s.stop != nil {
+			close(s.stop)
+			s.stop = nil
+		}
+		return nil
+	})
+	return s
+}
+
+// uptimeExtension bundles the uptimeReporterService, an /uptime command and a handler that logs
+// the guilds the bot joins.
+type uptimeExtension struct {
+	interval time.Duration
+	started  time.Time
+}
+
+func (e *uptimeExtension) Name() string {
+	return "uptime"
+}
+
+func (e *uptimeExtension) Register(b *app.Bootstrap) error {
+	e.started = time.Now()
+	if err := b.Register(newUptimeReporterService(e.interval)); err != nil {
+		return err
+	}
+	b.RegisterCommands(func(r *core.CommandRouter) {
+		r.RegisterCommand(core.NewSimpleCommand("uptime", "Show how long the bot has been running", nil,
+			func(ctx *core.Context) error {
+				return core.NewResponder(ctx.API).Info(ctx.Interaction, fmt.Sprintf("Up for %s", time.Since(e.started).Round(time.Second)))
+			}, false, false))
+	})
+	b.AddHandler(func(_ *discordgo.Session, g *discordgo.GuildCreate) {
+		log.Info().Applicationf("⏱️ Guild available: %s (%s)", g.Name, g.ID)
+	})
+	b.OnReady(func(s app.ReadySummary) {
+		log.Info().Applicationf("⏱️ Uptime extension active in %d guilds", s.GuildCount)
+	})
+	return nil
+}
+
+// A custom service registered on the Bootstrap before Run.
+func ExampleBootstrap_Register() {
+	b, err := app.NewBootstrap("mybot", "MYBOT_TOKEN")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := b.Register(newUptimeReporterService(time.Hour)); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := b.Run(); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// An extension registered in the package init() is applied by app.Run (via NewBootstrap).
+func ExampleMustRegisterExtension() {
+	app.MustRegisterExtension(&uptimeExtension{interval: time.Hour})
+
+	if err := app.Run("mybot", "MYBOT_TOKEN"); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/session"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// Extension bundles a downstream bot's services, commands and event handlers so they plug into
+// the Bootstrap without changing main.go. Register is called once, at the end of NewBootstrap
+// after the built-in services and commands; use b.Register for services, b.RegisterCommands for
+// slash commands, b.AddHandler for gateway events and b.OnReady for post-startup work. An error
+// aborts NewBootstrap.
+type Extension interface {
+	Name() string
+	Register(b *Bootstrap) error
+}
+
+var (
+	extensionsMu sync.Mutex
+	extensions   []Extension
+)
+
+// RegisterExtension adds ext to the global registry, usually from the extension package's
+// init(). Extensions are registered on the Bootstrap in the order they were added. Empty or
+// duplicate names are rejected.
+func RegisterExtension(ext Extension) error {
+	if ext == nil {
+		return fmt.Errorf("extension is nil")
+	}
+	name := strings.TrimSpace(ext.Name())
+	if name == "" {
+		return fmt.Errorf("extension name is empty")
+	}
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	for _, e := range extensions {
+		if e.Name() == name {
+			return fmt.Errorf("extension %q already registered", name)
+		}
+	}
+	extensions = append(extensions, ext)
+	return nil
+}
+
+// MustRegisterExtension is RegisterExtension for use in init(); it panics on error.
+func MustRegisterExtension(ext Extension) {
+	if err := RegisterExtension(ext); err != nil {
+		panic(err)
+	}
+}
+
+// Extensions returns the names of the registered extensions, in order.
+func Extensions() []string {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	names := make([]string, 0, len(extensions))
+	for _, e := range extensions {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+// registerExtensions calls Register on every registered extension in order, stopping at the first error.
+func (b *Bootstrap) registerExtensions() error {
+	extensionsMu.Lock()
+	exts := append([]Extension(nil), extensions...)
+	extensionsMu.Unlock()
+	for _, ext := range exts {
+		if err := ext.Register(b); err != nil {
+			return fmt.Errorf("register extension %q: %w", ext.Name(), err)
+		}
+		log.Info().Applicationf("🧩 Extension registered: %s", ext.Name())
+	}
+	return nil
+}
+
+// AddHandler subscribes to a gateway event (same signature as discordgo.Session.AddHandler, e.g.
+// func(*discordgo.Session, *discordgo.MessageCreate)) and returns a func that unsubscribes.
+// Panics in the handler are recovered and logged (session.Guard).
+func (b *Bootstrap) AddHandler(handler any) func() {
+	return b.Session.AddHandler(session.Guard(handler))
+}
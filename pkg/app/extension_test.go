@@ -0,0 +1,168 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	coreerrors "github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/service"
+)
+
+// testExtension records its Register calls in a shared log and optionally fails.
+type testExtension struct {
+	name     string
+	calls    *[]string
+	err      error
+	register func(b *Bootstrap) error
+}
+
+func (e *testExtension) Name() string { return e.name }
+
+func (e *testExtension) Register(b *Bootstrap) error {
+	*e.calls = append(*e.calls, e.name)
+	if e.err != nil {
+		return e.err
+	}
+	if e.register != nil {
+		return e.register(b)
+	}
+	return nil
+}
+
+// withExtensions swaps the global registry for the duration of the test.
+func withExtensions(t *testing.T) {
+	t.Helper()
+	extensionsMu.Lock()
+	saved := extensions
+	extensions = nil
+	extensionsMu.Unlock()
+	t.Cleanup(func() {
+		extensionsMu.Lock()
+		extensions = saved
+		extensionsMu.Unlock()
+	})
+}
+
+func newTestBootstrap(t *testing.T) *Bootstrap {
+	t.Helper()
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	return &Bootstrap{
+		AppName:  "test",
+		Session:  s,
+		Services: service.NewServiceManager(coreerrors.NewErrorHandler()),
+		readyCh:  make(chan struct{}),
+	}
+}
+
+func TestRegisterExtensionValidates(t *testing.T) {
+	withExtensions(t)
+	var calls []string
+	for _, tc := range []struct {
+		name    string
+		ext     Extension
+		wantErr string
+	}{
+		{"nil", nil, "nil"},
+		{"empty name", &testExtension{name: "  ", calls: &calls}, "empty"},
+		{"first", &testExtension{name: "alpha", calls: &calls}, ""},
+		{"duplicate", &testExtension{name: "alpha", calls: &calls}, "already registered"},
+		{"second", &testExtension{name: "beta", calls: &calls}, ""},
+	} {
+		err := RegisterExtension(tc.ext)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("%s: error = %v, want it to mention %q", tc.name, err, tc.wantErr)
+		}
+	}
+	if got := Extensions(); !slices.Equal(got, []string{"alpha", "beta"}) {
+		t.Errorf("Extensions() = %v, want [alpha beta]", got)
+	}
+}
+
+func TestMustRegisterExtensionPanicsOnDuplicate(t *testing.T) {
+	withExtensions(t)
+	var calls []string
+	MustRegisterExtension(&testExtension{name: "alpha", calls: &calls})
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRegisterExtension did not panic on a duplicate name")
+		}
+	}()
+	MustRegisterExtension(&testExtension{name: "alpha", calls: &calls})
+}
+
+func TestRegisterExtensionsRunsInOrder(t *testing.T) {
+	withExtensions(t)
+	b := newTestBootstrap(t)
+	var calls []string
+	var readyHooks int
+	MustRegisterExtension(&testExtension{name: "first", calls: &calls, register: func(b *Bootstrap) error {
+		svc := service.NewServiceWrapper("ext-service", service.TypeMonitoring, service.PriorityLow, nil, nil, nil, nil)
+		if err := b.Register(svc); err != nil {
+			return err
+		}
+		b.RegisterCommands(func(*core.CommandRouter) {})
+		b.AddHandler(func(*discordgo.Session, *discordgo.GuildCreate) {})
+		b.OnReady(func(ReadySummary) { readyHooks++ })
+		return nil
+	}})
+	MustRegisterExtension(&testExtension{name: "second", calls: &calls})
+
+	if err := b.registerExtensions(); err != nil {
+		t.Fatalf("registerExtensions: %v", err)
+	}
+	if !slices.Equal(calls, []string{"first", "second"}) {
+		t.Errorf("Register order = %v, want [first second]", calls)
+	}
+	if _, err := b.Services.GetServiceInfo("ext-service"); err != nil {
+		t.Errorf("service registered by the extension is missing: %v", err)
+	}
+	if len(b.commandRegistry) != 1 {
+		t.Errorf("%d command registrars, want 1", len(b.commandRegistry))
+	}
+	if len(b.readyHooks) != 1 {
+		t.Errorf("%d ready hooks, want 1", len(b.readyHooks))
+	}
+}
+
+func TestRegisterExtensionsStopsAtFirstError(t *testing.T) {
+	withExtensions(t)
+	b := newTestBootstrap(t)
+	var calls []string
+	boom := errors.New("boom")
+	MustRegisterExtension(&testExtension{name: "ok", calls: &calls})
+	MustRegisterExtension(&testExtension{name: "broken", calls: &calls, err: boom})
+	MustRegisterExtension(&testExtension{name: "never", calls: &calls})
+
+	err := b.registerExtensions()
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), `"broken"`) {
+		t.Fatalf("registerExtensions error = %v, want boom naming the extension", err)
+	}
+	if !slices.Equal(calls, []string{"ok", "broken"}) {
+		t.Errorf("Register calls = %v, want [ok broken]", calls)
+	}
+}
+
+func TestRegisterExtensionsRejectsDuplicateService(t *testing.T) {
+	withExtensions(t)
+	b := newTestBootstrap(t)
+	var calls []string
+	register := func(b *Bootstrap) error {
+		return b.Register(service.NewServiceWrapper("shared", service.TypeMonitoring, service.PriorityLow, nil, nil, nil, nil))
+	}
+	MustRegisterExtension(&testExtension{name: "a", calls: &calls, register: register})
+	MustRegisterExtension(&testExtension{name: "b", calls: &calls, register: register})
+
+	if err := b.registerExtensions(); err == nil || !strings.Contains(err.Error(), `"b"`) {
+		t.Errorf("registerExtensions error = %v, want the duplicate service of b", err)
+	}
+}
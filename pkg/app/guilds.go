@@ -0,0 +1,148 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/session"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// logConfiguredGuilds logs the configured guilds and returns an error if any is inaccessible.
+//
+// Guilds that READY listed as unavailable are still loading (or in a Discord outage), so
+// startup does not wait for them: a late GUILD_CREATE is logged when it arrives, for up to
+// guild_availability_timeout, and guilds that never arrive only produce a warning. Only guilds
+// missing from READY that the API also rejects count as inaccessible.
+func (b *Bootstrap) logConfiguredGuilds() error {
+	guilds := b.Config.Guilds()
+	if len(guilds) == 0 {
+		log.Warn().Applicationf(files.LogNoConfiguredGuilds)
+		return nil
+	}
+	log.Info().Applicationf(files.LogFoundConfiguredGuilds, len(guilds))
+
+	timeout, err := b.Config.GuildAvailabilityTimeout()
+	if err != nil {
+		timeout = files.DefaultGuildAvailabilityTimeout
+	}
+	ids := make([]string, 0, len(guilds))
+	for _, g := range guilds {
+		ids = append(ids, g.GuildID)
+	}
+	availability := session.WaitForGuilds(b.Session, ids, 0)
+
+	for _, id := range availability.Available {
+		if guild, err := b.Session.State.Guild(id); err == nil {
+			log.Info().Applicationf("🔎 Will monitor this guild: %s (%s)", guild.Name, guild.ID)
+		}
+	}
+	if len(availability.Pending) > 0 {
+		b.lateGuildsStop = watchLateGuilds(b.Session, availability.Pending, timeout)
+	}
+
+	// Not in READY: confirm with the API before declaring the guild inaccessible
+	var errCount int
+	for _, id := range availability.Missing {
+		guild, err := b.Session.Guild(id)
+		if err == nil {
+			log.Info().Applicationf("🔎 Will monitor this guild: %s (%s)", guild.Name, guild.ID)
+		} else {
+			log.Warn().Applicationf("%s: %s", files.LogGuildNotAccessible, id)
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		return fmt.Errorf(files.ErrGuildsNotAccessible, errCount)
+	}
+	return nil
+}
+
+// watchLateGuilds logs pending guilds as their GUILD_CREATE arrives. The handler removes itself
+// after the last one or when timeout expires (warning about the rest); timeout <= 0 only warns.
+// The returned func stops waiting early (e.g. on shutdown).
+func watchLateGuilds(s *discordgo.Session, guildIDs []string, timeout time.Duration) (stop func()) {
+	if timeout <= 0 {
+		for _, id := range guildIDs {
+			log.Warn().Applicationf("%s: %s", files.LogGuildStillUnavailable, id)
+		}
+		return func() {}
+	}
+	log.Info().Applicationf(files.LogWaitingForGuilds, timeout, len(guildIDs))
+
+	w := newLateGuildWatcher(guildIDs)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.remove = s.AddHandler(w.guildCreate)
+	w.timer = time.AfterFunc(timeout, w.expire)
+	return w.stop
+}
+
+// lateGuildWatcher is the state of watchLateGuilds: the guilds still awaited and how to stop.
+type lateGuildWatcher struct {
+	mu      sync.Mutex
+	waiting map[string]struct{}
+	done    bool
+	remove  func()
+	timer   *time.Timer
+}
+
+func newLateGuildWatcher(guildIDs []string) *lateGuildWatcher {
+	w := &lateGuildWatcher{waiting: make(map[string]struct{}, len(guildIDs))}
+	for _, id := range guildIDs {
+		w.waiting[id] = struct{}{}
+	}
+	return w
+}
+
+func (w *lateGuildWatcher) guildCreate(_ *discordgo.Session, e *discordgo.GuildCreate) {
+	if e == nil || e.Guild == nil || e.Unavailable {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.waiting[e.ID]; !ok || w.done {
+		return
+	}
+	delete(w.waiting, e.ID)
+	log.Info().Applicationf("%s: %s (%s)", files.LogGuildBecameAvailable, e.Name, e.ID)
+	if len(w.waiting) == 0 {
+		w.finishLocked()
+	}
+}
+
+// expire warns about the guilds that did not arrive in time and removes the handler.
+func (w *lateGuildWatcher) expire() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return
+	}
+	for id := range w.waiting {
+		log.Warn().Applicationf("%s: %s", files.LogGuildStillUnavailable, id)
+	}
+	w.finishLocked()
+}
+
+func (w *lateGuildWatcher) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.finishLocked()
+}
+
+// finishLocked removes the handler and stops the timer, once.
+func (w *lateGuildWatcher) finishLocked() {
+	if w.done {
+		return
+	}
+	w.done = true
+	if w.remove != nil {
+		w.remove()
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
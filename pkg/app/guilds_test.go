@@ -0,0 +1,65 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func guildCreate(id string, unavailable bool) *discordgo.GuildCreate {
+	return &discordgo.GuildCreate{Guild: &discordgo.Guild{ID: id, Name: "guild " + id, Unavailable: unavailable}}
+}
+
+func TestLateGuildWatcherRemovesHandlerAfterLastGuild(t *testing.T) {
+	w := newLateGuildWatcher([]string{"g1", "g2"})
+	removed := 0
+	w.remove = func() { removed++ }
+
+	w.guildCreate(nil, guildCreate("g1", false))
+	w.guildCreate(nil, guildCreate("g2", true)) // still unavailable: keep waiting
+	w.guildCreate(nil, guildCreate("other", false))
+	if removed != 0 {
+		t.Fatalf("handler removed with g2 still pending")
+	}
+	w.guildCreate(nil, guildCreate("g2", false))
+	if removed != 1 || len(w.waiting) != 0 {
+		t.Fatalf("removed %d times, %d guilds waiting; want 1 and 0", removed, len(w.waiting))
+	}
+	w.expire()
+	w.stop()
+	if removed != 1 {
+		t.Errorf("handler removed %d times, want 1", removed)
+	}
+}
+
+func TestLateGuildWatcherRemovesHandlerOnTimeout(t *testing.T) {
+	w := newLateGuildWatcher([]string{"g1", "never"})
+	removed := 0
+	w.remove = func() { removed++ }
+
+	w.guildCreate(nil, guildCreate("g1", false))
+	w.expire()
+	if removed != 1 {
+		t.Fatalf("handler removed %d times after the timeout, want 1", removed)
+	}
+	// A GUILD_CREATE after the timeout is ignored
+	w.guildCreate(nil, guildCreate("never", false))
+	if _, ok := w.waiting["never"]; !ok || removed != 1 {
+		t.Errorf("late event after the timeout changed the watcher (removed %d)", removed)
+	}
+}
+
+func TestWatchLateGuildsExpiresWithoutBlocking(t *testing.T) {
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	stop := watchLateGuilds(s, []string{"g1"}, time.Hour)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("watchLateGuilds blocked for %s", elapsed)
+	}
+	stop()
+	stop()
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// storeHealthy is the health check of the built-in services: the store must answer Ping.
+func (b *Bootstrap) storeHealthy() bool {
+	if b.Store == nil {
+		return false
+	}
+	if err := b.Store.Ping(context.Background()); err != nil {
+		log.Error().Errorf("Store health check failed: %v", err)
+		return false
+	}
+	return true
+}
+
+// ReadyzHandler returns an http.Handler for a readiness probe (e.g. mounted at /readyz by the
+// host application): 200 once startup completed and the store answers Ping within the
+// request's context (bounded by storage.DefaultPingTimeout), 503 otherwise. During a Discord
+// outage the body is "degraded" but the status stays 200, so orchestrators don't restart a
+// bot that can do nothing about the incident.
+func (b *Bootstrap) ReadyzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-b.Ready():
+		default:
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+			return
+		}
+		if b.Store == nil {
+			http.Error(w, "store unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if err := b.Store.Ping(r.Context()); err != nil {
+			http.Error(w, "store: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if b.Outage.Degraded() {
+			_, _ = w.Write([]byte("degraded"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+// HealthStatus is the JSON body served by HealthzHandler.
+type HealthStatus struct {
+	Status string        `json:"status"` // ok, degraded, starting or unhealthy
+	Ready  bool          `json:"ready"`
+	Store  string        `json:"store"` // ok or the Ping error
+	Outage *OutageHealth `json:"outage,omitempty"`
+}
+
+// OutageHealth describes the Discord outage mode in HealthStatus.
+type OutageHealth struct {
+	Degraded  bool      `json:"degraded"`
+	Since     time.Time `json:"since"`
+	Failures  int       `json:"failures"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// HealthzHandler returns an http.Handler reporting HealthStatus as JSON (e.g. mounted at
+// /healthz). The status code is 503 only when the store fails; a Discord outage is reported
+// as status "degraded" with 200.
+func (b *Bootstrap) HealthzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := HealthStatus{Status: "ok", Store: "ok"}
+		select {
+		case <-b.Ready():
+			h.Ready = true
+		default:
+			h.Status = "starting"
+		}
+		code := http.StatusOK
+		if b.Store == nil {
+			h.Store = "unavailable"
+		} else if err := b.Store.Ping(r.Context()); err != nil {
+			h.Store = err.Error()
+		}
+		if h.Store != "ok" {
+			h.Status, code = "unhealthy", http.StatusServiceUnavailable
+		}
+		if b.Outage != nil {
+			st := b.Outage.State()
+			h.Outage = &OutageHealth{Degraded: st.Degraded, Since: st.Since, Failures: st.Failures, LastError: st.LastError}
+			if st.Degraded && h.Status == "ok" {
+				h.Status = "degraded"
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(h)
+	})
+}
@@ -0,0 +1,254 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/discord/session"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/service"
+)
+
+// degradedPresenceText is the only status shown while outage mode is active.
+const degradedPresenceText = "⚠️ Discord instável — operando em modo degradado"
+
+// PresenceStats are the values available to presence.statuses templates.
+type PresenceStats struct {
+	GuildCount  int
+	MemberCount int
+	OnlineCount int
+	Uptime      string // e.g. "3h12m"
+}
+
+// PresenceRotationService cycles the bot status through presence.statuses every
+// presence.interval, re-reading the config on each tick. Without statuses the presence is left
+// alone. With an OutageMonitor attached, rotation pauses while degraded and a single outage
+// status is shown instead.
+type PresenceRotationService struct {
+	*service.BaseService
+	session    *discordgo.Session
+	config     *files.ConfigManager
+	monitoring *logging.MonitoringService
+	outage     *session.OutageMonitor
+	started    time.Time
+
+	mu       sync.Mutex
+	stop     chan struct{}
+	index    int
+	last     string
+	handlers *session.HandlerSet
+}
+
+// NewPresenceRotationService creates the service; monitoring (optional) provides member counts.
+func NewPresenceRotationService(session *discordgo.Session, config *files.ConfigManager, monitoring *logging.MonitoringService) *PresenceRotationService {
+	s := &PresenceRotationService{
+		BaseService: service.NewBaseService("presence", service.TypeNotifier, service.PriorityLow, []string{"monitoring"}),
+		session:     session,
+		config:      config,
+		monitoring:  monitoring,
+	}
+	s.SetStartHook(func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.started = time.Now()
+		s.stop = make(chan struct{})
+		s.installHandlers()
+		go s.loop(s.stop)
+		return nil
+	})
+	s.SetStopHook(func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.stop != nil {
+			close(s.stop)
+			s.stop = nil
+		}
+		if s.handlers != nil {
+			s.handlers.RemoveAll()
+			s.handlers = nil
+		}
+		return nil
+	})
+	return s
+}
+
+// installHandlers reapplies the status after Ready or Resumed, since Discord may drop the
+// previous presence. Called with s.mu held.
+func (s *PresenceRotationService) installHandlers() {
+	if s.session == nil {
+		return
+	}
+	s.handlers = session.NewHandlerSet(s.session)
+	s.handlers.Add(func(_ *discordgo.Session, _ *discordgo.Ready) { s.resetPresence() })
+	s.handlers.Add(func(_ *discordgo.Session, _ *discordgo.Resumed) { s.resetPresence() })
+}
+
+// resetPresence forgets the last status sent and applies the next one right away.
+func (s *PresenceRotationService) resetPresence() {
+	s.mu.Lock()
+	s.last = ""
+	running := s.stop != nil
+	s.mu.Unlock()
+	if running {
+		s.rotate()
+	}
+}
+
+// SetOutageMonitor attaches the outage monitor; call it before Start.
+func (s *PresenceRotationService) SetOutageMonitor(m *session.OutageMonitor) {
+	s.outage = m
+	m.OnChange(func(state session.OutageState) {
+		s.mu.Lock()
+		s.last = ""
+		running := s.stop != nil
+		s.mu.Unlock()
+		if !running {
+			return
+		}
+		if state.Degraded {
+			s.rotate()
+			return
+		}
+		if len(s.config.Presence().Statuses) == 0 {
+			// No rotation configured: just clear the outage status
+			if err := s.session.UpdateStatusComplex(discordgo.UpdateStatusData{Status: string(discordgo.StatusOnline)}); err != nil {
+				log.Warn().Discordf("Failed to clear degraded presence: %v", err)
+			}
+			return
+		}
+		s.rotate()
+	})
+}
+
+func (s *PresenceRotationService) loop(stop chan struct{}) {
+	for {
+		s.rotate()
+		timer := time.NewTimer(s.config.Presence().RotationInterval())
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// rotate applies the next status. Updates identical to the last one are skipped to spare the
+// gateway's presence update limit.
+func (s *PresenceRotationService) rotate() {
+	cfg := s.config.Presence()
+	var text string
+	if s.outage.Degraded() {
+		cfg = files.PresenceConfig{Status: string(discordgo.StatusIdle)}
+		text = degradedPresenceText
+	} else {
+		if len(cfg.Statuses) == 0 {
+			return
+		}
+		s.mu.Lock()
+		raw := cfg.Statuses[s.index%len(cfg.Statuses)]
+		s.index = (s.index + 1) % len(cfg.Statuses)
+		s.mu.Unlock()
+
+		rendered, err := renderPresence(raw, s.stats())
+		if err != nil {
+			log.Warn().Applicationf("Invalid presence status template %q: %v", raw, err)
+			return
+		}
+		text = strings.TrimSpace(rendered)
+	}
+	if text == "" {
+		return
+	}
+	key := cfg.Activity + "|" + cfg.Status + "|" + text
+	s.mu.Lock()
+	unchanged := key == s.last
+	s.last = key
+	s.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	if err := s.session.UpdateStatusComplex(presenceUpdate(cfg, text)); err != nil {
+		log.Warn().Discordf("Failed to update bot presence: %v", err)
+		s.mu.Lock()
+		s.last = ""
+		s.mu.Unlock()
+	}
+}
+
+// stats collects the live values used by the templates.
+func (s *PresenceRotationService) stats() PresenceStats {
+	st := PresenceStats{Uptime: time.Since(s.started).Round(time.Minute).String()}
+	if st.Uptime = strings.TrimSuffix(st.Uptime, "0s"); st.Uptime == "" {
+		st.Uptime = "0m"
+	}
+	if s.session == nil || s.session.State == nil {
+		return st
+	}
+	s.session.State.RLock()
+	guilds := make([]*discordgo.Guild, len(s.session.State.Guilds))
+	copy(guilds, s.session.State.Guilds)
+	s.session.State.RUnlock()
+
+	st.GuildCount = len(guilds)
+	for _, g := range guilds {
+		if s.monitoring != nil {
+			if gs, err := s.monitoring.GetGuildStats(g.ID); err == nil {
+				st.MemberCount += gs.MemberCount
+				st.OnlineCount += gs.OnlineCount
+				continue
+			}
+		}
+		st.MemberCount += g.MemberCount
+	}
+	return st
+}
+
+func renderPresence(raw string, stats PresenceStats) (string, error) {
+	tmpl, err := template.New("presence").Option("missingkey=error").Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, stats); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func presenceUpdate(cfg files.PresenceConfig, text string) discordgo.UpdateStatusData {
+	status := cfg.Status
+	switch status {
+	case string(discordgo.StatusIdle), string(discordgo.StatusDoNotDisturb), string(discordgo.StatusOnline):
+	default:
+		status = string(discordgo.StatusOnline)
+	}
+	activity := &discordgo.Activity{Name: text}
+	switch strings.ToLower(cfg.Activity) {
+	case "playing":
+		activity.Type = discordgo.ActivityTypeGame
+	case "watching":
+		activity.Type = discordgo.ActivityTypeWatching
+	case "listening":
+		activity.Type = discordgo.ActivityTypeListening
+	case "competing":
+		activity.Type = discordgo.ActivityTypeCompeting
+	default:
+		// Custom status: Discord shows State; Name is required but ignored
+		activity.Type = discordgo.ActivityTypeCustom
+		activity.Name = "Custom Status"
+		activity.State = text
+	}
+	return discordgo.UpdateStatusData{
+		Status:     status,
+		Activities: []*discordgo.Activity{activity},
+	}
+}
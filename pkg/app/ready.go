@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// ReadySummary describes a completed startup: READY received, services started and
+// slash commands synced. Warnings lists the non-fatal startup steps that failed.
+type ReadySummary struct {
+	AppName        string
+	GuildCount     int
+	Services       []string
+	CommandsSynced int
+	Warnings       []string
+	StartupTime    time.Duration
+}
+
+// OnReady registers fn to be called once, after every startup step in Run succeeded.
+// Callbacks run in registration order on the Run goroutine before it waits for the
+// interrupt signal; long work should be started in its own goroutine.
+// Registering after startup completed calls fn immediately with the same summary.
+func (b *Bootstrap) OnReady(fn func(ReadySummary)) {
+	if fn == nil {
+		return
+	}
+	b.readyMu.Lock()
+	if b.readySummary == nil {
+		b.readyHooks = append(b.readyHooks, fn)
+		b.readyMu.Unlock()
+		return
+	}
+	summary := *b.readySummary
+	b.readyMu.Unlock()
+	fn(summary)
+}
+
+// Ready returns a channel closed once startup completed (after OnReady callbacks ran).
+// Integration tests can wait on it instead of sleeping.
+func (b *Bootstrap) Ready() <-chan struct{} {
+	return b.readyCh
+}
+
+// warnf logs a non-fatal startup problem and records it for the ReadySummary.
+func (b *Bootstrap) warnf(format string, args ...any) {
+	log.Warn().Applicationf(format, args...)
+	b.recordWarning(fmt.Sprintf(format, args...))
+}
+
+// recordWarning adds a non-fatal startup problem to the ReadySummary without logging it.
+func (b *Bootstrap) recordWarning(msg string) {
+	b.readyMu.Lock()
+	b.warnings = append(b.warnings, msg)
+	b.readyMu.Unlock()
+}
+
+// fireReady builds the summary and runs the OnReady callbacks exactly once.
+func (b *Bootstrap) fireReady() {
+	summary := ReadySummary{
+		AppName:     b.AppName,
+		StartupTime: time.Since(b.started).Round(time.Millisecond),
+	}
+	if b.Session != nil && b.Session.State != nil {
+		b.Session.State.RLock()
+		summary.GuildCount = len(b.Session.State.Guilds)
+		b.Session.State.RUnlock()
+	}
+	if b.Services != nil {
+		summary.Services = b.Services.GetRunningServices()
+		sort.Strings(summary.Services)
+	}
+	if b.commandHandler != nil {
+		if cm := b.commandHandler.GetCommandManager(); cm != nil && cm.GetRouter() != nil {
+			summary.CommandsSynced = cm.GetRouter().CommandCount()
+		}
+	}
+
+	b.readyMu.Lock()
+	if b.readySummary != nil {
+		b.readyMu.Unlock()
+		return
+	}
+	summary.Warnings = append([]string(nil), b.warnings...)
+	b.readySummary = &summary
+	hooks := b.readyHooks
+	b.readyHooks = nil
+	b.readyMu.Unlock()
+
+	log.Info().Applicationf("✅ Ready: guilds=%d, services=%d, commands=%d, warnings=%d", summary.GuildCount, len(summary.Services), summary.CommandsSynced, len(summary.Warnings))
+	for _, fn := range hooks {
+		fn(summary)
+	}
+	close(b.readyCh)
+}
@@ -0,0 +1,241 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// selfTestTimeout bounds the whole self-test (store probes and Discord calls).
+const selfTestTimeout = 30 * time.Second
+
+// logChannelPermissions are what every log channel needs for notifications to be delivered.
+const logChannelPermissions = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionEmbedLinks
+
+// SelfTestCheck is the outcome of one self-test check. Critical checks cover things the bot
+// cannot work without (store, command registration); the rest are misconfigurations.
+type SelfTestCheck struct {
+	Name     string
+	Passed   bool
+	Critical bool
+	Detail   string
+}
+
+// SelfTestReport lists every check run by SelfTest.
+type SelfTestReport struct {
+	Checks   []SelfTestCheck
+	Duration time.Duration
+}
+
+// Failed returns the checks that did not pass.
+func (r SelfTestReport) Failed() []SelfTestCheck {
+	var out []SelfTestCheck
+	for _, c := range r.Checks {
+		if !c.Passed {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// CriticalFailed reports whether any critical check failed.
+func (r SelfTestReport) CriticalFailed() bool {
+	for _, c := range r.Checks {
+		if !c.Passed && c.Critical {
+			return true
+		}
+	}
+	return false
+}
+
+// SelfTest verifies the bot can do its job: the store accepts a write and reads it back,
+// slash commands are registered with Discord, and the bot can post in every configured log
+// channel (permission check, or a real test message with self_test.send_messages).
+// Run calls it after syncing commands; the command check is skipped when called earlier.
+func (b *Bootstrap) SelfTest() SelfTestReport {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+
+	var report SelfTestReport
+	report.Checks = append(report.Checks, b.selfTestStore(ctx))
+	if b.commandHandler != nil {
+		report.Checks = append(report.Checks, b.selfTestCommands())
+	}
+	report.Checks = append(report.Checks, b.selfTestLogChannels()...)
+	report.Duration = time.Since(started).Round(time.Millisecond)
+	return report
+}
+
+func (b *Bootstrap) selfTestStore(ctx context.Context) SelfTestCheck {
+	check := SelfTestCheck{Name: "store", Critical: true}
+	if b.Store == nil {
+		check.Detail = "store not initialized"
+		return check
+	}
+	if err := b.Store.Ping(ctx); err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	if err := b.Store.ProbeWrite(ctx); err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	check.Passed = true
+	check.Detail = "write/read-back ok"
+	return check
+}
+
+func (b *Bootstrap) selfTestCommands() SelfTestCheck {
+	check := SelfTestCheck{Name: "commands", Critical: true}
+	cm := b.commandHandler.GetCommandManager()
+	if cm == nil || cm.GetRouter() == nil {
+		check.Detail = "command router not initialized"
+		return check
+	}
+	// Compare per scope: commands with disabled_commands are registered per guild
+	want, missing, err := cm.MissingCommands()
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	if len(missing) > 0 {
+		check.Detail = fmt.Sprintf("%d of %d command registrations missing on Discord: %s", len(missing), want, strings.Join(missing, ", "))
+		return check
+	}
+	check.Passed = true
+	check.Detail = fmt.Sprintf("%d command registrations ok", want)
+	return check
+}
+
+// selfTestLogChannels checks every configured log channel, per guild.
+func (b *Bootstrap) selfTestLogChannels() []SelfTestCheck {
+	if b.Config == nil || b.Session == nil || b.Session.State == nil || b.Session.State.User == nil {
+		return nil
+	}
+	send := b.Config.SelfTest().SendMessages
+	botID := b.Session.State.User.ID
+	var checks []SelfTestCheck
+	for _, gcfg := range b.Config.Guilds() {
+		channels := []struct{ label, id string }{
+			{"user_log", gcfg.UserLogChannelID},
+			{"user_entry_leave", gcfg.UserEntryLeaveChannelID},
+			{"message_log", gcfg.MessageLogChannelID},
+			{"automod_log", gcfg.AutomodLogChannelID},
+		}
+		seen := make(map[string]bool)
+		for _, ch := range channels {
+			if ch.id == "" || seen[ch.id] {
+				continue
+			}
+			seen[ch.id] = true
+			check := SelfTestCheck{Name: fmt.Sprintf("guild %s %s channel", gcfg.GuildID, ch.label)}
+			if err := b.checkLogChannel(botID, ch.id, send); err != nil {
+				check.Detail = err.Error()
+			} else {
+				check.Passed = true
+			}
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+func (b *Bootstrap) checkLogChannel(botID, channelID string, send bool) error {
+	if send {
+		msg, err := b.Session.ChannelMessageSend(channelID, "🧪 Startup self-test (this message is deleted automatically)")
+		if err != nil {
+			return fmt.Errorf("send test message to <#%s>: %w", channelID, err)
+		}
+		_ = b.Session.ChannelMessageDelete(channelID, msg.ID)
+		return nil
+	}
+	perms, err := b.Session.State.UserChannelPermissions(botID, channelID)
+	if err != nil {
+		// Not in the state (e.g. an unloaded thread); ask the API
+		perms, err = b.Session.UserChannelPermissions(botID, channelID)
+	}
+	if err != nil {
+		return fmt.Errorf("resolve permissions in <#%s>: %w", channelID, err)
+	}
+	if missing := logChannelPermissions &^ perms; missing != 0 && perms&discordgo.PermissionAdministrator == 0 {
+		return fmt.Errorf("missing %s in <#%s>", describePermissions(missing), channelID)
+	}
+	return nil
+}
+
+func describePermissions(p int64) string {
+	var names []string
+	if p&discordgo.PermissionViewChannel != 0 {
+		names = append(names, "View Channel")
+	}
+	if p&discordgo.PermissionSendMessages != 0 {
+		names = append(names, "Send Messages")
+	}
+	if p&discordgo.PermissionEmbedLinks != 0 {
+		names = append(names, "Embed Links")
+	}
+	return strings.Join(names, ", ")
+}
+
+// runSelfTest runs the configured self-test, logs the report and returns an error if a
+// critical check failed and self_test.abort_on_failure is set.
+func (b *Bootstrap) runSelfTest() error {
+	cfg := b.Config.SelfTest()
+	if cfg.Disabled {
+		log.Info().Applicationf("Startup self-test disabled")
+		return nil
+	}
+	report := b.SelfTest()
+	failed := report.Failed()
+	log.Info().Applicationf("🧪 Self-test: %d/%d checks passed in %s", len(report.Checks)-len(failed), len(report.Checks), report.Duration)
+	for _, c := range failed {
+		b.warnf("Self-test check %q failed: %s", c.Name, c.Detail)
+	}
+	if cfg.ReportChannelID != "" {
+		if _, err := discord.ClientFor(b.Session).SendEmbed(context.Background(), cfg.ReportChannelID, selfTestEmbed(b.AppName, report)); err != nil {
+			b.warnf("Failed to post self-test report to channel %s: %v", cfg.ReportChannelID, err)
+		}
+	}
+	if cfg.AbortOnFailure && report.CriticalFailed() {
+		return fmt.Errorf("critical self-test check failed")
+	}
+	return nil
+}
+
+func selfTestEmbed(appName string, report SelfTestReport) *discordgo.MessageEmbed {
+	var lines []string
+	for _, c := range report.Checks {
+		mark := "✅"
+		if !c.Passed {
+			mark = "❌"
+		}
+		line := fmt.Sprintf("%s **%s**", mark, c.Name)
+		if c.Detail != "" {
+			line += " — " + c.Detail
+		}
+		lines = append(lines, line)
+	}
+	color := 0x00FF00
+	if report.CriticalFailed() {
+		color = 0xFF0000
+	} else if len(report.Failed()) > 0 {
+		color = 0xFFA500
+	}
+	description := strings.Join(lines, "\n")
+	if len(description) > 4000 {
+		description = description[:4000] + "…"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🧪 %s startup self-test", appName),
+		Description: description,
+		Color:       color,
+		Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%d/%d passed in %s", len(report.Checks)-len(report.Failed()), len(report.Checks), report.Duration)},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/admin"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/config"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core/fakesession"
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/service"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+	"github.com/small-frappuccino/discordcore/pkg/util"
+)
+
+// ValidationProblem is one problem found by Validate. Scope says where it was found:
+// "config", "guild <id>" or "command /<name>".
+type ValidationProblem struct {
+	Scope   string
+	Message string
+}
+
+func (p ValidationProblem) String() string {
+	return p.Scope + ": " + p.Message
+}
+
+// ValidationReport lists everything Validate checked and every problem it found.
+type ValidationReport struct {
+	ConfigPath string
+	Guilds     int
+	Commands   int
+	Problems   []ValidationProblem
+}
+
+// OK reports whether no problem was found.
+func (r ValidationReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Write prints the report in a human-readable form, one problem per line.
+func (r ValidationReport) Write(w io.Writer) {
+	fmt.Fprintf(w, "Config: %s (%d guilds, %d commands)\n", r.ConfigPath, r.Guilds, r.Commands)
+	for _, p := range r.Problems {
+		fmt.Fprintf(w, "  ✗ %s\n", p)
+	}
+	if r.OK() {
+		fmt.Fprintln(w, "  ✓ no problems found")
+		return
+	}
+	fmt.Fprintf(w, "%d problem(s) found\n", len(r.Problems))
+}
+
+func (r *ValidationReport) addf(scope, format string, args ...any) {
+	r.Problems = append(r.Problems, ValidationProblem{Scope: scope, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate is a dry run of the startup configuration: it loads configPath, validates every
+// guild (including automod domain patterns) and the root settings, and builds the slash
+// command definitions against a fake session, checking them against Discord's limits and
+// against each guild's disabled_commands. Every problem is reported, not only the first.
+//
+// Nothing is persisted and Discord is never contacted: the config file is only read and the
+// store-backed commands are built on an in-memory database. Extensions are not run; downstream
+// bots pass the same registrars they give to Bootstrap.RegisterCommands to have their commands
+// checked too.
+func Validate(configPath string, registrars ...func(router *core.CommandRouter)) ValidationReport {
+	report := ValidationReport{ConfigPath: configPath}
+
+	if _, err := os.Stat(configPath); err != nil {
+		report.addf("config", "cannot read config file: %v", err)
+		return report
+	}
+	cfgMgr := files.NewConfigManagerWithPath(configPath)
+	if err := cfgMgr.LoadConfig(); err != nil {
+		report.addf("config", "%v", err)
+		return report
+	}
+
+	report.validateRootSettings(cfgMgr)
+	report.validateGuilds(cfgMgr.Config().Guilds)
+	report.validateCommands(cfgMgr, registrars)
+	return report
+}
+
+func (r *ValidationReport) validateRootSettings(cfgMgr *files.ConfigManager) {
+	if _, err := cfgMgr.ShutdownTimeout(); err != nil {
+		r.addf("config", "%v", err)
+	}
+	if _, err := cfgMgr.GuildAvailabilityTimeout(); err != nil {
+		r.addf("config", "%v", err)
+	}
+	if _, err := cfgMgr.BotOperators(); err != nil {
+		r.addf("config", "%v", err)
+	}
+}
+
+func (r *ValidationReport) validateGuilds(guilds []files.GuildConfig) {
+	r.Guilds = len(guilds)
+	seen := make(map[string]bool, len(guilds))
+	for i := range guilds {
+		gcfg := &guilds[i]
+		scope := "guild " + gcfg.GuildID
+		if gcfg.GuildID == "" {
+			scope = fmt.Sprintf("guilds[%d]", i)
+		}
+		if seen[gcfg.GuildID] && gcfg.GuildID != "" {
+			r.addf(scope, "duplicate guild entry")
+		}
+		seen[gcfg.GuildID] = true
+		if err := gcfg.Validate(); err != nil {
+			r.addf(scope, "%v", err)
+		}
+		for _, err := range gcfg.AutomodPatternErrors() {
+			r.addf(scope, "%v", err)
+		}
+	}
+}
+
+// validateCommands registers the built-in commands (and registrars) on a router backed by a
+// fake session, then checks every definition and every disabled_commands entry.
+func (r *ValidationReport) validateCommands(cfgMgr *files.ConfigManager, registrars []func(router *core.CommandRouter)) {
+	store := storage.NewStore(util.MemoryDBPath)
+	if err := store.Init(); err != nil {
+		r.addf("commands", "in-memory store: %v", err)
+		return
+	}
+	defer store.Close()
+
+	router := core.NewCommandRouterWithAPI(fakesession.New(), cfgMgr)
+	register := func(name string, fn func(router *core.CommandRouter)) {
+		defer func() {
+			if p := recover(); p != nil {
+				r.addf("commands", "%s registrar panicked: %v", name, p)
+			}
+		}()
+		fn(router)
+	}
+	register("config", config.NewConfigCommands(cfgMgr).RegisterCommands)
+	adminCommands := admin.NewAdminCommands(service.NewServiceManager(errors.NewErrorHandler()))
+	adminCommands.SetStore(store)
+	register("admin", adminCommands.RegisterCommands)
+	for i, fn := range registrars {
+		register(fmt.Sprintf("registrar #%d", i+1), fn)
+	}
+
+	defs := router.CommandDefinitions(util.CurrentEnvironment())
+	r.Commands = len(defs)
+	var paths []string
+	for _, def := range defs {
+		for _, err := range core.ValidateCommandDefinition(def) {
+			r.addf("command /"+def.Name, "%v", err)
+		}
+		paths = append(paths, core.CommandPaths(def)...)
+	}
+
+	for _, gcfg := range cfgMgr.Config().Guilds {
+		for _, path := range gcfg.DisabledCommands {
+			if !slices.Contains(paths, files.NormalizeCommandPath(path)) {
+				r.addf("guild "+gcfg.GuildID, "disabled_commands: unknown command %q", path)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package discord
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Ações de BulkRole.
+const (
+	BulkRoleAdd    = "add"
+	BulkRoleRemove = "remove"
+)
+
+// bulkRolePageSize é o tamanho de página da listagem de membros (máximo do Discord).
+const bulkRolePageSize = 1000
+
+// Motivos pelos quais o bot não consegue atribuir um cargo (ver RoleAssignable).
+var (
+	ErrMissingManageRoles = stderrors.New("the bot lacks the MANAGE_ROLES permission")
+	ErrRoleNotFound       = stderrors.New("role not found")
+	ErrRoleManaged        = stderrors.New("role is managed by an integration")
+	ErrRoleAboveBot       = stderrors.New("role is not below the bot's highest role")
+)
+
+// ListMembers returns one page of guild members with IDs greater than after (see
+// DiscordSession.ListMembers). Iterate by passing the last member's ID as after until a page
+// comes back shorter than limit.
+func (c *Client) ListMembers(ctx context.Context, guildID, after string, limit int) ([]*discordgo.Member, error) {
+	members, err := c.ds.ListMembers(ctx, guildID, after, limit)
+	return members, classify("ListMembers", err)
+}
+
+// RoleAssignable checks against the session state whether the bot can add or remove roleID in
+// the guild: it needs MANAGE_ROLES (or administrator) and the role must sit below the bot's
+// highest role and not be managed by an integration. Without the guild or the bot member in
+// the state it returns nil and leaves the decision to the API.
+func (c *Client) RoleAssignable(guildID, roleID string) error {
+	raw := c.ds.Raw()
+	if raw == nil || raw.State == nil || raw.State.User == nil {
+		return nil
+	}
+	state := raw.State
+	guild, err := state.Guild(guildID)
+	if err != nil {
+		return nil
+	}
+	self, err := state.Member(guildID, state.User.ID)
+	if err != nil {
+		return nil
+	}
+
+	var target *discordgo.Role
+	var perms int64
+	top := 0
+	for _, r := range guild.Roles {
+		if r.ID == roleID {
+			target = r
+		}
+		if r.ID == guildID || slices.Contains(self.Roles, r.ID) {
+			perms |= r.Permissions
+			if r.ID != guildID {
+				top = max(top, r.Position)
+			}
+		}
+	}
+	owner := guild.OwnerID == state.User.ID
+	switch {
+	case target == nil || roleID == guildID:
+		return fmt.Errorf("%w: %s", ErrRoleNotFound, roleID)
+	case target.Managed:
+		return fmt.Errorf("%w: %s (%s)", ErrRoleManaged, target.Name, roleID)
+	case !owner && perms&(discordgo.PermissionAdministrator|discordgo.PermissionManageRoles) == 0:
+		return ErrMissingManageRoles
+	case !owner && target.Position >= top:
+		return fmt.Errorf("%w: %s (%s)", ErrRoleAboveBot, target.Name, roleID)
+	}
+	return nil
+}
+
+// BulkRoleRequest describes a role change applied to every member matching Filter.
+type BulkRoleRequest struct {
+	GuildID string
+	RoleID  string
+	Action  string                       // BulkRoleAdd or BulkRoleRemove
+	Filter  func(*discordgo.Member) bool // nil matches every member
+	After   string                       // resume after this user ID (members are walked in ID order)
+	Reason  string                       // shown in the guild's audit log for each change
+	// Progress, if set, is called after each member with the running totals.
+	Progress func(BulkRoleResult)
+}
+
+// BulkRoleResult are the running totals of a bulk role operation.
+type BulkRoleResult struct {
+	Scanned    int
+	Changed    int
+	Skipped    int // didn't match the filter or already in the requested state
+	Failed     int
+	LastUserID string // last member processed; pass it as BulkRoleRequest.After to resume
+}
+
+// BulkRole walks the guild's members page by page and adds or removes the role where needed.
+// Every change goes through the shared pacer. Cancelling ctx stops the walk and returns the
+// totals so far with ctx's error; LastUserID then resumes exactly after the last member handled.
+// Losing the permission midway (HTTP 403) also stops the walk.
+func (c *Client) BulkRole(ctx context.Context, req BulkRoleRequest) (BulkRoleResult, error) {
+	res := BulkRoleResult{LastUserID: req.After}
+	if req.Action != BulkRoleAdd && req.Action != BulkRoleRemove {
+		return res, fmt.Errorf("unknown bulk role action %q", req.Action)
+	}
+	if err := c.RoleAssignable(req.GuildID, req.RoleID); err != nil {
+		return res, err
+	}
+	var opts []discordgo.RequestOption
+	if req.Reason != "" {
+		opts = append(opts, discordgo.WithAuditLogReason(req.Reason))
+	}
+
+	after := req.After
+	for {
+		members, err := c.ListMembers(ctx, req.GuildID, after, bulkRolePageSize)
+		if err != nil {
+			return res, err
+		}
+		for _, m := range members {
+			if m == nil || m.User == nil {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return res, err
+			}
+			has := slices.Contains(m.Roles, req.RoleID)
+			switch {
+			case req.Filter != nil && !req.Filter(m),
+				req.Action == BulkRoleAdd && has,
+				req.Action == BulkRoleRemove && !has:
+				res.Skipped++
+			default:
+				var err error
+				if req.Action == BulkRoleAdd {
+					err = classify("AddRole", c.ds.AddRole(ctx, req.GuildID, m.User.ID, req.RoleID, opts...))
+				} else {
+					err = classify("RemoveRole", c.ds.RemoveRole(ctx, req.GuildID, m.User.ID, req.RoleID, opts...))
+				}
+				if err != nil {
+					if ctx.Err() != nil {
+						return res, ctx.Err()
+					}
+					if HTTPStatus(err) == http.StatusForbidden {
+						return res, err
+					}
+					res.Failed++
+				} else {
+					res.Changed++
+				}
+			}
+			res.Scanned++
+			res.LastUserID = m.User.ID
+			if req.Progress != nil {
+				req.Progress(res)
+			}
+		}
+		if len(members) < bulkRolePageSize {
+			return res, nil
+		}
+		after = members[len(members)-1].User.ID
+	}
+}
@@ -0,0 +1,241 @@
+// Package discord exposes Client, a typed facade over the Discord session for the
+// operations services, commands and extensions use most.
+package discord
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/session"
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+)
+
+// clientComponent is the Component reported in classified errors.
+const clientComponent = "discord.Client"
+
+// Client wraps a session with paced, error-classified helpers. Every REST call waits on the
+// shared pacer (see session.Pacer) and failures are returned as *errors.ServiceError with a
+// category (discord, network or validation), so callers can branch on
+// errors.As + Category instead of parsing discordgo errors themselves.
+type Client struct {
+	ds *session.DiscordSession
+}
+
+// NewClient builds a Client with its own pacer. Prefer ClientFor to share the pacer
+// with every other user of the same session.
+func NewClient(s *discordgo.Session) *Client {
+	return &Client{ds: session.Wrap(s)}
+}
+
+// NewClientWithPacer builds a Client sharing the provided pacer.
+func NewClientWithPacer(s *discordgo.Session, pacer *session.Pacer) *Client {
+	return &Client{ds: session.WrapWithPacer(s, pacer)}
+}
+
+var clients sync.Map // *discordgo.Session -> *Client
+
+// ClientFor returns the Client shared by everyone using s (created on first use), so
+// services and commands pace their calls against the same budget. The entry is dropped
+// when s is closed with session.Close.
+func ClientFor(s *discordgo.Session) *Client {
+	if c, ok := clients.Load(s); ok {
+		return c.(*Client)
+	}
+	c, loaded := clients.LoadOrStore(s, NewClient(s))
+	if !loaded {
+		session.OnClose(s, func() { clients.Delete(s) })
+	}
+	return c.(*Client)
+}
+
+// Session returns the underlying paced session wrapper (see DiscordSession.Raw for the escape hatch).
+func (c *Client) Session() *session.DiscordSession {
+	return c.ds
+}
+
+// SendMessage sends a plain text message to a channel.
+func (c *Client) SendMessage(ctx context.Context, channelID, content string) (*discordgo.Message, error) {
+	msg, err := c.ds.SendMessage(ctx, channelID, content)
+	return msg, classify("SendMessage", err)
+}
+
+// SendEmbed sends an embed to a channel.
+func (c *Client) SendEmbed(ctx context.Context, channelID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	msg, err := c.ds.SendEmbed(ctx, channelID, embed)
+	return msg, classify("SendEmbed", err)
+}
+
+// SendComplex sends a fully specified message to a channel.
+func (c *Client) SendComplex(ctx context.Context, channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	msg, err := c.ds.SendComplex(ctx, channelID, data)
+	return msg, classify("SendComplex", err)
+}
+
+// AddRole adds a role to a guild member.
+func (c *Client) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	return classify("AddRole", c.ds.AddRole(ctx, guildID, userID, roleID))
+}
+
+// RemoveRole removes a role from a guild member.
+func (c *Client) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	return classify("RemoveRole", c.ds.RemoveRole(ctx, guildID, userID, roleID))
+}
+
+// Timeout times a member out for d.
+func (c *Client) Timeout(ctx context.Context, guildID, userID string, d time.Duration) error {
+	if d <= 0 {
+		return &errors.ServiceError{
+			Category:  errors.CategoryValidation,
+			Severity:  errors.SeverityLow,
+			Message:   "invalid timeout duration: must be positive",
+			Operation: "Timeout",
+			Component: clientComponent,
+			Timestamp: time.Now(),
+			Actions:   []errors.ErrorAction{errors.ActionLog},
+		}
+	}
+	return classify("Timeout", c.ds.TimeoutMember(ctx, guildID, userID, d))
+}
+
+// RemoveTimeout clears a member's timeout.
+func (c *Client) RemoveTimeout(ctx context.Context, guildID, userID string) error {
+	return classify("RemoveTimeout", c.ds.TimeoutMember(ctx, guildID, userID, 0))
+}
+
+// SendDM sends a direct message to a user. Users with closed DMs fail with Discord code
+// discordgo.ErrCodeCannotSendMessagesToThisUser.
+func (c *Client) SendDM(ctx context.Context, userID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	msg, err := c.ds.SendDM(ctx, userID, data)
+	return msg, classify("SendDM", err)
+}
+
+// EditMessage replaces the content of a message sent by the bot.
+func (c *Client) EditMessage(ctx context.Context, channelID, messageID, content string) (*discordgo.Message, error) {
+	msg, err := c.ds.EditMessage(ctx, channelID, messageID, content)
+	return msg, classify("EditMessage", err)
+}
+
+// DeleteMessage deletes a message.
+func (c *Client) DeleteMessage(ctx context.Context, channelID, messageID string) error {
+	return classify("DeleteMessage", c.ds.DeleteMessage(ctx, channelID, messageID))
+}
+
+// maxBulkDelete is the most messages Discord accepts in one bulk delete request.
+const maxBulkDelete = 100
+
+// DeleteMessages deletes messages of a channel with bulk requests of up to 100 messages (a
+// single leftover message is deleted on its own). It stops at the first failing request.
+func (c *Client) DeleteMessages(ctx context.Context, channelID string, messageIDs []string) error {
+	for batch := range slices.Chunk(messageIDs, maxBulkDelete) {
+		if len(batch) == 1 {
+			if err := c.DeleteMessage(ctx, channelID, batch[0]); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.ds.BulkDeleteMessages(ctx, channelID, batch); err != nil {
+			return classify("DeleteMessages", err)
+		}
+	}
+	return nil
+}
+
+// EditChannel edits a channel or thread (e.g. to unarchive a thread before posting).
+func (c *Client) EditChannel(ctx context.Context, channelID string, data *discordgo.ChannelEdit) (*discordgo.Channel, error) {
+	ch, err := c.ds.EditChannel(ctx, channelID, data)
+	return ch, classify("EditChannel", err)
+}
+
+// FetchMember returns a guild member from the session state, falling back to the REST API.
+func (c *Client) FetchMember(ctx context.Context, guildID, userID string) (*discordgo.Member, error) {
+	if raw := c.ds.Raw(); raw != nil && raw.State != nil {
+		if m, err := raw.State.Member(guildID, userID); err == nil && m != nil {
+			return m, nil
+		}
+	}
+	m, err := c.ds.GetMember(ctx, guildID, userID)
+	return m, classify("FetchMember", err)
+}
+
+// classify wraps err into a *errors.ServiceError categorized by its cause.
+func classify(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	se := &errors.ServiceError{
+		Category:    errors.CategoryDiscord,
+		Severity:    errors.SeverityMedium,
+		Message:     "Discord API operation failed",
+		Operation:   operation,
+		Component:   clientComponent,
+		Cause:       err,
+		Timestamp:   time.Now(),
+		Recoverable: true,
+		Context:     make(map[string]interface{}),
+	}
+
+	var restErr *discordgo.RESTError
+	var netErr net.Error
+	switch {
+	case stderrors.As(err, &restErr):
+		status := 0
+		if restErr.Response != nil {
+			status = restErr.Response.StatusCode
+			se.Context["http_status"] = status
+		}
+		if restErr.Message != nil {
+			se.Context["discord_code"] = restErr.Message.Code
+			se.Context["discord_message"] = restErr.Message.Message
+		}
+		switch {
+		case status == http.StatusTooManyRequests:
+			se.Actions = []errors.ErrorAction{errors.ActionLog, errors.ActionRetry}
+		case status == http.StatusBadRequest:
+			se.Category = errors.CategoryValidation
+			se.Recoverable = false
+			se.Actions = []errors.ErrorAction{errors.ActionLog}
+		case status == http.StatusForbidden || status == http.StatusNotFound || status == http.StatusUnauthorized:
+			// Permissão ausente ou alvo inexistente: repetir não ajuda
+			se.Recoverable = false
+			se.Actions = []errors.ErrorAction{errors.ActionLog}
+		case status >= 500:
+			se.Actions = []errors.ErrorAction{errors.ActionLog, errors.ActionRetry}
+		default:
+			se.Actions = []errors.ErrorAction{errors.ActionLog}
+		}
+	case stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded):
+		se.Category = errors.CategoryNetwork
+		se.Severity = errors.SeverityLow
+		se.Message = "Discord API operation cancelled"
+		se.Recoverable = false
+		se.Actions = []errors.ErrorAction{errors.ActionLog}
+	case stderrors.As(err, &netErr):
+		se.Category = errors.CategoryNetwork
+		se.Message = "Discord API unreachable"
+		se.Actions = []errors.ErrorAction{errors.ActionLog, errors.ActionRetry}
+	default:
+		se.Actions = []errors.ErrorAction{errors.ActionLog}
+	}
+	return se
+}
+
+// HTTPStatus returns the HTTP status of a failed Client call (0 when not a REST error).
+func HTTPStatus(err error) int {
+	var se *errors.ServiceError
+	if stderrors.As(err, &se) {
+		if v, ok := se.Context["http_status"].(int); ok {
+			return v
+		}
+	}
+	var restErr *discordgo.RESTError
+	if stderrors.As(err, &restErr) && restErr.Response != nil {
+		return restErr.Response.StatusCode
+	}
+	return 0
+}
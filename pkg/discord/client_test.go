@@ -0,0 +1,110 @@
+package discord
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/session"
+)
+
+func TestClientForIsSharedAndDroppedOnClose(t *testing.T) {
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	c := ClientFor(s)
+	if ClientFor(s) != c {
+		t.Fatal("ClientFor returned a different client for the same session")
+	}
+	if _, ok := clients.Load(s); !ok {
+		t.Fatal("client not registered")
+	}
+
+	if err := session.Close(s); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, ok := clients.Load(s); ok {
+		t.Fatal("client of a closed session is still registered")
+	}
+}
+
+func TestDeleteMessagesEmpty(t *testing.T) {
+	if err := NewClient(nil).DeleteMessages(t.Context(), "c1", nil); err != nil {
+		t.Fatalf("DeleteMessages(nil) = %v, want nil", err)
+	}
+}
+
+// recordingTransport answers every request with 204 and records method, path and bulk size.
+type recordingTransport struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	call := r.Method + " " + r.URL.Path
+	if r.Body != nil {
+		var body struct {
+			Messages []string `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Messages) > 0 {
+			call += " " + strconv.Itoa(len(body.Messages))
+		}
+	}
+	rt.mu.Lock()
+	rt.calls = append(rt.calls, call)
+	rt.mu.Unlock()
+	return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}, Request: r}, nil
+}
+
+func TestDeleteMessagesChunksBulkRequests(t *testing.T) {
+	ids := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = strconv.Itoa(1000 + i)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{"single", 1, []string{"DELETE /api/v9/channels/c1/messages/1000"}},
+		{"one batch", 100, []string{"POST /api/v9/channels/c1/messages/bulk-delete 100"}},
+		{"batches", 250, []string{
+			"POST /api/v9/channels/c1/messages/bulk-delete 100",
+			"POST /api/v9/channels/c1/messages/bulk-delete 100",
+			"POST /api/v9/channels/c1/messages/bulk-delete 50",
+		}},
+		{"single leftover", 201, []string{
+			"POST /api/v9/channels/c1/messages/bulk-delete 100",
+			"POST /api/v9/channels/c1/messages/bulk-delete 100",
+			"DELETE /api/v9/channels/c1/messages/1200",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := discordgo.New("Bot test")
+			if err != nil {
+				t.Fatalf("new session: %v", err)
+			}
+			rt := &recordingTransport{}
+			s.Client = &http.Client{Transport: rt}
+			c := NewClientWithPacer(s, session.NewPacer(time.Millisecond))
+			if err := c.DeleteMessages(t.Context(), "c1", ids(tc.n)); err != nil {
+				t.Fatalf("DeleteMessages: %v", err)
+			}
+			if !slices.Equal(rt.calls, tc.want) {
+				t.Fatalf("requests = %q, want %q", rt.calls, tc.want)
+			}
+		})
+	}
+}
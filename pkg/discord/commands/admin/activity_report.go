@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/logging"
+)
+
+// ActivityReportCommand generates the server activity report on demand, either as the reply
+// or posted to the configured activity_report channel.
+type ActivityReportCommand struct {
+	adminCommands *AdminCommands
+}
+
+// createActivityReportCommand creates the activity report subcommand
+func (ac *AdminCommands) createActivityReportCommand() core.SubCommand {
+	return &ActivityReportCommand{
+		adminCommands: ac,
+	}
+}
+
+func (cmd *ActivityReportCommand) Name() string {
+	return "activity-report"
+}
+
+func (cmd *ActivityReportCommand) Description() string {
+	return "Generate the server activity report now"
+}
+
+func (cmd *ActivityReportCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "since",
+			Description: "Start date (YYYY-MM-DD) or duration back from now (default: the report period)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "post",
+			Description: "Post to the configured report channel instead of replying here",
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *ActivityReportCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *ActivityReportCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *ActivityReportCommand) Handle(ctx *core.Context) error {
+	store := cmd.adminCommands.store
+	if store == nil {
+		return core.NewCommandError("Message store is not available", true)
+	}
+	if ctx.GuildConfig == nil {
+		return core.NewCommandError("This server is not configured", true)
+	}
+	gcfg := *ctx.GuildConfig
+
+	extractor := core.NewOptionExtractor(core.GetSubCommandOptions(ctx.Interaction))
+	now := time.Now()
+	since := now.Add(-gcfg.ActivityReport.PeriodDuration())
+	if raw := extractor.String("since"); strings.TrimSpace(raw) != "" {
+		parsed, err := parseSince(raw, now)
+		if err != nil {
+			return core.NewCommandError(err.Error(), true)
+		}
+		since = parsed
+	}
+
+	if extractor.Bool("post") {
+		if cmd.adminCommands.activityReports == nil {
+			return core.NewCommandError("Activity reports are not available", true)
+		}
+		if gcfg.ActivityReport == nil || gcfg.ActivityReport.ChannelID == "" {
+			return core.NewCommandError("Set `activity_report.channel_id` to post reports", true)
+		}
+		if err := cmd.adminCommands.activityReports.Post(gcfg, since, now); err != nil {
+			return fmt.Errorf("post activity report: %w", err)
+		}
+		return core.NewResponseManager(ctx.API).Ephemeral(ctx.Interaction, fmt.Sprintf("Activity report posted to <#%s>.", gcfg.ActivityReport.ChannelID))
+	}
+
+	embed, err := logging.BuildActivityReport(store, gcfg, since, now)
+	if err != nil {
+		return fmt.Errorf("build activity report: %w", err)
+	}
+	return ctx.Respond().Embed(ctx.Interaction, embed)
+}
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// automodTriggerWindow agrupa as ações nativas (block/alert/timeout) disparadas pela mesma violação.
+const automodTriggerWindow = 30 * time.Second
+
+// AutomodFeedbackHandler handles the "Mark false positive" button on automod notifications:
+// it records the verdict for every action of the trigger and reverses what can be reversed.
+type AutomodFeedbackHandler struct {
+	store *storage.Store
+}
+
+// NewAutomodFeedbackHandler creates the component handler backed by the given store.
+func NewAutomodFeedbackHandler(store *storage.Store) *AutomodFeedbackHandler {
+	return &AutomodFeedbackHandler{store: store}
+}
+
+func (h *AutomodFeedbackHandler) RequiresPermissions() bool {
+	return true
+}
+
+func (h *AutomodFeedbackHandler) HandleComponent(ctx *core.Context) error {
+	i := ctx.Interaction
+	guildID, actionID, ok := logging.ParseAutomodFeedbackID(i.MessageComponentData().CustomID)
+	if !ok || guildID != ctx.GuildID {
+		return core.NewCommandError("Invalid automod feedback button", true)
+	}
+
+	action, err := h.store.GetAutomodAction(guildID, actionID)
+	if err != nil {
+		return fmt.Errorf("load automod action: %w", err)
+	}
+	if action == nil {
+		return core.NewCommandError("This automod action is no longer stored", true)
+	}
+
+	related, err := h.relatedActions(ctx.Ctx, *action)
+	if err != nil {
+		return fmt.Errorf("load related automod actions: %w", err)
+	}
+
+	recorded := 0
+	for _, a := range related {
+		ok, err := h.store.RecordAutomodFeedback(a, ctx.UserID, storage.VerdictFalsePositive)
+		if err != nil {
+			return fmt.Errorf("record automod feedback: %w", err)
+		}
+		if ok {
+			recorded++
+		}
+	}
+	if recorded == 0 {
+		return core.NewCommandError("This action was already reviewed", true)
+	}
+
+	reversed := h.reverse(ctx.Ctx, ctx.Session, related)
+	log.Info().Applicationf("AutoMod false positive marked: guildID=%s, ruleID=%s, userID=%s, moderatorID=%s, reversed=%v",
+		guildID, action.RuleID, action.UserID, ctx.UserID, reversed)
+
+	return h.updateNotification(ctx, reversed)
+}
+
+// relatedActions retorna as ações da mesma violação (mesmo usuário e regra, registradas juntas).
+func (h *AutomodFeedbackHandler) relatedActions(ctx context.Context, action storage.AutomodAction) ([]storage.AutomodAction, error) {
+	candidates, err := h.store.GetAutomodActionsContext(ctx, action.GuildID, action.CreatedAt.Add(-automodTriggerWindow))
+	if err != nil {
+		return nil, err
+	}
+	out := []storage.AutomodAction{action}
+	for _, a := range candidates {
+		if a.ID == action.ID || a.UserID != action.UserID || a.RuleID != action.RuleID {
+			continue
+		}
+		d := a.CreatedAt.Sub(action.CreatedAt)
+		if d < 0 {
+			d = -d
+		}
+		if d <= automodTriggerWindow {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// reverse desfaz o que for possível: remove timeouts e republica conteúdo bloqueado. Ações
+// simuladas (dry-run) não foram executadas e não têm o que desfazer.
+// Retorna a descrição do que foi desfeito.
+func (h *AutomodFeedbackHandler) reverse(ctx context.Context, s *discordgo.Session, actions []storage.AutomodAction) []string {
+	var reversed []string
+	restored := false
+	for _, a := range actions {
+		if a.Simulated {
+			continue
+		}
+		switch a.Action {
+		case "timeout":
+			if err := discord.ClientFor(s).RemoveTimeout(ctx, a.GuildID, a.UserID); err != nil {
+				log.Warn().Applicationf("Failed to remove automod timeout: guildID=%s, userID=%s, error=%v", a.GuildID, a.UserID, err)
+				continue
+			}
+			reversed = append(reversed, "timeout removed")
+		case "block_message":
+			if restored || a.ChannelID == "" || strings.TrimSpace(a.Content) == "" {
+				continue
+			}
+			_, err := s.ChannelMessageSendComplex(a.ChannelID, &discordgo.MessageSend{
+				Content:         fmt.Sprintf("Message from <@%s> restored (blocked by AutoMod, marked as false positive):\n%s", a.UserID, a.Content),
+				AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+			})
+			if err != nil {
+				log.Warn().Applicationf("Failed to restore blocked message: guildID=%s, channelID=%s, error=%v", a.GuildID, a.ChannelID, err)
+				continue
+			}
+			restored = true
+			reversed = append(reversed, "message restored")
+		}
+	}
+	return reversed
+}
+
+// updateNotification marca o embed original e desativa o botão.
+func (h *AutomodFeedbackHandler) updateNotification(ctx *core.Context, reversed []string) error {
+	i := ctx.Interaction
+	var embeds []*discordgo.MessageEmbed
+	if i.Message != nil {
+		embeds = i.Message.Embeds
+	}
+	result := "Nothing to reverse"
+	if len(reversed) > 0 {
+		result = strings.Join(reversed, ", ")
+	}
+	if len(embeds) > 0 {
+		embeds[0].Fields = append(embeds[0].Fields, &discordgo.MessageEmbedField{
+			Name:   "False positive",
+			Value:  fmt.Sprintf("Marked by <@%s> — %s", ctx.UserID, result),
+			Inline: false,
+		})
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Marked false positive",
+					Style:    discordgo.SecondaryButton,
+					CustomID: i.MessageComponentData().CustomID,
+					Disabled: true,
+				},
+			},
+		},
+	}
+	return ctx.API.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:          embeds,
+			Components:      components,
+			AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+		},
+	})
+}
@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// automodLogPageSize keeps each page well under the 4096-character embed description limit.
+const automodLogPageSize = 10
+
+// automodLogMatchedMax bounds the matched text shown per entry.
+const automodLogMatchedMax = 80
+
+// AutomodLogCommand lists the automod actions recorded for the guild, newest first, as a
+// paginated embed.
+type AutomodLogCommand struct {
+	adminCommands *AdminCommands
+}
+
+// createAutomodLogCommand creates the automod log subcommand
+func (ac *AdminCommands) createAutomodLogCommand() core.SubCommand {
+	return &AutomodLogCommand{
+		adminCommands: ac,
+	}
+}
+
+func (cmd *AutomodLogCommand) Name() string {
+	return "automod-log"
+}
+
+func (cmd *AutomodLogCommand) Description() string {
+	return "Browse the automod actions recorded for this server"
+}
+
+func (cmd *AutomodLogCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "since",
+			Description: "Start date (YYYY-MM-DD) or duration back from now (default: 168h)",
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *AutomodLogCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *AutomodLogCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *AutomodLogCommand) Handle(ctx *core.Context) error {
+	store := cmd.adminCommands.store
+	if store == nil {
+		return core.NewCommandError("Message store is not available", true)
+	}
+
+	extractor := core.NewOptionExtractor(core.GetSubCommandOptions(ctx.Interaction))
+	rawSince := extractor.String("since")
+	if strings.TrimSpace(rawSince) == "" {
+		rawSince = "168h"
+	}
+	since, err := parseSince(rawSince, time.Now())
+	if err != nil {
+		return core.NewCommandError(err.Error(), true)
+	}
+
+	guildID := ctx.GuildID
+	fetch := core.StorePageFetcher(automodLogPageSize, func(req storage.PageRequest) (storage.Page[storage.AutomodAction], error) {
+		return store.AutomodActionsPage(guildID, since, req)
+	}, func(actions []storage.AutomodAction) *discordgo.MessageEmbed {
+		return renderAutomodLog(actions, since)
+	})
+	return core.SendPaginated(ctx, fetch, ctx.Ephemeral)
+}
+
+// renderAutomodLog renders one page of automod actions.
+func renderAutomodLog(actions []storage.AutomodAction, since time.Time) *discordgo.MessageEmbed {
+	description := "No automod actions recorded for this period."
+	if len(actions) > 0 {
+		lines := make([]string, 0, len(actions))
+		for _, a := range actions {
+			lines = append(lines, formatAutomodLogEntry(a))
+		}
+		description = strings.Join(lines, "\n")
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "🛡️ AutoMod Log",
+		Description: description,
+		Color:       0x5865F2,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Since " + since.UTC().Format("2006-01-02 15:04") + " UTC"},
+	}
+}
+
+func formatAutomodLogEntry(a storage.AutomodAction) string {
+	line := fmt.Sprintf("`#%d` <t:%d:f> <@%s> **%s**", a.ID, a.CreatedAt.Unix(), a.UserID, a.Action)
+	if a.RuleID != "" {
+		line += " · rule `" + a.RuleID + "`"
+	}
+	if a.Simulated {
+		line += " · dry-run"
+	}
+	if matched := strings.TrimSpace(a.Matched); matched != "" {
+		if utf8.RuneCountInString(matched) > automodLogMatchedMax {
+			matched = string([]rune(matched)[:automodLogMatchedMax-1]) + "…"
+		}
+		line += "\n  ↳ `" + strings.ReplaceAll(matched, "`", "'") + "`"
+	}
+	return line
+}
@@ -0,0 +1,147 @@
+package admin
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+	"github.com/small-frappuccino/discordcore/pkg/util"
+)
+
+// automodStatsTopRules bounds the per-rule breakdown so the embed field stays short.
+const automodStatsTopRules = 10
+
+// AutomodStatsCommand summarizes the automod actions recorded for the guild: executed actions
+// per type and, per rule, how many actions were taken and how many were false positives.
+type AutomodStatsCommand struct {
+	store *storage.Store
+}
+
+// NewAutomodStatsCommand creates the /automod-stats command backed by the given store.
+func NewAutomodStatsCommand(store *storage.Store) *AutomodStatsCommand {
+	return &AutomodStatsCommand{store: store}
+}
+
+func (cmd *AutomodStatsCommand) Name() string {
+	return "automod-stats"
+}
+
+func (cmd *AutomodStatsCommand) Description() string {
+	return "Show how many automod actions were taken in this server"
+}
+
+func (cmd *AutomodStatsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "since",
+			Description: "Start date (YYYY-MM-DD) or duration back from now (default: 168h)",
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *AutomodStatsCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *AutomodStatsCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *AutomodStatsCommand) Handle(ctx *core.Context) error {
+	if cmd.store == nil {
+		return core.NewCommandError("Message store is not available", true)
+	}
+
+	extractor := core.NewOptionExtractor(ctx.Interaction.ApplicationCommandData().Options)
+	rawSince := extractor.String("since")
+	if strings.TrimSpace(rawSince) == "" {
+		rawSince = "168h"
+	}
+	since, err := parseSince(rawSince, time.Now())
+	if err != nil {
+		return core.NewCommandError(err.Error(), true)
+	}
+
+	counts, err := cmd.store.AutomodActionCounts(ctx.GuildID, since)
+	if err != nil {
+		return fmt.Errorf("count automod actions: %w", err)
+	}
+	rules, err := cmd.store.RuleFeedbackStats(ctx.GuildID, since)
+	if err != nil {
+		return fmt.Errorf("load automod rule stats: %w", err)
+	}
+
+	embed := renderAutomodStats(counts, rules, guildLocale(ctx))
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: "Since " + since.In(util.LoadTimezone(guildTimezone(ctx))).Format("2006-01-02") + " (" + util.RelativeTime(since, time.Now()) + ")"}
+	embed.Timestamp = time.Now().Format(time.RFC3339)
+	return ctx.Respond().Embed(ctx.Interaction, embed)
+}
+
+// renderAutomodStats builds the embed body: the total, one line per executed action type (most
+// frequent first) and the rules with the most actions, with their false-positive rate.
+func renderAutomodStats(counts map[string]int, rules []storage.RuleFeedbackStat, locale string) *discordgo.MessageEmbed {
+	total := 0
+	actions := make([]string, 0, len(counts))
+	for action, n := range counts {
+		actions = append(actions, action)
+		total += n
+	}
+	slices.SortFunc(actions, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+
+	description := "No automod actions recorded for this period."
+	if total > 0 {
+		lines := make([]string, 0, len(actions))
+		for _, action := range actions {
+			lines = append(lines, fmt.Sprintf("`%s` — %s", action, util.FormatInt(int64(counts[action]), locale)))
+		}
+		description = strings.Join(lines, "\n")
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Actions taken", Value: util.FormatInt(int64(total), locale), Inline: true},
+	}
+	if len(rules) > 0 {
+		// RuleFeedbackStats orders by false positives; the breakdown ranks rules by activity.
+		rules = slices.Clone(rules)
+		slices.SortStableFunc(rules, func(a, b storage.RuleFeedbackStat) int {
+			return cmp.Compare(b.Actions, a.Actions)
+		})
+		falsePositives := 0
+		lines := make([]string, 0, automodStatsTopRules)
+		for i, r := range rules {
+			falsePositives += r.FalsePositives
+			if i >= automodStatsTopRules {
+				continue
+			}
+			rule := r.RuleID
+			if rule == "" {
+				rule = "(no rule)"
+			}
+			line := fmt.Sprintf("`%s` — %s", rule, util.FormatInt(int64(r.Actions), locale))
+			if r.FalsePositives > 0 {
+				line += fmt.Sprintf(" (%s false positive, %.0f%%)", util.FormatInt(int64(r.FalsePositives), locale), r.Rate*100)
+			}
+			lines = append(lines, line)
+		}
+		fields = append(fields,
+			&discordgo.MessageEmbedField{Name: "False positives", Value: util.FormatInt(int64(falsePositives), locale), Inline: true},
+			&discordgo.MessageEmbedField{Name: "By rule (including dry-run)", Value: strings.Join(lines, "\n")},
+		)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "🛡️ AutoMod Stats",
+		Description: description,
+		Color:       0x5865F2,
+		Fields:      fields,
+	}
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+func TestRenderAutomodStats(t *testing.T) {
+	counts := map[string]int{"timeout": 2, "block_message": 5, "flag": 2}
+	rules := []storage.RuleFeedbackStat{
+		{RuleID: "links", Actions: 3, FalsePositives: 1, Rate: 1.0 / 3},
+		{RuleID: "spam", Actions: 6},
+	}
+	embed := renderAutomodStats(counts, rules, "en")
+
+	if want := "`block_message` — 5\n`flag` — 2\n`timeout` — 2"; embed.Description != want {
+		t.Errorf("description = %q, want %q", embed.Description, want)
+	}
+	values := map[string]string{}
+	for _, f := range embed.Fields {
+		values[f.Name] = f.Value
+	}
+	if values["Actions taken"] != "9" || values["False positives"] != "1" {
+		t.Errorf("totals = %q actions, %q false positives", values["Actions taken"], values["False positives"])
+	}
+	byRule := values["By rule (including dry-run)"]
+	if !strings.HasPrefix(byRule, "`spam` — 6\n`links` — 3 (1 false positive, 33%)") {
+		t.Errorf("by rule = %q", byRule)
+	}
+}
+
+func TestRenderAutomodStatsEmpty(t *testing.T) {
+	embed := renderAutomodStats(nil, nil, "en")
+	if !strings.Contains(embed.Description, "No automod actions") || len(embed.Fields) != 1 {
+		t.Errorf("empty stats = %q with %d fields", embed.Description, len(embed.Fields))
+	}
+}
@@ -0,0 +1,290 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// bulkRoleRuns tracks the bulk role operation running in each guild (at most one per guild).
+type bulkRoleRuns struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func (r *bulkRoleRuns) start(parent context.Context, guildID string) (context.Context, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, running := r.cancels[guildID]; running {
+		return nil, false
+	}
+	if r.cancels == nil {
+		r.cancels = make(map[string]context.CancelFunc)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	r.cancels[guildID] = cancel
+	return ctx, true
+}
+
+func (r *bulkRoleRuns) done(guildID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel, ok := r.cancels[guildID]; ok {
+		cancel()
+		delete(r.cancels, guildID)
+	}
+}
+
+func (r *bulkRoleRuns) cancel(guildID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cancel, ok := r.cancels[guildID]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// BulkRoleCommand adds or removes a role for every member (optionally filtered by another role),
+// reporting progress in the command response.
+type BulkRoleCommand struct {
+	adminCommands *AdminCommands
+}
+
+// createBulkRoleCommand creates the bulk role subcommand
+func (ac *AdminCommands) createBulkRoleCommand() core.SubCommand {
+	return &BulkRoleCommand{adminCommands: ac}
+}
+
+func (cmd *BulkRoleCommand) Name() string {
+	return "bulk-role"
+}
+
+func (cmd *BulkRoleCommand) Description() string {
+	return "Add or remove a role for all members (optionally only those with another role)"
+}
+
+func (cmd *BulkRoleCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "action",
+			Description: "Add or remove the role",
+			Required:    true,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Add", Value: discord.BulkRoleAdd},
+				{Name: "Remove", Value: discord.BulkRoleRemove},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionRole,
+			Name:        "role",
+			Description: "Role to add or remove",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionRole,
+			Name:        "has_role",
+			Description: "Only members that have this role",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "include_bots",
+			Description: "Also change bot accounts (default: false)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "resume_after",
+			Description: "Resume a stopped run after this user ID (shown when a run stops)",
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *BulkRoleCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *BulkRoleCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *BulkRoleCommand) Handle(ctx *core.Context) error {
+	if ctx.Session == nil {
+		return core.NewCommandError("Bulk role operations require a live Discord session", true)
+	}
+	options := core.GetSubCommandOptions(ctx.Interaction)
+	extractor := core.NewOptionExtractor(options)
+	action := extractor.String("action")
+	roleID := optionID(options, "role")
+	filterRoleID := optionID(options, "has_role")
+	includeBots := extractor.Bool("include_bots")
+	resumeAfter := strings.TrimSpace(extractor.String("resume_after"))
+	if roleID == "" {
+		return core.NewCommandError("Option 'role' is required", true)
+	}
+
+	client := discord.ClientFor(ctx.Session)
+	if err := client.RoleAssignable(ctx.GuildID, roleID); err != nil {
+		return core.NewCommandError(fmt.Sprintf("I can't manage <@&%s>: %v", roleID, err), true)
+	}
+	if !ctx.IsOwner && !invokerOutranks(ctx, roleID) {
+		return core.NewCommandError(fmt.Sprintf("<@&%s> is not below your highest role", roleID), true)
+	}
+
+	runs := &cmd.adminCommands.bulkRoles
+	// A operação continua depois que o handler retorna: herda os valores da interação, não o cancelamento
+	runCtx, ok := runs.start(context.WithoutCancel(ctx.Ctx), ctx.GuildID)
+	if !ok {
+		return core.NewCommandError("A bulk role operation is already running in this guild; use `/admin bulk-role-cancel` to stop it", true)
+	}
+	progress, err := core.StartProgress(ctx, fmt.Sprintf("👥 Bulk role %s: <@&%s>", action, roleID), ctx.Ephemeral)
+	if err != nil {
+		runs.done(ctx.GuildID)
+		return err
+	}
+	progress.SetExpiredFallback(bulkRoleDMFallback(client, ctx.GuildID, ctx.UserID))
+
+	total := 0
+	if g, err := ctx.Session.State.Guild(ctx.GuildID); err == nil {
+		total = g.MemberCount
+	}
+	req := discord.BulkRoleRequest{
+		GuildID: ctx.GuildID,
+		RoleID:  roleID,
+		Action:  action,
+		After:   resumeAfter,
+		Reason:  fmt.Sprintf("Bulk role %s by %s via /admin bulk-role", action, ctx.UserID),
+		Filter: func(m *discordgo.Member) bool {
+			if m.User.Bot && !includeBots {
+				return false
+			}
+			return filterRoleID == "" || slices.Contains(m.Roles, filterRoleID)
+		},
+		Progress: func(r discord.BulkRoleResult) {
+			progress.Report(r.Scanned, total, fmt.Sprintf("%d changed, %d skipped, %d failed", r.Changed, r.Skipped, r.Failed))
+		},
+	}
+	log.Info().Applicationf("🧾 Audit: bulk-role started by user=%s guild=%s action=%s role=%s has_role=%s include_bots=%t resume_after=%s",
+		ctx.UserID, ctx.GuildID, action, roleID, filterRoleID, includeBots, resumeAfter)
+
+	go func() {
+		defer runs.done(ctx.GuildID)
+		res, err := client.BulkRole(runCtx, req)
+		totals := fmt.Sprintf("%d scanned, %d changed, %d skipped, %d failed", res.Scanned, res.Changed, res.Skipped, res.Failed)
+		var summary, outcome string
+		switch {
+		case err == nil:
+			outcome = "completed"
+			summary = "✅ Done: " + totals
+		case errors.Is(err, context.Canceled):
+			outcome = "cancelled"
+			summary = "⏹️ Cancelled: " + totals
+		default:
+			outcome = "failed"
+			summary = fmt.Sprintf("❌ Stopped: %v\n%s", err, totals)
+		}
+		if err != nil && res.LastUserID != "" {
+			summary += fmt.Sprintf("\nResume with `resume_after:%s`", res.LastUserID)
+		}
+		log.Info().Applicationf("🧾 Audit: bulk-role %s by user=%s guild=%s action=%s role=%s: %s, last_user=%s",
+			outcome, ctx.UserID, ctx.GuildID, action, roleID, totals, res.LastUserID)
+		if err := progress.Finish(summary); err != nil {
+			log.Warn().Applicationf("Failed to publish bulk role summary: guildID=%s, error=%v", ctx.GuildID, err)
+		}
+	}()
+	return nil
+}
+
+// bulkRoleDMFallback publishes progress and the summary of a run that outlived the interaction
+// token in a DM to the invoker: the first call sends the DM, later calls edit it.
+func bulkRoleDMFallback(client *discord.Client, guildID, userID string) func(string) error {
+	var channelID, messageID string
+	return func(content string) error {
+		if messageID != "" {
+			_, err := client.EditMessage(context.Background(), channelID, messageID, content)
+			return err
+		}
+		msg, err := client.SendDM(context.Background(), userID, &discordgo.MessageSend{
+			Content:         content,
+			AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+		})
+		if err != nil {
+			log.Warn().Applicationf("Failed to send bulk role progress DM: guildID=%s, userID=%s, error=%v", guildID, userID, err)
+			return err
+		}
+		channelID, messageID = msg.ChannelID, msg.ID
+		return nil
+	}
+}
+
+// invokerOutranks reports whether the invoking member's highest role is above roleID, so that
+// moderators cannot hand out (or strip) roles above their own. Without the guild in the state it
+// defers to the permission check already done by the router.
+func invokerOutranks(ctx *core.Context, roleID string) bool {
+	guild, err := ctx.Session.State.Guild(ctx.GuildID)
+	if err != nil || ctx.Interaction.Member == nil {
+		return true
+	}
+	if guild.OwnerID == ctx.UserID {
+		return true
+	}
+	top, target := 0, -1
+	for _, r := range guild.Roles {
+		if r.ID == roleID {
+			target = r.Position
+		}
+		if slices.Contains(ctx.Interaction.Member.Roles, r.ID) {
+			top = max(top, r.Position)
+		}
+	}
+	return target < top
+}
+
+// BulkRoleCancelCommand stops the bulk role operation running in the guild.
+type BulkRoleCancelCommand struct {
+	adminCommands *AdminCommands
+}
+
+// createBulkRoleCancelCommand creates the bulk role cancel subcommand
+func (ac *AdminCommands) createBulkRoleCancelCommand() core.SubCommand {
+	return &BulkRoleCancelCommand{adminCommands: ac}
+}
+
+func (cmd *BulkRoleCancelCommand) Name() string {
+	return "bulk-role-cancel"
+}
+
+func (cmd *BulkRoleCancelCommand) Description() string {
+	return "Stop the bulk role operation running in this guild"
+}
+
+func (cmd *BulkRoleCancelCommand) Options() []*discordgo.ApplicationCommandOption {
+	return nil
+}
+
+func (cmd *BulkRoleCancelCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *BulkRoleCancelCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *BulkRoleCancelCommand) Handle(ctx *core.Context) error {
+	if !cmd.adminCommands.bulkRoles.cancel(ctx.GuildID) {
+		return core.NewCommandError("No bulk role operation is running in this guild", true)
+	}
+	log.Info().Applicationf("🧾 Audit: bulk-role cancel requested by user=%s guild=%s", ctx.UserID, ctx.GuildID)
+	return ctx.Respond().Success(ctx.Interaction, "Stopping the bulk role operation; the summary will show where to resume")
+}
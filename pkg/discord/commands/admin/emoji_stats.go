@@ -0,0 +1,126 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+	"github.com/small-frappuccino/discordcore/pkg/util"
+)
+
+// emojiStatsMaxLimit bounds the ranking so the embed stays under Discord's field limits.
+const emojiStatsMaxLimit = 25
+
+// EmojiStatsCommand shows the most used custom emojis and stickers of the guild
+// (requires emoji_stats_enabled in the guild config).
+type EmojiStatsCommand struct {
+	adminCommands *AdminCommands
+}
+
+// createEmojiStatsCommand creates the emoji stats subcommand
+func (ac *AdminCommands) createEmojiStatsCommand() core.SubCommand {
+	return &EmojiStatsCommand{
+		adminCommands: ac,
+	}
+}
+
+func (cmd *EmojiStatsCommand) Name() string {
+	return "emoji-stats"
+}
+
+func (cmd *EmojiStatsCommand) Description() string {
+	return "Show the most used custom emojis and stickers"
+}
+
+func (cmd *EmojiStatsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "since",
+			Description: "Start date (YYYY-MM-DD) or duration back from now (default: 720h)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "limit",
+			Description: fmt.Sprintf("Number of entries (default: 10, max: %d)", emojiStatsMaxLimit),
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *EmojiStatsCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *EmojiStatsCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *EmojiStatsCommand) Handle(ctx *core.Context) error {
+	store := cmd.adminCommands.store
+	if store == nil {
+		return core.NewCommandError("Message store is not available", true)
+	}
+
+	extractor := core.NewOptionExtractor(core.GetSubCommandOptions(ctx.Interaction))
+	rawSince := extractor.String("since")
+	if strings.TrimSpace(rawSince) == "" {
+		rawSince = "720h"
+	}
+	since, err := parseSince(rawSince, time.Now())
+	if err != nil {
+		return core.NewCommandError(err.Error(), true)
+	}
+	limit := int(extractor.Int("limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > emojiStatsMaxLimit {
+		limit = emojiStatsMaxLimit
+	}
+
+	top, err := store.TopEmojisContext(ctx.Ctx, ctx.GuildID, since, limit)
+	if err != nil {
+		return fmt.Errorf("load emoji usage: %w", err)
+	}
+
+	description := "No custom emoji or sticker usage recorded for this period."
+	if ctx.GuildConfig == nil || !ctx.GuildConfig.EmojiStatsEnabled {
+		description = "Emoji tracking is disabled for this server (set `emoji_stats_enabled`)."
+	}
+	if len(top) > 0 {
+		lines := make([]string, 0, len(top))
+		for i, u := range top {
+			lines = append(lines, fmt.Sprintf("%d. %s — %s", i+1, formatEmojiUsage(u), util.FormatInt(u.Count, guildLocale(ctx))))
+		}
+		description = strings.Join(lines, "\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "😀 Emoji & Sticker Usage",
+		Description: description,
+		Color:       0x5865F2,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Since " + since.In(util.LoadTimezone(guildTimezone(ctx))).Format("2006-01-02") + " (" + util.RelativeTime(since, time.Now()) + ")"},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	return ctx.Respond().Embed(ctx.Interaction, embed)
+}
+
+// formatEmojiUsage renders an emoji inline (it displays if the bot can see it) or a sticker by name.
+func formatEmojiUsage(u storage.EmojiUsage) string {
+	if u.Kind == storage.EmojiKindSticker {
+		return fmt.Sprintf("🏷️ %s (sticker)", u.Name)
+	}
+	return fmt.Sprintf("%s `:%s:`", u.MessageFormat(), u.Name)
+}
+
+func guildTimezone(ctx *core.Context) string {
+	if ctx != nil && ctx.GuildConfig != nil {
+		return ctx.GuildConfig.Timezone
+	}
+	return ""
+}
@@ -0,0 +1,364 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// exportMaxRows bounds a single export; larger ranges are cut and the reply warns about it.
+const exportMaxRows = 10000
+
+// ExportLogsCommand exports stored message logs of a channel as a CSV or JSON attachment.
+type ExportLogsCommand struct {
+	store *storage.Store
+}
+
+// NewExportLogsCommand creates the /export-logs command backed by the given store.
+func NewExportLogsCommand(store *storage.Store) *ExportLogsCommand {
+	return &ExportLogsCommand{store: store}
+}
+
+func (cmd *ExportLogsCommand) Name() string {
+	return "export-logs"
+}
+
+func (cmd *ExportLogsCommand) Description() string {
+	return "Export stored message logs of a channel as a file"
+}
+
+func (cmd *ExportLogsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionChannel,
+			Name:         "channel",
+			Description:  "Channel to export",
+			Required:     true,
+			ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "since",
+			Description: "Start date (YYYY-MM-DD) or duration back from now (e.g. 12h)",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "format",
+			Description: "File format (default: csv)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "CSV", Value: "csv"},
+				{Name: "JSON", Value: "json"},
+			},
+		},
+	}
+}
+
+func (cmd *ExportLogsCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *ExportLogsCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *ExportLogsCommand) Handle(ctx *core.Context) error {
+	if cmd.store == nil {
+		return core.NewCommandError("Message store is not available", true)
+	}
+
+	options := ctx.Interaction.ApplicationCommandData().Options
+	channelID := optionID(options, "channel")
+	if channelID == "" {
+		return core.NewCommandError("Option 'channel' is required", true)
+	}
+	extractor := core.NewOptionExtractor(options)
+	since, err := parseSince(extractor.String("since"), time.Now())
+	if err != nil {
+		return core.NewCommandError(err.Error(), true)
+	}
+	format := strings.ToLower(extractor.String("format"))
+	if format != "json" {
+		format = "csv"
+	}
+
+	rm := core.NewResponseManager(ctx.API)
+	if err := rm.DeferResponse(ctx.Interaction, true); err != nil {
+		return err
+	}
+
+	// Stream rows to temp files to keep memory bounded on large exports.
+	parts, rows, err := writeExport(ctx.Ctx, format, cmd.store, ctx.GuildID, channelID, since, exportMaxAttachmentBytes)
+	defer parts.remove()
+	if err != nil {
+		return rm.FollowUp(ctx.Interaction, fmt.Sprintf("Failed to export logs: %v", err), true)
+	}
+
+	// Audit the export itself
+	log.Info().Applicationf("📤 Audit: export-logs by user=%s guild=%s channel=%s since=%s format=%s rows=%d parts=%d",
+		ctx.UserID, ctx.GuildID, channelID, since.Format(time.RFC3339), format, rows, len(parts))
+
+	content := fmt.Sprintf("Exported %d message(s) from <#%s> since %s.", rows, channelID, since.Format("2006-01-02 15:04 MST"))
+	if len(parts) > 1 {
+		content += fmt.Sprintf("\nThe export is split into %d files to stay under Discord's attachment size limit.", len(parts))
+	}
+	if rows >= exportMaxRows {
+		content += fmt.Sprintf("\n⚠️ Export truncated at %d rows; narrow the range to get the rest.", exportMaxRows)
+	}
+	// One part per message, so each upload request stays under the limit.
+	stamp := time.Now().UTC().Format("20060102-150405")
+	for i, part := range parts {
+		if _, err := part.f.Seek(0, 0); err != nil {
+			return rm.FollowUp(ctx.Interaction, fmt.Sprintf("Failed to read export file: %v", err), true)
+		}
+		name := fmt.Sprintf("messages-%s-%s.%s", channelID, stamp, format)
+		if len(parts) > 1 {
+			name = fmt.Sprintf("messages-%s-%s-part%dof%d.%s", channelID, stamp, i+1, len(parts), format)
+		}
+		params := &discordgo.WebhookParams{
+			Flags: discordgo.MessageFlagsEphemeral,
+			Files: []*discordgo.File{{
+				Name:        name,
+				ContentType: exportContentType(format),
+				Reader:      part.f,
+			}},
+		}
+		if i == 0 {
+			params.Content = content
+		}
+		if _, err := ctx.API.FollowupMessageCreate(ctx.Interaction.Interaction, true, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportMaxAttachmentBytes keeps each export file under Discord's default upload limit
+// (10 MiB), with headroom for the multipart request.
+const exportMaxAttachmentBytes = 10<<20 - 64<<10
+
+// exportPart is one self-contained export file (CSV with its header, or a JSON array).
+type exportPart struct {
+	f    *os.File
+	size int64
+	rows int
+}
+
+type exportParts []*exportPart
+
+func (ps exportParts) remove() {
+	for _, p := range ps {
+		_ = p.f.Close()
+		_ = os.Remove(p.f.Name())
+	}
+}
+
+// exportWriter encodes rows and starts a new part whenever the next row would push the
+// current one past maxBytes, so every part can be uploaded on its own.
+type exportWriter struct {
+	format   string
+	maxBytes int64
+	parts    exportParts
+	buf      bytes.Buffer
+}
+
+func (w *exportWriter) header() string {
+	if w.format == "json" {
+		return "[\n"
+	}
+	return exportCSVHeader
+}
+
+const exportCSVHeader = "message_id,channel_id,author_id,author_username,cached_at,content,truncated,original_length,content_omitted,attachment_count\n"
+
+func (w *exportWriter) trailer() string {
+	if w.format == "json" {
+		return "]\n"
+	}
+	return ""
+}
+
+// encode renders rec as it is appended to a part that already holds rows rows.
+func (w *exportWriter) encode(rec storage.MessageRecord, rows int) ([]byte, error) {
+	w.buf.Reset()
+	if w.format == "json" {
+		if rows > 0 {
+			w.buf.WriteByte(',')
+		}
+		if err := json.NewEncoder(&w.buf).Encode(exportRow(rec)); err != nil {
+			return nil, err
+		}
+		return w.buf.Bytes(), nil
+	}
+	cw := csv.NewWriter(&w.buf)
+	_ = cw.Write([]string{
+		rec.MessageID,
+		rec.ChannelID,
+		rec.AuthorID,
+		rec.AuthorUsername,
+		rec.CachedAt.UTC().Format(time.RFC3339),
+		rec.Content,
+		strconv.FormatBool(rec.Truncated),
+		strconv.Itoa(rec.OriginalLength),
+		strconv.FormatBool(rec.ContentOmitted),
+		strconv.Itoa(rec.AttachmentCount),
+	})
+	cw.Flush()
+	return w.buf.Bytes(), cw.Error()
+}
+
+func (w *exportWriter) current() *exportPart {
+	if len(w.parts) == 0 {
+		return nil
+	}
+	return w.parts[len(w.parts)-1]
+}
+
+func (w *exportWriter) openPart() error {
+	if p := w.current(); p != nil {
+		if err := w.writeTo(p, []byte(w.trailer())); err != nil {
+			return err
+		}
+	}
+	f, err := os.CreateTemp("", "export-logs-*."+w.format)
+	if err != nil {
+		return err
+	}
+	p := &exportPart{f: f}
+	w.parts = append(w.parts, p)
+	return w.writeTo(p, []byte(w.header()))
+}
+
+func (w *exportWriter) writeTo(p *exportPart, b []byte) error {
+	n, err := p.f.Write(b)
+	p.size += int64(n)
+	return err
+}
+
+func (w *exportWriter) write(rec storage.MessageRecord) error {
+	p := w.current()
+	if p == nil {
+		if err := w.openPart(); err != nil {
+			return err
+		}
+		p = w.current()
+	}
+	row, err := w.encode(rec, p.rows)
+	if err != nil {
+		return err
+	}
+	// A row that does not fit even in an empty part still gets one of its own.
+	if p.rows > 0 && p.size+int64(len(row))+int64(len(w.trailer())) > w.maxBytes {
+		if err := w.openPart(); err != nil {
+			return err
+		}
+		p = w.current()
+		if row, err = w.encode(rec, 0); err != nil {
+			return err
+		}
+	}
+	if err := w.writeTo(p, row); err != nil {
+		return err
+	}
+	p.rows++
+	return nil
+}
+
+func (w *exportWriter) finish() error {
+	if w.current() == nil {
+		// An empty export still produces a (header-only) file.
+		if err := w.openPart(); err != nil {
+			return err
+		}
+	}
+	return w.writeTo(w.current(), []byte(w.trailer()))
+}
+
+// writeExport streams matching rows into one or more part files of at most maxBytes each
+// and returns them with the number of rows written. The caller removes the parts.
+func writeExport(ctx context.Context, format string, store *storage.Store, guildID, channelID string, since time.Time, maxBytes int64) (exportParts, int, error) {
+	w := &exportWriter{format: format, maxBytes: maxBytes}
+	rows := 0
+	err := store.ForEachGuildMessageInRangeContext(ctx, guildID, channelID, since, time.Time{}, exportMaxRows, func(rec storage.MessageRecord) error {
+		rows++
+		return w.write(rec)
+	})
+	if err == nil {
+		err = w.finish()
+	}
+	return w.parts, rows, err
+}
+
+type exportedMessage struct {
+	MessageID      string    `json:"message_id"`
+	ChannelID      string    `json:"channel_id"`
+	AuthorID       string    `json:"author_id"`
+	AuthorUsername string    `json:"author_username"`
+	CachedAt       time.Time `json:"cached_at"`
+	Content        string    `json:"content"`
+	Truncated      bool      `json:"truncated,omitempty"`
+	OriginalLength int       `json:"original_length,omitempty"`
+	ContentOmitted bool      `json:"content_omitted,omitempty"`
+	Attachments    int       `json:"attachment_count,omitempty"`
+}
+
+func exportRow(rec storage.MessageRecord) exportedMessage {
+	return exportedMessage{
+		MessageID:      rec.MessageID,
+		ChannelID:      rec.ChannelID,
+		AuthorID:       rec.AuthorID,
+		AuthorUsername: rec.AuthorUsername,
+		CachedAt:       rec.CachedAt.UTC(),
+		Content:        rec.Content,
+		Truncated:      rec.Truncated,
+		OriginalLength: rec.OriginalLength,
+		ContentOmitted: rec.ContentOmitted,
+		Attachments:    rec.AttachmentCount,
+	}
+}
+
+func exportContentType(format string) string {
+	if format == "json" {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// parseSince accepts a date (YYYY-MM-DD, UTC) or a Go duration counted back from now.
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, fmt.Errorf("option 'since' is required")
+	}
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid 'since' value %q: use YYYY-MM-DD or a duration like 12h", raw)
+}
+
+// optionID reads the ID of a channel, role or user option (StringValue panics for non-string option types).
+func optionID(options []*discordgo.ApplicationCommandInteractionDataOption, name string) string {
+	for _, opt := range options {
+		if opt.Name == name {
+			if id, ok := opt.Value.(string); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+func newExportStore(t *testing.T, n int) *storage.Store {
+	t.Helper()
+	s := storage.NewStore(filepath.Join(t.TempDir(), "export.db"))
+	if err := s.Init(); err != nil {
+		t.Fatalf("init store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	base := time.Now().Add(-time.Hour)
+	for i := range n {
+		err := s.UpsertMessage(storage.MessageRecord{
+			GuildID:   "g1",
+			ChannelID: "c1",
+			MessageID: "m" + strconv.Itoa(i),
+			AuthorID:  "u1",
+			Content:   "line one,\n\"quoted\" " + strings.Repeat("x", 200),
+			CachedAt:  base.Add(time.Duration(i) * time.Second),
+		})
+		if err != nil {
+			t.Fatalf("upsert message: %v", err)
+		}
+	}
+	s.FlushWrites()
+	return s
+}
+
+func readPart(t *testing.T, p *exportPart) []byte {
+	t.Helper()
+	if _, err := p.f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(p.f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(b)) != p.size {
+		t.Errorf("part size %d, tracked %d", len(b), p.size)
+	}
+	return b
+}
+
+func TestWriteExportSplitsCSV(t *testing.T) {
+	store := newExportStore(t, 50)
+	const maxBytes = 2048
+	parts, rows, err := writeExport(context.Background(), "csv", store, "g1", "c1", time.Time{}, maxBytes)
+	defer parts.remove()
+	if err != nil {
+		t.Fatalf("writeExport: %v", err)
+	}
+	if rows != 50 || len(parts) < 2 {
+		t.Fatalf("rows %d in %d parts, want 50 rows split into several parts", rows, len(parts))
+	}
+	total := 0
+	for i, p := range parts {
+		b := readPart(t, p)
+		if len(b) > maxBytes {
+			t.Errorf("part %d has %d bytes, limit %d", i, len(b), maxBytes)
+		}
+		records, err := csv.NewReader(strings.NewReader(string(b))).ReadAll()
+		if err != nil {
+			t.Fatalf("part %d is not valid CSV: %v", i, err)
+		}
+		if records[0][0] != "message_id" {
+			t.Errorf("part %d does not start with the header", i)
+		}
+		if got := len(records) - 1; got != p.rows {
+			t.Errorf("part %d has %d records, tracked %d", i, got, p.rows)
+		}
+		total += p.rows
+	}
+	if total != rows {
+		t.Errorf("parts hold %d rows, want %d", total, rows)
+	}
+}
+
+func TestWriteExportSplitsJSON(t *testing.T) {
+	store := newExportStore(t, 50)
+	const maxBytes = 2048
+	parts, rows, err := writeExport(context.Background(), "json", store, "g1", "c1", time.Time{}, maxBytes)
+	defer parts.remove()
+	if err != nil {
+		t.Fatalf("writeExport: %v", err)
+	}
+	total := 0
+	for i, p := range parts {
+		b := readPart(t, p)
+		if len(b) > maxBytes {
+			t.Errorf("part %d has %d bytes, limit %d", i, len(b), maxBytes)
+		}
+		var msgs []exportedMessage
+		if err := json.Unmarshal(b, &msgs); err != nil {
+			t.Fatalf("part %d is not a JSON array: %v", i, err)
+		}
+		total += len(msgs)
+	}
+	if len(parts) < 2 || total != rows || rows != 50 {
+		t.Errorf("%d rows in %d parts, want 50 rows split into several parts", total, len(parts))
+	}
+}
+
+func TestWriteExportSinglePart(t *testing.T) {
+	store := newExportStore(t, 3)
+	for _, format := range []string{"csv", "json"} {
+		parts, rows, err := writeExport(context.Background(), format, store, "g1", "c1", time.Time{}, exportMaxAttachmentBytes)
+		if err != nil {
+			t.Fatalf("%s: writeExport: %v", format, err)
+		}
+		if rows != 3 || len(parts) != 1 {
+			t.Errorf("%s: %d rows in %d parts, want 3 in 1", format, rows, len(parts))
+		}
+		parts.remove()
+	}
+
+	// An empty range still produces one valid file.
+	parts, rows, err := writeExport(context.Background(), "json", store, "g1", "other", time.Time{}, exportMaxAttachmentBytes)
+	defer parts.remove()
+	if err != nil || rows != 0 || len(parts) != 1 {
+		t.Fatalf("empty export: %d rows in %d parts, err %v", rows, len(parts), err)
+	}
+	if got := strings.TrimSpace(string(readPart(t, parts[0]))); got != "[\n]" {
+		t.Errorf("empty JSON export = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// InactiveGuildsCommand lists the guilds deactivated after the bot was removed from them
+// and reactivates or purges one of them.
+type InactiveGuildsCommand struct {
+	adminCommands *AdminCommands
+}
+
+// createInactiveGuildsCommand creates the inactive guilds subcommand
+func (ac *AdminCommands) createInactiveGuildsCommand() core.SubCommand {
+	return &InactiveGuildsCommand{
+		adminCommands: ac,
+	}
+}
+
+func (cmd *InactiveGuildsCommand) Name() string {
+	return "inactive-guilds"
+}
+
+func (cmd *InactiveGuildsCommand) Description() string {
+	return "List, reactivate or purge guilds the bot was removed from"
+}
+
+func (cmd *InactiveGuildsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "action",
+			Description: "What to do (default: list)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "List", Value: "list"},
+				{Name: "Reactivate", Value: "reactivate"},
+				{Name: "Purge config and data", Value: "purge"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "guild_id",
+			Description: "Inactive guild to reactivate or purge",
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *InactiveGuildsCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *InactiveGuildsCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *InactiveGuildsCommand) Handle(ctx *core.Context) error {
+	extractor := core.NewOptionExtractor(core.GetSubCommandOptions(ctx.Interaction))
+	action := extractor.String("action")
+	guildID := strings.TrimSpace(extractor.String("guild_id"))
+	if action == "" || action == "list" {
+		return cmd.list(ctx)
+	}
+	if guildID == "" {
+		return core.NewCommandError("Option 'guild_id' is required for this action", true)
+	}
+	gcfg, ok := ctx.Config.GuildConfig(guildID)
+	if !ok || !gcfg.Inactive {
+		return core.NewCommandError(fmt.Sprintf("Guild `%s` is not an inactive guild", guildID), true)
+	}
+
+	rm := ctx.Respond()
+	switch action {
+	case "reactivate":
+		if err := ctx.Config.SetGuildActive(guildID, true); err != nil {
+			return fmt.Errorf("reactivate guild: %w", err)
+		}
+		log.Info().Applicationf("Guild %s reactivated by user=%s", guildID, ctx.UserID)
+		return rm.Success(ctx.Interaction, fmt.Sprintf("Guild `%s` reactivated", guildID))
+	case "purge":
+		rows, err := logging.PurgeGuild(ctx.Config, cmd.adminCommands.store, guildID)
+		if err != nil {
+			return fmt.Errorf("purge guild: %w", err)
+		}
+		log.Info().Applicationf("Guild %s purged by user=%s (%d rows)", guildID, ctx.UserID, rows)
+		return rm.Success(ctx.Interaction, fmt.Sprintf("Guild `%s` removed from the config and %d stored rows deleted", guildID, rows))
+	default:
+		return core.NewCommandError(fmt.Sprintf("Unknown action %q", action), true)
+	}
+}
+
+func (cmd *InactiveGuildsCommand) list(ctx *core.Context) error {
+	inactive := ctx.Config.InactiveGuilds()
+	description := "No inactive guilds."
+	if len(inactive) > 0 {
+		lines := make([]string, 0, len(inactive))
+		for _, g := range inactive {
+			since := "unknown"
+			if t, err := time.Parse(time.RFC3339, g.InactiveSince); err == nil {
+				since = fmt.Sprintf("<t:%d:R>", t.Unix())
+			}
+			lines = append(lines, fmt.Sprintf("• `%s` — inactive since %s", g.GuildID, since))
+		}
+		description = strings.Join(lines, "\n")
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "💤 Inactive Guilds",
+		Description: description,
+		Color:       theme.Info(),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "guild_removal_policy: " + ctx.Config.GuildRemovalPolicy()},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	return ctx.Respond().Embed(ctx.Interaction, embed)
+}
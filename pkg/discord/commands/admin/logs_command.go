@@ -0,0 +1,120 @@
+package admin
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+const (
+	// logsDefaultLines/logsMaxLines bound how many buffered lines /logs returns.
+	logsDefaultLines = 30
+	logsMaxLines     = 500
+	// logsInlineLimit keeps the code-block reply under Discord's 2000-character message limit.
+	logsInlineLimit = 1900
+)
+
+// LogsCommand dumps the most recent buffered log lines (see log.Recent), redacted.
+// Restricted to bot operators: the buffer is process-wide and covers every guild the bot serves,
+// so a guild owner would read other guilds' lines.
+type LogsCommand struct{}
+
+// NewLogsCommand creates the /logs command.
+func NewLogsCommand() *LogsCommand {
+	return &LogsCommand{}
+}
+
+func (cmd *LogsCommand) Name() string {
+	return "logs"
+}
+
+func (cmd *LogsCommand) Description() string {
+	return "Show the most recent bot log lines (bot operators only)"
+}
+
+func (cmd *LogsCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "level",
+			Description: "Only lines of this level",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Info", Value: "INFO"},
+				{Name: "Warn", Value: "WARN"},
+				{Name: "Error", Value: "ERROR"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "category",
+			Description: "Only lines of this category",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Application", Value: "application"},
+				{Name: "Discord", Value: "discord"},
+				{Name: "Database", Value: "database"},
+				{Name: "Error", Value: "error"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "lines",
+			Description: fmt.Sprintf("Number of lines (default: %d, max: %d)", logsDefaultLines, logsMaxLines),
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *LogsCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *LogsCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *LogsCommand) Handle(ctx *core.Context) error {
+	if ctx.Config == nil || !ctx.Config.IsBotOperator(ctx.UserID) {
+		return core.NewCommandError("Only bot operators can read the bot logs, since they cover every server", true)
+	}
+
+	extractor := core.NewOptionExtractor(ctx.Interaction.ApplicationCommandData().Options)
+	lines := int(extractor.Int("lines"))
+	if lines <= 0 {
+		lines = logsDefaultLines
+	}
+	if lines > logsMaxLines {
+		lines = logsMaxLines
+	}
+	entries := log.Recent(extractor.String("level"), extractor.String("category"), lines)
+	if len(entries) == 0 {
+		return core.NewResponseManager(ctx.API).Ephemeral(ctx.Interaction, "No matching log lines in the buffer.")
+	}
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "%s [%s/%s] %s\n", e.Time.UTC().Format("15:04:05.000"), e.Level, e.Category, e.Message)
+	}
+	text := buf.String()
+
+	rm := core.NewResponseManager(ctx.API)
+	if len(text) <= logsInlineLimit {
+		// Crases no conteúdo fechariam o bloco de código antes da hora
+		text = strings.ReplaceAll(text, "```", "'''")
+		return rm.WithConfig(core.ResponseConfig{Ephemeral: true}).Custom(ctx.Interaction, "```\n"+text+"```", nil)
+	}
+	return rm.WithConfig(core.ResponseConfig{
+		Ephemeral: true,
+		Attachments: []*discordgo.File{{
+			Name:        fmt.Sprintf("logs-%s.txt", time.Now().UTC().Format("20060102-150405")),
+			ContentType: "text/plain",
+			Reader:      &buf,
+		}},
+	}).Custom(ctx.Interaction, fmt.Sprintf("Last %d log line(s):", len(entries)), nil)
+}
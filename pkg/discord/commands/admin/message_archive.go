@@ -0,0 +1,139 @@
+package admin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// maxListedArchives caps the archive files shown by the list action.
+const maxListedArchives = 20
+
+// MessageArchiveCommand lists the message archives of the guild and re-imports one of them
+// into the store for investigation.
+type MessageArchiveCommand struct {
+	adminCommands *AdminCommands
+}
+
+// createMessageArchiveCommand creates the message archive subcommand
+func (ac *AdminCommands) createMessageArchiveCommand() core.SubCommand {
+	return &MessageArchiveCommand{adminCommands: ac}
+}
+
+func (cmd *MessageArchiveCommand) Name() string {
+	return "message-archive"
+}
+
+func (cmd *MessageArchiveCommand) Description() string {
+	return "List this server's message archives or re-import one for investigation"
+}
+
+func (cmd *MessageArchiveCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "action",
+			Description: "What to do (default: list)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "List", Value: "list"},
+				{Name: "Import", Value: "import"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "file",
+			Description: "Archive file name to import (from the list)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "keep_hours",
+			Description: "How long the imported messages stay in the store (default: 24)",
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *MessageArchiveCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *MessageArchiveCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *MessageArchiveCommand) Handle(ctx *core.Context) error {
+	archive, ok := ctx.Config.MessageArchive()
+	if !ok {
+		return core.NewCommandError("Message archival is not configured (`message_archive.dir`)", true)
+	}
+	extractor := core.NewOptionExtractor(core.GetSubCommandOptions(ctx.Interaction))
+	files, err := guildArchives(archive.Dir, ctx.GuildID)
+	if err != nil {
+		return core.NewCommandError(fmt.Sprintf("Failed to list archives: %v", err), true)
+	}
+	if extractor.String("action") != "import" {
+		return cmd.list(ctx, files)
+	}
+
+	name := strings.TrimSpace(extractor.String("file"))
+	if name == "" {
+		return core.NewCommandError("Option 'file' is required to import", true)
+	}
+	// Só arquivos desta guild, pelo nome exato listado (nada de caminhos)
+	if !slices.Contains(files, name) {
+		return core.NewCommandError(fmt.Sprintf("`%s` is not an archive of this server", name), true)
+	}
+	keepFor := time.Duration(extractor.Int("keep_hours")) * time.Hour
+	n, err := cmd.adminCommands.store.ImportMessageArchive(ctx.Ctx, filepath.Join(archive.Dir, name), keepFor)
+	if err != nil {
+		return core.NewCommandError(fmt.Sprintf("Import stopped after %d messages: %v", n, err), true)
+	}
+	if keepFor <= 0 {
+		keepFor = storage.DefaultArchiveImportTTL
+	}
+	log.Info().Applicationf("🧾 Audit: message archive %s imported by user=%s guild=%s (%d messages, kept for %s)", name, ctx.UserID, ctx.GuildID, n, keepFor)
+	return ctx.Respond().Success(ctx.Interaction, fmt.Sprintf("Imported %d messages from `%s`; they stay available for %s", n, name, keepFor))
+}
+
+func (cmd *MessageArchiveCommand) list(ctx *core.Context, files []string) error {
+	if len(files) == 0 {
+		return ctx.Respond().Info(ctx.Interaction, "No message archives for this server yet.")
+	}
+	// Mais recentes primeiro (o nome termina no intervalo de datas)
+	slices.Reverse(files)
+	shown := files[:min(len(files), maxListedArchives)]
+	var b strings.Builder
+	for _, f := range shown {
+		fmt.Fprintf(&b, "`%s`\n", f)
+	}
+	if len(files) > len(shown) {
+		fmt.Fprintf(&b, "…and %d older\n", len(files)-len(shown))
+	}
+	return ctx.Respond().WithTitle("🗄️ Message archives").WithEmbed().Info(ctx.Interaction, b.String())
+}
+
+// guildArchives returns the archive file names of a guild in dir, sorted by name (oldest first).
+func guildArchives(dir, guildID string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "messages_"+guildID+"_*.jsonl.gz"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() {
+			names = append(names, filepath.Base(m))
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
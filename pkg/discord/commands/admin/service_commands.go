@@ -3,18 +3,28 @@ package admin
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
 	"github.com/small-frappuccino/discordcore/pkg/service"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
 	"github.com/small-frappuccino/discordcore/pkg/theme"
+	"github.com/small-frappuccino/discordcore/pkg/util"
 )
 
 // AdminCommands provides administrative commands for service management
 type AdminCommands struct {
-	serviceManager *service.ServiceManager
+	serviceManager  *service.ServiceManager
+	store           *storage.Store
+	activityReports *logging.ActivityReportService
+	bulkRoles       bulkRoleRuns
+	router          *core.CommandRouter // para as métricas de interações em execução
 }
 
 // NewAdminCommands creates a new admin commands handler
@@ -24,25 +34,74 @@ func NewAdminCommands(serviceManager *service.ServiceManager) *AdminCommands {
 	}
 }
 
+// SetStore wires the SQLite store used by data-backed admin commands (e.g. /export-logs).
+func (ac *AdminCommands) SetStore(store *storage.Store) {
+	ac.store = store
+}
+
+// SetActivityReports wires the service used by /admin activity-report to post reports on demand.
+func (ac *AdminCommands) SetActivityReports(svc *logging.ActivityReportService) {
+	ac.activityReports = svc
+}
+
 // RegisterCommands registers all admin commands with the router
 func (ac *AdminCommands) RegisterCommands(router *core.CommandRouter) {
+	ac.router = router
+
 	// Main admin command with subcommands
 	adminCmd := core.NewGroupCommand(
 		"admin",
 		"Administrative commands for bot management",
-		core.NewResponder(router.GetSession()),
-		core.NewPermissionChecker(router.GetSession(), router.GetConfigManager()),
+		core.NewResponder(router.GetSessionAPI()),
+		core.NewPermissionChecker(router.GetSessionAPI(), router.GetConfigManager()),
 	)
 
-	// Service management subcommands
+	// Service management subcommands (diagnostics: ephemeral by default, see router.RegisterCommand below)
 	adminCmd.AddSubCommand(ac.createMetricsCommand())
 	adminCmd.AddSubCommand(ac.createMetricsWatchCommand())
 	adminCmd.AddSubCommand(ac.createServiceStatusCommand())
 	adminCmd.AddSubCommand(ac.createServiceListCommand())
 	adminCmd.AddSubCommand(ac.createServiceRestartCommand())
 	adminCmd.AddSubCommand(ac.createHealthCheckCommand())
-
-	router.RegisterCommand(adminCmd)
+	adminCmd.AddSubCommand(ac.createInactiveGuildsCommand())
+	adminCmd.AddSubCommand(ac.createBulkRoleCommand())
+	adminCmd.AddSubCommand(ac.createBulkRoleCancelCommand())
+	if ac.store != nil {
+		// Stats are fine to share in the channel
+		adminCmd.AddSubCommand(ac.createEmojiStatsCommand(), core.PublicByDefault())
+		adminCmd.AddSubCommand(ac.createActivityReportCommand(), core.PublicByDefault(), core.Cooldown(time.Minute))
+		adminCmd.AddSubCommand(ac.createMessageArchiveCommand())
+		adminCmd.AddSubCommand(ac.createAutomodLogCommand())
+	}
+
+	// Admin commands need Manage Server (or one of the guild's admin_roles) on top of RequiresPermissions
+	adminOnly := core.RequirePermissions(discordgo.PermissionManageGuild)
+	router.RegisterCommand(adminCmd, core.EphemeralByDefault(), adminOnly)
+	router.RegisterCommand(NewLogsCommand(), core.EphemeralByDefault(), adminOnly)
+
+	// Data export and automod feedback (require the store)
+	if ac.store != nil {
+		// Exports scan the whole store; one per user per minute
+		router.RegisterCommand(NewExportLogsCommand(ac.store), core.EphemeralByDefault(), adminOnly, core.Cooldown(time.Minute))
+		router.RegisterCommand(NewUsageCommand(ac.store), core.PublicByDefault(), adminOnly)
+		router.RegisterCommand(NewAutomodStatsCommand(ac.store), core.PublicByDefault(), adminOnly)
+		router.RegisterComponent(logging.AutomodFalsePositivePrefix, NewAutomodFeedbackHandler(ac.store))
+	}
+}
+
+// serviceNameChoices lists the registered services matching what was typed in the focused
+// "service" option, sorted by name
+func (ac *AdminCommands) serviceNameChoices(focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice {
+	if focused.Name != "service" {
+		return nil
+	}
+	var names []string
+	for name := range ac.serviceManager.GetAllServices() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	utils := core.AutocompleteUtils{}
+	return utils.FilterChoices(utils.CreateChoicesFromStrings(names), focused.StringValue())
 }
 
 // createServiceStatusCommand creates the service status subcommand
@@ -125,7 +184,7 @@ func (cmd *MetricsCommand) Handle(ctx *core.Context) error {
 		summary = "No metrics available"
 	}
 
-	builder := core.NewResponseBuilder(ctx.Session).
+	builder := ctx.Respond().
 		WithEmbed().
 		WithTitle("📊 Metrics").
 		WithColor(theme.Info()).
@@ -147,7 +206,7 @@ func (cmd *MetricsCommand) formatMetrics(ctx *core.Context) string {
 		var ms []string
 		if len(stats.CustomMetrics) > 0 {
 			for k, v := range stats.CustomMetrics {
-				ms = append(ms, fmt.Sprintf("• %s: %v", k, v))
+				ms = append(ms, fmt.Sprintf("• %s: %s", k, util.FormatNumber(v, guildLocale(ctx))))
 			}
 		} else {
 			ms = append(ms, "• No custom metrics available")
@@ -169,9 +228,33 @@ func (cmd *MetricsCommand) formatMetrics(ctx *core.Context) string {
 		)
 	}
 
+	if r := cmd.adminCommands.router; r != nil {
+		limit := "unlimited"
+		if n := r.MaxInFlight(); n > 0 {
+			limit = util.FormatInt(int64(n), guildLocale(ctx))
+		}
+		lines = append(lines,
+			"**Commands**",
+			fmt.Sprintf("• in_flight: %s (limit: %s)", util.FormatInt(int64(r.InFlight()), guildLocale(ctx)), limit),
+			fmt.Sprintf("• rejected_busy: %s", util.FormatInt(r.RejectedInteractions(), guildLocale(ctx))),
+		)
+	}
+	lines = append(lines,
+		"**Stability**",
+		fmt.Sprintf("• recovered_panics: %s", util.FormatInt(errutil.RecoveredPanics(), guildLocale(ctx))),
+	)
+
 	return strings.TrimSpace(strings.Join(lines, "\n"))
 }
 
+// guildLocale returns the locale configured for the command's guild (util.DefaultLocale if unset).
+func guildLocale(ctx *core.Context) string {
+	if ctx != nil && ctx.GuildConfig != nil && ctx.GuildConfig.Locale != "" {
+		return ctx.GuildConfig.Locale
+	}
+	return util.DefaultLocale
+}
+
 // MetricsWatchCommand streams metrics updates to the current channel for a period
 type MetricsWatchCommand struct {
 	adminCommands *AdminCommands
@@ -222,7 +305,7 @@ func (cmd *MetricsWatchCommand) Handle(ctx *core.Context) error {
 	}
 
 	// Acknowledge start
-	if err := core.NewResponseBuilder(ctx.Session).Ephemeral().Success(ctx.Interaction, fmt.Sprintf("Starting metrics watch: interval=%ds, duration=%ds", intervalSec, durationSec)); err != nil {
+	if err := ctx.Respond().Success(ctx.Interaction, fmt.Sprintf("Starting metrics watch: interval=%ds, duration=%ds", intervalSec, durationSec)); err != nil {
 		return err
 	}
 
@@ -237,7 +320,7 @@ func (cmd *MetricsWatchCommand) Handle(ctx *core.Context) error {
 			Color:       theme.Info(),
 			Timestamp:   time.Now().Format(time.RFC3339),
 		}
-		return ctx.Session.ChannelMessageSendEmbed(channelID, embed)
+		return discord.ClientFor(ctx.Session).SendEmbed(context.Background(), channelID, embed)
 	}
 
 	// Send initial message
@@ -287,14 +370,20 @@ func (cmd *ServiceStatusCommand) Description() string {
 func (cmd *ServiceStatusCommand) Options() []*discordgo.ApplicationCommandOption {
 	return []*discordgo.ApplicationCommandOption{
 		{
-			Type:        discordgo.ApplicationCommandOptionString,
-			Name:        "service",
-			Description: "Name of the service to check",
-			Required:    true,
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "service",
+			Description:  "Name of the service to check",
+			Required:     true,
+			Autocomplete: true,
 		},
 	}
 }
 
+// Autocomplete suggests the registered service names
+func (cmd *ServiceStatusCommand) Autocomplete(ctx *core.Context, focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice {
+	return cmd.adminCommands.serviceNameChoices(focused)
+}
+
 func (cmd *ServiceStatusCommand) RequiresGuild() bool {
 	return true
 }
@@ -315,7 +404,7 @@ func (cmd *ServiceStatusCommand) Handle(ctx *core.Context) error {
 	}
 
 	// Perform health check
-	healthCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	healthCtx, cancel := context.WithTimeout(ctx.Ctx, 10*time.Second)
 	defer cancel()
 
 	health := info.Service.HealthCheck(healthCtx)
@@ -382,7 +471,7 @@ func (cmd *ServiceStatusCommand) Handle(ctx *core.Context) error {
 	if len(stats.CustomMetrics) > 0 {
 		var metrics []string
 		for k, v := range stats.CustomMetrics {
-			metrics = append(metrics, fmt.Sprintf("%s: %v", k, v))
+			metrics = append(metrics, fmt.Sprintf("%s: %s", k, util.FormatNumber(v, guildLocale(ctx))))
 		}
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:   "Metrics",
@@ -391,7 +480,7 @@ func (cmd *ServiceStatusCommand) Handle(ctx *core.Context) error {
 		})
 	}
 
-	return core.NewResponder(ctx.Session).SendEmbed(ctx.Interaction, embed)
+	return ctx.Respond().Embed(ctx.Interaction, embed)
 }
 
 // ServiceListCommand lists all registered services
@@ -446,7 +535,7 @@ func (cmd *ServiceListCommand) Handle(ctx *core.Context) error {
 		})
 	}
 
-	return core.NewResponder(ctx.Session).SendEmbed(ctx.Interaction, embed)
+	return ctx.Respond().Embed(ctx.Interaction, embed)
 }
 
 // ServiceRestartCommand restarts a specific service
@@ -465,14 +554,20 @@ func (cmd *ServiceRestartCommand) Description() string {
 func (cmd *ServiceRestartCommand) Options() []*discordgo.ApplicationCommandOption {
 	return []*discordgo.ApplicationCommandOption{
 		{
-			Type:        discordgo.ApplicationCommandOptionString,
-			Name:        "service",
-			Description: "Name of the service to restart",
-			Required:    true,
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "service",
+			Description:  "Name of the service to restart",
+			Required:     true,
+			Autocomplete: true,
 		},
 	}
 }
 
+// Autocomplete suggests the registered service names
+func (cmd *ServiceRestartCommand) Autocomplete(ctx *core.Context, focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice {
+	return cmd.adminCommands.serviceNameChoices(focused)
+}
+
 func (cmd *ServiceRestartCommand) RequiresGuild() bool {
 	return true
 }
@@ -494,8 +589,8 @@ func (cmd *ServiceRestartCommand) Handle(ctx *core.Context) error {
 	}
 
 	// Send initial response
-	responder := core.NewResponder(ctx.Session)
-	if err := responder.Info(ctx.Interaction, fmt.Sprintf("🔄 Restarting service: %s", serviceName)); err != nil {
+	responder := core.NewResponder(ctx.API)
+	if err := ctx.Respond().Info(ctx.Interaction, fmt.Sprintf("🔄 Restarting service: %s", serviceName)); err != nil {
 		return err
 	}
 
@@ -540,6 +635,11 @@ func (cmd *HealthCheckCommand) RequiresPermissions() bool {
 }
 
 func (cmd *HealthCheckCommand) Handle(ctx *core.Context) error {
+	// Health checks may take longer than Discord's 3s window: acknowledge first, then edit the
+	// deferred response with the result
+	if err := ctx.Defer(); err != nil {
+		return err
+	}
 	services := cmd.adminCommands.serviceManager.GetAllServices()
 
 	healthyCount := 0
@@ -547,7 +647,7 @@ func (cmd *HealthCheckCommand) Handle(ctx *core.Context) error {
 	totalServices := len(services)
 
 	// Check health of all services
-	healthCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	healthCtx, cancel := context.WithTimeout(ctx.Ctx, 30*time.Second)
 	defer cancel()
 
 	for name, info := range services {
@@ -594,7 +694,7 @@ func (cmd *HealthCheckCommand) Handle(ctx *core.Context) error {
 		})
 	}
 
-	return core.NewResponder(ctx.Session).SendEmbed(ctx.Interaction, embed)
+	return ctx.EditResponse("", embed)
 }
 
 // SystemInfoCommand shows general system information
@@ -649,7 +749,7 @@ func (cmd *SystemInfoCommand) Handle(ctx *core.Context) error {
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	return core.NewResponder(ctx.Session).SendEmbed(ctx.Interaction, embed)
+	return ctx.Respond().Embed(ctx.Interaction, embed)
 }
 
 // Helper methods
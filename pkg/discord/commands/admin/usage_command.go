@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+	"github.com/small-frappuccino/discordcore/pkg/util"
+)
+
+// usageMaxLimit bounds the ranking so the embed description stays short.
+const usageMaxLimit = 25
+
+// UsageCommand shows the most used slash commands of the guild (command_usage counters).
+type UsageCommand struct {
+	store *storage.Store
+}
+
+// NewUsageCommand creates the /usage command backed by the given store.
+func NewUsageCommand(store *storage.Store) *UsageCommand {
+	return &UsageCommand{store: store}
+}
+
+func (cmd *UsageCommand) Name() string {
+	return "usage"
+}
+
+func (cmd *UsageCommand) Description() string {
+	return "Show which slash commands are used the most in this server"
+}
+
+func (cmd *UsageCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "since",
+			Description: "Start date (YYYY-MM-DD) or duration back from now (default: 720h)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "limit",
+			Description: fmt.Sprintf("Number of entries (default: 10, max: %d)", usageMaxLimit),
+			Required:    false,
+		},
+	}
+}
+
+func (cmd *UsageCommand) RequiresGuild() bool {
+	return true
+}
+
+func (cmd *UsageCommand) RequiresPermissions() bool {
+	return true
+}
+
+func (cmd *UsageCommand) Handle(ctx *core.Context) error {
+	if cmd.store == nil {
+		return core.NewCommandError("Message store is not available", true)
+	}
+
+	extractor := core.NewOptionExtractor(ctx.Interaction.ApplicationCommandData().Options)
+	rawSince := extractor.String("since")
+	if strings.TrimSpace(rawSince) == "" {
+		rawSince = "720h"
+	}
+	since, err := parseSince(rawSince, time.Now())
+	if err != nil {
+		return core.NewCommandError(err.Error(), true)
+	}
+	limit := int(extractor.Int("limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > usageMaxLimit {
+		limit = usageMaxLimit
+	}
+
+	usage, err := cmd.store.CommandUsageContext(ctx.Ctx, ctx.GuildID, since)
+	if err != nil {
+		return fmt.Errorf("load command usage: %w", err)
+	}
+
+	locale := guildLocale(ctx)
+	var total int64
+	for _, u := range usage {
+		total += u.Count
+	}
+	description := "No command usage recorded for this period."
+	if len(usage) > 0 {
+		shown := usage
+		if len(shown) > limit {
+			shown = shown[:limit]
+		}
+		lines := make([]string, 0, len(shown))
+		for i, u := range shown {
+			line := fmt.Sprintf("%d. `/%s` — %s", i+1, u.Command, util.FormatInt(u.Count, locale))
+			if u.Errors > 0 {
+				line += fmt.Sprintf(" (%s failed)", util.FormatInt(u.Errors, locale))
+			}
+			lines = append(lines, line)
+		}
+		description = strings.Join(lines, "\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📊 Command Usage",
+		Description: description,
+		Color:       0x5865F2,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Invocations", Value: util.FormatInt(total, locale), Inline: true},
+			{Name: "Distinct commands", Value: util.FormatInt(int64(len(usage)), locale), Inline: true},
+		},
+		Footer:    &discordgo.MessageEmbedFooter{Text: "Since " + since.In(util.LoadTimezone(guildTimezone(ctx))).Format("2006-01-02") + " (" + util.RelativeTime(since, time.Now()) + ")"},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	return ctx.Respond().Embed(ctx.Interaction, embed)
+}
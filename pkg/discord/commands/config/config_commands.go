@@ -23,17 +23,17 @@ func NewConfigCommands(configManager *files.ConfigManager) *ConfigCommands {
 // RegisterCommands registers the /config command group and optional simple commands in the provided router.
 func (cc *ConfigCommands) RegisterCommands(router *core.CommandRouter) {
 	// Build /config group with permission checks
-	responder := core.NewResponder(router.GetSession())
-	checker := core.NewPermissionChecker(router.GetSession(), router.GetConfigManager())
+	responder := core.NewResponder(router.GetSessionAPI())
+	checker := core.NewPermissionChecker(router.GetSessionAPI(), router.GetConfigManager())
 	group := core.NewGroupCommand("config", "Manage server configuration", responder, checker)
 
-	// Attach subcommands
+	// Attach subcommands (configuration output stays with the invoker)
 	group.AddSubCommand(NewConfigSetSubCommand(cc.configManager))
 	group.AddSubCommand(NewConfigGetSubCommand(cc.configManager))
 	group.AddSubCommand(NewConfigListSubCommand(cc.configManager))
 
 	// Register the group
-	router.RegisterCommand(group)
+	router.RegisterCommand(group, core.EphemeralByDefault())
 
 	// Optionally register simple commands (useful for quick health checks of the routing stack)
 	router.RegisterCommand(NewPingCommand())
@@ -56,7 +56,7 @@ func (c *pingCommand) Options() []*discordgo.ApplicationCommandOption {
 func (c *pingCommand) RequiresGuild() bool       { return false }
 func (c *pingCommand) RequiresPermissions() bool { return false }
 func (c *pingCommand) Handle(ctx *core.Context) error {
-	return core.NewResponder(ctx.Session).Success(ctx.Interaction, "🏓 Pong!")
+	return ctx.Respond().Success(ctx.Interaction, "🏓 Pong!")
 }
 
 type echoCommand struct{}
@@ -92,7 +92,7 @@ func (c *echoCommand) Handle(ctx *core.Context) error {
 	}
 	ephemeral := extractor.Bool("ephemeral")
 
-	builder := core.NewResponseBuilder(ctx.Session)
+	builder := ctx.Respond()
 	if ephemeral {
 		builder = builder.Ephemeral()
 	}
@@ -173,7 +173,7 @@ func (c *ConfigSetSubCommand) Handle(ctx *core.Context) error {
 		return core.NewCommandError("Failed to save configuration", true)
 	}
 
-	return core.NewResponder(ctx.Session).Success(ctx.Interaction, fmt.Sprintf("Configuration `%s` set to `%s`", key, value))
+	return ctx.Respond().Success(ctx.Interaction, fmt.Sprintf("Configuration `%s` set to `%s`", key, value))
 }
 
 // ConfigGetSubCommand - subcommand to get configuration values
@@ -204,7 +204,7 @@ func (c *ConfigGetSubCommand) Handle(ctx *core.Context) error {
 	b.WriteString(fmt.Sprintf("Automod Channel: %s\n", emptyToDash(ctx.GuildConfig.AutomodLogChannelID)))
 	b.WriteString(fmt.Sprintf("Allowed Roles: %d configured\n", len(ctx.GuildConfig.AllowedRoles)))
 
-	builder := core.NewResponseBuilder(ctx.Session).
+	builder := ctx.Respond().
 		WithEmbed().
 		WithTitle("Server Configuration").
 		WithColor(0x0099FF)
@@ -240,10 +240,9 @@ func (c *ConfigListSubCommand) Handle(ctx *core.Context) error {
 		"Use `/config set <key> <value>` to modify these settings.",
 	}
 
-	builder := core.NewResponseBuilder(ctx.Session).
+	builder := ctx.Respond().
 		WithEmbed().
-		WithTitle("Configuration Options").
-		Ephemeral()
+		WithTitle("Configuration Options")
 
 	return builder.Info(ctx.Interaction, strings.Join(options, "\n"))
 }
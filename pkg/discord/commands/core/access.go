@@ -0,0 +1,90 @@
+package core
+
+import (
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// PermissionDeniedMessage é a resposta (ephemeral) a quem invoca um comando sem permissão.
+const PermissionDeniedMessage = "You do not have permission to use this command"
+
+// AccessRule restringe quem pode invocar um comando ou subcomando, declarada no registro com
+// RequirePermissions e RequireRoles. O router a verifica antes do handler, além de
+// RequiresPermissions.
+//
+// Um membro passa se tiver Administrator, todos os bits de Permissions (calculados pelo Discord
+// para o canal da interação), uma das Roles ou uma das admin_roles da guild. Operadores do bot
+// passam sempre (com log de auditoria). Sem membro na interação (DM), o acesso é negado.
+type AccessRule struct {
+	Permissions int64    // Bits de permissão exigidos (todos); 0: nenhum
+	Roles       []string // Roles que liberam o comando (qualquer uma)
+}
+
+// Restricted informa se a regra restringe o acesso.
+func (r AccessRule) Restricted() bool {
+	return r.Permissions != 0 || len(r.Roles) > 0
+}
+
+// Allows informa se member passa na regra; adminRoles são as roles de admin da guild
+// (GuildConfig.AdminRoles), que liberam qualquer comando restrito.
+func (r AccessRule) Allows(member *discordgo.Member, adminRoles []string) bool {
+	if !r.Restricted() {
+		return true
+	}
+	if member == nil {
+		return false
+	}
+	if member.Permissions&discordgo.PermissionAdministrator != 0 {
+		return true
+	}
+	if r.Permissions != 0 && member.Permissions&r.Permissions == r.Permissions {
+		return true
+	}
+	for _, role := range member.Roles {
+		if slices.Contains(r.Roles, role) || slices.Contains(adminRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermissions exige os bits de permissão do Discord (ex.: discordgo.PermissionManageGuild)
+// para invocar o comando; pode ser combinada com RequireRoles (basta uma das duas).
+func RequirePermissions(bits int64) RegisterOption {
+	return func(r *registration) {
+		r.access.Permissions |= bits
+	}
+}
+
+// RequireRoles libera o comando só para membros com uma das roles (IDs); pode ser combinada com
+// RequirePermissions (basta uma das duas).
+func RequireRoles(roleIDs ...string) RegisterOption {
+	return func(r *registration) {
+		r.access.Roles = append(r.access.Roles, roleIDs...)
+	}
+}
+
+// authorize verifica rule para quem invocou a interação de ctx (path é o comando, para o log).
+func authorize(ctx *Context, rule AccessRule, checker *PermissionChecker, path string) bool {
+	if !rule.Restricted() {
+		return true
+	}
+	if ctx.GuildID != "" && rule.Allows(ctx.Interaction.Member, adminRoles(ctx.GuildConfig)) {
+		return true
+	}
+	if ctx.GuildID != "" && checker.OperatorOverride(ctx.GuildID, ctx.UserID, "access rule of /"+path) {
+		return true
+	}
+	ctx.Logger.Warn().Applicationf("Access denied: userID=%s, guildID=%s, command=/%s", ctx.UserID, ctx.GuildID, path)
+	return false
+}
+
+// adminRoles retorna as roles de admin configuradas na guild (nil sem configuração).
+func adminRoles(gc *files.GuildConfig) []string {
+	if gc == nil {
+		return nil
+	}
+	return gc.AdminRoles
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestAccessRuleAllows(t *testing.T) {
+	manageGuild := AccessRule{Permissions: discordgo.PermissionManageGuild}
+	modRole := AccessRule{Roles: []string{"mod"}}
+	for _, tc := range []struct {
+		name       string
+		rule       AccessRule
+		member     *discordgo.Member
+		adminRoles []string
+		want       bool
+	}{
+		{"unrestricted without member", AccessRule{}, nil, nil, true},
+		{"nil member", manageGuild, nil, nil, false},
+		{"has permission", manageGuild, &discordgo.Member{Permissions: discordgo.PermissionManageGuild | discordgo.PermissionSendMessages}, nil, true},
+		{"missing permission", manageGuild, &discordgo.Member{Permissions: discordgo.PermissionSendMessages}, nil, false},
+		{"needs every bit", AccessRule{Permissions: discordgo.PermissionManageGuild | discordgo.PermissionBanMembers}, &discordgo.Member{Permissions: discordgo.PermissionManageGuild}, nil, false},
+		{"administrator bit", manageGuild, &discordgo.Member{Permissions: discordgo.PermissionAdministrator}, nil, true},
+		{"administrator bit for role rule", modRole, &discordgo.Member{Permissions: discordgo.PermissionAdministrator}, nil, true},
+		{"has role", modRole, &discordgo.Member{Roles: []string{"other", "mod"}}, nil, true},
+		{"missing role", modRole, &discordgo.Member{Roles: []string{"other"}}, nil, false},
+		{"admin role", manageGuild, &discordgo.Member{Roles: []string{"staff"}}, []string{"staff"}, true},
+		{"admin roles of another member", manageGuild, &discordgo.Member{Roles: []string{"other"}}, []string{"staff"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.rule.Allows(tc.member, tc.adminRoles); got != tc.want {
+				t.Errorf("Allows = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	const operatorID = "111111111111111111"
+	t.Setenv(files.BotOperatorsEnv, operatorID)
+	checker := NewPermissionChecker(nil, files.NewConfigManagerWithPath(filepath.Join(t.TempDir(), "settings.json")))
+	rule := AccessRule{Permissions: discordgo.PermissionManageGuild}
+
+	for _, tc := range []struct {
+		name    string
+		guildID string
+		userID  string
+		member  *discordgo.Member
+		config  *files.GuildConfig
+		rule    AccessRule
+		want    bool
+	}{
+		{"unrestricted", "g1", "u1", nil, nil, AccessRule{}, true},
+		{"allowed member", "g1", "u1", &discordgo.Member{Permissions: discordgo.PermissionManageGuild}, nil, rule, true},
+		{"denied member", "g1", "u1", &discordgo.Member{}, nil, rule, false},
+		{"admin role from guild config", "g1", "u1", &discordgo.Member{Roles: []string{"staff"}}, &files.GuildConfig{AdminRoles: []string{"staff"}}, rule, true},
+		{"missing member", "g1", "u1", nil, nil, rule, false},
+		{"operator override", "g1", operatorID, &discordgo.Member{}, nil, rule, true},
+		{"operator with missing member", "g1", operatorID, nil, nil, rule, true},
+		{"operator outside a guild", "", operatorID, nil, nil, rule, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &Context{
+				Interaction: &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{GuildID: tc.guildID, Member: tc.member}},
+				GuildID:     tc.guildID,
+				UserID:      tc.userID,
+				GuildConfig: tc.config,
+			}
+			if got := authorize(ctx, tc.rule, checker, "admin"); got != tc.want {
+				t.Errorf("authorize = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
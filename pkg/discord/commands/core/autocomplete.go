@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxAutocompleteChoices é o limite do Discord de sugestões por resposta de autocomplete.
+const MaxAutocompleteChoices = 25
+
+// AutocompleteFunc sugere valores para a opção com foco (focused.Name é a opção e
+// focused.StringValue() o que o usuário já digitou). Sugestões além de MaxAutocompleteChoices
+// são descartadas na resposta.
+//
+// Implementa AutocompleteHandler, para uso com CommandRouter.RegisterAutocomplete.
+type AutocompleteFunc func(ctx *Context, focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice
+
+// HandleAutocomplete localiza a opção com foco na interação (dentro de grupos e subcomandos) e chama f.
+func (f AutocompleteFunc) HandleAutocomplete(ctx *Context, focusedOption string) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+	focused, ok := HasFocusedOption(ctx.Interaction.ApplicationCommandData().Options)
+	if !ok || focused.Name != focusedOption {
+		return nil, fmt.Errorf("focused option %q not found", focusedOption)
+	}
+	return f(ctx, focused), nil
+}
+
+// Autocompleter é implementado por comandos e subcomandos que respondem ao autocomplete das
+// próprias opções (marcadas com Autocomplete: true). O router o usa quando não há um handler
+// registrado com RegisterAutocomplete para o comando; GroupCommand repassa ao subcomando invocado.
+type Autocompleter interface {
+	Autocomplete(ctx *Context, focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice
+}
+
+// Autocomplete repassa o autocomplete ao subcomando invocado, se ele implementar Autocompleter.
+func (gc *GroupCommand) Autocomplete(ctx *Context, focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice {
+	target := gc
+	if groupName := GetSubCommandGroupName(ctx.Interaction); groupName != "" {
+		if target = gc.groups[groupName]; target == nil {
+			return nil
+		}
+	}
+	if ac, ok := target.subcommands[GetSubCommandName(ctx.Interaction)].(Autocompleter); ok {
+		return ac.Autocomplete(ctx, focused)
+	}
+	return nil
+}
+
+// WithAutocomplete define o autocomplete das opções do comando e o retorna, para encadear
+// (ex.: core.NewSubCommand(...).WithAutocomplete(fn)).
+func (sc *SimpleCommand) WithAutocomplete(fn AutocompleteFunc) *SimpleCommand {
+	sc.autocomplete = fn
+	return sc
+}
+
+// Autocomplete chama a função definida em WithAutocomplete (sem ela, não há sugestões).
+func (sc *SimpleCommand) Autocomplete(ctx *Context, focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice {
+	if sc.autocomplete == nil {
+		return nil
+	}
+	return sc.autocomplete(ctx, focused)
+}
+
+// autocompleteHandler retorna o handler de autocomplete de commandName: o registrado com
+// RegisterAutocomplete ou, sem ele, o próprio comando se implementar Autocompleter.
+func (cr *CommandRouter) autocompleteHandler(commandName string) (AutocompleteHandler, bool) {
+	if handler, ok := cr.autocompleteMap[commandName]; ok {
+		return handler, true
+	}
+	if cmd, ok := cr.registry.GetCommand(commandName); ok {
+		if ac, ok := cmd.(Autocompleter); ok {
+			return AutocompleteFunc(ac.Autocomplete), true
+		}
+	}
+	return nil, false
+}
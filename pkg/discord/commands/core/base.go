@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
@@ -8,13 +10,14 @@ import (
 
 // ContextBuilder cria contextos para execução de comandos
 type ContextBuilder struct {
-	session       *discordgo.Session
+	session       SessionAPI
 	configManager *files.ConfigManager
 	checker       *PermissionChecker
+	paginator     *Paginator
 }
 
 // NewContextBuilder cria um novo construtor de contexto
-func NewContextBuilder(session *discordgo.Session, configManager *files.ConfigManager, checker *PermissionChecker) *ContextBuilder {
+func NewContextBuilder(session SessionAPI, configManager *files.ConfigManager, checker *PermissionChecker) *ContextBuilder {
 	return &ContextBuilder{
 		session:       session,
 		configManager: configManager,
@@ -29,37 +32,50 @@ func (cb *ContextBuilder) BuildContext(i *discordgo.InteractionCreate) *Context
 
 	var guildConfig *files.GuildConfig
 	if guildID != "" {
-		guildConfig = cb.configManager.GuildConfig(guildID)
+		if gc, ok := cb.configManager.GuildConfig(guildID); ok {
+			guildConfig = &gc
+		}
 	}
 
-	isOwner := false
+	isOwner, override := false, false
 	if guildID != "" {
 		isOwner = cb.isGuildOwner(guildID, userID)
+		// Operadores do bot contam como dono; o uso é registrado pelo router ao executar o comando
+		if !isOwner && cb.configManager.IsBotOperator(userID) {
+			isOwner, override = true, true
+		}
 	}
 
 	logger := log.GlobalLogger
 
 	return &Context{
-		Session:     cb.session,
-		Interaction: i,
-		Config:      cb.configManager,
-		Logger:      logger,
-		GuildID:     guildID,
-		UserID:      userID,
-		IsOwner:     isOwner,
-		GuildConfig: guildConfig,
+		Ctx:              context.Background(),
+		Session:          rawSession(cb.session),
+		API:              cb.session,
+		Interaction:      i,
+		Config:           cb.configManager,
+		Logger:           logger,
+		GuildID:          guildID,
+		UserID:           userID,
+		IsOwner:          isOwner,
+		OperatorOverride: override,
+		GuildConfig:      guildConfig,
+		Paginator:        cb.paginator,
 	}
 }
 
 // isGuildOwner verifica se o usuário é o dono do servidor
 func (cb *ContextBuilder) isGuildOwner(guildID, userID string) bool {
 	// Preferir cache do state para evitar chamada REST quando possível
-	if cb.session != nil && cb.session.State != nil {
-		if g, _ := cb.session.State.Guild(guildID); g != nil {
+	if state := stateOf(cb.session); state != nil {
+		if g, _ := state.Guild(guildID); g != nil {
 			return g.OwnerID == userID
 		}
 	}
 	// Fallback para REST apenas se necessário
+	if cb.session == nil {
+		return false
+	}
 	guild, err := cb.session.Guild(guildID)
 	if err != nil || guild == nil {
 		return false
@@ -77,24 +93,46 @@ func extractUserID(i *discordgo.InteractionCreate) string {
 	return ""
 }
 
-// GetSubCommandName extrai o nome do subcomando da interação
-func GetSubCommandName(i *discordgo.InteractionCreate) string {
+// GetSubCommandGroupName extrai o nome do grupo de subcomandos da interação ("" sem grupo)
+func GetSubCommandGroupName(i *discordgo.InteractionCreate) string {
 	options := i.ApplicationCommandData().Options
-	if len(options) > 0 && options[0].Type == discordgo.ApplicationCommandOptionSubCommand {
+	if len(options) > 0 && options[0].Type == discordgo.ApplicationCommandOptionSubCommandGroup {
 		return options[0].Name
 	}
 	return ""
 }
 
-// GetSubCommandOptions extrai as opções do subcomando da interação
+// GetSubCommandName extrai o nome do subcomando da interação, dentro do grupo quando houver
+func GetSubCommandName(i *discordgo.InteractionCreate) string {
+	if sub := invokedSubCommand(i.ApplicationCommandData().Options); sub != nil {
+		return sub.Name
+	}
+	return ""
+}
+
+// GetSubCommandOptions extrai as opções do subcomando da interação, dentro do grupo quando houver
 func GetSubCommandOptions(i *discordgo.InteractionCreate) []*discordgo.ApplicationCommandInteractionDataOption {
 	options := i.ApplicationCommandData().Options
-	if len(options) > 0 && options[0].Type == discordgo.ApplicationCommandOptionSubCommand {
-		return options[0].Options
+	if sub := invokedSubCommand(options); sub != nil {
+		return sub.Options
 	}
 	return options // Retorna as opções diretas se não for subcomando
 }
 
+// invokedSubCommand retorna a opção do subcomando invocado, descendo por um grupo se houver.
+func invokedSubCommand(options []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	if len(options) == 0 {
+		return nil
+	}
+	switch options[0].Type {
+	case discordgo.ApplicationCommandOptionSubCommand:
+		return options[0]
+	case discordgo.ApplicationCommandOptionSubCommandGroup:
+		return invokedSubCommand(options[0].Options)
+	}
+	return nil
+}
+
 // CommandLogEntry cria uma entrada de log padronizada para comandos
 func CommandLogEntry(i *discordgo.InteractionCreate, command string, userID string) *log.Logger {
 	return log.GlobalLogger
@@ -128,8 +166,8 @@ func HasFocusedOption(options []*discordgo.ApplicationCommandInteractionDataOpti
 		if opt.Focused {
 			return opt, true
 		}
-		// Verifica recursivamente em subcomandos
-		if opt.Type == discordgo.ApplicationCommandOptionSubCommand && len(opt.Options) > 0 {
+		// Verifica recursivamente em subcomandos e grupos
+		if (opt.Type == discordgo.ApplicationCommandOptionSubCommand || opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup) && len(opt.Options) > 0 {
 			if focused, found := HasFocusedOption(opt.Options); found {
 				return focused, true
 			}
@@ -138,16 +176,9 @@ func HasFocusedOption(options []*discordgo.ApplicationCommandInteractionDataOpti
 	return nil, false
 }
 
-// GetCommandPath retorna o caminho completo do comando (comando + subcomando se houver)
+// GetCommandPath retorna o caminho completo do comando (comando, grupo e subcomando se houver)
 func GetCommandPath(i *discordgo.InteractionCreate) string {
-	path := i.ApplicationCommandData().Name
-
-	subCmd := GetSubCommandName(i)
-	if subCmd != "" {
-		path += " " + subCmd
-	}
-
-	return path
+	return CommandPath(i)
 }
 
 // IsAutocompleteInteraction verifica se a interação é de autocomplete
@@ -155,6 +186,11 @@ func IsAutocompleteInteraction(i *discordgo.InteractionCreate) bool {
 	return i.Type == discordgo.InteractionApplicationCommandAutocomplete
 }
 
+// IsComponentInteraction verifica se a interação é de componente (botão, select)
+func IsComponentInteraction(i *discordgo.InteractionCreate) bool {
+	return i.Type == discordgo.InteractionMessageComponent
+}
+
 // IsSlashCommandInteraction verifica se a interação é de comando slash
 func IsSlashCommandInteraction(i *discordgo.InteractionCreate) bool {
 	return i.Type == discordgo.InteractionApplicationCommand
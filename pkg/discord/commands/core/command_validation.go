@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Limites do Discord para definições de slash commands.
+const (
+	maxCommandDescription = 100
+	maxCommandOptions     = 25
+	maxOptionChoices      = 25
+	maxChoiceName         = 100
+)
+
+// commandNamePattern é o formato aceito pelo Discord para nomes de comandos e opções de chat.
+var commandNamePattern = regexp.MustCompile(`^[-_\p{Ll}\p{Lo}\p{N}]{1,32}$`)
+
+// ValidateCommandDefinition verifica se cmd seria aceito pelo Discord (nomes, descrições, limites
+// de opções e escolhas, aninhamento de subcomandos e obrigatórias antes das opcionais) e retorna
+// todos os problemas encontrados, com o caminho da opção.
+func ValidateCommandDefinition(cmd *discordgo.ApplicationCommand) []error {
+	if cmd == nil {
+		return []error{fmt.Errorf("command is nil")}
+	}
+	var errs []error
+	errs = append(errs, validateNameAndDescription("/"+cmd.Name, cmd.Name, cmd.Description)...)
+	errs = append(errs, validateCommandOptions("/"+cmd.Name, cmd.Options, 0)...)
+	return errs
+}
+
+func validateNameAndDescription(where, name, description string) []error {
+	var errs []error
+	if !commandNamePattern.MatchString(name) {
+		errs = append(errs, fmt.Errorf("%s: invalid name %q (1-32 lowercase letters, digits, - or _)", where, name))
+	}
+	if n := utf8.RuneCountInString(description); n < 1 || n > maxCommandDescription {
+		errs = append(errs, fmt.Errorf("%s: description must have 1-%d characters (has %d)", where, maxCommandDescription, n))
+	}
+	return errs
+}
+
+// validateCommandOptions valida um nível de opções; depth 1 está dentro de um grupo ou subcomando.
+func validateCommandOptions(where string, options []*discordgo.ApplicationCommandOption, depth int) []error {
+	var errs []error
+	if len(options) > maxCommandOptions {
+		errs = append(errs, fmt.Errorf("%s: too many options (%d > %d)", where, len(options), maxCommandOptions))
+	}
+	seen := make(map[string]bool, len(options))
+	optional := false
+	for _, opt := range options {
+		if opt == nil {
+			errs = append(errs, fmt.Errorf("%s: nil option", where))
+			continue
+		}
+		at := where + " " + opt.Name
+		errs = append(errs, validateNameAndDescription(at, opt.Name, opt.Description)...)
+		if seen[opt.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate option name", at))
+		}
+		seen[opt.Name] = true
+
+		switch opt.Type {
+		case discordgo.ApplicationCommandOptionSubCommandGroup:
+			if depth > 0 {
+				errs = append(errs, fmt.Errorf("%s: subcommand groups can only be top-level options", at))
+			}
+			for _, sub := range opt.Options {
+				if sub != nil && sub.Type != discordgo.ApplicationCommandOptionSubCommand {
+					errs = append(errs, fmt.Errorf("%s %s: subcommand groups can only contain subcommands", at, sub.Name))
+				}
+			}
+			errs = append(errs, validateCommandOptions(at, opt.Options, depth+1)...)
+		case discordgo.ApplicationCommandOptionSubCommand:
+			if depth > 1 || strings.Count(at, " ") > 2 {
+				errs = append(errs, fmt.Errorf("%s: subcommands nested too deep", at))
+			}
+			for _, sub := range opt.Options {
+				if sub != nil && (sub.Type == discordgo.ApplicationCommandOptionSubCommand || sub.Type == discordgo.ApplicationCommandOptionSubCommandGroup) {
+					errs = append(errs, fmt.Errorf("%s %s: subcommands cannot contain subcommands", at, sub.Name))
+				}
+			}
+			errs = append(errs, validateCommandOptions(at, opt.Options, depth+1)...)
+		default:
+			if opt.Required && optional {
+				errs = append(errs, fmt.Errorf("%s: required options must come before optional ones", at))
+			}
+			optional = optional || !opt.Required
+			if len(opt.Choices) > maxOptionChoices {
+				errs = append(errs, fmt.Errorf("%s: too many choices (%d > %d)", at, len(opt.Choices), maxOptionChoices))
+			}
+			if len(opt.Choices) > 0 && opt.Autocomplete {
+				errs = append(errs, fmt.Errorf("%s: autocomplete cannot be combined with choices", at))
+			}
+			for _, c := range opt.Choices {
+				if n := utf8.RuneCountInString(c.Name); n < 1 || n > maxChoiceName {
+					errs = append(errs, fmt.Errorf("%s: choice name %q must have 1-%d characters", at, c.Name, maxChoiceName))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// CommandPaths retorna os caminhos invocáveis de cmd e dos seus grupos/subcomandos
+// (ex.: "admin", "admin emoji-stats"), usados para conferir GuildConfig.DisabledCommands.
+func CommandPaths(cmd *discordgo.ApplicationCommand) []string {
+	paths := []string{cmd.Name}
+	var walk func(prefix string, options []*discordgo.ApplicationCommandOption)
+	walk = func(prefix string, options []*discordgo.ApplicationCommandOption) {
+		for _, opt := range options {
+			if opt == nil {
+				continue
+			}
+			if opt.Type == discordgo.ApplicationCommandOptionSubCommand || opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+				path := prefix + " " + opt.Name
+				paths = append(paths, path)
+				walk(path, opt.Options)
+			}
+		}
+	}
+	walk(cmd.Name, cmd.Options)
+	return paths
+}
+
+// CommandDefinitions retorna as definições que a sincronização enviaria ao Discord para os
+// comandos do router disponíveis no ambiente env, sem chamar a API.
+func (cr *CommandRouter) CommandDefinitions(env string) []*discordgo.ApplicationCommand {
+	var out []*discordgo.ApplicationCommand
+	for _, cmd := range cr.registry.GetAllCommands() {
+		if AvailableIn(cmd, env) {
+			out = append(out, desiredCommand(cmd, nil))
+		}
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CooldownMessage é a resposta (ephemeral) a quem invoca um comando em cooldown; recebe o
+// comando e a espera restante.
+const CooldownMessage = "⏳ /%s is on cooldown for you. Try again in %s."
+
+// Cooldown limita cada usuário a uma invocação do comando (ou subcomando) a cada d. O router
+// registra a invocação antes do handler e responde com a espera restante a quem repetir antes.
+// O cooldown de um comando com subcomandos vale para todos eles juntos; um subcomando com
+// cooldown próprio (GroupCommand.AddSubCommand) é contado à parte.
+func Cooldown(d time.Duration) RegisterOption {
+	return func(r *registration) {
+		r.cooldown = d
+	}
+}
+
+// CooldownStore guarda as invocações em cooldown. O padrão é MemoryCooldownStore; implemente a
+// interface (ex.: sobre o store SQLite) para compartilhar cooldowns entre processos ou shards.
+type CooldownStore interface {
+	// Reserve registra uma invocação de key se o cooldown anterior já passou (ok=true); senão
+	// retorna a espera restante sem registrar nada.
+	Reserve(key string, cooldown time.Duration, now time.Time) (remaining time.Duration, ok bool, err error)
+}
+
+// cooldownSweepInterval é o intervalo mínimo entre as limpezas das entradas expiradas.
+const cooldownSweepInterval = time.Minute
+
+// MemoryCooldownStore é o CooldownStore em memória. Entradas expiradas são removidas a cada
+// cooldownSweepInterval, durante as próprias reservas, então a memória acompanha só os usuários
+// em cooldown.
+type MemoryCooldownStore struct {
+	mu        sync.Mutex
+	until     map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryCooldownStore cria um CooldownStore em memória.
+func NewMemoryCooldownStore() *MemoryCooldownStore {
+	return &MemoryCooldownStore{until: make(map[string]time.Time)}
+}
+
+func (m *MemoryCooldownStore) Reserve(key string, cooldown time.Duration, now time.Time) (time.Duration, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Sub(m.lastSweep) >= cooldownSweepInterval {
+		for k, until := range m.until {
+			if !until.After(now) {
+				delete(m.until, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	if until, ok := m.until[key]; ok && until.After(now) {
+		return until.Sub(now), false, nil
+	}
+	m.until[key] = now.Add(cooldown)
+	return 0, true, nil
+}
+
+// Len retorna quantas entradas o store mantém (incluindo expiradas ainda não limpas).
+func (m *MemoryCooldownStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.until)
+}
+
+// SetCooldownStore troca onde o router guarda os cooldowns (padrão: MemoryCooldownStore).
+// Deve ser chamado antes de o router receber interações.
+func (cr *CommandRouter) SetCooldownStore(store CooldownStore) {
+	if store == nil {
+		store = NewMemoryCooldownStore()
+	}
+	cr.cooldowns = store
+}
+
+// checkCooldown reserva a invocação de ctx no cooldown do comando e responde com a espera
+// restante quando o usuário ainda está em cooldown. Falhas do store liberam a invocação.
+func (cr *CommandRouter) checkCooldown(ctx *Context, cmd Command) bool {
+	i := ctx.Interaction
+	key := i.ApplicationCommandData().Name
+	cooldown := cr.registry.Cooldown(key)
+	if gc, ok := cmd.(*GroupCommand); ok {
+		if d := gc.subCommandCooldown(i); d > 0 {
+			key, cooldown = CommandPath(i), d
+		}
+	}
+	if cooldown <= 0 || ctx.UserID == "" {
+		return true
+	}
+
+	remaining, ok, err := cr.cooldowns.Reserve(key+"|"+ctx.UserID, cooldown, time.Now())
+	if err != nil {
+		ctx.Logger.Warn().Applicationf("Cooldown store failed for /%s: %v; allowing the invocation", key, err)
+		return true
+	}
+	if ok {
+		return true
+	}
+	ctx.Logger.Info().Applicationf("Command on cooldown: /%s, userID=%s, remaining=%s", key, ctx.UserID, remaining)
+	cr.responder.Ephemeral(i, fmt.Sprintf(CooldownMessage, key, formatCooldown(remaining)))
+	return false
+}
+
+// formatCooldown arredonda a espera para cima em segundos (ex.: "4s", "1m30s").
+func formatCooldown(d time.Duration) string {
+	if rem := d % time.Second; rem != 0 {
+		d += time.Second - rem
+	}
+	return max(d, time.Second).String()
+}
+
+// subCommandCooldown retorna o cooldown próprio do subcomando invocado (0 sem cooldown próprio).
+func (gc *GroupCommand) subCommandCooldown(i *discordgo.InteractionCreate) time.Duration {
+	target := gc
+	if groupName := GetSubCommandGroupName(i); groupName != "" {
+		if target = gc.groups[groupName]; target == nil {
+			return 0
+		}
+	}
+	return target.cooldown[GetSubCommandName(i)]
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Defer confirma a interação na hora (o Discord mostra "pensando...") para handlers que
+// demoram mais que os 3s da primeira resposta; o token continua válido por 15 minutos. A
+// visibilidade é a do comando (ctx.Ephemeral) e vale para a resposta final: depois do Defer,
+// responda com EditResponse ou FollowUp, não com ctx.Respond(). Chamadas repetidas não fazem nada.
+//
+// Se o handler retornar erro depois do Defer, o router edita a resposta adiada com a mensagem de
+// erro (ou a envia num follow-up, se a resposta já foi editada).
+func (ctx *Context) Defer() error {
+	if ctx.deferred {
+		return nil
+	}
+	if err := NewResponseManager(ctx.API).DeferResponse(ctx.Interaction, ctx.Ephemeral); err != nil {
+		return fmt.Errorf("defer response: %w", err)
+	}
+	ctx.markDeferred(ctx.Ephemeral)
+	return nil
+}
+
+// Deferred informa se a resposta da interação foi adiada (Defer ou StartProgress).
+func (ctx *Context) Deferred() bool {
+	return ctx.deferred
+}
+
+func (ctx *Context) markDeferred(ephemeral bool) {
+	ctx.deferred = true
+	ctx.deferredEphemeral = ephemeral
+}
+
+// EditResponse substitui a resposta adiada (ou a resposta original) por content e embeds.
+func (ctx *Context) EditResponse(content string, embeds ...*discordgo.MessageEmbed) error {
+	edit := &discordgo.WebhookEdit{Content: &content}
+	if len(embeds) > 0 {
+		edit.Embeds = &embeds
+	}
+	if _, err := ctx.API.InteractionResponseEdit(ctx.Interaction.Interaction, edit); err != nil {
+		return err
+	}
+	ctx.edited = true
+	return nil
+}
+
+// FollowUp envia uma mensagem adicional à interação. Depois de um Defer, a mensagem usa a
+// visibilidade do Defer: o primeiro follow-up de uma resposta adiada ainda não editada a
+// substitui, e o Discord mantém as flags do Defer nesse caso. Sem Defer, usa ctx.Ephemeral.
+func (ctx *Context) FollowUp(content string, embeds ...*discordgo.MessageEmbed) (*discordgo.Message, error) {
+	ephemeral := ctx.Ephemeral
+	if ctx.deferred {
+		ephemeral = ctx.deferredEphemeral
+	}
+	var flags discordgo.MessageFlags
+	if ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+	msg, err := ctx.API.FollowupMessageCreate(ctx.Interaction.Interaction, true, &discordgo.WebhookParams{
+		Content: content,
+		Embeds:  embeds,
+		Flags:   flags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx.edited = true
+	return msg, nil
+}
+
+// replyError responde com content ao erro de um handler: em uma mensagem ephemeral ou, se a
+// resposta foi adiada, editando-a (ou num follow-up quando o handler já a editou).
+func (cr *CommandRouter) replyError(ctx *Context, content string) {
+	var err error
+	switch {
+	case !ctx.deferred:
+		err = cr.responder.Ephemeral(ctx.Interaction, content)
+	case !ctx.edited:
+		err = ctx.EditResponse(content)
+	default:
+		_, err = ctx.FollowUp(content)
+	}
+	if err != nil {
+		ctx.Logger.Error().Errorf("Failed to reply with command error: %v", err)
+	}
+}
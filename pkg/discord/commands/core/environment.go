@@ -0,0 +1,33 @@
+package core
+
+// EnvironmentScoped é implementado por comandos registrados apenas em alguns ambientes
+// (util.EnvDev, util.EnvStaging, util.EnvProd). Comandos sem ele valem em todos.
+type EnvironmentScoped interface {
+	Environments() []string
+}
+
+// OnlyIn restringe cmd aos ambientes dados, ex.: core.OnlyIn(debugCmd, util.EnvDev).
+func OnlyIn(cmd Command, envs ...string) Command {
+	return &scopedCommand{Command: cmd, envs: envs}
+}
+
+type scopedCommand struct {
+	Command
+	envs []string
+}
+
+func (c *scopedCommand) Environments() []string { return c.envs }
+
+// AvailableIn informa se o comando deve ser registrado no ambiente env.
+func AvailableIn(cmd Command, env string) bool {
+	scoped, ok := cmd.(EnvironmentScoped)
+	if !ok {
+		return true
+	}
+	for _, e := range scoped.Environments() {
+		if e == env {
+			return true
+		}
+	}
+	return false
+}
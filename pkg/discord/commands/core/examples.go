@@ -43,7 +43,7 @@ func (c *PingCommand) RequiresPermissions() bool {
 }
 
 func (c *PingCommand) Handle(ctx *Context) error {
-	responder := NewResponder(ctx.Session)
+	responder := NewResponder(ctx.API)
 	return responder.Success(ctx.Interaction, "🏓 Pong!")
 }
 
@@ -103,7 +103,7 @@ func (c *EchoCommand) Handle(ctx *Context) error {
 	ephemeral := extractor.Bool("ephemeral")
 
 	// Usar ResponseBuilder para resposta mais flexível
-	builder := NewResponseBuilder(ctx.Session)
+	builder := NewResponseBuilder(ctx.API)
 	if ephemeral {
 		builder = builder.Ephemeral()
 	}
@@ -162,7 +162,7 @@ func (c *UserInfoSubCommand) Handle(ctx *Context) error {
 	}
 
 	// Criar embed com informações do usuário
-	builder := NewResponseBuilder(ctx.Session).
+	builder := NewResponseBuilder(ctx.API).
 		WithEmbed().
 		WithTitle("User Information").
 		WithTimestamp()
@@ -281,7 +281,7 @@ func (c *ConfigSetSubCommand) Handle(ctx *Context) error {
 		return NewCommandError("Failed to save configuration", true)
 	}
 
-	responder := NewResponder(ctx.Session)
+	responder := NewResponder(ctx.API)
 	return responder.Success(ctx.Interaction, fmt.Sprintf("Configuration `%s` set to `%s`", key, value))
 }
 
@@ -326,7 +326,7 @@ func (c *ConfigGetSubCommand) Handle(ctx *Context) error {
 	config.WriteString(fmt.Sprintf("Automod Channel: %s\n", ctx.GuildConfig.AutomodLogChannelID))
 	config.WriteString(fmt.Sprintf("Allowed Roles: %d configured\n", len(ctx.GuildConfig.AllowedRoles)))
 
-	builder := NewResponseBuilder(ctx.Session).
+	builder := NewResponseBuilder(ctx.API).
 		WithEmbed().
 		WithTitle("Server Configuration").
 		WithColor(0x0099FF)
@@ -373,7 +373,7 @@ func (c *ConfigListSubCommand) Handle(ctx *Context) error {
 		"Use `/config set <key> <value>` to modify these settings.",
 	}
 
-	builder := NewResponseBuilder(ctx.Session).
+	builder := NewResponseBuilder(ctx.API).
 		WithEmbed().
 		WithTitle("Configuration Options").
 		Ephemeral()
@@ -525,7 +525,7 @@ func (c *AdvancedCommand) Handle(ctx *Context) error {
 	}
 
 	// Resposta de sucesso
-	builder := NewResponseBuilder(ctx.Session).
+	builder := NewResponseBuilder(ctx.API).
 		WithEmbed().
 		WithTitle("Processing Complete").
 		WithTimestamp()
@@ -0,0 +1,232 @@
+// Package fakesession implementa core.SessionAPI em memória, para testar o router e os handlers
+// de comandos sem uma sessão real do Discord. Todas as chamadas são gravadas em ordem; guilds e
+// membros vêm de um *discordgo.State próprio, populado pelo teste.
+//
+// Exemplo:
+//
+//	fake := fakesession.New()
+//	fake.AddGuild(&discordgo.Guild{ID: "g1", OwnerID: "u1"})
+//	router := core.NewCommandRouterWithAPI(fake, configManager)
+//	router.RegisterCommand(cmd)
+//	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "ping"))
+//	resp := fake.LastResponse() // *discordgo.InteractionResponse enviada pelo handler
+package fakesession
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+)
+
+// Nomes dos métodos gravados em Call.Method (e usados em Session.Fail).
+const (
+	MethodInteractionRespond        = "InteractionRespond"
+	MethodInteractionResponseEdit   = "InteractionResponseEdit"
+	MethodInteractionResponseDelete = "InteractionResponseDelete"
+	MethodFollowupMessageCreate     = "FollowupMessageCreate"
+	MethodGuild                     = "Guild"
+	MethodGuildMember               = "GuildMember"
+)
+
+// ErrNotFound é retornado por Guild e GuildMember quando o objeto não está no state da fake.
+var ErrNotFound = errors.New("fakesession: not found")
+
+var _ core.SessionAPI = (*Session)(nil)
+var _ core.StateProvider = (*Session)(nil)
+
+// Call é uma chamada gravada; só os campos do método correspondente são preenchidos.
+type Call struct {
+	Method      string
+	Interaction *discordgo.Interaction
+	Response    *discordgo.InteractionResponse // InteractionRespond
+	Edit        *discordgo.WebhookEdit         // InteractionResponseEdit
+	Followup    *discordgo.WebhookParams       // FollowupMessageCreate
+	GuildID     string                         // Guild, GuildMember
+	UserID      string                         // GuildMember
+}
+
+// Session é a sessão fake. O valor zero não é utilizável; use New.
+type Session struct {
+	state *discordgo.State
+
+	mu     sync.Mutex
+	calls  []Call
+	errs   map[string]error
+	nextID int
+}
+
+// New cria uma sessão fake com um state vazio.
+func New() *Session {
+	state := discordgo.NewState()
+	state.User = &discordgo.User{ID: "bot", Username: "fakesession", Bot: true}
+	return &Session{state: state, errs: make(map[string]error)}
+}
+
+// SessionState expõe o state da fake ao PermissionChecker e ao ContextBuilder.
+func (f *Session) SessionState() *discordgo.State {
+	return f.state
+}
+
+// AddGuild adiciona (ou substitui) uma guild no state.
+func (f *Session) AddGuild(g *discordgo.Guild) {
+	_ = f.state.GuildAdd(g)
+}
+
+// AddMember adiciona (ou substitui) um membro no state; a guild precisa ter sido adicionada.
+func (f *Session) AddMember(guildID string, m *discordgo.Member) error {
+	m.GuildID = guildID
+	return f.state.MemberAdd(m)
+}
+
+// Fail faz as próximas chamadas de method retornarem err (nil volta ao comportamento normal).
+func (f *Session) Fail(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, method)
+		return
+	}
+	f.errs[method] = err
+}
+
+// Calls retorna uma cópia das chamadas gravadas, em ordem.
+func (f *Session) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsTo retorna só as chamadas de method.
+func (f *Session) CallsTo(method string) []Call {
+	var out []Call
+	for _, c := range f.Calls() {
+		if c.Method == method {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// LastResponse retorna a última resposta enviada por InteractionRespond (nil se nenhuma).
+func (f *Session) LastResponse() *discordgo.InteractionResponse {
+	calls := f.CallsTo(MethodInteractionRespond)
+	if len(calls) == 0 {
+		return nil
+	}
+	return calls[len(calls)-1].Response
+}
+
+// Reset apaga as chamadas gravadas e as falhas configuradas.
+func (f *Session) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+	f.errs = make(map[string]error)
+}
+
+func (f *Session) record(c Call) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, c)
+	return f.errs[c.Method]
+}
+
+// message monta a mensagem devolvida pelas chamadas que retornam uma.
+func (f *Session) message(i *discordgo.Interaction, content string, embeds []*discordgo.MessageEmbed) *discordgo.Message {
+	f.mu.Lock()
+	f.nextID++
+	id := f.nextID
+	f.mu.Unlock()
+	msg := &discordgo.Message{ID: fmt.Sprintf("fake-%d", id), Content: content, Embeds: embeds, Author: f.state.User}
+	if i != nil {
+		msg.ChannelID, msg.GuildID = i.ChannelID, i.GuildID
+	}
+	return msg
+}
+
+func (f *Session) InteractionRespond(i *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+	return f.record(Call{Method: MethodInteractionRespond, Interaction: i, Response: resp})
+}
+
+func (f *Session) InteractionResponseEdit(i *discordgo.Interaction, edit *discordgo.WebhookEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if err := f.record(Call{Method: MethodInteractionResponseEdit, Interaction: i, Edit: edit}); err != nil {
+		return nil, err
+	}
+	var content string
+	var embeds []*discordgo.MessageEmbed
+	if edit != nil {
+		if edit.Content != nil {
+			content = *edit.Content
+		}
+		if edit.Embeds != nil {
+			embeds = *edit.Embeds
+		}
+	}
+	return f.message(i, content, embeds), nil
+}
+
+func (f *Session) InteractionResponseDelete(i *discordgo.Interaction, _ ...discordgo.RequestOption) error {
+	return f.record(Call{Method: MethodInteractionResponseDelete, Interaction: i})
+}
+
+func (f *Session) FollowupMessageCreate(i *discordgo.Interaction, _ bool, data *discordgo.WebhookParams, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if err := f.record(Call{Method: MethodFollowupMessageCreate, Interaction: i, Followup: data}); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return f.message(i, "", nil), nil
+	}
+	return f.message(i, data.Content, data.Embeds), nil
+}
+
+func (f *Session) Guild(guildID string, _ ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	if err := f.record(Call{Method: MethodGuild, GuildID: guildID}); err != nil {
+		return nil, err
+	}
+	g, err := f.state.Guild(guildID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return g, nil
+}
+
+func (f *Session) GuildMember(guildID, userID string, _ ...discordgo.RequestOption) (*discordgo.Member, error) {
+	if err := f.record(Call{Method: MethodGuildMember, GuildID: guildID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	m, err := f.state.Member(guildID, userID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return m, nil
+}
+
+// SlashCommand monta a interação de um slash command invocado por userID na guild
+// (options opcionais, ex.: um subcomando com suas opções).
+func SlashCommand(guildID, userID, name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "fake-interaction",
+		Type:      discordgo.InteractionApplicationCommand,
+		GuildID:   guildID,
+		ChannelID: "fake-channel",
+		Token:     "fake-token",
+		Member:    &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}},
+		Data:      discordgo.ApplicationCommandInteractionData{Name: name, Options: options},
+	}}
+}
+
+// Component monta a interação de um clique no componente customID.
+func Component(guildID, userID, customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "fake-interaction",
+		Type:      discordgo.InteractionMessageComponent,
+		GuildID:   guildID,
+		ChannelID: "fake-channel",
+		Token:     "fake-token",
+		Member:    &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: customID, ComponentType: discordgo.ButtonComponent},
+	}}
+}
@@ -0,0 +1,109 @@
+package fakesession
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestSessionRecordsCallsInOrder(t *testing.T) {
+	fake := New()
+	i := SlashCommand("g1", "u1", "ping").Interaction
+	content := "edited"
+
+	if err := fake.InteractionRespond(i, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource}); err != nil {
+		t.Fatalf("InteractionRespond: %v", err)
+	}
+	msg, err := fake.InteractionResponseEdit(i, &discordgo.WebhookEdit{Content: &content})
+	if err != nil {
+		t.Fatalf("InteractionResponseEdit: %v", err)
+	}
+	if msg.Content != content || msg.GuildID != "g1" || msg.ChannelID != i.ChannelID {
+		t.Errorf("edited message = %+v", msg)
+	}
+	followup, err := fake.FollowupMessageCreate(i, true, &discordgo.WebhookParams{Content: "more"})
+	if err != nil {
+		t.Fatalf("FollowupMessageCreate: %v", err)
+	}
+	if followup.ID == msg.ID {
+		t.Errorf("messages share the id %s", msg.ID)
+	}
+
+	want := []string{MethodInteractionRespond, MethodInteractionResponseEdit, MethodFollowupMessageCreate}
+	calls := fake.Calls()
+	if len(calls) != len(want) {
+		t.Fatalf("recorded %d calls, want %d", len(calls), len(want))
+	}
+	for n, c := range calls {
+		if c.Method != want[n] || c.Interaction != i {
+			t.Errorf("call %d = %s, want %s on the interaction", n, c.Method, want[n])
+		}
+	}
+	if got := fake.CallsTo(MethodFollowupMessageCreate); len(got) != 1 || got[0].Followup.Content != "more" {
+		t.Errorf("CallsTo(followup) = %+v", got)
+	}
+	if fake.LastResponse().Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("LastResponse = %+v", fake.LastResponse())
+	}
+
+	fake.Reset()
+	if len(fake.Calls()) != 0 || fake.LastResponse() != nil {
+		t.Error("Reset kept recorded calls")
+	}
+}
+
+func TestSessionFail(t *testing.T) {
+	fake := New()
+	i := SlashCommand("g1", "u1", "ping").Interaction
+	boom := errors.New("boom")
+
+	fake.Fail(MethodInteractionRespond, boom)
+	if err := fake.InteractionRespond(i, &discordgo.InteractionResponse{}); !errors.Is(err, boom) {
+		t.Errorf("InteractionRespond error = %v, want boom", err)
+	}
+	if len(fake.CallsTo(MethodInteractionRespond)) != 1 {
+		t.Error("failed call was not recorded")
+	}
+	if _, err := fake.InteractionResponseEdit(i, &discordgo.WebhookEdit{}); err != nil {
+		t.Errorf("other methods must keep working: %v", err)
+	}
+
+	fake.Fail(MethodInteractionRespond, nil)
+	if err := fake.InteractionRespond(i, &discordgo.InteractionResponse{}); err != nil {
+		t.Errorf("InteractionRespond after clearing the failure: %v", err)
+	}
+
+	fake.Fail(MethodGuild, boom)
+	fake.Reset()
+	fake.AddGuild(&discordgo.Guild{ID: "g1"})
+	if _, err := fake.Guild("g1"); err != nil {
+		t.Errorf("Reset kept the configured failure: %v", err)
+	}
+}
+
+func TestSessionStateLookups(t *testing.T) {
+	fake := New()
+	fake.AddGuild(&discordgo.Guild{ID: "g1", OwnerID: "owner"})
+	if err := fake.AddMember("g1", &discordgo.Member{User: &discordgo.User{ID: "u1"}, Roles: []string{"r1"}}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	g, err := fake.Guild("g1")
+	if err != nil || g.OwnerID != "owner" {
+		t.Errorf("Guild = %+v, %v", g, err)
+	}
+	m, err := fake.GuildMember("g1", "u1")
+	if err != nil || m.GuildID != "g1" || len(m.Roles) != 1 {
+		t.Errorf("GuildMember = %+v, %v", m, err)
+	}
+	if _, err := fake.Guild("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing guild error = %v, want ErrNotFound", err)
+	}
+	if _, err := fake.GuildMember("g1", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing member error = %v, want ErrNotFound", err)
+	}
+	if calls := fake.CallsTo(MethodGuildMember); len(calls) != 2 || calls[1].UserID != "missing" {
+		t.Errorf("GuildMember calls = %+v", calls)
+	}
+}
@@ -0,0 +1,338 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/util"
+)
+
+// DisabledCommandMessage é a resposta (efêmera) a um comando desativado na guild
+// (GuildConfig.DisabledCommands) que o Discord ainda entregou, ex.: antes da ressincronização.
+const DisabledCommandMessage = "This command is not enabled in this server."
+
+// commandSyncStats conta o resultado da reconciliação de um escopo (global ou guild).
+type commandSyncStats struct {
+	created, updated, deleted, unchanged int
+}
+
+func (st commandSyncStats) changed() bool {
+	return st.created+st.updated+st.deleted > 0
+}
+
+// syncCommands reconcilia os comandos do código com o Discord. Comandos desativados em alguma
+// guild (GuildConfig.DisabledCommands) saem do registro global e são registrados em cada guild
+// configurada onde estão ativos, com os subcomandos desativados removidos; os demais continuam
+// globais. Com force=false nada é feito se o escopo dos comandos não mudou desde a última sincronização.
+// Com only != nil só o registro dessas guilds é reconciliado, a menos que a mudança tenha movido
+// comandos entre o registro global e o por guild: aí todos os escopos são.
+func (cm *CommandManager) syncCommands(force bool, only []string) error {
+	cm.syncMu.Lock()
+	defer cm.syncMu.Unlock()
+
+	var guilds []files.GuildConfig
+	if cfg := cm.router.GetConfigManager(); cfg != nil {
+		guilds = cfg.Guilds()
+	}
+	signature := commandScopeSignature(guilds)
+	if !force && signature == cm.scopeSignature {
+		return nil
+	}
+
+	env := util.CurrentEnvironment()
+	global, perGuild := cm.desiredCommands(guilds, force)
+	globalNames := commandNames(global)
+	partial := only != nil && globalNames == cm.globalNames
+	if !partial {
+		stats, err := cm.reconcile("", global)
+		if err != nil {
+			return err
+		}
+		cm.logger.Info().Applicationf("Command synchronization completed: created=%d, updated=%d, deleted=%d, unchanged=%d, total=%d, env=%s, mode=incremental",
+			stats.created, stats.updated, stats.deleted, stats.unchanged, len(global), env)
+	}
+
+	// Também roda sem comandos por guild, para remover registros de uma configuração anterior
+	for _, scope := range perGuild {
+		if partial && !slices.Contains(only, scope.guildID) {
+			continue
+		}
+		st, err := cm.reconcile(scope.guildID, scope.desired)
+		if err != nil {
+			cm.logger.Warn().Applicationf("Guild command synchronization failed: guildID=%s, error=%v", scope.guildID, err)
+			continue
+		}
+		if st.changed() || len(scope.desired) > 0 {
+			cm.logger.Info().Applicationf("Guild command synchronization completed: guildID=%s, created=%d, updated=%d, deleted=%d, unchanged=%d, total=%d",
+				scope.guildID, st.created, st.updated, st.deleted, st.unchanged, len(scope.desired))
+		}
+	}
+	cm.scopeSignature = signature
+	cm.globalNames = globalNames
+	return nil
+}
+
+// commandNames resume os nomes de um escopo, para detectar comandos que mudaram de escopo.
+func commandNames(cmds map[string]*discordgo.ApplicationCommand) string {
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ",")
+}
+
+// guildCommandScope são os comandos desejados no registro de uma guild.
+type guildCommandScope struct {
+	guildID string
+	desired map[string]*discordgo.ApplicationCommand
+}
+
+// desiredCommands monta os comandos que devem estar registrados no Discord: os globais e, para
+// cada guild configurada (na ordem de guilds), os registrados só nela. Comandos de outros
+// ambientes ficam de fora e, se existirem no Discord, são removidos como órfãos.
+func (cm *CommandManager) desiredCommands(guilds []files.GuildConfig, logSkipped bool) (map[string]*discordgo.ApplicationCommand, []guildCommandScope) {
+	env := util.CurrentEnvironment()
+	codeCommands := make(map[string]Command)
+	for name, cmd := range cm.router.registry.GetAllCommands() {
+		if !AvailableIn(cmd, env) {
+			if logSkipped {
+				cm.logger.Info().Applicationf("Command skipped (not enabled in %s): %s", env, name)
+			}
+			continue
+		}
+		codeCommands[name] = cmd
+	}
+
+	scoped := guildScopedCommands(codeCommands, guilds)
+	global := make(map[string]*discordgo.ApplicationCommand, len(codeCommands))
+	for name, cmd := range codeCommands {
+		if !scoped[name] {
+			global[name] = desiredCommand(cmd, nil)
+		}
+	}
+	perGuild := make([]guildCommandScope, 0, len(guilds))
+	for i := range guilds {
+		gcfg := &guilds[i]
+		desired := make(map[string]*discordgo.ApplicationCommand)
+		for name := range scoped {
+			if cmd := desiredCommand(codeCommands[name], gcfg); cmd != nil {
+				desired[name] = cmd
+			}
+		}
+		perGuild = append(perGuild, guildCommandScope{guildID: gcfg.GuildID, desired: desired})
+	}
+	return global, perGuild
+}
+
+// MissingCommands compara o registro no Discord com os comandos desejados, nos mesmos escopos
+// usados pela sincronização: globais e, com disabled_commands, os registrados por guild.
+// Retorna quantos registros eram esperados e os ausentes ("name" ou "name (guild ID)").
+func (cm *CommandManager) MissingCommands() (want int, missing []string, err error) {
+	if cm.session == nil || cm.session.State == nil || cm.session.State.User == nil {
+		return 0, nil, fmt.Errorf("session not properly initialized")
+	}
+	var guilds []files.GuildConfig
+	if cfg := cm.router.GetConfigManager(); cfg != nil {
+		guilds = cfg.Guilds()
+	}
+	global, perGuild := cm.desiredCommands(guilds, false)
+	scopes := append([]guildCommandScope{{desired: global}}, perGuild...)
+	appID := cm.session.State.User.ID
+	for _, scope := range scopes {
+		if len(scope.desired) == 0 {
+			continue
+		}
+		want += len(scope.desired)
+		registered, err := cm.session.ApplicationCommands(appID, scope.guildID)
+		if err != nil {
+			return want, missing, fmt.Errorf("list registered commands (guild %q): %w", scope.guildID, err)
+		}
+		names := make(map[string]bool, len(registered))
+		for _, rc := range registered {
+			names[rc.Name] = true
+		}
+		for name := range scope.desired {
+			if names[name] {
+				continue
+			}
+			if scope.guildID == "" {
+				missing = append(missing, name)
+			} else {
+				missing = append(missing, fmt.Sprintf("%s (guild %s)", name, scope.guildID))
+			}
+		}
+	}
+	slices.Sort(missing)
+	return want, missing, nil
+}
+
+// ResyncCommands reaplica GuildConfig.DisabledCommands ao registro no Discord depois de uma
+// mudança de configuração (ex.: hot reload). Não faz nada se nenhuma guild mudou seus comandos.
+func (cm *CommandManager) ResyncCommands() error {
+	if cm.session == nil || cm.session.State == nil || cm.session.State.User == nil {
+		return fmt.Errorf("session not properly initialized")
+	}
+	return cm.syncCommands(false, nil)
+}
+
+// ResyncGuildCommands é ResyncCommands limitado às guilds cujo disabled_commands mudou: só o
+// registro delas é reconciliado, exceto quando a mudança move um comando entre o registro
+// global e o por guild (ex.: o primeiro disabled_commands a citá-lo), que ressincroniza tudo.
+func (cm *CommandManager) ResyncGuildCommands(guildIDs ...string) error {
+	if cm.session == nil || cm.session.State == nil || cm.session.State.User == nil {
+		return fmt.Errorf("session not properly initialized")
+	}
+	if len(guildIDs) == 0 {
+		return nil
+	}
+	return cm.syncCommands(false, guildIDs)
+}
+
+// reconcile cria, atualiza e remove os comandos do escopo guildID ("" = global) até bater com desired.
+// Erros de criação/atualização interrompem a reconciliação; falhas ao remover órfãos só são logadas.
+func (cm *CommandManager) reconcile(guildID string, desired map[string]*discordgo.ApplicationCommand) (commandSyncStats, error) {
+	var st commandSyncStats
+	appID := cm.session.State.User.ID
+
+	registered, err := cm.session.ApplicationCommands(appID, guildID)
+	if err != nil {
+		return st, fmt.Errorf("failed to fetch registered commands: %w", err)
+	}
+	regByName := make(map[string]*discordgo.ApplicationCommand, len(registered))
+	for _, rc := range registered {
+		regByName[rc.Name] = rc
+	}
+
+	scope := "Command"
+	if guildID != "" {
+		scope = "Guild command (" + guildID + ")"
+	}
+	for name, cmd := range desired {
+		if existing, ok := regByName[name]; ok {
+			if CompareCommands(existing, cmd) {
+				cm.logger.Info().Applicationf("%s unchanged, skipping: %s", scope, name)
+				st.unchanged++
+				continue
+			}
+			if _, err := cm.session.ApplicationCommandEdit(appID, guildID, existing.ID, cmd); err != nil {
+				return st, fmt.Errorf("error updating command '%s': %w", name, err)
+			}
+			cm.logger.Info().Applicationf("%s updated: %s", scope, name)
+			st.updated++
+			continue
+		}
+		if _, err := cm.session.ApplicationCommandCreate(appID, guildID, cmd); err != nil {
+			return st, fmt.Errorf("error creating command '%s': %w", name, err)
+		}
+		cm.logger.Info().Applicationf("%s created: %s", scope, name)
+		st.created++
+	}
+
+	// Remover comandos órfãos (existem no Discord mas não no escopo desejado)
+	for _, rc := range registered {
+		if _, ok := desired[rc.Name]; ok {
+			continue
+		}
+		if err := cm.session.ApplicationCommandDelete(appID, guildID, rc.ID); err != nil {
+			cm.logger.Warn().Applicationf("Error removing orphan command: %s, error: %v", rc.Name, err)
+			continue
+		}
+		cm.logger.Info().Applicationf("Orphan command removed: %s", rc.Name)
+		st.deleted++
+	}
+	return st, nil
+}
+
+// guildScopedCommands retorna os comandos (por nome) desativados, inteiros ou em parte, em alguma guild.
+func guildScopedCommands(cmds map[string]Command, guilds []files.GuildConfig) map[string]bool {
+	scoped := make(map[string]bool)
+	for _, gcfg := range guilds {
+		for _, path := range gcfg.DisabledCommands {
+			name, _, _ := strings.Cut(files.NormalizeCommandPath(path), " ")
+			if _, ok := cmds[name]; ok {
+				scoped[name] = true
+			}
+		}
+	}
+	return scoped
+}
+
+// desiredCommand monta a definição de cmd para o Discord. Com gcfg, subcomandos desativados na
+// guild são removidos; retorna nil se o comando (ou todos os seus subcomandos) estiver desativado.
+func desiredCommand(cmd Command, gcfg *files.GuildConfig) *discordgo.ApplicationCommand {
+	name := cmd.Name()
+	if !gcfg.CommandEnabled(name) {
+		return nil
+	}
+	options := cmd.Options()
+	if gcfg != nil {
+		var ok bool
+		if options, ok = enabledOptions(name, options, gcfg); !ok {
+			return nil
+		}
+	}
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: cmd.Description(),
+		Options:     options,
+	}
+}
+
+// enabledOptions remove de options os subcomandos (e grupos) desativados em gcfg. ok é false
+// quando havia subcomandos e nenhum sobrou.
+func enabledOptions(path string, options []*discordgo.ApplicationCommandOption, gcfg *files.GuildConfig) ([]*discordgo.ApplicationCommandOption, bool) {
+	out := make([]*discordgo.ApplicationCommandOption, 0, len(options))
+	subcommands := 0
+	for _, opt := range options {
+		switch opt.Type {
+		case discordgo.ApplicationCommandOptionSubCommand, discordgo.ApplicationCommandOptionSubCommandGroup:
+		default:
+			out = append(out, opt)
+			continue
+		}
+		subcommands++
+		subPath := path + " " + opt.Name
+		if !gcfg.CommandEnabled(subPath) {
+			continue
+		}
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			nested, ok := enabledOptions(subPath, opt.Options, gcfg)
+			if !ok {
+				continue
+			}
+			copied := *opt
+			copied.Options = nested
+			opt = &copied
+		}
+		out = append(out, opt)
+	}
+	if subcommands > 0 && len(out) == len(options)-subcommands {
+		return nil, false
+	}
+	return out, true
+}
+
+// commandScopeSignature resume o que define o registro por guild: as guilds ativas e seus
+// comandos desativados. Vazio quando nenhuma guild desativa comandos (tudo global).
+func commandScopeSignature(guilds []files.GuildConfig) string {
+	restricted := false
+	parts := make([]string, 0, len(guilds))
+	for _, gcfg := range guilds {
+		disabled := make([]string, 0, len(gcfg.DisabledCommands))
+		for _, path := range gcfg.DisabledCommands {
+			disabled = append(disabled, files.NormalizeCommandPath(path))
+		}
+		slices.Sort(disabled)
+		restricted = restricted || len(disabled) > 0
+		parts = append(parts, gcfg.GuildID+"="+strings.Join(disabled, ","))
+	}
+	if !restricted {
+		return ""
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ";")
+}
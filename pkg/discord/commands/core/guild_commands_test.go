@@ -0,0 +1,146 @@
+package core_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// commandAPI is an in-memory application command registry served over HTTP. Scopes are keyed
+// by guild ID ("" for global); lists counts the registry reads per scope.
+type commandAPI struct {
+	mu     sync.Mutex
+	scopes map[string][]*discordgo.ApplicationCommand
+	lists  map[string]int
+	nextID int
+}
+
+func (a *commandAPI) RoundTrip(r *http.Request) (*http.Response, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v"+discordgo.APIVersion+"/applications/app/")
+	guildID := ""
+	if after, ok := strings.CutPrefix(rest, "guilds/"); ok {
+		guildID, rest, _ = strings.Cut(after, "/")
+	}
+	_, id, _ := strings.Cut(rest, "/")
+
+	var body any
+	switch r.Method {
+	case http.MethodGet:
+		a.lists[guildID]++
+		body = a.scopes[guildID]
+	case http.MethodPost:
+		cmd := &discordgo.ApplicationCommand{}
+		_ = json.NewDecoder(r.Body).Decode(cmd)
+		a.nextID++
+		cmd.ID = strconv.Itoa(a.nextID)
+		a.scopes[guildID] = append(a.scopes[guildID], cmd)
+		body = cmd
+	case http.MethodPatch:
+		cmd := &discordgo.ApplicationCommand{}
+		_ = json.NewDecoder(r.Body).Decode(cmd)
+		cmd.ID = id
+		a.scopes[guildID] = slices.DeleteFunc(a.scopes[guildID], func(c *discordgo.ApplicationCommand) bool { return c.ID == id })
+		a.scopes[guildID] = append(a.scopes[guildID], cmd)
+		body = cmd
+	case http.MethodDelete:
+		a.scopes[guildID] = slices.DeleteFunc(a.scopes[guildID], func(c *discordgo.ApplicationCommand) bool { return c.ID == id })
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: r}, nil
+	}
+	data, _ := json.Marshal(body)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(data))), Header: http.Header{"Content-Type": {"application/json"}}, Request: r}, nil
+}
+
+func (a *commandAPI) names(guildID string) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var names []string
+	for _, c := range a.scopes[guildID] {
+		names = append(names, c.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (a *commandAPI) resetLists() map[string]int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	lists := a.lists
+	a.lists = map[string]int{}
+	return lists
+}
+
+func TestResyncGuildCommandsOnlyTouchesChangedGuilds(t *testing.T) {
+	api := &commandAPI{scopes: map[string][]*discordgo.ApplicationCommand{}, lists: map[string]int{}}
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	s.Client = &http.Client{Transport: api}
+	s.State.User = &discordgo.User{ID: "app"}
+
+	config := files.NewConfigManagerWithPath(filepath.Join(t.TempDir(), "settings.json"))
+	for _, g := range []files.GuildConfig{{GuildID: "g1", DisabledCommands: []string{"ping"}}, {GuildID: "g2"}} {
+		if err := config.AddGuildConfig(g); err != nil {
+			t.Fatalf("add guild config: %v", err)
+		}
+	}
+	cm := NewCommandManager(s, config)
+	for _, name := range []string{"ping", "pong"} {
+		cm.GetRouter().RegisterCommand(&testCommand{name: name})
+	}
+	if err := cm.SetupCommands(); err != nil {
+		t.Fatalf("setup commands: %v", err)
+	}
+	if got := api.names("g2"); !slices.Equal(got, []string{"ping"}) {
+		t.Fatalf("g2 commands = %v, want [ping]", got)
+	}
+	api.resetLists()
+
+	// ping is already guild-scoped: only g2's registry is reconciled
+	disable := func(guildID string, cmds ...string) {
+		t.Helper()
+		if err := config.UpdateGuild(guildID, func(g *files.GuildConfig) error {
+			g.DisabledCommands = cmds
+			return nil
+		}); err != nil {
+			t.Fatalf("update guild: %v", err)
+		}
+	}
+	disable("g2", "ping")
+	if err := cm.ResyncGuildCommands("g2"); err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	if lists := api.resetLists(); len(lists) != 1 || lists["g2"] != 1 {
+		t.Fatalf("registry reads = %v, want only g2", lists)
+	}
+	if got := api.names("g2"); len(got) != 0 {
+		t.Fatalf("g2 commands = %v, want none", got)
+	}
+
+	// Disabling pong moves it out of the global registry, so every scope is reconciled
+	disable("g1", "ping", "pong")
+	if err := cm.ResyncGuildCommands("g1"); err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	if lists := api.resetLists(); lists[""] != 1 || lists["g2"] != 1 {
+		t.Fatalf("registry reads = %v, want global and every guild", lists)
+	}
+	if got := api.names(""); len(got) != 0 {
+		t.Fatalf("global commands = %v, want none", got)
+	}
+	if got := api.names("g2"); !slices.Equal(got, []string{"pong"}) {
+		t.Fatalf("g2 commands = %v, want [pong]", got)
+	}
+}
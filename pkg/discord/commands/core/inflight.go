@@ -0,0 +1,78 @@
+package core
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// InFlightQueueWait é quanto uma interação acima do limite espera por uma vaga antes de ser
+// recusada; fica abaixo dos 3s que o Discord dá para a primeira resposta.
+const InFlightQueueWait = 2 * time.Second
+
+// BusyMessage é a resposta (efêmera) às interações recusadas por excesso de carga.
+const BusyMessage = "The bot is busy right now, please try again in a moment."
+
+// inFlightLimiter limita quantos handlers de interação executam ao mesmo tempo. O discordgo
+// entrega cada evento numa goroutine própria; o limiter não evita a goroutine, mas impede que
+// uma rajada de comandos lentos execute sem limite (as excedentes esperam e depois desistem).
+type inFlightLimiter struct {
+	slots    chan struct{} // nil: sem limite
+	active   atomic.Int64
+	rejected atomic.Int64
+}
+
+func newInFlightLimiter(limit int) *inFlightLimiter {
+	l := &inFlightLimiter{}
+	if limit > 0 {
+		l.slots = make(chan struct{}, limit)
+	}
+	return l
+}
+
+// acquire reserva uma vaga, esperando até InFlightQueueWait; false se a interação deve ser recusada.
+func (l *inFlightLimiter) acquire() bool {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			timer := time.NewTimer(InFlightQueueWait)
+			defer timer.Stop()
+			select {
+			case l.slots <- struct{}{}:
+			case <-timer.C:
+				l.rejected.Add(1)
+				return false
+			}
+		}
+	}
+	l.active.Add(1)
+	return true
+}
+
+func (l *inFlightLimiter) release() {
+	l.active.Add(-1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// SetMaxInFlight troca o limite de interações executando ao mesmo tempo (<= 0: sem limite).
+// Deve ser chamado antes de o router receber interações.
+func (cr *CommandRouter) SetMaxInFlight(limit int) {
+	cr.inFlight = newInFlightLimiter(limit)
+}
+
+// InFlight retorna quantas interações estão executando agora.
+func (cr *CommandRouter) InFlight() int {
+	return int(cr.inFlight.active.Load())
+}
+
+// MaxInFlight retorna o limite de interações simultâneas (0: sem limite).
+func (cr *CommandRouter) MaxInFlight() int {
+	return cap(cr.inFlight.slots)
+}
+
+// RejectedInteractions retorna quantas interações foram recusadas por excesso de carga.
+func (cr *CommandRouter) RejectedInteractions() int64 {
+	return cr.inFlight.rejected.Load()
+}
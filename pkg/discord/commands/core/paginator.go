@@ -0,0 +1,256 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// PaginatorPrefix é o prefixo dos CustomIDs dos botões de paginação (registrado pelo CommandRouter).
+const PaginatorPrefix = "page"
+
+// DefaultPaginatorIdle é quanto tempo uma listagem fica navegável sem cliques. Fica abaixo
+// dos 15 minutos de validade do token da interação, para que os botões ainda possam ser
+// removidos quando ela expira.
+const DefaultPaginatorIdle = 10 * time.Minute
+
+// PageFetcher busca a página que começa em cursor ("" = primeira) e retorna o embed dela e o
+// cursor da próxima ("" quando é a última).
+type PageFetcher func(cursor string) (embed *discordgo.MessageEmbed, next string, err error)
+
+// StorePageFetcher adapta uma listagem paginada do store (ex.: store.AutomodActionsPage) a um
+// PageFetcher, renderizando os itens de cada página com render.
+func StorePageFetcher[T any](limit int, list func(storage.PageRequest) (storage.Page[T], error), render func(items []T) *discordgo.MessageEmbed) PageFetcher {
+	return func(cursor string) (*discordgo.MessageEmbed, string, error) {
+		page, err := list(storage.PageRequest{Cursor: cursor, Limit: limit})
+		if err != nil {
+			return nil, "", err
+		}
+		return render(page.Items), page.NextCursor, nil
+	}
+}
+
+// Paginator mantém as listagens paginadas abertas e trata os botões Previous/Next/Close delas.
+// As páginas são buscadas sob demanda a cada clique e a mensagem é editada no lugar.
+type Paginator struct {
+	session SessionAPI
+	idle    time.Duration
+
+	mu    sync.Mutex
+	views map[string]*pageView
+}
+
+// pageView é o estado de uma listagem aberta.
+type pageView struct {
+	id      string
+	userID  string
+	fetch   PageFetcher
+	cursors []string // cursor de cada página já visitada; cursors[index] é a página atual
+	index   int
+	next    string
+	last    *discordgo.Interaction // interação mais recente, cujo token permite editar a mensagem
+	timer   *time.Timer
+}
+
+// NewPaginator cria um paginator; idle <= 0 usa DefaultPaginatorIdle.
+func NewPaginator(session SessionAPI, idle time.Duration) *Paginator {
+	if idle <= 0 {
+		idle = DefaultPaginatorIdle
+	}
+	return &Paginator{session: session, idle: idle, views: make(map[string]*pageView)}
+}
+
+// Send responde à interação do comando com a primeira página e os botões de navegação.
+// Só quem executou o comando pode navegar.
+func (p *Paginator) Send(ctx *Context, fetch PageFetcher, ephemeral bool) error {
+	embed, next, err := fetch("")
+	if err != nil {
+		return err
+	}
+	view := &pageView{
+		id:      newPageViewID(),
+		userID:  ctx.UserID,
+		fetch:   fetch,
+		cursors: []string{""},
+		next:    next,
+		last:    ctx.Interaction.Interaction,
+	}
+	data := &discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{view.decorate(embed)},
+		Components: view.components(),
+	}
+	if ephemeral {
+		data.Flags = discordgo.MessageFlagsEphemeral
+	}
+	if err := ctx.API.InteractionRespond(ctx.Interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	}); err != nil {
+		return err
+	}
+	// Uma única página não precisa de navegação
+	if next == "" {
+		return nil
+	}
+	p.mu.Lock()
+	p.views[view.id] = view
+	view.timer = time.AfterFunc(p.idle, func() { p.expire(view.id) })
+	p.mu.Unlock()
+	return nil
+}
+
+// SendPaginated envia uma listagem paginada pelo paginator do contexto.
+func SendPaginated(ctx *Context, fetch PageFetcher, ephemeral bool) error {
+	if ctx.Paginator == nil {
+		return fmt.Errorf("paginator not configured")
+	}
+	return ctx.Paginator.Send(ctx, fetch, ephemeral)
+}
+
+// RequiresPermissions é false: a navegação é restrita a quem executou o comando.
+func (p *Paginator) RequiresPermissions() bool {
+	return false
+}
+
+// HandleComponent trata os botões Previous/Next/Close.
+func (p *Paginator) HandleComponent(ctx *Context) error {
+	i := ctx.Interaction
+	_, rest, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+	id, action, _ := strings.Cut(rest, ":")
+
+	p.mu.Lock()
+	view, ok := p.views[id]
+	p.mu.Unlock()
+	if !ok {
+		// Expirada (ou de antes de um restart): remove os botões e avisa
+		_ = ctx.API.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{Components: []discordgo.MessageComponent{}},
+		})
+		_, err := ctx.API.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+			Content: "This list has expired. Run the command again to browse it.",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		return err
+	}
+	if ctx.UserID != view.userID {
+		return NewCommandError("Only the user who ran the command can change pages", true)
+	}
+
+	if action == "close" {
+		p.drop(id)
+		return ctx.API.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{Components: []discordgo.MessageComponent{}},
+		})
+	}
+
+	p.mu.Lock()
+	index := view.index
+	switch action {
+	case "next":
+		if view.next != "" {
+			index++
+		}
+	case "prev":
+		if index > 0 {
+			index--
+		}
+	}
+	cursor := view.next
+	if index < len(view.cursors) {
+		cursor = view.cursors[index]
+	}
+	p.mu.Unlock()
+
+	embed, next, err := view.fetch(cursor)
+	if err != nil {
+		return fmt.Errorf("fetch page: %w", err)
+	}
+
+	p.mu.Lock()
+	if index == len(view.cursors) {
+		view.cursors = append(view.cursors, cursor)
+	}
+	view.index, view.next, view.last = index, next, i.Interaction
+	view.timer.Reset(p.idle)
+	embed = view.decorate(embed)
+	components := view.components()
+	p.mu.Unlock()
+
+	return ctx.API.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+}
+
+// expire encerra uma listagem ociosa, removendo os botões enquanto o token ainda é válido.
+func (p *Paginator) expire(id string) {
+	view := p.drop(id)
+	if view == nil || p.session == nil {
+		return
+	}
+	empty := []discordgo.MessageComponent{}
+	if _, err := p.session.InteractionResponseEdit(view.last, &discordgo.WebhookEdit{Components: &empty}); err != nil {
+		log.Info().Applicationf("Failed to remove buttons of expired paginated list %s: %v", id, err)
+	}
+}
+
+func (p *Paginator) drop(id string) *pageView {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	view, ok := p.views[id]
+	if !ok {
+		return nil
+	}
+	delete(p.views, id)
+	if view.timer != nil {
+		view.timer.Stop()
+	}
+	return view
+}
+
+// decorate acrescenta o número da página ao rodapé do embed.
+func (v *pageView) decorate(embed *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	if embed == nil {
+		embed = &discordgo.MessageEmbed{Description: "Nothing to show."}
+	}
+	label := fmt.Sprintf("Page %d", v.index+1)
+	if embed.Footer != nil && embed.Footer.Text != "" {
+		label = embed.Footer.Text + " • " + label
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: label}
+	return embed
+}
+
+func (v *pageView) components() []discordgo.MessageComponent {
+	if v.index == 0 && v.next == "" {
+		return []discordgo.MessageComponent{}
+	}
+	customID := func(action string) string { return PaginatorPrefix + ":" + v.id + ":" + action }
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Previous", Style: discordgo.SecondaryButton, CustomID: customID("prev"), Disabled: v.index == 0},
+				discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: customID("next"), Disabled: v.next == ""},
+				discordgo.Button{Label: "Close", Style: discordgo.DangerButton, CustomID: customID("close")},
+			},
+		},
+	}
+}
+
+func newPageViewID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
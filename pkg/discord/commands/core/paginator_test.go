@@ -0,0 +1,112 @@
+package core_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core/fakesession"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// listNumbers pages over 1..total, using the next index as the cursor.
+func listNumbers(total int) func(storage.PageRequest) (storage.Page[int], error) {
+	return func(req storage.PageRequest) (storage.Page[int], error) {
+		start := 0
+		if req.Cursor != "" {
+			start, _ = strconv.Atoi(req.Cursor)
+		}
+		end := min(start+req.Limit, total)
+		var page storage.Page[int]
+		for n := start; n < end; n++ {
+			page.Items = append(page.Items, n+1)
+		}
+		if end < total {
+			page.NextCursor = strconv.Itoa(end)
+		}
+		return page, nil
+	}
+}
+
+func renderNumbers(items []int) *discordgo.MessageEmbed {
+	parts := make([]string, len(items))
+	for i, n := range items {
+		parts[i] = strconv.Itoa(n)
+	}
+	return &discordgo.MessageEmbed{Description: strings.Join(parts, ",")}
+}
+
+// buttonID returns the CustomID of the paginator button with the given label.
+func buttonID(t *testing.T, components []discordgo.MessageComponent, label string) string {
+	t.Helper()
+	for _, c := range components {
+		row, ok := c.(discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, b := range row.Components {
+			if btn, ok := b.(discordgo.Button); ok && btn.Label == label {
+				return btn.CustomID
+			}
+		}
+	}
+	t.Fatalf("no %q button", label)
+	return ""
+}
+
+func TestSendPaginatedNavigatesStorePages(t *testing.T) {
+	fake := fakesession.New()
+	p := NewPaginator(fake, time.Hour)
+	ctx := &Context{API: fake, Interaction: fakesession.SlashCommand("g1", "u1", "list"), UserID: "u1", Paginator: p}
+
+	if err := SendPaginated(ctx, StorePageFetcher(2, listNumbers(5), renderNumbers), true); err != nil {
+		t.Fatalf("SendPaginated: %v", err)
+	}
+	resp := fake.LastResponse()
+	if got := resp.Data.Embeds[0].Description; got != "1,2" {
+		t.Fatalf("first page = %q, want 1,2", got)
+	}
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("first page is not ephemeral")
+	}
+	next := buttonID(t, resp.Data.Components, "Next")
+
+	click := func(userID, customID string) error {
+		return p.HandleComponent(&Context{API: fake, Interaction: fakesession.Component("g1", userID, customID), UserID: userID})
+	}
+	if err := click("u2", next); err == nil {
+		t.Error("another user could change pages")
+	}
+	for _, want := range []string{"3,4", "5"} {
+		if err := click("u1", next); err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if got := fake.LastResponse().Data.Embeds[0].Description; got != want {
+			t.Fatalf("page = %q, want %q", got, want)
+		}
+	}
+	if err := click("u1", buttonID(t, fake.LastResponse().Data.Components, "Previous")); err != nil {
+		t.Fatalf("previous: %v", err)
+	}
+	resp = fake.LastResponse()
+	if got := resp.Data.Embeds[0].Description; got != "3,4" {
+		t.Fatalf("page after previous = %q, want 3,4", got)
+	}
+	if !strings.HasSuffix(resp.Data.Embeds[0].Footer.Text, "Page 2") {
+		t.Errorf("footer = %q, want page 2", resp.Data.Embeds[0].Footer.Text)
+	}
+}
+
+func TestSendPaginatedSinglePageHasNoButtons(t *testing.T) {
+	fake := fakesession.New()
+	ctx := &Context{API: fake, Interaction: fakesession.SlashCommand("g1", "u1", "list"), UserID: "u1", Paginator: NewPaginator(fake, time.Hour)}
+	if err := SendPaginated(ctx, StorePageFetcher(10, listNumbers(3), renderNumbers), false); err != nil {
+		t.Fatalf("SendPaginated: %v", err)
+	}
+	if n := len(fake.LastResponse().Data.Components); n != 0 {
+		t.Errorf("single page has %d component rows, want 0", n)
+	}
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultProgressInterval é o intervalo mínimo entre edições da resposta de progresso.
+// Edições de interação compartilham o rate limit do webhook, então atualizações mais
+// frequentes são aglutinadas e apenas a mais recente é exibida.
+const DefaultProgressInterval = 2 * time.Second
+
+const progressBarWidth = 20
+
+// InteractionTokenTTL é por quanto tempo o Discord aceita edições e follow-ups de uma interação.
+const InteractionTokenTTL = 15 * time.Minute
+
+// progressTokenMargin antecipa a troca para o fallback, para não perder a última edição no limite.
+const progressTokenMargin = time.Minute
+
+// ProgressUpdate é um passo de progresso enviado por uma operação longa.
+// Total <= 0 indica progresso indeterminado (apenas Done e a mensagem são exibidos).
+type ProgressUpdate struct {
+	Done    int
+	Total   int
+	Message string
+}
+
+// ProgressReporter edita a resposta adiada de um comando com uma barra de progresso.
+// A operação publica o progresso com Report, que pode ser chamado de qualquer goroutine e é
+// ignorado depois de Finish; o reporter aglutina as atualizações e edita a resposta no máximo
+// uma vez por intervalo.
+type ProgressReporter struct {
+	session     SessionAPI
+	interaction *discordgo.InteractionCreate
+	title       string
+	interval    time.Duration
+
+	updates  chan ProgressUpdate
+	done     chan struct{}
+	finishMu sync.Mutex
+	finished bool
+
+	// tokenExpiry é quando o token da interação deixa de valer (com margem); a partir daí, ou
+	// quando uma edição falha por token inválido, o conteúdo vai para o fallback.
+	fallbackMu  sync.Mutex
+	fallback    func(content string) error
+	tokenExpiry time.Time
+	expired     bool
+}
+
+// StartProgress adia a resposta da interação e inicia o reporter de progresso.
+// Finish deve ser chamado ao final da operação para publicar o resumo.
+func StartProgress(ctx *Context, title string, ephemeral bool) (*ProgressReporter, error) {
+	if err := NewResponseManager(ctx.API).DeferResponse(ctx.Interaction, ephemeral); err != nil {
+		return nil, fmt.Errorf("defer response: %w", err)
+	}
+	ctx.markDeferred(ephemeral)
+	return NewProgressReporter(ctx.API, ctx.Interaction, title, DefaultProgressInterval), nil
+}
+
+// NewProgressReporter cria um reporter para uma interação já adiada.
+func NewProgressReporter(session SessionAPI, i *discordgo.InteractionCreate, title string, interval time.Duration) *ProgressReporter {
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	p := &ProgressReporter{
+		session:     session,
+		interaction: i,
+		title:       title,
+		interval:    interval,
+		updates:     make(chan ProgressUpdate, 64),
+		done:        make(chan struct{}),
+		tokenExpiry: time.Now().Add(InteractionTokenTTL - progressTokenMargin),
+	}
+	go p.loop()
+	return p
+}
+
+// SetExpiredFallback define onde publicar o progresso e o resumo quando a operação passa dos 15
+// minutos de validade do token da interação (ex.: uma DM a quem invocou o comando, editada a cada
+// chamada). fn recebe o conteúdo completo a exibir. Sem fallback, as edições depois da expiração
+// falham e o resumo de Finish é perdido.
+func (p *ProgressReporter) SetExpiredFallback(fn func(content string) error) {
+	p.fallbackMu.Lock()
+	p.fallback = fn
+	p.fallbackMu.Unlock()
+}
+
+// publish edita a resposta da interação com content ou, com o token expirado, usa o fallback.
+func (p *ProgressReporter) publish(content string) error {
+	p.fallbackMu.Lock()
+	fallback := p.fallback
+	if fallback != nil && !p.expired && !time.Now().Before(p.tokenExpiry) {
+		p.expired = true
+	}
+	expired := p.expired
+	p.fallbackMu.Unlock()
+	if fallback != nil && expired {
+		return fallback(content)
+	}
+
+	_, err := p.session.InteractionResponseEdit(p.interaction.Interaction, &discordgo.WebhookEdit{Content: &content})
+	var restErr *discordgo.RESTError
+	if fallback != nil && errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeInvalidWebhookTokenProvided {
+		p.fallbackMu.Lock()
+		p.expired = true
+		p.fallbackMu.Unlock()
+		return fallback(content)
+	}
+	return err
+}
+
+// Report publica um passo de progresso sem bloquear; se o buffer estiver cheio,
+// o passo é descartado (um mais recente será exibido de qualquer forma).
+func (p *ProgressReporter) Report(done, total int, message string) {
+	p.finishMu.Lock()
+	defer p.finishMu.Unlock()
+	if p.finished {
+		return
+	}
+	select {
+	case p.updates <- ProgressUpdate{Done: done, Total: total, Message: message}:
+	default:
+	}
+}
+
+// Finish encerra o reporter e edita a resposta com o resumo final.
+func (p *ProgressReporter) Finish(summary string) error {
+	p.finishMu.Lock()
+	if p.finished {
+		p.finishMu.Unlock()
+		return nil
+	}
+	p.finished = true
+	close(p.updates)
+	p.finishMu.Unlock()
+
+	<-p.done
+	content := summary
+	if p.title != "" {
+		content = "**" + p.title + "**\n" + summary
+	}
+	return p.publish(content)
+}
+
+func (p *ProgressReporter) loop() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var latest ProgressUpdate
+	dirty := false
+	for {
+		select {
+		case u, ok := <-p.updates:
+			if !ok {
+				return
+			}
+			latest, dirty = u, true
+		case <-ticker.C:
+			if !dirty {
+				continue
+			}
+			dirty = false
+			// Falhas de edição são ignoradas; a próxima atualização tenta de novo
+			_ = p.publish(p.render(latest))
+		}
+	}
+}
+
+func (p *ProgressReporter) render(u ProgressUpdate) string {
+	var b strings.Builder
+	if p.title != "" {
+		b.WriteString("**" + p.title + "**\n")
+	}
+	if u.Total > 0 {
+		done := min(max(u.Done, 0), u.Total)
+		filled := done * progressBarWidth / u.Total
+		pct := done * 100 / u.Total
+		fmt.Fprintf(&b, "`[%s%s]` %d%% (%d/%d)", strings.Repeat("█", filled), strings.Repeat("░", progressBarWidth-filled), pct, done, u.Total)
+	} else {
+		fmt.Fprintf(&b, "⏳ %d processed", max(u.Done, 0))
+	}
+	if u.Message != "" {
+		b.WriteString(" — " + u.Message)
+	}
+	return b.String()
+}
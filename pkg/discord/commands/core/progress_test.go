@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core/fakesession"
+)
+
+func lastEdit(t *testing.T, fake *fakesession.Session) string {
+	t.Helper()
+	edits := fake.CallsTo(fakesession.MethodInteractionResponseEdit)
+	if len(edits) == 0 {
+		t.Fatal("no response edit recorded")
+	}
+	return *edits[len(edits)-1].Edit.Content
+}
+
+func waitForEdit(t *testing.T, fake *fakesession.Session) string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(fake.CallsTo(fakesession.MethodInteractionResponseEdit)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return lastEdit(t, fake)
+}
+
+func TestProgressReporterClampsDone(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		done, tot  int
+		wantSuffix string
+	}{
+		{"over total", 15, 10, "100% (10/10)"},
+		{"negative", -3, 10, "0% (0/10)"},
+		{"in range", 5, 10, "50% (5/10)"},
+		{"indeterminate negative", -1, 0, "⏳ 0 processed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := fakesession.New()
+			p := NewProgressReporter(fake, fakesession.SlashCommand("g1", "u1", "bulk"), "", 10*time.Millisecond)
+			p.Report(tc.done, tc.tot, "")
+			got := waitForEdit(t, fake)
+			if !strings.HasSuffix(got, tc.wantSuffix) {
+				t.Fatalf("progress = %q, want suffix %q", got, tc.wantSuffix)
+			}
+			if err := p.Finish("done"); err != nil {
+				t.Fatalf("Finish: %v", err)
+			}
+		})
+	}
+}
+
+func TestProgressReporterIgnoresReportsAfterFinish(t *testing.T) {
+	fake := fakesession.New()
+	p := NewProgressReporter(fake, fakesession.SlashCommand("g1", "u1", "bulk"), "Bulk", time.Hour)
+	p.Report(1, 2, "")
+	if err := p.Finish("all done"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	p.Report(2, 2, "late") // must not panic on the closed channel
+	if err := p.Finish("again"); err != nil {
+		t.Fatalf("second Finish: %v", err)
+	}
+	if got := lastEdit(t, fake); got != "**Bulk**\nall done" {
+		t.Fatalf("final edit = %q", got)
+	}
+}
+
+func TestProgressReporterFallsBackWhenTokenExpires(t *testing.T) {
+	fake := fakesession.New()
+	p := NewProgressReporter(fake, fakesession.SlashCommand("g1", "u1", "bulk"), "Bulk", time.Hour)
+	var fallback []string
+	p.SetExpiredFallback(func(content string) error {
+		fallback = append(fallback, content)
+		return nil
+	})
+	fake.Fail(fakesession.MethodInteractionResponseEdit, &discordgo.RESTError{
+		Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeInvalidWebhookTokenProvided, Message: "Invalid Webhook Token"},
+	})
+
+	if err := p.Finish("done after 20 minutes"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if len(fallback) != 1 || fallback[0] != "**Bulk**\ndone after 20 minutes" {
+		t.Fatalf("fallback got %q, want the summary", fallback)
+	}
+}
+
+func TestProgressReporterWithoutFallbackReturnsEditError(t *testing.T) {
+	fake := fakesession.New()
+	p := NewProgressReporter(fake, fakesession.SlashCommand("g1", "u1", "bulk"), "", time.Hour)
+	fake.Fail(fakesession.MethodInteractionResponseEdit, &discordgo.RESTError{
+		Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeInvalidWebhookTokenProvided},
+	})
+	if err := p.Finish("summary"); err == nil {
+		t.Fatal("expected the edit error without a fallback")
+	}
+}
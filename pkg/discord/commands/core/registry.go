@@ -1,13 +1,21 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 	"github.com/small-frappuccino/discordcore/pkg/storage"
+	"github.com/small-frappuccino/discordcore/pkg/util"
 )
 
 // CommandRouter gerencia o roteamento e execução de comandos
@@ -17,17 +25,41 @@ type CommandRouter struct {
 	responder       *Responder
 	permChecker     *PermissionChecker
 	autocompleteMap map[string]AutocompleteHandler
+	componentMap    map[string]ComponentHandler
+	store           *storage.Store // contadores de uso (command_usage); nil desativa
+	inFlight        *inFlightLimiter
+	cooldowns       CooldownStore
+	baseCtx         context.Context // pai dos contextos das interações (SetBaseContext)
+
+	// Resposta (ephemeral) para comandos que o Discord ainda envia mas não estão registrados aqui;
+	// tem precedência sobre unknown_command_message do settings.json
+	unknownCommandMessage string
 }
 
+// DefaultUnknownCommandMessage é a resposta padrão para comandos desconhecidos (registro obsoleto no Discord).
+const DefaultUnknownCommandMessage = "This command is no longer available. It may have been renamed or removed."
+
 // NewCommandRouter cria um novo roteador de comandos
 func NewCommandRouter(
 	session *discordgo.Session,
 	configManager *files.ConfigManager,
 ) *CommandRouter {
+	return NewCommandRouterWithAPI(sessionAPI(session), configManager)
+}
+
+// NewCommandRouterWithAPI cria o roteador sobre qualquer SessionAPI (ex.: fakesession.Session
+// em testes, chamando HandleInteraction diretamente).
+func NewCommandRouterWithAPI(session SessionAPI, configManager *files.ConfigManager) *CommandRouter {
 	registry := NewCommandRegistry()
 	responder := NewResponder(session)
 	permChecker := NewPermissionChecker(session, configManager)
 	contextBuilder := NewContextBuilder(session, configManager, permChecker)
+	paginator := NewPaginator(session, DefaultPaginatorIdle)
+	contextBuilder.paginator = paginator
+	maxInFlight := files.DefaultMaxInFlightCommands
+	if configManager != nil {
+		maxInFlight = configManager.MaxInFlightCommands()
+	}
 
 	return &CommandRouter{
 		registry:        registry,
@@ -35,12 +67,28 @@ func NewCommandRouter(
 		responder:       responder,
 		permChecker:     permChecker,
 		autocompleteMap: make(map[string]AutocompleteHandler),
+		componentMap:    map[string]ComponentHandler{PaginatorPrefix: paginator},
+		inFlight:        newInFlightLimiter(maxInFlight),
+		cooldowns:       NewMemoryCooldownStore(),
 	}
 }
 
-// RegisterCommand registra um comando simples
-func (cr *CommandRouter) RegisterCommand(cmd Command) {
-	cr.registry.Register(cmd)
+// RegisterCommand registra um comando simples; opts definem metadados como a visibilidade
+// padrão das respostas (EphemeralByDefault)
+func (cr *CommandRouter) RegisterCommand(cmd Command, opts ...RegisterOption) {
+	cr.registry.Register(cmd, opts...)
+}
+
+// CommandCount retorna quantos comandos de topo estão registrados para o ambiente ativo
+func (cr *CommandRouter) CommandCount() int {
+	env := util.CurrentEnvironment()
+	n := 0
+	for _, cmd := range cr.registry.GetAllCommands() {
+		if AvailableIn(cmd, env) {
+			n++
+		}
+	}
+	return n
 }
 
 // RegisterSubCommand registra um subcomando
@@ -53,35 +101,78 @@ func (cr *CommandRouter) RegisterAutocomplete(commandName string, handler Autoco
 	cr.autocompleteMap[commandName] = handler
 }
 
+// SetUnknownCommandMessage fixa a resposta para comandos desconhecidos, ignorando
+// unknown_command_message do settings.json (vazio volta a usar a configuração ou o padrão)
+func (cr *CommandRouter) SetUnknownCommandMessage(message string) {
+	cr.unknownCommandMessage = message
+}
+
+// RegisterComponent registra um handler de componentes para CustomIDs com o prefixo dado
+func (cr *CommandRouter) RegisterComponent(prefix string, handler ComponentHandler) {
+	cr.componentMap[prefix] = handler
+}
+
 // HandleInteraction roteia interações para os handlers apropriados
 func (cr *CommandRouter) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Rede de segurança para o roteamento; panics dos handlers são tratados em safeHandle
+	defer errutil.RecoverPanic(errors.CategoryCommand, "route interaction "+i.ID, nil)
+
 	if IsAutocompleteInteraction(i) {
-		cr.handleAutocomplete(i)
+		reqCtx, cancel := cr.requestContext(i)
+		defer cancel()
+		cr.handleAutocomplete(reqCtx, i)
+		return
+	}
+
+	component := IsComponentInteraction(i)
+	if !component && !IsSlashCommandInteraction(i) {
 		return
 	}
 
-	if !IsSlashCommandInteraction(i) {
+	// Autocomplete fica fora do limite (é barato e não aceita a resposta de "ocupado")
+	if !cr.inFlight.acquire() {
+		log.Warn().Applicationf("Interaction rejected (max in-flight reached): limit=%d, guildID=%s, interactionType=%d", cr.MaxInFlight(), i.GuildID, i.Type)
+		cr.responder.Ephemeral(i, BusyMessage)
 		return
 	}
+	defer cr.inFlight.release()
 
-	cr.handleSlashCommand(i)
+	// Cancelado quando o handler retorna ou o token da interação expira
+	reqCtx, cancel := cr.requestContext(i)
+	defer cancel()
+	if component {
+		cr.handleComponent(reqCtx, i)
+		return
+	}
+	cr.handleSlashCommand(reqCtx, i)
 }
 
 // handleSlashCommand processa comandos slash
-func (cr *CommandRouter) handleSlashCommand(i *discordgo.InteractionCreate) {
+func (cr *CommandRouter) handleSlashCommand(reqCtx context.Context, i *discordgo.InteractionCreate) {
 	ctx := cr.contextBuilder.BuildContext(i)
+	ctx.Ctx = reqCtx
 	commandName := i.ApplicationCommandData().Name
 
 	ctx.Logger.Info().Applicationf("Processing slash command")
 
 	// Verificar se o comando existe
 	cmd, exists := cr.registry.GetCommand(commandName)
-	if !exists {
-		ctx.Logger.Error().Errorf("Command not found")
-		cr.responder.Error(i, "Command not found")
+	if !exists || !AvailableIn(cmd, util.CurrentEnvironment()) {
+		cr.handleUnknownCommand(ctx, commandName)
 		return
 	}
 
+	// Defensivo: o registro por guild já esconde comandos desativados, mas pode estar defasado
+	if !ctx.GuildConfig.CommandEnabled(CommandPath(i)) {
+		ctx.Logger.Warn().Applicationf("Disabled command invoked: /%s, guildID=%s; guild command registration out of date?", CommandPath(i), ctx.GuildID)
+		cr.responder.Ephemeral(i, DisabledCommandMessage)
+		return
+	}
+
+	if ctx.OperatorOverride {
+		ctx.Logger.Warn().Applicationf("🔑 Bot operator override used: userID=%s, guildID=%s, check=guild owner, command=/%s", ctx.UserID, ctx.GuildID, CommandPath(i))
+	}
+
 	// Verificar se requer servidor
 	if cmd.RequiresGuild() && ctx.GuildID == "" {
 		ctx.Logger.Warn().Applicationf("Command used outside of guild")
@@ -90,38 +181,108 @@ func (cr *CommandRouter) handleSlashCommand(i *discordgo.InteractionCreate) {
 	}
 
 	// Verificar permissões
+	if !authorize(ctx, cr.registry.Access(commandName), cr.permChecker, CommandPath(i)) {
+		cr.responder.Error(i, PermissionDeniedMessage)
+		return
+	}
 	if cmd.RequiresPermissions() && !cr.permChecker.HasPermission(ctx.GuildID, ctx.UserID) {
 		ctx.Logger.Warn().Applicationf("User without permission tried to use command")
-		cr.responder.Error(i, "You do not have permission to use this command")
+		cr.responder.Error(i, PermissionDeniedMessage)
+		return
+	}
+
+	if !cr.checkCooldown(ctx, cmd) {
 		return
 	}
 
 	// Executar comando
+	ctx.Ephemeral = cr.registry.DefaultEphemeral(commandName)
 	ctx.Logger.Info().Applicationf("Executing command")
-	if err := cmd.Handle(ctx); err != nil {
+	err := safeHandle("/"+CommandPath(i), func() error { return cmd.Handle(ctx) })
+	cr.recordUsage(ctx.GuildID, CommandPath(i), err != nil)
+	if err != nil {
 		ctx.Logger.Error().Errorf("Command execution failed: %v", err)
 
 		// Verificar se é um erro específico de comando
+		message := "❌ An error occurred while executing the command"
 		if cmdErr, ok := err.(*CommandError); ok {
-			if cmdErr.Ephemeral {
-				cr.responder.Ephemeral(i, cmdErr.Message)
-			} else {
-				cr.responder.Error(i, cmdErr.Message)
+			message = cmdErr.Message
+			if !cmdErr.Ephemeral {
+				message = "❌ " + message
 			}
-		} else {
-			cr.responder.Error(i, "An error occurred while executing the command")
 		}
+		cr.replyError(ctx, message)
+	}
+}
+
+// safeHandle executa um handler convertendo um panic em erro, para que o router responda com a
+// mensagem genérica de erro em vez de derrubar o bot; o panic é logado com stack trace e contado
+// em errutil.RecoveredPanics.
+func safeHandle(operation string, fn func() error) (err error) {
+	defer errutil.RecoverPanic(errors.CategoryCommand, operation, func(r any) {
+		err = fmt.Errorf("panic: %v", r)
+	})
+	return fn()
+}
+
+// recordUsage incrementa o contador diário do comando em background, para não atrasar a resposta.
+func (cr *CommandRouter) recordUsage(guildID, command string, failed bool) {
+	if cr.store == nil {
+		return
+	}
+	store := cr.store
+	go func() {
+		if err := store.IncrementCommandUsage(guildID, command, failed, time.Now()); err != nil {
+			log.Warn().Applicationf("Failed to record usage of /%s: %v", command, err)
+		}
+	}()
+}
+
+// CommandPath retorna o nome completo do comando invocado, incluindo grupo e subcomando
+// (ex.: "admin emoji-stats").
+func CommandPath(i *discordgo.InteractionCreate) string {
+	data := i.ApplicationCommandData()
+	parts := []string{data.Name}
+	options := data.Options
+	for len(options) > 0 {
+		opt := options[0]
+		if opt.Type != discordgo.ApplicationCommandOptionSubCommandGroup && opt.Type != discordgo.ApplicationCommandOptionSubCommand {
+			break
+		}
+		parts = append(parts, opt.Name)
+		options = opt.Options
+	}
+	return strings.Join(parts, " ")
+}
+
+// handleUnknownCommand responde a comandos sem handler. Isso indica drift entre os comandos
+// registrados no Discord e os do router, então o evento é registrado para o operador.
+func (cr *CommandRouter) handleUnknownCommand(ctx *Context, commandName string) {
+	i := ctx.Interaction
+	ctx.Logger.Warn().Applicationf("Received interaction for unknown command /%s (commandID=%s, guildID=%s); stale command registration on Discord?",
+		commandName, i.ApplicationCommandData().ID, ctx.GuildID)
+
+	message := cr.unknownCommandMessage
+	if message == "" && cr.contextBuilder.configManager != nil {
+		message = cr.contextBuilder.configManager.UnknownCommandMessage()
+	}
+	if message == "" {
+		message = DefaultUnknownCommandMessage
+	}
+	if err := cr.responder.Ephemeral(i, message); err != nil {
+		ctx.Logger.Error().Errorf("Failed to reply to unknown command /%s: %v", commandName, err)
 	}
 }
 
 // handleAutocomplete processa interações de autocomplete
-func (cr *CommandRouter) handleAutocomplete(i *discordgo.InteractionCreate) {
+func (cr *CommandRouter) handleAutocomplete(reqCtx context.Context, i *discordgo.InteractionCreate) {
 	ctx := cr.contextBuilder.BuildContext(i)
+	ctx.Ctx = reqCtx
 	commandName := i.ApplicationCommandData().Name
 
 	// Buscar handler de autocomplete
-	handler, exists := cr.autocompleteMap[commandName]
-	if !exists {
+	handler, exists := cr.autocompleteHandler(commandName)
+	if !exists || !ctx.GuildConfig.CommandEnabled(CommandPath(i)) || !cr.registry.Access(commandName).Allows(i.Member, adminRoles(ctx.GuildConfig)) {
 		cr.responder.Autocomplete(i, []*discordgo.ApplicationCommandOptionChoice{})
 		return
 	}
@@ -134,7 +295,11 @@ func (cr *CommandRouter) handleAutocomplete(i *discordgo.InteractionCreate) {
 	}
 
 	// Executar autocomplete
-	choices, err := handler.HandleAutocomplete(ctx, focusedOpt.Name)
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	err := safeHandle("autocomplete /"+commandName, func() (err error) {
+		choices, err = handler.HandleAutocomplete(ctx, focusedOpt.Name)
+		return err
+	})
 	if err != nil {
 		ctx.Logger.Error().Errorf("Autocomplete handler failed: %v", err)
 		choices = []*discordgo.ApplicationCommandOptionChoice{}
@@ -143,11 +308,45 @@ func (cr *CommandRouter) handleAutocomplete(i *discordgo.InteractionCreate) {
 	cr.responder.Autocomplete(i, choices)
 }
 
+// handleComponent processa interações de componentes pelo prefixo do CustomID
+func (cr *CommandRouter) handleComponent(reqCtx context.Context, i *discordgo.InteractionCreate) {
+	ctx := cr.contextBuilder.BuildContext(i)
+	ctx.Ctx = reqCtx
+	customID := i.MessageComponentData().CustomID
+	prefix, _, _ := strings.Cut(customID, ":")
+
+	handler, exists := cr.componentMap[prefix]
+	if !exists {
+		ctx.Logger.Warn().Applicationf("No component handler for custom ID %s", customID)
+		cr.responder.Ephemeral(i, "This action is no longer available")
+		return
+	}
+
+	if handler.RequiresPermissions() && !cr.permChecker.HasPermission(ctx.GuildID, ctx.UserID) {
+		ctx.Logger.Warn().Applicationf("User without permission tried to use component %s", prefix)
+		cr.responder.Ephemeral(i, "You do not have permission to use this action")
+		return
+	}
+
+	if err := safeHandle("component "+prefix, func() error { return handler.HandleComponent(ctx) }); err != nil {
+		ctx.Logger.Error().Errorf("Component handler failed: %v", err)
+		if cmdErr, ok := err.(*CommandError); ok {
+			cr.responder.Ephemeral(i, cmdErr.Message)
+		} else {
+			cr.responder.Ephemeral(i, "An error occurred while handling this action")
+		}
+	}
+}
+
 // CommandManager gerencia o ciclo de vida dos comandos no Discord
 type CommandManager struct {
 	session *discordgo.Session
 	router  *CommandRouter
 	logger  *log.Logger
+
+	syncMu         sync.Mutex
+	scopeSignature string // escopo aplicado na última sincronização (commandScopeSignature)
+	globalNames    string // comandos registrados globalmente na última sincronização (commandNames)
 }
 
 // NewCommandManager cria um novo gerenciador de comandos
@@ -177,81 +376,20 @@ func (cm *CommandManager) SetupCommands() error {
 		return fmt.Errorf("session not properly initialized")
 	}
 
-	// Obter comandos já registrados no Discord
-	registered, err := cm.session.ApplicationCommands(cm.session.State.User.ID, "")
-	if err != nil {
-		return fmt.Errorf("failed to fetch registered commands: %w", err)
-	}
-
-	// Criar mapa de comandos registrados
-	regByName := make(map[string]*discordgo.ApplicationCommand, len(registered))
-	for _, rc := range registered {
-		regByName[rc.Name] = rc
-	}
-
-	// Criar mapa de comandos do código
-	codeCommands := cm.router.registry.GetAllCommands()
-	codeByName := make(map[string]Command, len(codeCommands))
-	for name, cmd := range codeCommands {
-		codeByName[name] = cmd
-	}
-
-	// Criar/Atualizar comandos conforme necessário
-	created, updated, unchanged := 0, 0, 0
-	for name, cmd := range codeCommands {
-		desired := &discordgo.ApplicationCommand{
-			Name:        cmd.Name(),
-			Description: cmd.Description(),
-			Options:     cmd.Options(),
-		}
-
-		if existing, ok := regByName[name]; ok {
-			// Comando já existe, verificar se precisa atualizar
-			if CompareCommands(existing, desired) {
-				cm.logger.Info().Applicationf("Command unchanged, skipping: %s", name)
-				unchanged++
-				continue
-			}
-
-			// Atualizar comando
-			if _, err := cm.session.ApplicationCommandEdit(cm.session.State.User.ID, "", existing.ID, desired); err != nil {
-				return fmt.Errorf("error updating command '%s': %w", name, err)
-			}
-			cm.logger.Info().Applicationf("Command updated: %s", name)
-			updated++
-		} else {
-			// Criar novo comando
-			if _, err := cm.session.ApplicationCommandCreate(cm.session.State.User.ID, "", desired); err != nil {
-				return fmt.Errorf("error creating command '%s': %w", name, err)
-			}
-			cm.logger.Info().Applicationf("Command created: %s", name)
-			created++
-		}
-	}
-
-	// Remover comandos órfãos (existem no Discord mas não no código)
-	deleted := 0
-	for _, rc := range registered {
-		if _, exists := codeByName[rc.Name]; !exists {
-			if err := cm.session.ApplicationCommandDelete(cm.session.State.User.ID, "", rc.ID); err != nil {
-				cm.logger.Warn().Applicationf("Error removing orphan command: %s, error: %v", rc.Name, err)
-				continue
-			}
-			cm.logger.Info().Applicationf("Orphan command removed: %s", rc.Name)
-			deleted++
-		}
-	}
-	// Log do resumo
-	cm.logger.Info().Applicationf("Command synchronization completed: created=%d, updated=%d, deleted=%d, unchanged=%d, total=%d, mode=incremental", created, updated, deleted, unchanged, len(codeCommands))
-
-	return nil
+	return cm.syncCommands(true, nil)
 }
 
-// GroupCommand representa um comando que contém subcomandos
+// GroupCommand representa um comando que contém subcomandos e, opcionalmente, grupos de
+// subcomandos (/config automod enable). O Discord aceita um único nível de grupos.
 type GroupCommand struct {
 	name        string
 	description string
 	subcommands map[string]SubCommand
+	groups      map[string]*GroupCommand
+	order       []string                 // subcomandos e grupos na ordem em que foram adicionados
+	ephemeral   map[string]bool          // visibilidade padrão por subcomando; ausente herda do grupo
+	access      map[string]AccessRule    // restrições de acesso por subcomando, além das do comando
+	cooldown    map[string]time.Duration // cooldown próprio por subcomando
 	responder   *Responder
 	checker     *PermissionChecker
 }
@@ -262,14 +400,72 @@ func NewGroupCommand(name, description string, responder *Responder, checker *Pe
 		name:        name,
 		description: description,
 		subcommands: make(map[string]SubCommand),
+		groups:      make(map[string]*GroupCommand),
+		ephemeral:   make(map[string]bool),
+		access:      make(map[string]AccessRule),
+		cooldown:    make(map[string]time.Duration),
 		responder:   responder,
 		checker:     checker,
 	}
 }
 
-// AddSubCommand adiciona um subcomando ao grupo
-func (gc *GroupCommand) AddSubCommand(subcmd SubCommand) {
+// AddSubCommand adiciona um subcomando ao grupo; sem opções de visibilidade, o subcomando
+// herda a do grupo. RequirePermissions e RequireRoles restringem o subcomando além do que o
+// comando já exige.
+func (gc *GroupCommand) AddSubCommand(subcmd SubCommand, opts ...RegisterOption) {
+	gc.add(subcmd.Name())
 	gc.subcommands[subcmd.Name()] = subcmd
+	reg := applyRegisterOptions(opts)
+	if reg.ephemeral != nil {
+		gc.ephemeral[subcmd.Name()] = *reg.ephemeral
+	}
+	if reg.access.Restricted() {
+		gc.access[subcmd.Name()] = reg.access
+	}
+	if reg.cooldown > 0 {
+		gc.cooldown[subcmd.Name()] = reg.cooldown
+	}
+}
+
+// SubCommand adiciona um subcomando a partir de uma função (ver NewSubCommand) e retorna o
+// próprio comando, para encadear:
+//
+//	cmd.SubCommand("status", "Show the status", handleStatus).
+//		Group("automod", "Automod settings",
+//			core.NewSubCommand("enable", "Enable automod", handleEnable),
+//			core.NewSubCommand("disable", "Disable automod", handleDisable))
+func (gc *GroupCommand) SubCommand(name, description string, handler func(ctx *Context) error, options ...*discordgo.ApplicationCommandOption) *GroupCommand {
+	gc.AddSubCommand(NewSubCommand(name, description, handler, options...))
+	return gc
+}
+
+// Group adiciona um grupo de subcomandos (/comando grupo subcomando) e retorna o próprio
+// comando. Um grupo ou subcomando já existente com o mesmo nome é substituído.
+func (gc *GroupCommand) Group(name, description string, subcmds ...SubCommand) *GroupCommand {
+	group := NewGroupCommand(name, description, gc.responder, gc.checker)
+	for _, subcmd := range subcmds {
+		group.AddSubCommand(subcmd)
+	}
+	gc.add(name)
+	gc.groups[name] = group
+	return gc
+}
+
+// add registra name na ordem das opções, removendo um subcomando ou grupo anterior com o mesmo nome.
+func (gc *GroupCommand) add(name string) {
+	delete(gc.subcommands, name)
+	delete(gc.groups, name)
+	delete(gc.ephemeral, name)
+	delete(gc.access, name)
+	delete(gc.cooldown, name)
+	gc.order = slices.DeleteFunc(gc.order, func(n string) bool { return n == name })
+	gc.order = append(gc.order, name)
+}
+
+// NewSubCommand cria um subcomando a partir de uma função, sem exigir servidor nem
+// permissões próprias (para isso use NewSimpleCommand).
+func NewSubCommand(name, description string, handler func(ctx *Context) error, options ...*discordgo.ApplicationCommandOption) *SimpleCommand {
+	return NewSimpleCommand(name, description, options, handler, false, false)
 }
 
 // Name retorna o nome do comando
@@ -282,18 +478,28 @@ func (gc *GroupCommand) Description() string {
 	return gc.description
 }
 
-// Options constrói as opções do comando baseadas nos subcomandos
+// Options constrói as opções do comando baseadas nos subcomandos e grupos, na ordem em que
+// foram adicionados
 func (gc *GroupCommand) Options() []*discordgo.ApplicationCommandOption {
-	options := make([]*discordgo.ApplicationCommandOption, 0, len(gc.subcommands))
-
-	for _, subcmd := range gc.subcommands {
-		option := &discordgo.ApplicationCommandOption{
+	options := make([]*discordgo.ApplicationCommandOption, 0, len(gc.order))
+
+	for _, name := range gc.order {
+		if group, ok := gc.groups[name]; ok {
+			options = append(options, &discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Name:        group.Name(),
+				Description: group.Description(),
+				Options:     group.Options(),
+			})
+			continue
+		}
+		subcmd := gc.subcommands[name]
+		options = append(options, &discordgo.ApplicationCommandOption{
 			Type:        discordgo.ApplicationCommandOptionSubCommand,
 			Name:        subcmd.Name(),
 			Description: subcmd.Description(),
 			Options:     subcmd.Options(),
-		}
-		options = append(options, option)
+		})
 	}
 
 	return options
@@ -306,6 +512,11 @@ func (gc *GroupCommand) RequiresGuild() bool {
 			return true
 		}
 	}
+	for _, group := range gc.groups {
+		if group.RequiresGuild() {
+			return true
+		}
+	}
 	return false
 }
 
@@ -316,12 +527,28 @@ func (gc *GroupCommand) RequiresPermissions() bool {
 			return true
 		}
 	}
+	for _, group := range gc.groups {
+		if group.RequiresPermissions() {
+			return true
+		}
+	}
 	return false
 }
 
-// Handle roteia para o subcomando apropriado
+// Handle roteia para o subcomando apropriado, passando pelo grupo quando houver
 func (gc *GroupCommand) Handle(ctx *Context) error {
-	subCommandName := GetSubCommandName(ctx.Interaction)
+	if groupName := GetSubCommandGroupName(ctx.Interaction); groupName != "" {
+		group, exists := gc.groups[groupName]
+		if !exists {
+			return NewCommandError("Unknown subcommand group", true)
+		}
+		return group.handleSubCommand(ctx, GetSubCommandName(ctx.Interaction))
+	}
+	return gc.handleSubCommand(ctx, GetSubCommandName(ctx.Interaction))
+}
+
+// handleSubCommand executa o subcomando subCommandName deste comando (ou grupo)
+func (gc *GroupCommand) handleSubCommand(ctx *Context, subCommandName string) error {
 	if subCommandName == "" {
 		return NewCommandError("No subcommand specified", true)
 	}
@@ -340,6 +567,13 @@ func (gc *GroupCommand) Handle(ctx *Context) error {
 		return NewCommandError("You don't have permission to use this subcommand", true)
 	}
 
+	if !authorize(ctx, gc.access[subCommandName], gc.checker, CommandPath(ctx.Interaction)) {
+		return NewCommandError(PermissionDeniedMessage, true)
+	}
+
+	if ephemeral, ok := gc.ephemeral[subCommandName]; ok {
+		ctx.Ephemeral = ephemeral
+	}
 	return subcmd.Handle(ctx)
 }
 
@@ -349,6 +583,7 @@ type SimpleCommand struct {
 	description         string
 	options             []*discordgo.ApplicationCommandOption
 	handler             func(ctx *Context) error
+	autocomplete        AutocompleteFunc
 	requiresGuild       bool
 	requiresPermissions bool
 }
@@ -379,8 +614,14 @@ func (sc *SimpleCommand) Handle(ctx *Context) error { return sc.handler(ctx) }
 func (sc *SimpleCommand) RequiresGuild() bool       { return sc.requiresGuild }
 func (sc *SimpleCommand) RequiresPermissions() bool { return sc.requiresPermissions }
 
-// GetSession returns the Discord session from the context builder
+// GetSession returns the Discord session from the context builder (nil when the router was
+// built over another SessionAPI)
 func (cr *CommandRouter) GetSession() *discordgo.Session {
+	return rawSession(cr.contextBuilder.session)
+}
+
+// GetSessionAPI returns the session interface used for interaction responses
+func (cr *CommandRouter) GetSessionAPI() SessionAPI {
 	return cr.contextBuilder.session
 }
 
@@ -389,6 +630,11 @@ func (cr *CommandRouter) GetConfigManager() *files.ConfigManager {
 	return cr.contextBuilder.configManager
 }
 
+// GetPaginator returns the paginator behind the Previous/Next/Close buttons of paginated lists
+func (cr *CommandRouter) GetPaginator() *Paginator {
+	return cr.contextBuilder.paginator
+}
+
 // GetRegistry returns the command registry
 func (cr *CommandRouter) GetRegistry() *CommandRegistry {
 	return cr.registry
@@ -404,8 +650,10 @@ func (cr *CommandRouter) GetPermissionChecker() *PermissionChecker {
 	return cr.permChecker
 }
 
-// SetStore sets the shared store for the permission checker to enable local OwnerID cache usage.
+// SetStore sets the shared store for the permission checker to enable local OwnerID cache usage
+// and for the per-command usage counters (command_usage).
 func (cr *CommandRouter) SetStore(store *storage.Store) {
+	cr.store = store
 	if cr.permChecker != nil {
 		cr.permChecker.SetStore(store)
 	}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// InteractionTokenLifetime é a validade do token de uma interação: depois disso nem a resposta
+// nem follow-ups podem ser enviados, então o trabalho do handler é cancelado.
+const InteractionTokenLifetime = 15 * time.Minute
+
+// RequestInfo são os valores de escopo da interação carregados em Context.Ctx.
+type RequestInfo struct {
+	InteractionID string
+	GuildID       string
+	UserID        string
+	Command       string    // caminho do comando (ex.: "admin bulk-role") ou CustomID do componente
+	ReceivedAt    time.Time // criação da interação (do snowflake), base do prazo
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo retorna uma cópia de ctx carregando info.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom retorna os valores da interação carregados em ctx, se houver.
+func RequestInfoFrom(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// SetBaseContext define o contexto do qual derivam os contextos das interações; cancelá-lo (ex.:
+// no shutdown) cancela o trabalho de todos os handlers em andamento. Padrão: context.Background().
+func (cr *CommandRouter) SetBaseContext(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cr.baseCtx = ctx
+}
+
+// requestContext cria o contexto de uma interação: carrega RequestInfo e expira junto com o
+// token da interação. O cancel deve ser chamado quando o handler retornar.
+func (cr *CommandRouter) requestContext(i *discordgo.InteractionCreate) (context.Context, context.CancelFunc) {
+	base := cr.baseCtx
+	if base == nil {
+		base = context.Background()
+	}
+	received := time.Now()
+	if t, err := discordgo.SnowflakeTimestamp(i.ID); err == nil {
+		received = t
+	}
+	info := RequestInfo{
+		InteractionID: i.ID,
+		GuildID:       i.GuildID,
+		UserID:        extractUserID(i),
+		ReceivedAt:    received,
+	}
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		info.Command = CommandPath(i)
+	case discordgo.InteractionMessageComponent:
+		info.Command = i.MessageComponentData().CustomID
+	}
+	return context.WithDeadline(WithRequestInfo(base, info), received.Add(InteractionTokenLifetime))
+}
@@ -32,12 +32,12 @@ type ResponseConfig struct {
 
 // ResponseManager gerencia todas as respostas de interação
 type ResponseManager struct {
-	session *discordgo.Session
+	session SessionAPI
 	config  ResponseConfig
 }
 
 // NewResponseManager cria um novo gerenciador de respostas
-func NewResponseManager(session *discordgo.Session) *ResponseManager {
+func NewResponseManager(session SessionAPI) *ResponseManager {
 	return &ResponseManager{
 		session: session,
 		config:  ResponseConfig{},
@@ -320,7 +320,7 @@ type ResponseBuilder struct {
 }
 
 // NewResponseBuilder cria um novo construtor de respostas
-func NewResponseBuilder(session *discordgo.Session) *ResponseBuilder {
+func NewResponseBuilder(session SessionAPI) *ResponseBuilder {
 	return &ResponseBuilder{
 		manager: NewResponseManager(session),
 		config:  ResponseConfig{},
@@ -0,0 +1,144 @@
+package core_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core/fakesession"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// testCommand is a minimal command whose handler is set by each test.
+type testCommand struct {
+	name   string
+	handle func(ctx *Context) error
+}
+
+func (c *testCommand) Name() string                                   { return c.name }
+func (c *testCommand) Description() string                            { return "test command" }
+func (c *testCommand) Options() []*discordgo.ApplicationCommandOption { return nil }
+func (c *testCommand) RequiresGuild() bool                            { return true }
+func (c *testCommand) RequiresPermissions() bool                      { return false }
+func (c *testCommand) Handle(ctx *Context) error                      { return c.handle(ctx) }
+
+func newTestRouter(t *testing.T) (*CommandRouter, *fakesession.Session) {
+	t.Helper()
+	fake := fakesession.New()
+	fake.AddGuild(&discordgo.Guild{ID: "g1", OwnerID: "owner"})
+	config := files.NewConfigManagerWithPath(filepath.Join(t.TempDir(), "settings.json"))
+	return NewCommandRouterWithAPI(fake, config), fake
+}
+
+func responseContent(t *testing.T, fake *fakesession.Session) string {
+	t.Helper()
+	resp := fake.LastResponse()
+	if resp == nil || resp.Data == nil {
+		t.Fatal("no interaction response recorded")
+	}
+	return resp.Data.Content
+}
+
+func TestRouterRunsCommand(t *testing.T) {
+	router, fake := newTestRouter(t)
+	var gotUser, gotGuild string
+	router.RegisterCommand(&testCommand{name: "ping", handle: func(ctx *Context) error {
+		gotUser, gotGuild = ctx.UserID, ctx.GuildID
+		return ctx.Respond().Success(ctx.Interaction, "pong")
+	}})
+
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "ping"))
+	if gotUser != "u1" || gotGuild != "g1" {
+		t.Errorf("handler saw user %q guild %q", gotUser, gotGuild)
+	}
+	if got := responseContent(t, fake); !strings.Contains(got, "pong") {
+		t.Errorf("response = %q, want pong", got)
+	}
+}
+
+func TestRouterReportsCommandErrors(t *testing.T) {
+	router, fake := newTestRouter(t)
+	router.RegisterCommand(&testCommand{name: "fail", handle: func(*Context) error {
+		return NewCommandError("nope", true)
+	}})
+	router.RegisterCommand(&testCommand{name: "crash", handle: func(*Context) error {
+		return errors.New("internal detail")
+	}})
+
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "fail"))
+	if got := responseContent(t, fake); got != "nope" {
+		t.Errorf("command error reply = %q, want nope", got)
+	}
+	fake.Reset()
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "crash"))
+	if got := responseContent(t, fake); got != "❌ An error occurred while executing the command" {
+		t.Errorf("generic error reply = %q", got)
+	}
+}
+
+func TestRouterDeniesAccess(t *testing.T) {
+	router, fake := newTestRouter(t)
+	ran := false
+	router.RegisterCommand(&testCommand{name: "mod", handle: func(*Context) error {
+		ran = true
+		return nil
+	}}, RequirePermissions(discordgo.PermissionManageGuild))
+
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "mod"))
+	if ran {
+		t.Fatal("handler ran without permission")
+	}
+	if got := responseContent(t, fake); !strings.Contains(got, PermissionDeniedMessage) {
+		t.Errorf("denial reply = %q", got)
+	}
+
+	i := fakesession.SlashCommand("g1", "u1", "mod")
+	i.Member.Permissions = discordgo.PermissionManageGuild
+	router.HandleInteraction(nil, i)
+	if !ran {
+		t.Error("handler did not run with Manage Server")
+	}
+}
+
+func TestRouterUnknownCommand(t *testing.T) {
+	router, fake := newTestRouter(t)
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "missing"))
+	if got := responseContent(t, fake); got != DefaultUnknownCommandMessage {
+		t.Errorf("unknown command reply = %q", got)
+	}
+}
+
+func TestRouterUnknownCommandMessageFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{"guilds":[],"unknown_command_message":"Try /help instead."}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := files.NewConfigManagerWithPath(path)
+	if err := config.LoadConfig(); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	fake := fakesession.New()
+	fake.AddGuild(&discordgo.Guild{ID: "g1", OwnerID: "owner"})
+	router := NewCommandRouterWithAPI(fake, config)
+
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "missing"))
+	if got := responseContent(t, fake); got != "Try /help instead." {
+		t.Errorf("configured reply = %q", got)
+	}
+
+	// The setter overrides the config; clearing it falls back to the config again.
+	router.SetUnknownCommandMessage("Gone.")
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "missing"))
+	if got := responseContent(t, fake); got != "Gone." {
+		t.Errorf("overridden reply = %q", got)
+	}
+	router.SetUnknownCommandMessage("")
+	router.HandleInteraction(nil, fakesession.SlashCommand("g1", "u1", "missing"))
+	if got := responseContent(t, fake); got != "Try /help instead." {
+		t.Errorf("reply after clearing the override = %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+)
+
+// SessionAPI é o subconjunto da sessão do Discord usado pelo router e pelos helpers de resposta:
+// responder/editar/apagar a resposta de uma interação, follow-ups e busca de guild e membro.
+// *discordgo.Session a implementa; em testes, fakesession.Session grava as chamadas sem rede.
+type SessionAPI interface {
+	InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	InteractionResponseDelete(interaction *discordgo.Interaction, options ...discordgo.RequestOption) error
+	FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error)
+	GuildMember(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error)
+}
+
+// StateProvider é implementado por sessões (como a fake) que expõem um cache de estado próprio,
+// consultado antes das chamadas REST de Guild e GuildMember.
+type StateProvider interface {
+	SessionState() *discordgo.State
+}
+
+// sessionAPI converte a sessão real na interface, mantendo nil como nil (uma *discordgo.Session
+// nil dentro da interface não seria comparável a nil).
+func sessionAPI(s *discordgo.Session) SessionAPI {
+	if s == nil {
+		return nil
+	}
+	return s
+}
+
+// rawSession retorna a *discordgo.Session por trás da interface (nil para outras implementações).
+func rawSession(api SessionAPI) *discordgo.Session {
+	s, _ := api.(*discordgo.Session)
+	return s
+}
+
+// fetchMember busca um membro pela API: pelo discord.Client compartilhado (pacer e erros
+// classificados) quando api é a sessão real, ou pela própria api nas outras implementações.
+func fetchMember(api SessionAPI, guildID, userID string) (*discordgo.Member, error) {
+	if s := rawSession(api); s != nil {
+		return discord.ClientFor(s).FetchMember(context.Background(), guildID, userID)
+	}
+	return api.GuildMember(guildID, userID)
+}
+
+// stateOf retorna o cache de estado da sessão, se houver.
+func stateOf(api SessionAPI) *discordgo.State {
+	switch s := api.(type) {
+	case *discordgo.Session:
+		if s != nil {
+			return s.State
+		}
+	case StateProvider:
+		return s.SessionState()
+	}
+	return nil
+}
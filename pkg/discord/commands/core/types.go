@@ -1,6 +1,9 @@
 package core
 
 import (
+	"context"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
@@ -28,7 +31,12 @@ type SubCommand interface {
 
 // Context fornece contexto unificado para execução de comandos
 type Context struct {
-	Session     *discordgo.Session
+	// Ctx é cancelado quando o handler retorna, quando o token da interação expira
+	// (InteractionTokenLifetime) ou no shutdown; carrega RequestInfo. Passe-o às chamadas de
+	// store e de serviços. Trabalho que continua depois do retorno deve usar context.WithoutCancel(Ctx).
+	Ctx         context.Context
+	Session     *discordgo.Session // sessão real, para chamadas fora da interação (nil com uma SessionAPI fake)
+	API         SessionAPI         // respostas da interação e busca de guild/membro; prefira-a a Session
 	Interaction *discordgo.InteractionCreate
 	Config      *files.ConfigManager
 	Logger      *log.Logger
@@ -36,6 +44,19 @@ type Context struct {
 	UserID      string
 	IsOwner     bool
 	GuildConfig *files.GuildConfig
+	Paginator   *Paginator // listagens paginadas (SendPaginated)
+
+	// Ephemeral é a visibilidade padrão das respostas, vinda do registro do comando
+	// (EphemeralByDefault/PublicByDefault); aplicada por ctx.Respond()
+	Ephemeral bool
+
+	// OperatorOverride indica que IsOwner vem apenas de bot_operators (não é o dono da guild)
+	OperatorOverride bool
+
+	// Estado da resposta adiada (Defer): visibilidade do Defer e se a resposta já foi editada
+	deferred          bool
+	deferredEphemeral bool
+	edited            bool
 }
 
 // Response padroniza respostas de comandos
@@ -77,18 +98,52 @@ func (bh *BaseHandler) GetConfigManager() *files.ConfigManager {
 type CommandRegistry struct {
 	commands    map[string]Command
 	subcommands map[string]map[string]SubCommand // [commandName][subcommandName]
+	ephemeral   map[string]bool                  // visibilidade padrão por comando (RegisterOption)
+	access      map[string]AccessRule            // restrições de acesso por comando (RegisterOption)
+	cooldown    map[string]time.Duration         // cooldown por usuário de cada comando (RegisterOption)
 }
 
 func NewCommandRegistry() *CommandRegistry {
 	return &CommandRegistry{
 		commands:    make(map[string]Command),
 		subcommands: make(map[string]map[string]SubCommand),
+		ephemeral:   make(map[string]bool),
+		access:      make(map[string]AccessRule),
+		cooldown:    make(map[string]time.Duration),
 	}
 }
 
 // Register registra um comando no registry
-func (r *CommandRegistry) Register(cmd Command) {
+func (r *CommandRegistry) Register(cmd Command, opts ...RegisterOption) {
 	r.commands[cmd.Name()] = cmd
+	delete(r.ephemeral, cmd.Name())
+	delete(r.access, cmd.Name())
+	delete(r.cooldown, cmd.Name())
+	reg := applyRegisterOptions(opts)
+	if reg.ephemeral != nil {
+		r.ephemeral[cmd.Name()] = *reg.ephemeral
+	}
+	if reg.access.Restricted() {
+		r.access[cmd.Name()] = reg.access
+	}
+	if reg.cooldown > 0 {
+		r.cooldown[cmd.Name()] = reg.cooldown
+	}
+}
+
+// DefaultEphemeral informa se as respostas do comando são ephemeral por padrão
+func (r *CommandRegistry) DefaultEphemeral(name string) bool {
+	return r.ephemeral[name]
+}
+
+// Access retorna a restrição de acesso do comando (zero quando livre)
+func (r *CommandRegistry) Access(name string) AccessRule {
+	return r.access[name]
+}
+
+// Cooldown retorna o cooldown por usuário do comando (0 sem cooldown)
+func (r *CommandRegistry) Cooldown(name string) time.Duration {
+	return r.cooldown[name]
 }
 
 // RegisterSubCommand registra um subcomando no registry
@@ -147,6 +202,13 @@ type AutocompleteHandler interface {
 	HandleAutocomplete(ctx *Context, focusedOption string) ([]*discordgo.ApplicationCommandOptionChoice, error)
 }
 
+// ComponentHandler define um handler para interações de componentes (botões, selects).
+// Handlers são registrados por prefixo do CustomID (parte antes do primeiro ":").
+type ComponentHandler interface {
+	RequiresPermissions() bool
+	HandleComponent(ctx *Context) error
+}
+
 // PermissionLevel define níveis de permissão para comandos
 type PermissionLevel int
 
@@ -10,6 +10,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
 	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
 	"github.com/small-frappuccino/discordcore/pkg/storage"
 	"github.com/small-frappuccino/discordcore/pkg/theme"
 )
@@ -131,13 +132,13 @@ func (cp *ConfigPersister) SaveWithBackup(config *files.GuildConfig) error {
 // PermissionChecker gerencia verificação de permissões
 // PermissionChecker verifica permissões do usuário
 type PermissionChecker struct {
-	session *discordgo.Session
+	session SessionAPI
 	config  *files.ConfigManager
 	store   *storage.Store
 	cache   *cache.UnifiedCache
 }
 
-func NewPermissionChecker(session *discordgo.Session, config *files.ConfigManager) *PermissionChecker {
+func NewPermissionChecker(session SessionAPI, config *files.ConfigManager) *PermissionChecker {
 	return &PermissionChecker{session: session, config: config}
 }
 
@@ -149,12 +150,30 @@ func (pc *PermissionChecker) SetCache(unifiedCache *cache.UnifiedCache) {
 	pc.cache = unifiedCache
 }
 
-// HasPermission verifica se o usuário tem permissão para usar comandos
+// HasPermission verifica se o usuário tem permissão para usar comandos. Operadores do bot
+// (bot_operators) são autorizados em qualquer guild.
 func (pc *PermissionChecker) HasPermission(guildID, userID string) bool {
 	if guildID == "" {
 		return false
 	}
-	guildConfig := pc.config.GuildConfig(guildID)
+	if pc.hasPermission(guildID, userID) {
+		return true
+	}
+	return pc.OperatorOverride(guildID, userID, "command permission")
+}
+
+// OperatorOverride informa se userID é um operador do bot e, nesse caso, registra o uso do
+// override (reason descreve a checagem contornada) para auditoria.
+func (pc *PermissionChecker) OperatorOverride(guildID, userID, reason string) bool {
+	if pc == nil || !pc.config.IsBotOperator(userID) {
+		return false
+	}
+	log.Warn().Applicationf("🔑 Bot operator override used: userID=%s, guildID=%s, check=%s", userID, guildID, reason)
+	return true
+}
+
+func (pc *PermissionChecker) hasPermission(guildID, userID string) bool {
+	guildConfig, hasConfig := pc.config.GuildConfig(guildID)
 
 	// Try unified cache first
 	var ownerID string
@@ -164,8 +183,8 @@ func (pc *PermissionChecker) HasPermission(guildID, userID string) bool {
 		}
 	}
 	// Fallback: state cache
-	if ownerID == "" && pc.session != nil && pc.session.State != nil {
-		if g, _ := pc.session.State.Guild(guildID); g != nil {
+	if state := stateOf(pc.session); ownerID == "" && state != nil {
+		if g, _ := state.Guild(guildID); g != nil {
 			ownerID = g.OwnerID
 			if pc.cache != nil {
 				pc.cache.SetGuild(guildID, g)
@@ -194,7 +213,7 @@ func (pc *PermissionChecker) HasPermission(guildID, userID string) bool {
 	}
 	isOwner := ownerID == userID
 
-	if guildConfig == nil || len(guildConfig.AllowedRoles) == 0 {
+	if !hasConfig || len(guildConfig.AllowedRoles) == 0 {
 		return isOwner
 	}
 
@@ -211,8 +230,8 @@ func (pc *PermissionChecker) HasPermission(guildID, userID string) bool {
 		}
 	}
 	// Fallback: state cache
-	if member == nil && pc.session != nil && pc.session.State != nil {
-		if m, _ := pc.session.State.Member(guildID, userID); m != nil {
+	if state := stateOf(pc.session); member == nil && state != nil {
+		if m, _ := state.Member(guildID, userID); m != nil {
 			member = m
 			if pc.cache != nil {
 				pc.cache.SetMember(guildID, userID, m)
@@ -221,7 +240,7 @@ func (pc *PermissionChecker) HasPermission(guildID, userID string) bool {
 	}
 	// Fallback: REST
 	if member == nil {
-		member, err = pc.session.GuildMember(guildID, userID)
+		member, err = fetchMember(pc.session, guildID, userID)
 		if err != nil {
 			return false
 		}
@@ -249,8 +268,8 @@ func (pc *PermissionChecker) HasRole(guildID, userID, roleID string) bool {
 		}
 	}
 	// Fallback: state cache
-	if member == nil && pc.session != nil && pc.session.State != nil {
-		if m, _ := pc.session.State.Member(guildID, userID); m != nil {
+	if state := stateOf(pc.session); member == nil && state != nil {
+		if m, _ := state.Member(guildID, userID); m != nil {
 			member = m
 			if pc.cache != nil {
 				pc.cache.SetMember(guildID, userID, m)
@@ -259,7 +278,7 @@ func (pc *PermissionChecker) HasRole(guildID, userID, roleID string) bool {
 	}
 	// Fallback: REST
 	if member == nil {
-		member, err = pc.session.GuildMember(guildID, userID)
+		member, err = fetchMember(pc.session, guildID, userID)
 		if err != nil {
 			return false
 		}
@@ -271,11 +290,18 @@ func (pc *PermissionChecker) HasRole(guildID, userID, roleID string) bool {
 	return slices.Contains(member.Roles, roleID)
 }
 
-// IsOwner verifica se o usuário é dono do servidor
+// IsOwner verifica se o usuário é dono do servidor; operadores do bot também passam.
 func (pc *PermissionChecker) IsOwner(guildID, userID string) bool {
 	if guildID == "" {
 		return false
 	}
+	if pc.isOwner(guildID, userID) {
+		return true
+	}
+	return pc.OperatorOverride(guildID, userID, "guild owner")
+}
+
+func (pc *PermissionChecker) isOwner(guildID, userID string) bool {
 	// Try unified cache first
 	var ownerID string
 	if pc.cache != nil {
@@ -284,8 +310,8 @@ func (pc *PermissionChecker) IsOwner(guildID, userID string) bool {
 		}
 	}
 	// Fallback: state cache
-	if ownerID == "" && pc.session != nil && pc.session.State != nil {
-		if g, _ := pc.session.State.Guild(guildID); g != nil {
+	if state := stateOf(pc.session); ownerID == "" && state != nil {
+		if g, _ := state.Guild(guildID); g != nil {
 			ownerID = g.OwnerID
 			if pc.cache != nil {
 				pc.cache.SetGuild(guildID, g)
@@ -317,11 +343,11 @@ func (pc *PermissionChecker) IsOwner(guildID, userID string) bool {
 
 // Responder gerencia respostas padronizadas
 type Responder struct {
-	session *discordgo.Session
+	session SessionAPI
 }
 
 // NewResponder cria um novo respondedor
-func NewResponder(session *discordgo.Session) *Responder {
+func NewResponder(session SessionAPI) *Responder {
 	return &Responder{session: session}
 }
 
@@ -397,8 +423,12 @@ func (r *Responder) EditResponse(i *discordgo.InteractionCreate, content string)
 
 // Autocomplete envia uma resposta de autocomplete
 func (r *Responder) Autocomplete(i *discordgo.InteractionCreate, choices []*discordgo.ApplicationCommandOptionChoice) error {
-	if len(choices) > 25 {
-		choices = choices[:25]
+	if len(choices) > MaxAutocompleteChoices {
+		choices = choices[:MaxAutocompleteChoices]
+	}
+	if choices == nil {
+		// O Discord espera a lista, mesmo vazia
+		choices = []*discordgo.ApplicationCommandOptionChoice{}
 	}
 
 	return r.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -579,9 +609,9 @@ type ConfigurationUtils struct{}
 
 // EnsureGuildConfig garante que existe uma configuração para o servidor
 func (ConfigurationUtils) EnsureGuildConfig(configManager *files.ConfigManager, guildID string) *files.GuildConfig {
-	config := configManager.GuildConfig(guildID)
-	if config == nil {
-		config = &files.GuildConfig{
+	config, ok := configManager.GuildConfig(guildID)
+	if !ok {
+		config = files.GuildConfig{
 			GuildID:      guildID,
 			AllowedRoles: []string{},
 			Rulesets:     []files.Ruleset{},
@@ -589,7 +619,7 @@ func (ConfigurationUtils) EnsureGuildConfig(configManager *files.ConfigManager,
 			Blocklist:    []string{},
 		}
 	}
-	return config
+	return &config
 }
 
 // CompareCommands compara dois comandos para verificar se são semanticamente iguais
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RegisterOption ajusta os metadados de registro de um comando ou subcomando
+// (RegisterCommand, CommandRegistry.Register, GroupCommand.AddSubCommand).
+type RegisterOption func(*registration)
+
+type registration struct {
+	ephemeral *bool         // visibilidade padrão das respostas; nil herda do comando pai (ou pública)
+	access    AccessRule    // quem pode invocar (RequirePermissions, RequireRoles)
+	cooldown  time.Duration // intervalo mínimo entre invocações de cada usuário (Cooldown)
+}
+
+func applyRegisterOptions(opts []RegisterOption) registration {
+	var reg registration
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&reg)
+		}
+	}
+	return reg
+}
+
+// EphemeralByDefault faz as respostas do comando serem visíveis só para quem o invocou, a menos
+// que o handler peça o contrário (ctx.Respond().Public()). Use em config e diagnósticos.
+func EphemeralByDefault() RegisterOption {
+	return func(r *registration) {
+		v := true
+		r.ephemeral = &v
+	}
+}
+
+// PublicByDefault faz as respostas do comando serem públicas; útil para um subcomando de um
+// grupo registrado com EphemeralByDefault (ex.: estatísticas dentro de /admin).
+func PublicByDefault() RegisterOption {
+	return func(r *registration) {
+		v := false
+		r.ephemeral = &v
+	}
+}
+
+// Respond retorna um ResponseBuilder com a visibilidade padrão do comando (ctx.Ephemeral); o
+// handler pode sobrescrevê-la com Ephemeral() ou Public().
+func (ctx *Context) Respond() *ResponseBuilder {
+	rb := NewResponseBuilder(ctx.API)
+	rb.config.Ephemeral = ctx.Ephemeral
+	return rb
+}
+
+// Public torna a resposta visível no canal, sobrescrevendo a visibilidade padrão
+func (rb *ResponseBuilder) Public() *ResponseBuilder {
+	rb.config.Ephemeral = false
+	return rb
+}
+
+// Embed envia embed como resposta (método de conveniência)
+func (rb *ResponseBuilder) Embed(i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) error {
+	return rb.Build().Custom(i, "", []*discordgo.MessageEmbed{embed})
+}
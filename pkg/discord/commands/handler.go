@@ -15,6 +15,7 @@ type CommandHandler struct {
 	session        *discordgo.Session
 	configManager  *files.ConfigManager
 	commandManager *core.CommandManager
+	registrars     []func(router *core.CommandRouter)
 }
 
 // NewCommandHandler cria uma nova instância do command handler
@@ -40,6 +41,11 @@ func (ch *CommandHandler) SetupCommands() error {
 		return fmt.Errorf("failed to register config commands: %w", err)
 	}
 
+	// Registrar comandos adicionais (admin, extensões) antes da sincronização com o Discord
+	for _, register := range ch.registrars {
+		register(ch.commandManager.GetRouter())
+	}
+
 	// Configurar os comandos no Discord
 	if err := ch.commandManager.SetupCommands(); err != nil {
 		return fmt.Errorf("failed to setup commands: %w", err)
@@ -49,6 +55,12 @@ func (ch *CommandHandler) SetupCommands() error {
 	return nil
 }
 
+// AddRegistrar adiciona uma função que registra comandos no router. Deve ser chamada antes de
+// SetupCommands para que os comandos sejam incluídos na sincronização com o Discord.
+func (ch *CommandHandler) AddRegistrar(register func(router *core.CommandRouter)) {
+	ch.registrars = append(ch.registrars, register)
+}
+
 // registerConfigCommands registra os comandos de configuração
 func (ch *CommandHandler) registerConfigCommands() error {
 	router := ch.commandManager.GetRouter()
@@ -0,0 +1,255 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+	"github.com/small-frappuccino/discordcore/pkg/task"
+	"github.com/small-frappuccino/discordcore/pkg/util"
+)
+
+// TaskTypeActivityReport é o tipo da tarefa durável recorrente que posta o relatório de uma guild.
+const TaskTypeActivityReport = "activity_report"
+
+// Quantidade de linhas por seção do relatório.
+const (
+	activityReportTopChannels = 10
+	activityReportTopEmojis   = 5
+)
+
+// BuildActivityReport agrega as estatísticas guardadas da guild em [since, until) num embed:
+// mensagens por canal, emojis mais usados, entradas e saídas de membros e ações de automod.
+func BuildActivityReport(store *storage.Store, gcfg files.GuildConfig, since, until time.Time) (*discordgo.MessageEmbed, error) {
+	if store == nil {
+		return nil, fmt.Errorf("activity report requires a store")
+	}
+	locale := gcfg.Locale
+	if locale == "" {
+		locale = util.DefaultLocale
+	}
+
+	channels, err := store.MessageCountsByChannel(gcfg.GuildID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("count messages: %w", err)
+	}
+	joins, err := store.CountMemberJoins(gcfg.GuildID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("count joins: %w", err)
+	}
+	leaves, err := store.CountMembershipEvents(gcfg.GuildID, storage.MembershipLeave, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("count leaves: %w", err)
+	}
+	automod, err := store.AutomodActionCountsBetween(gcfg.GuildID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("count automod actions: %w", err)
+	}
+
+	var totalMessages int64
+	channelLines := make([]string, 0, activityReportTopChannels)
+	for i, c := range channels {
+		totalMessages += c.Count
+		if i < activityReportTopChannels {
+			channelLines = append(channelLines, fmt.Sprintf("%d. <#%s> — %s", i+1, c.ChannelID, util.FormatInt(c.Count, locale)))
+		}
+	}
+	if len(channelLines) == 0 {
+		channelLines = append(channelLines, "No messages recorded.")
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "💬 Messages", Value: util.FormatInt(totalMessages, locale), Inline: true},
+		{Name: "👋 Joins", Value: util.FormatInt(joins, locale), Inline: true},
+		{Name: "🚪 Leaves", Value: util.FormatInt(leaves, locale), Inline: true},
+		{Name: "🛡️ AutoMod actions", Value: formatAutomodCounts(automod, locale), Inline: true},
+		{Name: "📊 Busiest channels", Value: strings.Join(channelLines, "\n")},
+	}
+
+	if gcfg.EmojiStatsEnabled {
+		top, err := store.TopEmojisBetween(context.Background(), gcfg.GuildID, since, until, activityReportTopEmojis)
+		if err != nil {
+			return nil, fmt.Errorf("load emoji usage: %w", err)
+		}
+		emojiLines := make([]string, 0, len(top))
+		for i, u := range top {
+			label := u.MessageFormat()
+			if u.Kind == storage.EmojiKindSticker {
+				label = "🏷️ " + u.Name
+			}
+			emojiLines = append(emojiLines, fmt.Sprintf("%d. %s — %s", i+1, label, util.FormatInt(u.Count, locale)))
+		}
+		if len(emojiLines) == 0 {
+			emojiLines = append(emojiLines, "No custom emoji usage recorded.")
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "😀 Top emojis", Value: strings.Join(emojiLines, "\n")})
+	}
+
+	loc := util.LoadTimezone(gcfg.Timezone)
+	return &discordgo.MessageEmbed{
+		Title:       "📈 Server Activity Report",
+		Description: fmt.Sprintf("%s → %s", since.In(loc).Format("2006-01-02 15:04"), until.In(loc).Format("2006-01-02 15:04")),
+		Color:       0x5865F2,
+		Fields:      fields,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Based on stored data; expired messages are not counted"},
+		Timestamp:   until.Format(time.RFC3339),
+	}, nil
+}
+
+func formatAutomodCounts(counts map[string]int, locale string) string {
+	if len(counts) == 0 {
+		return "0"
+	}
+	actions := make([]string, 0, len(counts))
+	total := 0
+	for action, n := range counts {
+		actions = append(actions, action)
+		total += n
+	}
+	sort.Strings(actions)
+	parts := make([]string, 0, len(actions))
+	for _, action := range actions {
+		parts = append(parts, fmt.Sprintf("%s: %s", action, util.FormatInt(int64(counts[action]), locale)))
+	}
+	return util.FormatInt(int64(total), locale) + "\n" + strings.Join(parts, "\n")
+}
+
+// ActivityReportService posta o relatório de atividade das guilds com activity_report habilitado.
+// Cada guild tem uma tarefa durável recorrente (TaskTypeActivityReport) com o período configurado,
+// então o agendamento sobrevive a reinícios; Start e Reload criam, reagendam ou cancelam as tarefas.
+type ActivityReportService struct {
+	session       *discordgo.Session
+	configManager *files.ConfigManager
+	store         *storage.Store
+	dispatcher    *task.Dispatcher
+	notifier      *NotificationSender
+}
+
+// NewActivityReportService cria o serviço; notifier (opcional) trata destinos em threads.
+func NewActivityReportService(session *discordgo.Session, configManager *files.ConfigManager, store *storage.Store, dispatcher *task.Dispatcher, notifier *NotificationSender) *ActivityReportService {
+	return &ActivityReportService{
+		session:       session,
+		configManager: configManager,
+		store:         store,
+		dispatcher:    dispatcher,
+		notifier:      notifier,
+	}
+}
+
+// Start registra o handler da tarefa e sincroniza o agendamento com a configuração.
+func (rs *ActivityReportService) Start() error {
+	if rs.store == nil || rs.dispatcher == nil {
+		return fmt.Errorf("activity reports require a store and the scheduled tasks dispatcher")
+	}
+	rs.dispatcher.RegisterHandler(TaskTypeActivityReport, rs.runScheduled)
+	return rs.reconcile(rs.configManager.AllGuilds())
+}
+
+// Stop não tem trabalho a encerrar: as tarefas pendentes continuam no store.
+func (rs *ActivityReportService) Stop() error {
+	return nil
+}
+
+// Reload sincroniza o agendamento com a configuração recarregada.
+func (rs *ActivityReportService) Reload(cfg *files.BotConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	return rs.reconcile(cfg.Guilds)
+}
+
+// reconcile garante uma tarefa recorrente por guild ativa com relatório habilitado (no período
+// configurado) e cancela as demais.
+func (rs *ActivityReportService) reconcile(guilds []files.GuildConfig) error {
+	pending, err := rs.store.PendingScheduledTasks("")
+	if err != nil {
+		return fmt.Errorf("list scheduled activity reports: %w", err)
+	}
+	scheduled := make(map[string][]storage.ScheduledTask)
+	for _, t := range pending {
+		if t.Type == TaskTypeActivityReport {
+			scheduled[t.GuildID] = append(scheduled[t.GuildID], t)
+		}
+	}
+
+	wanted := make(map[string]time.Duration)
+	for _, gcfg := range guilds {
+		if !gcfg.Inactive && gcfg.ActivityReport != nil && gcfg.ActivityReport.Enabled {
+			wanted[gcfg.GuildID] = gcfg.ActivityReport.PeriodDuration()
+		}
+	}
+
+	var errs []string
+	for guildID, tasks := range scheduled {
+		period, ok := wanted[guildID]
+		keep := ok && len(tasks) == 1 && tasks[0].Interval == period
+		if keep {
+			delete(wanted, guildID)
+			continue
+		}
+		for _, t := range tasks {
+			if _, err := rs.dispatcher.Cancel(t.ID); err != nil {
+				errs = append(errs, fmt.Sprintf("cancel report of guild %s: %v", guildID, err))
+			}
+		}
+		if !ok {
+			log.Info().Applicationf("📈 Activity report disabled: guildID=%s", guildID)
+		}
+	}
+	for guildID, period := range wanted {
+		if _, err := rs.dispatcher.ScheduleRecurring(TaskTypeActivityReport, guildID, struct{}{}, time.Now().Add(period), period); err != nil {
+			errs = append(errs, fmt.Sprintf("schedule report of guild %s: %v", guildID, err))
+			continue
+		}
+		log.Info().Applicationf("📈 Activity report scheduled: guildID=%s, every %s", guildID, period)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("activity reports: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// runScheduled é o handler da tarefa recorrente: cobre o último período e posta no canal configurado.
+func (rs *ActivityReportService) runScheduled(_ context.Context, t storage.ScheduledTask) error {
+	gcfg, ok := rs.configManager.GuildConfig(t.GuildID)
+	if !ok || gcfg.Inactive || gcfg.ActivityReport == nil || !gcfg.ActivityReport.Enabled {
+		// Desabilitado depois do agendamento; a próxima sincronização cancela a tarefa
+		return nil
+	}
+	period := t.Interval
+	if period <= 0 {
+		period = gcfg.ActivityReport.PeriodDuration()
+	}
+	now := time.Now()
+	return rs.Post(gcfg, now.Add(-period), now)
+}
+
+// Post gera o relatório de [since, until) e o envia ao canal de activity_report da guild.
+func (rs *ActivityReportService) Post(gcfg files.GuildConfig, since, until time.Time) error {
+	if gcfg.ActivityReport == nil || gcfg.ActivityReport.ChannelID == "" {
+		return fmt.Errorf("activity_report.channel_id is not configured for guild %s", gcfg.GuildID)
+	}
+	embed, err := BuildActivityReport(rs.store, gcfg, since, until)
+	if err != nil {
+		return err
+	}
+	msg := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+	channelID := gcfg.ActivityReport.ChannelID
+	if rs.notifier != nil {
+		err = rs.notifier.sendToDestination(channelID, msg)
+	} else {
+		_, err = discord.ClientFor(rs.session).SendComplex(context.Background(), channelID, msg)
+	}
+	if err != nil {
+		return fmt.Errorf("post activity report to %s: %w", channelID, err)
+	}
+	log.Info().Applicationf("📈 Activity report posted: guildID=%s, channelID=%s", gcfg.GuildID, channelID)
+	return nil
+}
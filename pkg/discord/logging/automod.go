@@ -2,11 +2,14 @@ package logging
 
 import (
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	discordsession "github.com/small-frappuccino/discordcore/pkg/discord/session"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
 	"github.com/small-frappuccino/discordcore/pkg/task"
 	"github.com/small-frappuccino/discordcore/pkg/theme"
 )
@@ -16,16 +19,38 @@ type AutomodService struct {
 	session       *discordgo.Session
 	configManager *files.ConfigManager
 	adapters      *task.NotificationAdapters
+	store         *storage.Store
+	notifier      *NotificationSender
+	spam          *spamTracker
 	isRunning     bool
 
-	// unsubscribe function for the registered handler
-	handlerCancel func()
+	// Último envio de DM de automod por guild:usuário (cooldown)
+	dmMu   sync.Mutex
+	lastDM map[string]time.Time
+
+	// Última ação de automod por guild:usuário (automod_action_cooldown; espelhada em automod_user_state)
+	actionMu   sync.Mutex
+	lastAction map[string]time.Time
+
+	// Configuração já aplicada por guild (comparada em Reload)
+	appliedMu sync.Mutex
+	applied   map[string]automodApplied
+
+	// registered handlers and reconnect hook (handlers are reinstalled after gateway reconnects)
+	handlers        *discordsession.HandlerSet
+	reconnectCancel func()
 }
 
 func NewAutomodService(session *discordgo.Session, configManager *files.ConfigManager) *AutomodService {
+	notifier := NewNotificationSender(session)
+	notifier.SetConfigManager(configManager)
 	return &AutomodService{
 		session:       session,
 		configManager: configManager,
+		notifier:      notifier,
+		spam:          newSpamTracker(),
+		lastDM:        make(map[string]time.Time),
+		lastAction:    make(map[string]time.Time),
 	}
 }
 
@@ -34,6 +59,11 @@ func (as *AutomodService) SetAdapters(adapters *task.NotificationAdapters) {
 	as.adapters = adapters
 }
 
+// SetStore allows wiring the SQLite store so automod actions are recorded for reporting.
+func (as *AutomodService) SetStore(store *storage.Store) {
+	as.store = store
+}
+
 // Start registers handlers.
 func (as *AutomodService) Start() {
 	if as.isRunning {
@@ -42,7 +72,16 @@ func (as *AutomodService) Start() {
 	as.isRunning = true
 
 	// Use Discord native AutoMod: listen for action execution events
-	as.handlerCancel = as.session.AddHandler(as.handleAutoModerationAction)
+	as.handlers = discordsession.NewHandlerSet(as.session)
+	as.handlers.Add(as.handleAutoModerationAction)
+	as.handlers.Add(as.handleSpamMessage)
+	as.handlers.Add(as.handleLinkMessage)
+	as.reconnectCancel = discordsession.OnReconnect(as.session, as.handlers.Reinstall)
+
+	// Opt-in: mesclar isenções configuradas nas regras nativas do Discord (em background; usa a API REST)
+	if resync := as.applyConfig(as.configManager.Guilds()); len(resync) > 0 {
+		go as.syncNativeExemptions(resync)
+	}
 }
 
 // Stop stops the service (no-op for now).
@@ -50,9 +89,12 @@ func (as *AutomodService) Stop() {
 	if !as.isRunning {
 		return
 	}
-	if as.handlerCancel != nil {
-		as.handlerCancel()
-		as.handlerCancel = nil
+	if as.reconnectCancel != nil {
+		as.reconnectCancel()
+		as.reconnectCancel = nil
+	}
+	if as.handlers != nil {
+		as.handlers.RemoveAll()
 	}
 	as.isRunning = false
 }
@@ -62,12 +104,37 @@ func (as *AutomodService) handleAutoModerationAction(s *discordgo.Session, e *di
 	if e == nil || e.GuildID == "" {
 		return
 	}
-	// Find guild config for logging
-	guildCfg := as.configManager.GuildConfig(e.GuildID)
-	if guildCfg == nil {
+
+	// Find guild config for exemptions and logging
+	guildCfg, ok := as.configManager.GuildConfig(e.GuildID)
+	if ok && as.isExempt(&guildCfg, e.GuildID, e.ChannelID, e.UserID) {
+		log.Info().Applicationf("AutoMod event ignored (exempt): guildID=%s, channelID=%s, userID=%s, ruleID=%s", e.GuildID, e.ChannelID, e.UserID, e.RuleID)
+		return
+	}
+	writes := newAutomodWrites(e.GuildID, e.UserID)
+	writes.recordAction(as.nativeAction(e))
+	as.noteNativeAction(writes, e.RuleID, automodActionName(e.Action.Type))
+	actionID := as.commitWrites(writes)
+	if !ok {
+		return
+	}
+	// Em canais com shadow-delete a ação nativa só fica registrada, sem notificação nem DM
+	if channelCfg := as.channelConfig(guildCfg, e.ChannelID); channelCfg.AutomodShadowDelete() {
 		return
 	}
+	as.notifyAction(&guildCfg, e, actionID, nil)
+	if name := automodActionName(e.Action.Type); automodActionLabels[name] != "" {
+		matched := e.MatchedKeyword
+		if matched == "" {
+			matched = e.MatchedContent
+		}
+		go as.notifyUser(&guildCfg, e.GuildID, e.UserID, e.ChannelID, e.RuleID, matched, []string{name})
+	}
+}
 
+// notifyAction envia a notificação de uma ação de automod ao canal de log da guild. simulated,
+// quando não vazio, lista as ações que o dry-run deixou de executar.
+func (as *AutomodService) notifyAction(guildCfg *files.GuildConfig, e *discordgo.AutoModerationActionExecution, actionID int64, simulated []string) {
 	logChannelID := guildCfg.AutomodLogChannelID
 	if logChannelID == "" {
 		logChannelID = guildCfg.CommandChannelID
@@ -78,7 +145,7 @@ func (as *AutomodService) handleAutoModerationAction(s *discordgo.Session, e *di
 
 	// If adapters are wired, enqueue via TaskRouter for retries/backoff
 	if as.adapters != nil {
-		if err := as.adapters.EnqueueAutomodAction(logChannelID, e); err != nil {
+		if err := as.adapters.EnqueueAutomodAction(logChannelID, e, actionID, simulated); err != nil {
 			log.Error().Errorf("Failed to enqueue automod log task: guildID=%s, channelID=%s, userID=%s, error=%v", e.GuildID, logChannelID, e.UserID, err)
 		}
 		return
@@ -86,10 +153,9 @@ func (as *AutomodService) handleAutoModerationAction(s *discordgo.Session, e *di
 
 	// Build embed from event data (fallback when adapters are not available)
 	title := "AutoMod action executed"
-	desc := "A native AutoMod rule was triggered."
 	embed := &discordgo.MessageEmbed{
 		Title:       title,
-		Description: desc,
+		Description: automodDescription(e.RuleID),
 		Color:       theme.AutomodAction(),
 		Timestamp:   time.Now().Format(time.RFC3339),
 		Fields: []*discordgo.MessageEmbedField{
@@ -126,12 +192,84 @@ func (as *AutomodService) handleAutoModerationAction(s *discordgo.Session, e *di
 			Inline: false,
 		})
 	}
+	markDryRun(embed, simulated)
 
-	if _, err := s.ChannelMessageSendEmbed(logChannelID, embed); err != nil {
+	msg := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+	if actionID > 0 {
+		msg.Components = AutomodFeedbackComponents(e.GuildID, actionID)
+	}
+	if err := as.notifier.sendMessage(logChannelID, files.NotificationEventAutomod, msg); err != nil {
 		log.Error().Errorf("Failed to send native automod log message: guildID=%s, channelID=%s, userID=%s, error=%v", e.GuildID, logChannelID, e.UserID, err)
 	}
 }
 
+// automodShadowDeleteAction é o nome em automod_actions das remoções silenciosas (AutomodShadow).
+const automodShadowDeleteAction = "shadow_delete"
+
+// markDryRun marca o embed de uma ação simulada com "[DRY RUN]" e lista o que teria sido feito.
+func markDryRun(embed *discordgo.MessageEmbed, simulated []string) {
+	if len(simulated) == 0 {
+		return
+	}
+	embed.Title = "[DRY RUN] AutoMod action simulated"
+	embed.Description += "\nDry run: no action was taken."
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Would have taken",
+		Value:  "``" + strings.Join(simulated, ", ") + "``",
+		Inline: false,
+	})
+}
+
+// nativeAction builds the automod_actions row of a native AutoMod execution.
+func (as *AutomodService) nativeAction(e *discordgo.AutoModerationActionExecution) storage.AutomodAction {
+	matched := e.MatchedKeyword
+	if matched == "" {
+		matched = e.MatchedContent
+	}
+	return storage.AutomodAction{
+		GuildID:   e.GuildID,
+		UserID:    e.UserID,
+		ChannelID: e.ChannelID,
+		RuleID:    e.RuleID,
+		Matched:   matched,
+		Action:    automodActionName(e.Action.Type),
+		Content:   as.privacyContent(e.GuildID, e.Content),
+		CreatedAt: time.Now(),
+	}
+}
+
+// automodActionName maps a native AutoMod action type to a stable storage label.
+func automodActionName(t discordgo.AutoModerationActionType) string {
+	switch t {
+	case discordgo.AutoModerationRuleActionBlockMessage:
+		return "block_message"
+	case discordgo.AutoModerationRuleActionSendAlertMessage:
+		return "send_alert"
+	case discordgo.AutoModerationRuleActionTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// automodDescription descreve a origem da ação: regra nativa ou detecção de spam local.
+func automodDescription(ruleID string) string {
+	switch ruleID {
+	case SpamRuleRate:
+		return "Rate-based spam detection was triggered (too many messages)."
+	case SpamRuleDuplicate:
+		return "Rate-based spam detection was triggered (repeated messages)."
+	case LinkRuleBlocked:
+		return "A blocked link was posted."
+	case LinkRuleNewAccount:
+		return "A recently joined member posted a link."
+	case AttachmentRuleBlocked:
+		return "An attachment with a blocked file extension was posted."
+	default:
+		return "A native AutoMod rule was triggered."
+	}
+}
+
 // sanitizeForCodeBlock prevents breaking out of the code fence and removes backticks.
 func sanitizeForCodeBlock(input string) string {
 	// Replace backticks and normalize newlines for safer preview in a code block
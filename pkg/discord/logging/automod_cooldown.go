@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// reserveAction decide se o automod pode punir o usuário agora, respeitando automod_action_cooldown.
+// Retorna true (e registra a ação como a mais recente) fora do cooldown; dentro dele retorna false
+// e o chamador aplica apenas a remoção do conteúdo. A última ação fica em memória e é gravada em
+// automod_user_state com as demais operações de w, então o cooldown sobrevive a reinícios.
+func (as *AutomodService) reserveAction(gcfg *files.GuildConfig, w *automodWrites, rule, action string) bool {
+	guildID, userID := w.guildID, w.userID
+	now := time.Now()
+	cooldown := gcfg.AutomodActionCooldownDuration()
+	key := guildID + ":" + userID
+
+	as.actionMu.Lock()
+	last, ok := as.lastAction[key]
+	if !ok && cooldown > 0 && as.store != nil {
+		if st, err := as.store.GetAutomodUserState(guildID, userID); err != nil {
+			log.Warn().Applicationf("Failed to load automod user state: guildID=%s, userID=%s, error=%v", guildID, userID, err)
+		} else if st != nil {
+			last, ok = st.LastActionAt, true
+		}
+	}
+	if ok && cooldown > 0 && now.Sub(last) < cooldown {
+		as.actionMu.Unlock()
+		log.Info().Applicationf("AutoMod action suppressed (cooldown): guildID=%s, userID=%s, rule=%s, last=%s ago, cooldown=%s",
+			guildID, userID, rule, now.Sub(last).Round(time.Second), cooldown)
+		return false
+	}
+	as.lastAction[key] = now
+	// Limpeza oportunista: entradas mais velhas que o cooldown não suprimem mais nada
+	if len(as.lastAction) > 1024 {
+		for k, t := range as.lastAction {
+			if now.Sub(t) > time.Hour {
+				delete(as.lastAction, k)
+			}
+		}
+	}
+	as.actionMu.Unlock()
+
+	w.markAction(rule, action, now)
+	return true
+}
+
+// noteNativeAction registra uma ação do AutoMod nativo para que ela também inicie o cooldown.
+func (as *AutomodService) noteNativeAction(w *automodWrites, rule, action string) {
+	if w.userID == "" {
+		return
+	}
+	now := time.Now()
+	as.actionMu.Lock()
+	as.lastAction[w.guildID+":"+w.userID] = now
+	as.actionMu.Unlock()
+	w.markAction(rule, action, now)
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// automodActionLabels descreve para o usuário as ações que o afetam; ações fora daqui
+// (ex.: send_alert, flag) não geram DM.
+var automodActionLabels = map[string]string{
+	"block_message":   "message blocked",
+	"delete_messages": "messages deleted",
+	"timeout":         "timed out",
+}
+
+// notifyUser envia ao usuário afetado a DM configurada em automod_dm, respeitando o cooldown
+// por usuário. DMs fechadas são registradas e ignoradas.
+func (as *AutomodService) notifyUser(gcfg *files.GuildConfig, guildID, userID, channelID, ruleID, matched string, actions []string) {
+	if gcfg == nil || gcfg.AutomodDM == nil || !gcfg.AutomodDM.Enabled || userID == "" {
+		return
+	}
+	var labels []string
+	for _, a := range actions {
+		if l, ok := automodActionLabels[a]; ok {
+			labels = append(labels, l)
+		}
+	}
+	if len(labels) == 0 {
+		return
+	}
+	if !as.reserveDM(guildID+":"+userID, gcfg.AutomodDM.CooldownDuration()) {
+		return
+	}
+
+	text := renderAutomodDM(gcfg.AutomodDM, map[string]string{
+		"{user}":    "<@" + userID + ">",
+		"{guild}":   as.guildName(guildID),
+		"{rule}":    as.ruleLabel(guildID, ruleID),
+		"{action}":  strings.Join(labels, ", "),
+		"{matched}": matched,
+		"{channel}": channelMention(channelID),
+	})
+
+	_, err := discord.ClientFor(as.session).SendDM(context.Background(), userID, &discordgo.MessageSend{
+		Content:         text,
+		AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+	})
+	if err != nil {
+		if restErrorCode(err) == discordgo.ErrCodeCannotSendMessagesToThisUser {
+			log.Info().Applicationf("AutoMod DM not delivered (DMs closed): guildID=%s, userID=%s", guildID, userID)
+			return
+		}
+		log.Warn().Applicationf("Failed to send automod DM: guildID=%s, userID=%s, error=%v", guildID, userID, err)
+	}
+}
+
+// reserveDM registra o envio para key e retorna false se ainda estiver no cooldown.
+func (as *AutomodService) reserveDM(key string, cooldown time.Duration) bool {
+	now := time.Now()
+	as.dmMu.Lock()
+	defer as.dmMu.Unlock()
+	if last, ok := as.lastDM[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	// Limpeza oportunista das entradas expiradas
+	for k, t := range as.lastDM {
+		if now.Sub(t) >= cooldown {
+			delete(as.lastDM, k)
+		}
+	}
+	as.lastDM[key] = now
+	return true
+}
+
+func renderAutomodDM(cfg *files.AutomodDMConfig, values map[string]string) string {
+	tmpl := cfg.Template
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = files.DefaultAutomodDMTemplate
+	}
+	values["{appeal}"] = cfg.Appeal
+	pairs := make([]string, 0, len(values)*2)
+	for k, v := range values {
+		pairs = append(pairs, k, v)
+	}
+	out := strings.NewReplacer(pairs...).Replace(tmpl)
+	return strings.TrimSpace(truncateRunes(out, 2000))
+}
+
+func (as *AutomodService) guildName(guildID string) string {
+	if g, err := as.session.State.Guild(guildID); err == nil && g != nil && g.Name != "" {
+		return g.Name
+	}
+	return guildID
+}
+
+// ruleLabel retorna um nome legível para a regra: as regras locais têm nomes fixos e as
+// nativas são resolvidas pela API (com o ID como fallback).
+func (as *AutomodService) ruleLabel(guildID, ruleID string) string {
+	switch ruleID {
+	case SpamRuleRate:
+		return "Spam (too many messages)"
+	case SpamRuleDuplicate:
+		return "Spam (repeated messages)"
+	case LinkRuleBlocked:
+		return "Blocked link"
+	case LinkRuleNewAccount:
+		return "Links from new members"
+	case AttachmentRuleBlocked:
+		return "Blocked file type"
+	case "":
+		return "AutoMod"
+	}
+	if rule, err := as.session.AutoModerationRule(guildID, ruleID); err == nil && rule != nil && rule.Name != "" {
+		return rule.Name
+	}
+	return ruleID
+}
+
+func channelMention(channelID string) string {
+	if channelID == "" {
+		return ""
+	}
+	return "<#" + channelID + ">"
+}
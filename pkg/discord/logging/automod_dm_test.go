@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestRenderAutomodDMTruncatesByCharacters(t *testing.T) {
+	cfg := &files.AutomodDMConfig{Template: "{matched}"}
+	out := renderAutomodDM(cfg, map[string]string{"{matched}": strings.Repeat("ç", 2100)})
+	if !utf8.ValidString(out) {
+		t.Fatal("DM text is not valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(out); n != 2000 {
+		t.Fatalf("DM has %d characters, want 2000", n)
+	}
+
+	short := renderAutomodDM(cfg, map[string]string{"{matched}": strings.Repeat("ç", 1500)})
+	if short != strings.Repeat("ç", 1500) {
+		t.Fatal("DM within the character limit was cut")
+	}
+}
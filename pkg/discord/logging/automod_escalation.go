@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// escalatedTimeout retorna a duração do timeout do usuário escalada a partir de base (ver
+// files.AutomodEscalationConfig) e a ofensa a gravar com a ação (automodWrites.recordOffense)
+// quando o timeout é aplicado. O nível é previsto a partir de automod_user_state com o
+// decaimento aplicado; o incremento em si acontece na transação de commitWrites. Sem
+// escalonamento habilitado (ou sem store), retorna base e nenhuma ofensa.
+func (as *AutomodService) escalatedTimeout(gcfg *files.GuildConfig, guildID, userID string, base time.Duration) (time.Duration, *automodOffense) {
+	esc := gcfg.AutomodEscalation
+	if esc == nil || !esc.Enabled || as.store == nil || userID == "" {
+		return base, nil
+	}
+	now := time.Now()
+	decay := func(level int, lastOffense time.Time) int {
+		return esc.DecayLevel(level, lastOffense, now)
+	}
+
+	st, err := as.store.GetAutomodUserState(guildID, userID)
+	if err != nil {
+		log.Warn().Applicationf("Failed to load automod escalation: guildID=%s, userID=%s, error=%v", guildID, userID, err)
+		return base, nil
+	}
+	level := 0
+	if st != nil {
+		level = decay(st.EscalationLevel, st.LastOffenseAt)
+		if level < st.EscalationLevel {
+			log.Info().Applicationf("AutoMod escalation decayed: guildID=%s, userID=%s, level=%d->%d, lastOffense=%s ago",
+				guildID, userID, st.EscalationLevel, level, now.Sub(st.LastOffenseAt).Round(time.Second))
+		}
+	}
+	level++
+
+	timeout := esc.Timeout(base, level)
+	log.Info().Applicationf("AutoMod escalation: guildID=%s, userID=%s, level=%d, timeout=%s", guildID, userID, level, timeout)
+	return timeout, &automodOffense{at: now, decay: decay}
+}
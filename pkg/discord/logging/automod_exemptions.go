@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"context"
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// Limites da API do Discord para isenções em regras nativas do AutoMod.
+const (
+	maxNativeExemptRoles    = 20
+	maxNativeExemptChannels = 50
+)
+
+// isExempt decide se um evento de automod deve ser ignorado para o autor/canal.
+// Ordem: canais isentos (incluindo o pai de threads), cargos isentos e, salvo se desativadas,
+// as isenções implícitas (dono da guild e membros com MANAGE_MESSAGES no canal).
+func (as *AutomodService) isExempt(gcfg *files.GuildConfig, guildID, channelID, userID string) bool {
+	if gcfg == nil || userID == "" {
+		return false
+	}
+	if channelID != "" && gcfg.IsAutomodExemptChannel(as.channelLineage(channelID)) {
+		return true
+	}
+	if len(gcfg.AutomodExemptRoles) > 0 {
+		if member := as.member(guildID, userID); member != nil && gcfg.HasAutomodExemptRole(member.Roles) {
+			return true
+		}
+	}
+	if gcfg.AutomodDisableImplicitExemptions {
+		return false
+	}
+	if g, err := as.session.State.Guild(guildID); err == nil && g != nil && g.OwnerID == userID {
+		return true
+	}
+	if channelID != "" {
+		perms, err := as.session.UserChannelPermissions(userID, channelID)
+		if err == nil && perms&discordgo.PermissionManageMessages != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// channelConfig aplica à configuração da guild os overrides de automod do canal (ou do canal
+// pai, em threads); ver files.GuildConfig.AutomodForChannel.
+func (as *AutomodService) channelConfig(gcfg files.GuildConfig, channelID string) files.GuildConfig {
+	if len(gcfg.AutomodChannelOverrides) == 0 || channelID == "" {
+		return gcfg
+	}
+	return gcfg.AutomodForChannel(as.channelLineage(channelID))
+}
+
+// channelLineage retorna o canal e, para threads, o canal pai.
+func (as *AutomodService) channelLineage(channelID string) []string {
+	ids := []string{channelID}
+	if ch, err := as.session.State.Channel(channelID); err == nil && ch != nil && ch.IsThread() && ch.ParentID != "" {
+		ids = append(ids, ch.ParentID)
+	}
+	return ids
+}
+
+// member resolve o membro via State com fallback para a API.
+func (as *AutomodService) member(guildID, userID string) *discordgo.Member {
+	m, err := discord.ClientFor(as.session).FetchMember(context.Background(), guildID, userID)
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+// SyncNativeExemptions mescla os cargos/canais isentos configurados nas regras nativas do AutoMod
+// da guild, para que o próprio Discord deixe de aplicá-las a esses membros/canais. Isenções já
+// existentes nas regras são preservadas; a união é limitada aos máximos aceitos pela API.
+func (as *AutomodService) SyncNativeExemptions(guildID string) error {
+	gcfg, ok := as.configManager.GuildConfig(guildID)
+	if !ok || (len(gcfg.AutomodExemptRoles) == 0 && len(gcfg.AutomodExemptChannels) == 0) {
+		return nil
+	}
+	rules, err := as.session.AutoModerationRules(guildID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		var curRoles, curChannels []string
+		if rule.ExemptRoles != nil {
+			curRoles = *rule.ExemptRoles
+		}
+		if rule.ExemptChannels != nil {
+			curChannels = *rule.ExemptChannels
+		}
+		roles, rolesChanged := mergeIDs(curRoles, gcfg.AutomodExemptRoles, maxNativeExemptRoles)
+		channels, channelsChanged := mergeIDs(curChannels, gcfg.AutomodExemptChannels, maxNativeExemptChannels)
+		if !rolesChanged && !channelsChanged {
+			continue
+		}
+		if _, err := as.session.AutoModerationRuleEdit(guildID, rule.ID, &discordgo.AutoModerationRule{
+			ExemptRoles:    &roles,
+			ExemptChannels: &channels,
+		}); err != nil {
+			log.Warn().Applicationf("Failed to sync automod exemptions to native rule %s (%s) in guild %s: %v", rule.Name, rule.ID, guildID, err)
+			continue
+		}
+		log.Info().Applicationf("Synced automod exemptions to native rule %s (%s) in guild %s", rule.Name, rule.ID, guildID)
+	}
+	return nil
+}
+
+// mergeIDs adiciona a current os IDs de extra ainda ausentes, sem ultrapassar max.
+func mergeIDs(current, extra []string, max int) ([]string, bool) {
+	out := append([]string(nil), current...)
+	changed := false
+	for _, id := range extra {
+		if containsID(out, id) {
+			continue
+		}
+		if len(out) >= max {
+			log.Warn().Applicationf("Native automod exemption limit (%d) reached; skipping %s", max, id)
+			break
+		}
+		out = append(out, id)
+		changed = true
+	}
+	return out, changed
+}
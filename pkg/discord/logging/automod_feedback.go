@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AutomodFalsePositivePrefix é o prefixo do CustomID do botão "Mark false positive"
+// (formato: "automod_fp:<guildID>:<actionID>"), tratado pelo handler de componentes admin.
+const AutomodFalsePositivePrefix = "automod_fp"
+
+// AutomodFeedbackComponents monta a linha de botões anexada às notificações de automod.
+func AutomodFeedbackComponents(guildID string, actionID int64) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Mark false positive",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%s:%d", AutomodFalsePositivePrefix, guildID, actionID),
+				},
+			},
+		},
+	}
+}
+
+// ParseAutomodFeedbackID extrai guildID e actionID do CustomID do botão de feedback.
+func ParseAutomodFeedbackID(customID string) (guildID string, actionID int64, ok bool) {
+	parts := strings.Split(customID, ":")
+	if len(parts) != 3 || parts[0] != AutomodFalsePositivePrefix {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || id <= 0 || parts[1] == "" {
+		return "", 0, false
+	}
+	return parts[1], id, true
+}
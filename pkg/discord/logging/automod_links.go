@@ -0,0 +1,271 @@
+package logging
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// RuleIDs registrados em automod_actions pela varredura de links e anexos.
+const (
+	LinkRuleBlocked       = "link_blocked"
+	LinkRuleNewAccount    = "link_new_account"
+	AttachmentRuleBlocked = "attachment_blocked"
+)
+
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>]+`)
+
+// extractURLs retorna os links do conteúdo (http/https e www.), sem pontuação final.
+func extractURLs(content string) []*url.URL {
+	var out []*url.URL
+	for _, raw := range urlPattern.FindAllString(content, -1) {
+		raw = strings.TrimRight(raw, ".,;:!?)]}>'\"|*_~")
+		if strings.HasPrefix(strings.ToLower(raw), "www.") {
+			raw = "http://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		out = append(out, u)
+	}
+	return out
+}
+
+// linkCandidate é um link extraído do conteúdo normalizado junto com o trecho original.
+type linkCandidate struct {
+	url      *url.URL // forma normalizada, usada para casar os padrões de domínio
+	original string   // como o autor escreveu (ex.: com homóglifos)
+}
+
+// extractLinks normaliza cada palavra do conteúdo separadamente e extrai os links dela, para
+// que cada link normalizado continue associado ao texto original de onde saiu.
+func extractLinks(content string, passes []string) []linkCandidate {
+	var out []linkCandidate
+	for _, word := range strings.Fields(content) {
+		for _, u := range extractURLs(Normalize(word, passes)) {
+			original := urlPattern.FindString(word)
+			if original == "" {
+				// O esquema só vira "https://"/"www." depois de normalizado (ex.: fullwidth)
+				original = word
+			}
+			out = append(out, linkCandidate{url: u, original: strings.TrimRight(original, ".,;:!?)]}>'\"|*_~")})
+		}
+	}
+	return out
+}
+
+// matchLinkPattern casa um link com um padrão de domínio (ver files.AutomodLinkConfig).
+func matchLinkPattern(u *url.URL, pattern string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if strings.Contains(pattern, "/") {
+		return strings.HasPrefix(host+strings.ToLower(u.EscapedPath()), strings.TrimPrefix(pattern, "www."))
+	}
+	if strings.Contains(pattern, "*") {
+		ok, _ := path.Match(pattern, host)
+		return ok
+	}
+	pattern = strings.TrimPrefix(pattern, "www.")
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+func matchAnyLinkPattern(u *url.URL, patterns []string) bool {
+	for _, p := range patterns {
+		if matchLinkPattern(u, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBlockedExtension informa se o nome do arquivo termina com alguma das extensões.
+func hasBlockedExtension(filename string, exts []string) bool {
+	name := strings.ToLower(filename)
+	for _, ext := range exts {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkMatch descreve o primeiro item ofensivo encontrado numa mensagem.
+type linkMatch struct {
+	rule       string
+	matched    string // URL como foi escrita ou nome do arquivo
+	normalized string // URL normalizada que casou a regra ("" quando igual a matched)
+	action     string
+}
+
+// recorded é o texto gravado em automod_actions e no log: o original e, se diferente, a
+// forma normalizada que casou a regra.
+func (lm *linkMatch) recorded() string {
+	if lm.normalized == "" {
+		return lm.matched
+	}
+	return lm.matched + " (normalized: " + lm.normalized + ")"
+}
+
+func newLinkMatch(rule string, c linkCandidate, action string) *linkMatch {
+	lm := &linkMatch{rule: rule, matched: c.original, action: action}
+	// Compara com o original parseado do mesmo jeito, para não registrar só o "http://" de "www."
+	key := c.url.String()
+	if raw := extractURLs(c.original); len(raw) == 0 || raw[0].String() != key {
+		lm.normalized = key
+	}
+	return lm
+}
+
+// scanMessage aplica a configuração à mensagem. Anexos e links bloqueados têm prioridade
+// sobre o modo de contas novas.
+func scanMessage(cfg *files.AutomodLinkConfig, m *discordgo.Message, passes []string, now time.Time) *linkMatch {
+	for _, a := range m.Attachments {
+		if a != nil && hasBlockedExtension(a.Filename, cfg.BlockedExtensions) {
+			return &linkMatch{rule: AttachmentRuleBlocked, matched: a.Filename, action: cfg.LinkAction()}
+		}
+	}
+
+	var firstLink *linkCandidate
+	// Normalizar antes de casar pega domínios com homóglifos (ex.: "disсord.com" com "с" cirílico)
+	for _, c := range extractLinks(m.Content, passes) {
+		if matchAnyLinkPattern(c.url, cfg.AllowedDomains) {
+			continue
+		}
+		if matchAnyLinkPattern(c.url, cfg.BlockedDomains) {
+			return newLinkMatch(LinkRuleBlocked, c, cfg.LinkAction())
+		}
+		if firstLink == nil {
+			firstLink = &c
+		}
+	}
+
+	if firstLink != nil && m.Member != nil && !m.Member.JoinedAt.IsZero() {
+		if window := cfg.NewAccountWindow(); window > 0 && now.Sub(m.Member.JoinedAt) < window {
+			return newLinkMatch(LinkRuleNewAccount, *firstLink, cfg.NewAccountLinkAction())
+		}
+	}
+	return nil
+}
+
+// handleLinkMessage aplica a varredura de links/anexos às mensagens de guilds com automod_links ativo.
+func (as *AutomodService) handleLinkMessage(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m == nil || m.Message == nil || m.GuildID == "" || m.Author == nil || m.Author.Bot {
+		return
+	}
+	gcfg, ok := as.configManager.GuildConfig(m.GuildID)
+	if !ok {
+		return
+	}
+	gcfg = as.channelConfig(gcfg, m.ChannelID)
+	if gcfg.AutomodLinks == nil || !gcfg.AutomodLinks.Enabled {
+		return
+	}
+	match := scanMessage(gcfg.AutomodLinks, m.Message, NormalizationPasses(gcfg.AutomodLanguage, gcfg.AutomodNormalization), time.Now())
+	if match == nil {
+		return
+	}
+	if as.isExempt(&gcfg, m.GuildID, m.ChannelID, m.Author.ID) {
+		return
+	}
+	log.Info().Applicationf("Link scan match: guildID=%s, channelID=%s, userID=%s, rule=%s, matched=%s", m.GuildID, m.ChannelID, m.Author.ID, match.rule, match.recorded())
+	go as.enforceLinkMatch(&gcfg, m.Message, match)
+}
+
+// enforceLinkMatch executa a ação, registra em automod_actions citando o link/arquivo e notifica.
+func (as *AutomodService) enforceLinkMatch(gcfg *files.GuildConfig, m *discordgo.Message, match *linkMatch) {
+	event := &discordgo.AutoModerationActionExecution{
+		GuildID:        m.GuildID,
+		UserID:         m.Author.ID,
+		ChannelID:      m.ChannelID,
+		MessageID:      m.ID,
+		RuleID:         match.rule,
+		MatchedContent: match.matched,
+		Content:        m.Content,
+	}
+	dryRun := gcfg.AutomodLinksDryRun()
+
+	writes := newAutomodWrites(m.GuildID, m.Author.ID)
+	var taken []string
+	record := func(name string) {
+		taken = append(taken, name)
+		writes.recordAction(as.localAction(m.GuildID, m.Author.ID, m.ChannelID, match.rule, match.recorded(), name, m.Content, dryRun))
+	}
+
+	if dryRun {
+		// Só registra e notifica o que seria feito: a mensagem fica, sem timeout, DM nem cooldown de ações
+		switch match.action {
+		case files.LinkActionFlag:
+			record("flag")
+		case files.LinkActionDelete:
+			record("block_message")
+		case files.LinkActionDeleteTimeout:
+			record("block_message")
+			record("timeout")
+		}
+		log.Info().Applicationf("AutoMod dry run: guildID=%s, userID=%s, rule=%s, wouldTake=%v", m.GuildID, m.Author.ID, match.rule, taken)
+		as.notifyAction(gcfg, event, as.commitWrites(writes), taken)
+		return
+	}
+
+	if gcfg.AutomodShadowDelete() {
+		// Shadow-delete: a mensagem some em silêncio, sem timeout, DM, notificação nem cooldown de ações
+		if err := discord.ClientFor(as.session).DeleteMessage(context.Background(), m.ChannelID, m.ID); err != nil {
+			log.Warn().Applicationf("Failed to shadow-delete flagged message: guildID=%s, channelID=%s, messageID=%s, error=%v", m.GuildID, m.ChannelID, m.ID, err)
+			return
+		}
+		record(automodShadowDeleteAction)
+		as.commitWrites(writes)
+		log.Info().Applicationf("AutoMod shadow delete: guildID=%s, channelID=%s, userID=%s, rule=%s", m.GuildID, m.ChannelID, m.Author.ID, match.rule)
+		return
+	}
+
+	// Dentro do cooldown de ações, só o conteúdo é removido (sem timeout, DM nem notificação)
+	punish := match.action == files.LinkActionFlag || as.reserveAction(gcfg, writes, match.rule, match.action)
+
+	switch match.action {
+	case files.LinkActionFlag:
+		record("flag")
+	case files.LinkActionDelete, files.LinkActionDeleteTimeout:
+		// Registrado como block_message para que o feedback de falso positivo republique o conteúdo
+		if err := discord.ClientFor(as.session).DeleteMessage(context.Background(), m.ChannelID, m.ID); err != nil {
+			log.Warn().Applicationf("Failed to delete flagged message: guildID=%s, channelID=%s, messageID=%s, error=%v", m.GuildID, m.ChannelID, m.ID, err)
+		} else {
+			record("block_message")
+		}
+		if !punish {
+			as.commitWrites(writes)
+			return
+		}
+		if match.action == files.LinkActionDeleteTimeout {
+			timeout, offense := as.escalatedTimeout(gcfg, m.GuildID, m.Author.ID, gcfg.AutomodLinks.Timeout())
+			if err := discord.ClientFor(as.session).Timeout(context.Background(), m.GuildID, m.Author.ID, timeout); err != nil {
+				log.Warn().Applicationf("Failed to timeout member for link: guildID=%s, userID=%s, error=%v", m.GuildID, m.Author.ID, err)
+			} else {
+				record("timeout")
+				writes.recordOffense(offense)
+			}
+		}
+	}
+
+	as.notifyAction(gcfg, event, as.commitWrites(writes), nil)
+	as.notifyUser(gcfg, m.GuildID, m.Author.ID, m.ChannelID, match.rule, match.matched, taken)
+}
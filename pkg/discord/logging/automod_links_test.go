@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestScanMessageRecordsOriginalLink(t *testing.T) {
+	cfg := &files.AutomodLinkConfig{Enabled: true, BlockedDomains: []string{"discord-nitro.com"}}
+	passes := NormalizationPasses("en", nil)
+	tests := []struct {
+		name           string
+		content        string
+		wantMatched    string
+		wantNormalized string
+	}{
+		{"plain", "claim it: https://discord-nitro.com/gift.", "https://discord-nitro.com/gift", ""},
+		{"www without scheme", "see www.discord-nitro.com", "www.discord-nitro.com", ""},
+		// "о" e "с" cirílicos: a regra casa pela forma normalizada, o registro guarda o que foi escrito
+		{"homoglyphs", "free https://disсоrd-nitro.com/gift now", "https://disсоrd-nitro.com/gift", "https://discord-nitro.com/gift"},
+		{"zero width", "https://discord\u200b-nitro.com", "https://discord\u200b-nitro.com", "https://discord-nitro.com"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			match := scanMessage(cfg, &discordgo.Message{Content: tc.content}, passes, time.Now())
+			if match == nil {
+				t.Fatal("no match")
+			}
+			if match.rule != LinkRuleBlocked {
+				t.Errorf("rule = %q", match.rule)
+			}
+			if match.matched != tc.wantMatched || match.normalized != tc.wantNormalized {
+				t.Errorf("matched %q normalized %q, want %q and %q", match.matched, match.normalized, tc.wantMatched, tc.wantNormalized)
+			}
+		})
+	}
+}
+
+func TestLinkMatchRecorded(t *testing.T) {
+	plain := &linkMatch{matched: "https://a.com"}
+	if got := plain.recorded(); got != "https://a.com" {
+		t.Errorf("recorded = %q", got)
+	}
+	folded := &linkMatch{matched: "https://а.com", normalized: "https://a.com"}
+	if got, want := folded.recorded(), "https://а.com (normalized: https://a.com)"; got != want {
+		t.Errorf("recorded = %q, want %q", got, want)
+	}
+}
+
+func TestScanMessageSafePassesKeepCyrillic(t *testing.T) {
+	// Sem dica de idioma os homóglifos não são dobrados: o domínio cirílico não casa o bloqueio.
+	cfg := &files.AutomodLinkConfig{Enabled: true, BlockedDomains: []string{"discord-nitro.com"}}
+	if match := scanMessage(cfg, &discordgo.Message{Content: "https://disсоrd-nitro.com"}, NormalizationPasses("", nil), time.Now()); match != nil {
+		t.Errorf("unexpected match %+v", match)
+	}
+}
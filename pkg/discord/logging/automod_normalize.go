@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Passes de normalização aplicados ao conteúdo antes das verificações locais de automod
+// (repetições de spam e varredura de links).
+const (
+	// NormalizeCase dobra maiúsculas/minúsculas.
+	NormalizeCase = "case"
+	// NormalizeWhitespace remove caracteres invisíveis (zero-width etc.) e colapsa espaços.
+	NormalizeWhitespace = "whitespace"
+	// NormalizeFullwidth converte formas fullwidth (ｆｒｅｅ) para ASCII.
+	NormalizeFullwidth = "fullwidth"
+	// NormalizeDiacritics remove acentos de letras latinas (é -> e).
+	NormalizeDiacritics = "diacritics"
+	// NormalizeHomoglyphs troca letras cirílicas/gregas parecidas com latinas (а -> a, ο -> o).
+	NormalizeHomoglyphs = "homoglyphs"
+)
+
+// safeNormalizationPasses é o conjunto agnóstico de idioma, usado sem dica de idioma:
+// nenhum destes passes altera texto legítimo em outros alfabetos.
+var safeNormalizationPasses = []string{NormalizeCase, NormalizeWhitespace, NormalizeFullwidth}
+
+// latinOnlyLanguages são idiomas escritos só em alfabeto latino, onde letras cirílicas/gregas
+// no meio de palavras são quase sempre evasão, e acentos não mudam o que deve casar.
+var latinOnlyLanguages = map[string]bool{
+	"en": true, "pt": true, "es": true, "fr": true, "de": true, "it": true,
+	"nl": true, "pl": true, "sv": true, "no": true, "da": true, "fi": true,
+	"tr": true, "ro": true, "cs": true, "id": true,
+}
+
+// NormalizationPasses resolve os passes de uma guild: a lista explícita, se houver; senão o
+// conjunto seguro, mais diacritics/homoglyphs quando a dica de idioma é de alfabeto latino.
+func NormalizationPasses(language string, explicit []string) []string {
+	if len(explicit) > 0 {
+		return explicit
+	}
+	passes := append([]string(nil), safeNormalizationPasses...)
+	lang := strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	if latinOnlyLanguages[lang] {
+		passes = append(passes, NormalizeDiacritics, NormalizeHomoglyphs)
+	}
+	return passes
+}
+
+// Normalize aplica os passes, na ordem canônica, a s.
+func Normalize(s string, passes []string) string {
+	enabled := make(map[string]bool, len(passes))
+	for _, p := range passes {
+		enabled[p] = true
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if enabled[NormalizeWhitespace] && isInvisible(r) {
+			continue
+		}
+		if enabled[NormalizeFullwidth] && r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFF01 - 0x21
+		}
+		if enabled[NormalizeHomoglyphs] {
+			if l, ok := homoglyphs[r]; ok {
+				r = l
+			}
+		}
+		if enabled[NormalizeDiacritics] {
+			if unicode.Is(unicode.Mn, r) {
+				continue
+			}
+			if l, ok := latinDiacritics[r]; ok {
+				r = l
+			}
+		}
+		if enabled[NormalizeCase] {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	out := b.String()
+	if enabled[NormalizeWhitespace] {
+		out = strings.Join(strings.Fields(out), " ")
+	}
+	return out
+}
+
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff', '\u00ad', '\u180e':
+		return true
+	}
+	return false
+}
+
+// homoglyphs mapeia letras cirílicas e gregas visualmente idênticas a letras latinas.
+var homoglyphs = map[rune]rune{
+	// Cirílico
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i', 'ј': 'j', 'ԁ': 'd',
+	'ԛ': 'q', 'ԝ': 'w', 'ɡ': 'g',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P',
+	'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J',
+	// Grego
+	'α': 'a', 'ο': 'o', 'ν': 'v', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'ι': 'i', 'κ': 'k',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// latinDiacritics mapeia letras latinas acentuadas pré-compostas para a letra base.
+var latinDiacritics = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ā': 'a',
+	'ç': 'c', 'č': 'c', 'ć': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e', 'ę': 'e', 'ě': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ī': 'i', 'ı': 'i',
+	'ñ': 'n', 'ń': 'n', 'ň': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o', 'ō': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ū': 'u', 'ů': 'u',
+	'ý': 'y', 'ÿ': 'y', 'ś': 's', 'š': 's', 'ş': 's', 'ź': 'z', 'ž': 'z', 'ż': 'z',
+	'ł': 'l', 'ř': 'r', 'ť': 't', 'ď': 'd', 'ğ': 'g',
+	'À': 'A', 'Á': 'A', 'Â': 'A', 'Ã': 'A', 'Ä': 'A', 'Å': 'A',
+	'Ç': 'C', 'È': 'E', 'É': 'E', 'Ê': 'E', 'Ë': 'E',
+	'Ì': 'I', 'Í': 'I', 'Î': 'I', 'Ï': 'I', 'Ñ': 'N',
+	'Ò': 'O', 'Ó': 'O', 'Ô': 'O', 'Õ': 'O', 'Ö': 'O', 'Ø': 'O',
+	'Ù': 'U', 'Ú': 'U', 'Û': 'U', 'Ü': 'U', 'Ý': 'Y',
+}
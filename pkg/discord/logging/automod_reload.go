@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// automodApplied resume as partes da configuração de uma guild que geram estado no serviço.
+type automodApplied struct {
+	spam    string // automod_spam (com overrides de canal) e normalização: janelas contadas sob outros limites são descartadas
+	exempts string // isenções a sincronizar com as regras nativas ("" com a sincronização desligada)
+}
+
+func automodAppliedFor(gcfg files.GuildConfig) automodApplied {
+	var a automodApplied
+	if b, err := json.Marshal(struct {
+		Spam          *files.AutomodSpamConfig
+		Overrides     []files.AutomodChannelOverride
+		Language      string
+		Normalization []string
+	}{gcfg.AutomodSpam, gcfg.AutomodChannelOverrides, gcfg.AutomodLanguage, gcfg.AutomodNormalization}); err == nil {
+		a.spam = string(b)
+	}
+	if gcfg.AutomodSyncNativeExemptions {
+		if b, err := json.Marshal([][]string{gcfg.AutomodExemptRoles, gcfg.AutomodExemptChannels}); err == nil {
+			a.exempts = string(b)
+		}
+	}
+	return a
+}
+
+// applyConfig troca o resumo aplicado pelo de guilds e devolve as guilds cujas isenções precisam
+// ser sincronizadas com as regras nativas. Janelas de spam de guilds alteradas ou removidas são descartadas.
+func (as *AutomodService) applyConfig(guilds []files.GuildConfig) []string {
+	next := make(map[string]automodApplied, len(guilds))
+	for _, gcfg := range guilds {
+		if gcfg.Inactive {
+			continue
+		}
+		next[gcfg.GuildID] = automodAppliedFor(gcfg)
+	}
+
+	as.appliedMu.Lock()
+	prev := as.applied
+	as.applied = next
+	as.appliedMu.Unlock()
+
+	var resync []string
+	for guildID, a := range next {
+		old, known := prev[guildID]
+		if known && old.spam != a.spam {
+			as.spam.resetGuild(guildID)
+		}
+		if a.exempts != "" && a.exempts != old.exempts {
+			resync = append(resync, guildID)
+		}
+	}
+	for guildID := range prev {
+		if _, ok := next[guildID]; !ok {
+			as.spam.resetGuild(guildID)
+		}
+	}
+	return resync
+}
+
+// Reload aplica uma configuração nova sem Stop/Start. As regras já são lidas a cada mensagem;
+// aqui só o estado derivado é trocado: as janelas de spam das guilds cujo automod_spam mudou são
+// descartadas e isenções alteradas são ressincronizadas com as regras nativas (em background).
+func (as *AutomodService) Reload(cfg *files.BotConfig) error {
+	if cfg == nil || !as.isRunning {
+		return nil
+	}
+	if resync := as.applyConfig(cfg.Guilds); len(resync) > 0 {
+		go as.syncNativeExemptions(resync)
+	}
+	return nil
+}
+
+// syncNativeExemptions chama SyncNativeExemptions para cada guild (usa a API REST).
+func (as *AutomodService) syncNativeExemptions(guildIDs []string) {
+	for _, guildID := range guildIDs {
+		if err := as.SyncNativeExemptions(guildID); err != nil {
+			log.Warn().Applicationf("Failed to sync automod exemptions for guild %s: %v", guildID, err)
+		}
+	}
+}
+
+// resetGuild descarta as janelas de spam dos usuários da guild.
+func (t *spamTracker) resetGuild(guildID string) {
+	prefix := guildID + ":"
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.windows {
+		if strings.HasPrefix(key, prefix) {
+			delete(t.windows, key)
+		}
+	}
+}
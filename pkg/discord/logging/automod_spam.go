@@ -0,0 +1,246 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// RuleIDs registrados em automod_actions pela detecção de spam por taxa.
+const (
+	SpamRuleRate      = "spam_rate"
+	SpamRuleDuplicate = "spam_duplicate"
+)
+
+const (
+	// spamSweepEvery controla a frequência da limpeza de janelas inativas no tracker.
+	spamSweepEvery = 1000
+	// spamIdle é o tempo mínimo sem mensagens antes de uma janela ser descartada na limpeza.
+	spamIdle = 5 * time.Minute
+)
+
+// spamTracker mantém, por guild+usuário, as mensagens recentes numa janela deslizante.
+type spamTracker struct {
+	mu       sync.Mutex
+	windows  map[string][]spamEntry
+	observed int
+}
+
+type spamEntry struct {
+	at        time.Time
+	channelID string
+	messageID string
+	content   string // normalizado, para detectar repetições
+}
+
+func newSpamTracker() *spamTracker {
+	return &spamTracker{windows: make(map[string][]spamEntry)}
+}
+
+// observe adiciona a mensagem à janela do usuário e, se algum limite foi excedido, retorna o
+// RuleID correspondente e as mensagens envolvidas. A janela é esvaziada ao disparar, para que
+// uma mesma rajada não gere várias detecções.
+func (t *spamTracker) observe(key string, e spamEntry, maxMessages, maxDuplicates int, window time.Duration) (string, []spamEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.observed++
+	if t.observed%spamSweepEvery == 0 {
+		t.sweepLocked(e.at, window)
+	}
+
+	cutoff := e.at.Add(-window)
+	entries := t.windows[key]
+	kept := entries[:0]
+	for _, prev := range entries {
+		if prev.at.After(cutoff) {
+			kept = append(kept, prev)
+		}
+	}
+	kept = append(kept, e)
+
+	var rule string
+	var involved []spamEntry
+	if e.content != "" {
+		for _, prev := range kept {
+			if prev.content == e.content {
+				involved = append(involved, prev)
+			}
+		}
+		if len(involved) >= maxDuplicates {
+			rule = SpamRuleDuplicate
+		}
+	}
+	if rule == "" && len(kept) >= maxMessages {
+		rule, involved = SpamRuleRate, append([]spamEntry(nil), kept...)
+	}
+	if rule != "" {
+		delete(t.windows, key)
+		return rule, involved
+	}
+	t.windows[key] = kept
+	return "", nil
+}
+
+// sweepLocked descarta janelas sem mensagens recentes (usuários que pararam de falar).
+func (t *spamTracker) sweepLocked(now time.Time, window time.Duration) {
+	if window < spamIdle {
+		window = spamIdle
+	}
+	for key, entries := range t.windows {
+		if len(entries) == 0 || now.Sub(entries[len(entries)-1].at) > window {
+			delete(t.windows, key)
+		}
+	}
+}
+
+// handleSpamMessage aplica a detecção de spam por taxa às mensagens de guilds com automod_spam ativo.
+func (as *AutomodService) handleSpamMessage(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m == nil || m.Message == nil || m.GuildID == "" || m.Author == nil || m.Author.Bot {
+		return
+	}
+	gcfg, ok := as.configManager.GuildConfig(m.GuildID)
+	if !ok {
+		return
+	}
+	gcfg = as.channelConfig(gcfg, m.ChannelID)
+	if gcfg.AutomodSpam == nil || !gcfg.AutomodSpam.Enabled {
+		return
+	}
+	maxMessages, maxDuplicates, window, timeout := gcfg.AutomodSpam.Limits()
+
+	ts := m.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	content := Normalize(m.Content, NormalizationPasses(gcfg.AutomodLanguage, gcfg.AutomodNormalization))
+	rule, involved := as.spam.observe(m.GuildID+":"+m.Author.ID, spamEntry{
+		at:        ts,
+		channelID: m.ChannelID,
+		messageID: m.ID,
+		content:   content,
+	}, maxMessages, maxDuplicates, window)
+	if rule == "" {
+		return
+	}
+	// Isenções só são avaliadas quando há detecção, evitando consultas a cada mensagem
+	if as.isExempt(&gcfg, m.GuildID, m.ChannelID, m.Author.ID) {
+		return
+	}
+
+	log.Info().Applicationf("Spam detected: guildID=%s, userID=%s, rule=%s, messages=%d, window=%s", m.GuildID, m.Author.ID, rule, len(involved), window)
+	go as.enforceSpam(&gcfg, m.Author.ID, rule, m.Content, involved, window, timeout)
+}
+
+// enforceSpam executa a ação configurada, registra em automod_actions e notifica o canal de log.
+func (as *AutomodService) enforceSpam(gcfg *files.GuildConfig, userID, rule, sample string, involved []spamEntry, window, timeout time.Duration) {
+	guildID := gcfg.GuildID
+	action := gcfg.AutomodSpam.SpamAction()
+	matched := fmt.Sprintf("%d messages in %s", len(involved), window)
+	channelID := involved[len(involved)-1].channelID
+
+	event := &discordgo.AutoModerationActionExecution{
+		GuildID:        guildID,
+		UserID:         userID,
+		ChannelID:      channelID,
+		RuleID:         rule,
+		MatchedContent: matched,
+		Content:        sample,
+	}
+	dryRun := gcfg.AutomodSpamDryRun()
+
+	writes := newAutomodWrites(guildID, userID)
+	var taken []string
+	record := func(name string) {
+		taken = append(taken, name)
+		writes.recordAction(as.localAction(guildID, userID, channelID, rule, matched, name, sample, dryRun))
+	}
+
+	if dryRun {
+		// Só registra e notifica o que seria feito: sem remoção, timeout, DM nem cooldown de ações
+		if action == files.SpamActionDelete || action == files.SpamActionDeleteTimeout {
+			record("delete_messages")
+		}
+		if action == files.SpamActionTimeout || action == files.SpamActionDeleteTimeout {
+			record("timeout")
+		}
+		log.Info().Applicationf("AutoMod dry run: guildID=%s, userID=%s, rule=%s, wouldTake=%v", guildID, userID, rule, taken)
+		as.notifyAction(gcfg, event, as.commitWrites(writes), taken)
+		return
+	}
+
+	if gcfg.AutomodShadowDelete() {
+		// Shadow-delete: as mensagens somem em silêncio, sem timeout, DM, notificação nem cooldown de ações
+		if deleted := as.deleteSpamMessages(guildID, involved); deleted > 0 {
+			record(automodShadowDeleteAction)
+			as.commitWrites(writes)
+			log.Info().Applicationf("AutoMod shadow delete: guildID=%s, channelID=%s, userID=%s, rule=%s, messages=%d", guildID, channelID, userID, rule, deleted)
+		}
+		return
+	}
+
+	// Dentro do cooldown de ações, só o conteúdo é removido (sem timeout, DM nem notificação)
+	punish := as.reserveAction(gcfg, writes, rule, action)
+
+	if action == files.SpamActionDelete || action == files.SpamActionDeleteTimeout {
+		if deleted := as.deleteSpamMessages(guildID, involved); deleted > 0 {
+			record("delete_messages")
+		}
+	}
+	if !punish {
+		as.commitWrites(writes)
+		return
+	}
+	if action == files.SpamActionTimeout || action == files.SpamActionDeleteTimeout {
+		timeout, offense := as.escalatedTimeout(gcfg, guildID, userID, timeout)
+		if err := discord.ClientFor(as.session).Timeout(context.Background(), guildID, userID, timeout); err != nil {
+			log.Warn().Applicationf("Failed to timeout spammer: guildID=%s, userID=%s, error=%v", guildID, userID, err)
+		} else {
+			record("timeout")
+			writes.recordOffense(offense)
+		}
+	}
+
+	as.notifyAction(gcfg, event, as.commitWrites(writes), nil)
+	as.notifyUser(gcfg, guildID, userID, channelID, rule, matched, taken)
+}
+
+// deleteSpamMessages remove as mensagens envolvidas, em lote por canal quando possível.
+func (as *AutomodService) deleteSpamMessages(guildID string, involved []spamEntry) int {
+	byChannel := make(map[string][]string)
+	for _, e := range involved {
+		byChannel[e.channelID] = append(byChannel[e.channelID], e.messageID)
+	}
+	deleted := 0
+	for channelID, ids := range byChannel {
+		if err := discord.ClientFor(as.session).DeleteMessages(context.Background(), channelID, ids); err != nil {
+			log.Warn().Applicationf("Failed to delete spam messages: guildID=%s, channelID=%s, count=%d, error=%v", guildID, channelID, len(ids), err)
+			continue
+		}
+		deleted += len(ids)
+	}
+	return deleted
+}
+
+// localAction monta o registro em automod_actions de uma ação tomada (ou, com simulated, só
+// simulada) pela detecção local (spam, links, anexos).
+func (as *AutomodService) localAction(guildID, userID, channelID, rule, matched, action, content string, simulated bool) storage.AutomodAction {
+	return storage.AutomodAction{
+		GuildID:   guildID,
+		UserID:    userID,
+		ChannelID: channelID,
+		RuleID:    rule,
+		Matched:   matched,
+		Action:    action,
+		Content:   as.privacyContent(guildID, content),
+		Simulated: simulated,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// automodWrites acumula as gravações de uma aplicação de automod sobre um usuário (ações em
+// automod_actions, cooldown e ofensa de escalonamento em automod_user_state) para que
+// commitWrites as persista numa única transação: uma falha no meio não deixa o registro da ação
+// e o estado do usuário inconsistentes.
+type automodWrites struct {
+	guildID string
+	userID  string
+	actions []storage.AutomodAction
+	mark    *automodMark
+	offense *automodOffense
+}
+
+type automodMark struct {
+	rule   string
+	action string
+	at     time.Time
+}
+
+// automodOffense é uma ofensa com timeout; decay é aplicado ao nível gravado antes do incremento.
+type automodOffense struct {
+	at    time.Time
+	decay func(level int, lastOffense time.Time) int
+}
+
+func newAutomodWrites(guildID, userID string) *automodWrites {
+	return &automodWrites{guildID: guildID, userID: userID}
+}
+
+func (w *automodWrites) recordAction(a storage.AutomodAction) {
+	w.actions = append(w.actions, a)
+}
+
+func (w *automodWrites) markAction(rule, action string, at time.Time) {
+	w.mark = &automodMark{rule: rule, action: action, at: at}
+}
+
+func (w *automodWrites) recordOffense(o *automodOffense) {
+	if o != nil {
+		w.offense = o
+	}
+}
+
+// commitWrites grava as operações acumuladas em w numa transação (store.WithTx) e retorna o ID da
+// primeira ação registrada. Sem store, sem nada a gravar ou em erro (só logado: o registro do
+// automod é best effort) retorna 0.
+func (as *AutomodService) commitWrites(w *automodWrites) int64 {
+	if as.store == nil || (len(w.actions) == 0 && w.mark == nil && w.offense == nil) {
+		return 0
+	}
+	var actionID int64
+	err := as.store.WithTx(context.Background(), func(tx storage.StoreTx) error {
+		actionID = 0 // WithTx pode repetir fn em contenção de lock
+		for _, a := range w.actions {
+			id, err := tx.RecordAutomodAction(a)
+			if err != nil {
+				return fmt.Errorf("record action %s: %w", a.Action, err)
+			}
+			if actionID == 0 {
+				actionID = id
+			}
+		}
+		if m := w.mark; m != nil {
+			if err := tx.MarkAutomodAction(w.guildID, w.userID, m.rule, m.action, m.at); err != nil {
+				return fmt.Errorf("mark action: %w", err)
+			}
+		}
+		if o := w.offense; o != nil {
+			if _, err := tx.RecordAutomodOffense(w.guildID, w.userID, o.at, o.decay); err != nil {
+				return fmt.Errorf("record offense: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warn().Applicationf("Failed to record automod action: guildID=%s, userID=%s, error=%v", w.guildID, w.userID, err)
+		return 0
+	}
+	return actionID
+}
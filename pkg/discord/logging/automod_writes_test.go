@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+func TestEnforceSpamRecordsActionAndStateTogether(t *testing.T) {
+	store := storage.NewStoreWithOptions(filepath.Join(t.TempDir(), "automod.db"), storage.Options{})
+	if err := store.Init(); err != nil {
+		t.Fatalf("init store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	s.Client = &http.Client{Transport: &sendCounter{posts: map[string]int{}}}
+	config := files.NewConfigManagerWithPath(filepath.Join(t.TempDir(), "settings.json"))
+	as := NewAutomodService(s, config)
+	as.SetStore(store)
+
+	gcfg := &files.GuildConfig{
+		GuildID:           "g1",
+		AutomodSpam:       &files.AutomodSpamConfig{Enabled: true, Action: files.SpamActionDeleteTimeout},
+		AutomodEscalation: &files.AutomodEscalationConfig{Enabled: true},
+	}
+	involved := []spamEntry{{at: time.Now(), channelID: "c1", messageID: "m1", content: "spam"}}
+	before := time.Now().Add(-time.Minute)
+	as.enforceSpam(gcfg, "u1", "spam_rate", "spam", involved, 10*time.Second, time.Minute)
+
+	counts, err := store.AutomodActionCounts("g1", before)
+	if err != nil {
+		t.Fatalf("action counts: %v", err)
+	}
+	if counts["delete_messages"] != 1 || counts["timeout"] != 1 {
+		t.Fatalf("recorded actions = %v, want one delete_messages and one timeout", counts)
+	}
+	st, err := store.GetAutomodUserState("g1", "u1")
+	if err != nil || st == nil {
+		t.Fatalf("user state = %v, %v", st, err)
+	}
+	if st.EscalationLevel != 1 || st.LastRule != "spam_rate" || st.LastActionAt.IsZero() {
+		t.Fatalf("user state = %+v, want level 1 and the spam action marked", st)
+	}
+}
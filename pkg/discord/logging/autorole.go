@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// scheduleAutorole atribui os cargos de autorole ao novo membro, na entrada ou depois do delay
+// configurado. A configuração é relida no momento da atribuição, então mudanças durante a espera valem.
+func (mes *MemberEventService) scheduleAutorole(gcfg files.GuildConfig, userID string) {
+	if gcfg.Autorole == nil || !gcfg.Autorole.Enabled || len(gcfg.Autorole.RoleIDs) == 0 {
+		return
+	}
+	guildID := gcfg.GuildID
+	if delay := gcfg.Autorole.DelayDuration(); delay > 0 {
+		time.AfterFunc(delay, func() {
+			if mes.IsRunning() {
+				mes.assignAutoroles(guildID, userID)
+			}
+		})
+		return
+	}
+	go mes.assignAutoroles(guildID, userID)
+}
+
+// assignAutoroles aplica a checagem de idade da conta, filtra os cargos que o bot não consegue
+// atribuir (com aviso) e registra os atribuídos em member_joins.
+func (mes *MemberEventService) assignAutoroles(guildID, userID string) {
+	gcfg, ok := mes.configManager.GuildConfig(guildID)
+	if !ok || gcfg.Autorole == nil || !gcfg.Autorole.Enabled {
+		return
+	}
+	cfg := gcfg.Autorole
+	if minAge := cfg.MinAccountAgeDuration(); minAge > 0 {
+		if age := mes.calculateAccountAge(userID); age < minAge {
+			log.Info().Applicationf("Autorole skipped (account too new): guildID=%s, userID=%s, accountAge=%s, required=%s",
+				guildID, userID, age.Round(time.Minute), minAge)
+			return
+		}
+	}
+
+	client := discord.ClientFor(mes.session)
+	ctx := context.Background()
+	member, err := client.FetchMember(ctx, guildID, userID)
+	if err != nil {
+		// Normalmente o membro saiu antes do delay
+		log.Info().Applicationf("Autorole skipped (member not found): guildID=%s, userID=%s, error=%v", guildID, userID, err)
+		return
+	}
+
+	var assigned []string
+	for _, roleID := range mes.assignableAutoroles(client, guildID, cfg.RoleIDs) {
+		if slices.Contains(member.Roles, roleID) {
+			continue
+		}
+		err := client.AddRole(ctx, guildID, userID, roleID)
+		switch {
+		case err == nil:
+			assigned = append(assigned, roleID)
+		case restErrorCode(err) == discordgo.ErrCodeMissingPermissions:
+			log.Warn().Applicationf("Autorole failed: missing MANAGE_ROLES or role %s is above the bot's highest role: guildID=%s, userID=%s", roleID, guildID, userID)
+		default:
+			log.Warn().Applicationf("Autorole failed: guildID=%s, userID=%s, roleID=%s, error=%v", guildID, userID, roleID, err)
+		}
+	}
+	if len(assigned) == 0 {
+		return
+	}
+	log.Info().Applicationf("Autorole assigned: guildID=%s, userID=%s, roles=%s", guildID, userID, strings.Join(assigned, ","))
+	if mes.store != nil {
+		if err := mes.store.RecordAutoroleAssignment(guildID, userID, assigned, time.Now()); err != nil {
+			log.Warn().Applicationf("Failed to record autorole assignment: guildID=%s, userID=%s, error=%v", guildID, userID, err)
+		}
+	}
+}
+
+// assignableAutoroles retorna os cargos que o bot consegue atribuir segundo o state (ver
+// discord.Client.RoleAssignable). Os demais são descartados com um aviso; sem MANAGE_ROLES,
+// nenhum é atribuído.
+func (mes *MemberEventService) assignableAutoroles(client *discord.Client, guildID string, roleIDs []string) []string {
+	out := make([]string, 0, len(roleIDs))
+	for _, id := range roleIDs {
+		err := client.RoleAssignable(guildID, id)
+		switch {
+		case err == nil:
+			out = append(out, id)
+		case errors.Is(err, discord.ErrMissingManageRoles):
+			log.Warn().Applicationf("Autorole disabled in practice: the bot lacks MANAGE_ROLES in guild %s", guildID)
+			return nil
+		case errors.Is(err, discord.ErrRoleAboveBot):
+			log.Warn().Applicationf("Autorole role skipped, move the bot's role above it: guildID=%s, error=%v", guildID, err)
+		default:
+			log.Warn().Applicationf("Autorole role skipped: guildID=%s, error=%v", guildID, err)
+		}
+	}
+	return out
+}
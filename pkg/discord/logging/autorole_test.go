@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// requestCounter answers every REST call with an empty object and counts them.
+type requestCounter struct{ n atomic.Int64 }
+
+func (c *requestCounter) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.n.Add(1)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`)), Header: http.Header{"Content-Type": {"application/json"}}, Request: r}, nil
+}
+
+func TestDelayedAutoroleStopsWithService(t *testing.T) {
+	autorole := &files.AutoroleConfig{Enabled: true, RoleIDs: []string{"r1"}, Delay: "20ms"}
+	config := files.NewConfigManagerWithPath(filepath.Join(t.TempDir(), "settings.json"))
+	if err := config.AddGuildConfig(files.GuildConfig{GuildID: "g1", Autorole: autorole}); err != nil {
+		t.Fatalf("add guild config: %v", err)
+	}
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	counter := &requestCounter{}
+	s.Client = &http.Client{Transport: counter}
+
+	mes := NewMemberEventService(s, config, NewNotificationSender(s), nil)
+	if err := mes.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	gcfg, _ := config.GuildConfig("g1")
+
+	// Os timers leem o estado do serviço enquanto Stop o altera (roda sob -race)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() { mes.scheduleAutorole(gcfg, "u"+string(rune('a'+i))) })
+	}
+	wg.Wait()
+	if err := mes.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if n := counter.n.Load(); n != 0 {
+		t.Errorf("%d REST calls after Stop, want none", n)
+	}
+}
@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// Limites do embed de resumo: membros listados na descrição e miniaturas (um embed cada; o
+// Discord aceita até 10 embeds por mensagem, o primeiro é o resumo).
+const (
+	avatarDigestListed     = 40
+	avatarDigestThumbnails = 9
+)
+
+// avatarDigests acumula as trocas de avatar das guilds em modo digest até o envio do resumo.
+type avatarDigests struct {
+	mu      sync.Mutex
+	pending map[string]*avatarDigest
+}
+
+type avatarDigest struct {
+	changes []files.AvatarChange
+	index   map[string]int // userID -> posição em changes (várias trocas no intervalo viram uma)
+	since   time.Time
+	timer   *time.Timer
+}
+
+// queueAvatarDigest guarda change para o próximo resumo da guild; a primeira troca do intervalo agenda o envio.
+func (aw *UserWatcher) queueAvatarDigest(guildID string, change files.AvatarChange, interval time.Duration) {
+	if change.Username == "" || (change.OldAvatar == "" && change.NewAvatar == "") {
+		return
+	}
+	d := &aw.digests
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending == nil {
+		d.pending = make(map[string]*avatarDigest)
+	}
+	p := d.pending[guildID]
+	if p == nil {
+		p = &avatarDigest{index: make(map[string]int), since: change.Timestamp}
+		p.timer = time.AfterFunc(interval, func() { aw.flushAvatarDigest(guildID) })
+		d.pending[guildID] = p
+	}
+	if i, ok := p.index[change.UserID]; ok {
+		// Mantém o avatar de antes do intervalo e o mais recente
+		change.OldAvatar = p.changes[i].OldAvatar
+		p.changes[i] = change
+		return
+	}
+	p.index[change.UserID] = len(p.changes)
+	p.changes = append(p.changes, change)
+}
+
+// flushAvatarDigest envia o resumo pendente da guild para o canal de log configurado agora.
+func (aw *UserWatcher) flushAvatarDigest(guildID string) {
+	d := &aw.digests
+	d.mu.Lock()
+	p := d.pending[guildID]
+	delete(d.pending, guildID)
+	d.mu.Unlock()
+	if p == nil || len(p.changes) == 0 {
+		return
+	}
+	p.timer.Stop()
+
+	gcfg, ok := aw.configManager.GuildConfig(guildID)
+	if !ok || gcfg.UserLogChannelID == "" {
+		log.Warn().Applicationf("Avatar digest dropped (no user log channel): guildID=%s, changes=%d", guildID, len(p.changes))
+		return
+	}
+	if err := aw.notifier.SendAvatarDigest(gcfg.UserLogChannelID, p.changes, time.Since(p.since)); err != nil {
+		log.Error().Errorf("Error sending avatar digest to channel %s in guild %s: %v", gcfg.UserLogChannelID, guildID, err)
+		return
+	}
+	log.Info().Applicationf("Avatar digest sent: guildID=%s, channelID=%s, changes=%d", guildID, gcfg.UserLogChannelID, len(p.changes))
+}
+
+// flushAvatarDigests envia na hora todos os resumos pendentes (usado no shutdown).
+func (aw *UserWatcher) flushAvatarDigests() {
+	aw.digests.mu.Lock()
+	guilds := make([]string, 0, len(aw.digests.pending))
+	for guildID := range aw.digests.pending {
+		guilds = append(guilds, guildID)
+	}
+	aw.digests.mu.Unlock()
+	for _, guildID := range guilds {
+		aw.flushAvatarDigest(guildID)
+	}
+}
+
+// SendAvatarDigest envia o resumo de trocas de avatar: um embed com a lista de membros e miniaturas
+// dos novos avatares dos primeiros.
+func (ns *NotificationSender) SendAvatarDigest(channelID string, changes []files.AvatarChange, window time.Duration) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	title := fmt.Sprintf("🖼️ %d members changed avatars", len(changes))
+	if len(changes) == 1 {
+		title = "🖼️ 1 member changed avatar"
+	}
+	var b strings.Builder
+	for _, c := range changes[:min(len(changes), avatarDigestListed)] {
+		fmt.Fprintf(&b, "**%s** (<@%s>)\n", c.Username, c.UserID)
+	}
+	if extra := len(changes) - avatarDigestListed; extra > 0 {
+		fmt.Fprintf(&b, "…and %d more\n", extra)
+	}
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       title,
+		Color:       theme.AvatarChange(),
+		Description: b.String(),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Last " + formatDurationSmart(max(window.Round(time.Minute), time.Minute))},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}}
+	for _, c := range changes[:min(len(changes), avatarDigestThumbnails)] {
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Color:       theme.AvatarChange(),
+			Description: fmt.Sprintf("**%s**", c.Username),
+			Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: ns.buildAvatarURL(c.UserID, c.NewAvatar)},
+		})
+	}
+	if err := ns.sendEmbeds(channelID, files.NotificationEventAvatarChange, embeds...); err != nil {
+		return fmt.Errorf(ErrSendMessage, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// avatarRefreshScope é o conjunto de membros cujo avatar entra no refresh de uma guild.
+// nil significa todos os membros (refresh completo, o padrão).
+type avatarRefreshScope map[string]struct{}
+
+// activeMembers resolve o escopo do refresh segundo avatar_refresh da guild. Se os membros ativos
+// não puderem ser consultados, volta ao refresh completo para não perder trocas de avatar.
+func (ms *MonitoringService) activeMembers(guildID string) avatarRefreshScope {
+	gcfg, ok := ms.configManager.GuildConfig(guildID)
+	if !ok || gcfg.AvatarRefresh == nil || !gcfg.AvatarRefresh.ActiveOnly || ms.store == nil {
+		return nil
+	}
+	ids, err := ms.store.ActiveMemberIDs(guildID, time.Now().Add(-gcfg.AvatarRefresh.Window()))
+	if err != nil {
+		log.Warn().Applicationf("Failed to load active members, refreshing all avatars: guildID=%s, error=%v", guildID, err)
+		return nil
+	}
+	return avatarRefreshScope(ids)
+}
+
+func (s avatarRefreshScope) includes(userID string) bool {
+	if s == nil {
+		return true
+	}
+	_, ok := s[userID]
+	return ok
+}
+
+// logScope registra quantos membros ficaram fora de um refresh restrito aos ativos.
+func (s avatarRefreshScope) logScope(guildID string, refreshed, total int) {
+	if s == nil {
+		return
+	}
+	log.Info().Applicationf("Avatar refresh limited to active members: guildID=%s, refreshed=%d, skipped=%d", guildID, refreshed, total-refreshed)
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// AvatarSubscriberBuffer é o tamanho do buffer de cada assinatura de avatar.
+const AvatarSubscriberBuffer = 64
+
+// AvatarChangeEvent descreve uma troca de avatar detectada pelo MonitoringService.
+type AvatarChangeEvent struct {
+	GuildID   string
+	UserID    string
+	OldHash   string
+	NewHash   string
+	Timestamp time.Time
+}
+
+// avatarBroker distribui eventos de avatar para os assinantes sem nunca bloquear a detecção.
+type avatarBroker struct {
+	mu      sync.Mutex
+	subs    map[<-chan AvatarChangeEvent]chan AvatarChangeEvent
+	closed  bool
+	dropped uint64
+}
+
+func newAvatarBroker() *avatarBroker {
+	return &avatarBroker{subs: make(map[<-chan AvatarChangeEvent]chan AvatarChangeEvent)}
+}
+
+func (b *avatarBroker) subscribe() <-chan AvatarChangeEvent {
+	ch := make(chan AvatarChangeEvent, AvatarSubscriberBuffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.subs[ch] = ch
+	return ch
+}
+
+func (b *avatarBroker) unsubscribe(ch <-chan AvatarChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(c)
+	}
+}
+
+// publish entrega o evento a cada assinante com espaço no buffer; para os lentos, o evento é descartado.
+func (b *avatarBroker) publish(e AvatarChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.subs {
+		select {
+		case c <- e:
+		default:
+			if n := atomic.AddUint64(&b.dropped, 1); n == 1 || n%100 == 0 {
+				log.Warn().Applicationf("Avatar change subscriber too slow; dropped %d events so far", n)
+			}
+		}
+	}
+}
+
+// close encerra todas as assinaturas (os canais são fechados).
+func (b *avatarBroker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for key, c := range b.subs {
+		delete(b.subs, key)
+		close(c)
+	}
+}
+
+// reopen volta a aceitar assinaturas depois de um close (restart do serviço).
+func (b *avatarBroker) reopen() {
+	b.mu.Lock()
+	b.closed = false
+	b.mu.Unlock()
+}
+
+// SubscribeAvatarChanges retorna um canal com as trocas de avatar detectadas a partir de agora.
+//
+// Backpressure: cada assinatura tem buffer de AvatarSubscriberBuffer eventos e a publicação nunca
+// bloqueia a detecção; se o buffer estiver cheio, o evento é descartado para aquele assinante
+// (com aviso no log). O canal é fechado por UnsubscribeAvatarChanges ou quando o serviço para,
+// então um consumidor com `for e := range ch` termina sozinho.
+func (ms *MonitoringService) SubscribeAvatarChanges() <-chan AvatarChangeEvent {
+	return ms.avatarEvents.subscribe()
+}
+
+// UnsubscribeAvatarChanges encerra a assinatura e fecha o canal. Chamadas repetidas são no-op.
+func (ms *MonitoringService) UnsubscribeAvatarChanges(ch <-chan AvatarChangeEvent) {
+	ms.avatarEvents.unsubscribe(ch)
+}
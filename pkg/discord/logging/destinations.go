@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// sendToDestination envia a mensagem para um canal ou thread de log. Threads arquivadas são
+// desarquivadas antes do envio; se a thread foi deletada, a mensagem vai para o canal pai.
+func (ns *NotificationSender) sendToDestination(channelID string, msg *discordgo.MessageSend) error {
+	ns.prepareThreadDestination(channelID)
+
+	client := discord.ClientFor(ns.session)
+	_, err := client.SendComplex(context.Background(), channelID, msg)
+	switch restErrorCode(err) {
+	case discordgo.ErrCodePerformedOperationOnArchivedThread:
+		// Estado local desatualizado: a thread foi arquivada depois do último THREAD_UPDATE visto
+		if uerr := ns.unarchiveThread(channelID); uerr != nil {
+			return err
+		}
+		_, err = client.SendComplex(context.Background(), channelID, msg)
+	case discordgo.ErrCodeUnknownChannel:
+		parentID := ns.threadParent(channelID)
+		if parentID == "" {
+			return err
+		}
+		log.Warn().Applicationf("Log thread %s no longer exists; falling back to parent channel %s", channelID, parentID)
+		_, err = client.SendComplex(context.Background(), parentID, msg)
+	}
+	return err
+}
+
+// prepareThreadDestination memoriza o canal pai de threads de log e desarquiva threads arquivadas.
+func (ns *NotificationSender) prepareThreadDestination(channelID string) {
+	if ns.session.State == nil {
+		return
+	}
+	ch, err := ns.session.State.Channel(channelID)
+	if err != nil || ch == nil || !ch.IsThread() {
+		return
+	}
+	ns.rememberThreadParent(ch)
+	if ch.ThreadMetadata != nil && ch.ThreadMetadata.Archived {
+		_ = ns.unarchiveThread(channelID)
+	}
+}
+
+// unarchiveThread desarquiva uma thread antes de postar nela.
+func (ns *NotificationSender) unarchiveThread(threadID string) error {
+	archived := false
+	if _, err := discord.ClientFor(ns.session).EditChannel(context.Background(), threadID, &discordgo.ChannelEdit{Archived: &archived}); err != nil {
+		log.Warn().Applicationf("Failed to unarchive log thread %s: %v", threadID, err)
+		return err
+	}
+	log.Info().Applicationf("Unarchived log thread %s before posting", threadID)
+	return nil
+}
+
+func (ns *NotificationSender) rememberThreadParent(ch *discordgo.Channel) {
+	if ch == nil || ch.ParentID == "" {
+		return
+	}
+	ns.threadMu.Lock()
+	defer ns.threadMu.Unlock()
+	if ns.threadParents == nil {
+		ns.threadParents = make(map[string]string)
+	}
+	ns.threadParents[ch.ID] = ch.ParentID
+}
+
+func (ns *NotificationSender) threadParent(threadID string) string {
+	ns.threadMu.Lock()
+	defer ns.threadMu.Unlock()
+	return ns.threadParents[threadID]
+}
+
+// ValidateLogDestinations verifica os canais de log configurados, aceitando threads como destino.
+// Para threads, registra o canal pai (usado como fallback se a thread for deletada) e avisa quando
+// a thread está trancada (locked) ou inacessível.
+func (ns *NotificationSender) ValidateLogDestinations() {
+	if ns.configManager == nil {
+		return
+	}
+	for _, gcfg := range ns.configManager.Guilds() {
+		for _, channelID := range logDestinations(gcfg) {
+			ch, err := ns.session.Channel(channelID)
+			if err != nil {
+				log.Warn().Applicationf("Log destination %s in guild %s is not accessible: %v", channelID, gcfg.GuildID, err)
+				continue
+			}
+			if !ch.IsThread() {
+				continue
+			}
+			ns.rememberThreadParent(ch)
+			if ch.ThreadMetadata != nil && ch.ThreadMetadata.Locked {
+				log.Warn().Applicationf("Log thread %s in guild %s is locked; posting requires Manage Threads", channelID, gcfg.GuildID)
+			}
+		}
+	}
+}
+
+// logDestinations retorna os IDs distintos de canais/threads de log configurados para a guild.
+func logDestinations(gcfg files.GuildConfig) []string {
+	var out []string
+	for _, id := range []string{gcfg.UserLogChannelID, gcfg.UserEntryLeaveChannelID, gcfg.MessageLogChannelID, gcfg.AutomodLogChannelID} {
+		if id != "" && !containsID(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// restErrorCode extrai o código de erro JSON da API do Discord (0 se não houver).
+func restErrorCode(err error) int {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		return restErr.Message.Code
+	}
+	return 0
+}
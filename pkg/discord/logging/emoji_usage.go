@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// customEmojiPattern reconhece emojis customizados no conteúdo: <:nome:id> ou <a:nome:id> (animado).
+var customEmojiPattern = regexp.MustCompile(`<(a?):([A-Za-z0-9_]{2,32}):([0-9]{15,25})>`)
+
+// extractEmojiUses lista os emojis customizados e figurinhas de uma mensagem. Cada emoji conta
+// uma vez por mensagem, para que repetições na mesma mensagem não inflem o ranking.
+func extractEmojiUses(m *discordgo.Message) []storage.EmojiUse {
+	if m == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var uses []storage.EmojiUse
+	for _, match := range customEmojiPattern.FindAllStringSubmatch(m.Content, -1) {
+		if seen[match[3]] {
+			continue
+		}
+		seen[match[3]] = true
+		uses = append(uses, storage.EmojiUse{ID: match[3], Name: match[2], Kind: storage.EmojiKindEmoji, Animated: match[1] == "a"})
+	}
+	for _, st := range m.StickerItems {
+		if st == nil || st.ID == "" || seen[st.ID] {
+			continue
+		}
+		seen[st.ID] = true
+		uses = append(uses, storage.EmojiUse{ID: st.ID, Name: st.Name, Kind: storage.EmojiKindSticker})
+	}
+	return uses
+}
+
+// handleEmojiUsage incrementa os contadores de emojis/figurinhas em guilds com EmojiStatsEnabled.
+func (ms *MonitoringService) handleEmojiUsage(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m == nil || m.Message == nil || m.GuildID == "" || ms.store == nil {
+		return
+	}
+	if m.Author == nil || m.Author.Bot {
+		return
+	}
+	gcfg, ok := ms.configManager.GuildConfig(m.GuildID)
+	if !ok || !gcfg.EmojiStatsEnabled {
+		return
+	}
+	uses := extractEmojiUses(m.Message)
+	if len(uses) == 0 {
+		return
+	}
+	if err := ms.store.IncrementEmojiUsage(m.GuildID, uses, time.Now()); err != nil {
+		log.Warn().Applicationf("Failed to record emoji usage: guildID=%s, messageID=%s, err=%v", m.GuildID, m.ID, err)
+	}
+}
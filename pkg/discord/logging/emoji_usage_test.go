@@ -0,0 +1,21 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestExtractEmojiUsesAnimated(t *testing.T) {
+	m := &discordgo.Message{Content: "<a:party:111111111111111111> <:smile:222222222222222222> <a:party:111111111111111111>"}
+	uses := extractEmojiUses(m)
+	if len(uses) != 2 {
+		t.Fatalf("got %d uses, want 2", len(uses))
+	}
+	if uses[0].ID != "111111111111111111" || uses[0].Name != "party" || !uses[0].Animated {
+		t.Errorf("first use = %+v, want animated party", uses[0])
+	}
+	if uses[1].ID != "222222222222222222" || uses[1].Name != "smile" || uses[1].Animated {
+		t.Errorf("second use = %+v, want static smile", uses[1])
+	}
+}
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// greeterBatchMentions é quantos membros um lote de boas-vindas menciona; o resto vira "and N more".
+const greeterBatchMentions = 50
+
+// greeterState acompanha as rajadas de entradas por guild para o agrupamento das boas-vindas.
+type greeterState struct {
+	mu     sync.Mutex
+	bursts map[string]*greeterBurst
+}
+
+type greeterBurst struct {
+	joins   []time.Time       // entradas dentro da janela
+	pending []*discordgo.User // boas-vindas agrupadas aguardando o envio do lote
+	timer   *time.Timer
+}
+
+// greetJoin envia as boas-vindas configuradas em greeter.welcome. Durante uma rajada de entradas
+// (mais de burst_limit na janela), as mensagens no canal são agrupadas e as DMs suprimidas.
+func (mes *MemberEventService) greetJoin(gcfg files.GuildConfig, user *discordgo.User) {
+	if gcfg.Greeter == nil || gcfg.Greeter.Welcome == nil || !gcfg.Greeter.Welcome.Enabled {
+		return
+	}
+	welcome := gcfg.Greeter.Welcome
+	limit, window := gcfg.Greeter.Burst()
+	now := time.Now()
+
+	st := &mes.greeter
+	st.mu.Lock()
+	if st.bursts == nil {
+		st.bursts = make(map[string]*greeterBurst)
+	}
+	b := st.bursts[gcfg.GuildID]
+	if b == nil {
+		b = &greeterBurst{}
+		st.bursts[gcfg.GuildID] = b
+	}
+	b.joins = append(pruneBefore(b.joins, now.Add(-window)), now)
+	if len(b.joins) <= limit && b.timer == nil {
+		st.mu.Unlock()
+		mes.sendGreeting(gcfg, welcome, files.DefaultGreeterWelcomeTemplate, []*discordgo.User{user})
+		return
+	}
+	if welcome.DM {
+		st.mu.Unlock()
+		log.Info().Applicationf("Welcome DM suppressed (join burst): guildID=%s, userID=%s", gcfg.GuildID, user.ID)
+		return
+	}
+	b.pending = append(b.pending, user)
+	if b.timer == nil {
+		log.Info().Applicationf("Join burst detected, batching welcome messages: guildID=%s, joins=%d in %s", gcfg.GuildID, len(b.joins), window)
+		guildID := gcfg.GuildID
+		b.timer = time.AfterFunc(window, func() { mes.flushGreetings(guildID) })
+	}
+	st.mu.Unlock()
+}
+
+// flushGreetings envia o lote de boas-vindas pendente da guild, com a configuração atual.
+func (mes *MemberEventService) flushGreetings(guildID string) {
+	st := &mes.greeter
+	st.mu.Lock()
+	b := st.bursts[guildID]
+	if b == nil {
+		st.mu.Unlock()
+		return
+	}
+	users := b.pending
+	b.pending, b.timer = nil, nil
+	st.mu.Unlock()
+
+	gcfg, ok := mes.configManager.GuildConfig(guildID)
+	if !ok || len(users) == 0 || gcfg.Greeter == nil || gcfg.Greeter.Welcome == nil || !gcfg.Greeter.Welcome.Enabled {
+		return
+	}
+	mes.sendGreeting(gcfg, gcfg.Greeter.Welcome, files.DefaultGreeterWelcomeTemplate, users)
+}
+
+// greetLeave envia a despedida configurada em greeter.leave.
+func (mes *MemberEventService) greetLeave(gcfg files.GuildConfig, user *discordgo.User) {
+	if gcfg.Greeter == nil || gcfg.Greeter.Leave == nil || !gcfg.Greeter.Leave.Enabled {
+		return
+	}
+	mes.sendGreeting(gcfg, gcfg.Greeter.Leave, files.DefaultGreeterLeaveTemplate, []*discordgo.User{user})
+}
+
+// sendGreeting renderiza o template para users e envia ao canal configurado ou, com dm, ao membro.
+func (mes *MemberEventService) sendGreeting(gcfg files.GuildConfig, msg *files.GreeterMessage, defaultTemplate string, users []*discordgo.User) {
+	mentions := make([]string, 0, min(len(users), greeterBatchMentions))
+	names := make([]string, 0, cap(mentions))
+	ids := make([]string, 0, cap(mentions))
+	for _, u := range users[:min(len(users), greeterBatchMentions)] {
+		mentions = append(mentions, "<@"+u.ID+">")
+		names = append(names, u.Username)
+		ids = append(ids, u.ID)
+	}
+	if extra := len(users) - len(mentions); extra > 0 {
+		more := " and " + strconv.Itoa(extra) + " more"
+		mentions[len(mentions)-1] += more
+		names[len(names)-1] += more
+	}
+
+	guildName, memberCount := gcfg.GuildID, ""
+	if g, err := mes.session.State.Guild(gcfg.GuildID); err == nil && g != nil {
+		if g.Name != "" {
+			guildName = g.Name
+		}
+		if g.MemberCount > 0 {
+			memberCount = strconv.Itoa(g.MemberCount)
+		}
+	}
+
+	tmpl := msg.Template
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = defaultTemplate
+	}
+	text := strings.NewReplacer(
+		"{mention}", strings.Join(mentions, ", "),
+		"{username}", strings.Join(names, ", "),
+		"{guild}", guildName,
+		"{member_count}", memberCount,
+	).Replace(tmpl)
+	text = truncateRunes(text, 2000)
+	send := &discordgo.MessageSend{
+		Content:         strings.TrimSpace(text),
+		AllowedMentions: &discordgo.MessageAllowedMentions{Users: ids},
+	}
+
+	if msg.DM {
+		_, err := discord.ClientFor(mes.session).SendDM(context.Background(), users[0].ID, send)
+		if restErrorCode(err) == discordgo.ErrCodeCannotSendMessagesToThisUser {
+			log.Info().Applicationf("Welcome DM not delivered (DMs closed): guildID=%s, userID=%s", gcfg.GuildID, users[0].ID)
+		} else if err != nil {
+			log.Warn().Applicationf("Failed to send welcome DM: guildID=%s, userID=%s, error=%v", gcfg.GuildID, users[0].ID, err)
+		}
+		return
+	}
+
+	var err error
+	if mes.notifier != nil {
+		err = mes.notifier.sendToDestination(msg.ChannelID, send)
+	} else {
+		_, err = discord.ClientFor(mes.session).SendComplex(context.Background(), msg.ChannelID, send)
+	}
+	if err != nil {
+		log.Warn().Applicationf("Failed to send greeter message: guildID=%s, channelID=%s, members=%d, error=%v", gcfg.GuildID, msg.ChannelID, len(users), err)
+	}
+}
+
+// pruneBefore remove do início de times (em ordem crescente) os instantes anteriores a cutoff.
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// handleGuildRemoved aplica a guild_removal_policy quando o bot é removido de uma guild.
+// GUILD_DELETE com Unavailable=true é só uma queda do Discord e é ignorado.
+func (ms *MonitoringService) handleGuildRemoved(s *discordgo.Session, e *discordgo.GuildDelete) {
+	if e == nil || e.Guild == nil || e.ID == "" || e.Unavailable {
+		return
+	}
+	guildID := e.ID
+	gcfg, ok := ms.configManager.GuildConfig(guildID)
+	if !ok || gcfg.Inactive {
+		return
+	}
+
+	switch policy := ms.configManager.GuildRemovalPolicy(); policy {
+	case files.GuildRemovalDeactivate:
+		if err := ms.configManager.SetGuildActive(guildID, false); err != nil {
+			log.Error().Errorf("Failed to deactivate guild %s after removal: %v", guildID, err)
+			return
+		}
+		log.Info().Applicationf("👋 Bot removed from guild %s; guild deactivated (config and data kept)", guildID)
+	case files.GuildRemovalPurge:
+		rows, err := PurgeGuild(ms.configManager, ms.store, guildID)
+		if err != nil {
+			log.Error().Errorf("Failed to purge guild %s after removal: %v", guildID, err)
+			return
+		}
+		log.Info().Applicationf("👋 Bot removed from guild %s; config removed and %d stored rows purged", guildID, rows)
+	default:
+		log.Info().Applicationf("👋 Bot removed from guild %s; keeping its config (guild_removal_policy=%s)", guildID, policy)
+	}
+}
+
+// reactivateIfInactive reativa uma guild desativada quando o bot volta a ela (GUILD_CREATE).
+func (ms *MonitoringService) reactivateIfInactive(guildID string) bool {
+	gcfg, ok := ms.configManager.GuildConfig(guildID)
+	if !ok || !gcfg.Inactive {
+		return false
+	}
+	if err := ms.configManager.SetGuildActive(guildID, true); err != nil {
+		log.Error().Errorf("Failed to reactivate guild %s: %v", guildID, err)
+		return false
+	}
+	log.Info().Applicationf("♻️ Bot is back in inactive guild %s; guild reactivated", guildID)
+	return true
+}
+
+// PurgeGuild removes a guild from the config (saving it) and deletes its stored data,
+// returning the number of rows removed. store may be nil.
+func PurgeGuild(cm *files.ConfigManager, store *storage.Store, guildID string) (int64, error) {
+	if cm == nil {
+		return 0, fmt.Errorf("config manager not available")
+	}
+	var rows int64
+	if store != nil {
+		n, err := store.PurgeGuild(guildID)
+		if err != nil {
+			return 0, fmt.Errorf("purge stored data: %w", err)
+		}
+		rows = n
+	}
+	cm.RemoveGuildConfig(guildID)
+	if err := cm.SaveConfig(); err != nil {
+		return rows, fmt.Errorf("save config: %w", err)
+	}
+	return rows, nil
+}
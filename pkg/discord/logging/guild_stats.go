@@ -0,0 +1,191 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildStats é um snapshot das contagens de membros de uma guild.
+type GuildStats struct {
+	GuildID     string
+	MemberCount int
+	OnlineCount int
+	BotCount    int
+	// Approximate is true when the numbers came from the REST fallback (approximate counts,
+	// BotCount unknown) instead of the gateway-fed cache.
+	Approximate bool
+}
+
+// guildStatsCache mantém contagens por guild atualizadas por eventos do gateway
+// (GUILD_CREATE, member add/remove e presence updates), evitando chamadas à API.
+type guildStatsCache struct {
+	mu     sync.RWMutex
+	guilds map[string]*guildStatsEntry
+}
+
+type guildStatsEntry struct {
+	members int
+	bots    int
+	online  map[string]struct{}
+}
+
+func newGuildStatsCache() *guildStatsCache {
+	return &guildStatsCache{guilds: make(map[string]*guildStatsEntry)}
+}
+
+// load (re)initializes a guild's entry from a GUILD_CREATE payload. For large guilds the
+// payload only carries a subset of members, so BotCount starts from what is visible there.
+func (c *guildStatsCache) load(g *discordgo.Guild) {
+	entry := &guildStatsEntry{members: g.MemberCount, online: make(map[string]struct{})}
+	for _, m := range g.Members {
+		if m != nil && m.User != nil && m.User.Bot {
+			entry.bots++
+		}
+	}
+	for _, p := range g.Presences {
+		if p != nil && p.User != nil && isOnline(p.Status) {
+			entry.online[p.User.ID] = struct{}{}
+		}
+	}
+	c.mu.Lock()
+	c.guilds[g.ID] = entry
+	c.mu.Unlock()
+}
+
+func (c *guildStatsCache) remove(guildID string) {
+	c.mu.Lock()
+	delete(c.guilds, guildID)
+	c.mu.Unlock()
+}
+
+func (c *guildStatsCache) memberAdded(guildID string, user *discordgo.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.guilds[guildID]
+	if !ok {
+		return
+	}
+	entry.members++
+	if user != nil && user.Bot {
+		entry.bots++
+	}
+}
+
+func (c *guildStatsCache) memberRemoved(guildID string, user *discordgo.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.guilds[guildID]
+	if !ok {
+		return
+	}
+	if entry.members > 0 {
+		entry.members--
+	}
+	if user != nil {
+		if user.Bot && entry.bots > 0 {
+			entry.bots--
+		}
+		delete(entry.online, user.ID)
+	}
+}
+
+func (c *guildStatsCache) presence(guildID, userID string, status discordgo.Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.guilds[guildID]
+	if !ok {
+		return
+	}
+	if isOnline(status) {
+		entry.online[userID] = struct{}{}
+	} else {
+		delete(entry.online, userID)
+	}
+}
+
+func (c *guildStatsCache) get(guildID string) (GuildStats, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.guilds[guildID]
+	if !ok {
+		return GuildStats{}, false
+	}
+	return GuildStats{
+		GuildID:     guildID,
+		MemberCount: entry.members,
+		OnlineCount: len(entry.online),
+		BotCount:    entry.bots,
+	}, true
+}
+
+func isOnline(status discordgo.Status) bool {
+	return status != "" && status != discordgo.StatusOffline && status != discordgo.StatusInvisible
+}
+
+// GetGuildStats retorna as contagens de membros da guild a partir do cache alimentado
+// pelo gateway. Com o cache frio (guild ainda não recebida), faz fallback para a API
+// usando as contagens aproximadas do Discord.
+func (ms *MonitoringService) GetGuildStats(guildID string) (GuildStats, error) {
+	if guildID == "" {
+		return GuildStats{}, fmt.Errorf("guild ID is empty")
+	}
+	if stats, ok := ms.guildStats.get(guildID); ok {
+		return stats, nil
+	}
+	// Guilds received before the handlers were installed are still in the State cache
+	if ms.session.State != nil {
+		if g, err := ms.session.State.Guild(guildID); err == nil && g != nil && !g.Unavailable {
+			ms.guildStats.load(g)
+			if stats, ok := ms.guildStats.get(guildID); ok {
+				return stats, nil
+			}
+		}
+	}
+	g, err := ms.session.GuildWithCounts(guildID)
+	if err != nil {
+		return GuildStats{}, fmt.Errorf("fetch guild counts for %s: %w", guildID, err)
+	}
+	return GuildStats{
+		GuildID:     guildID,
+		MemberCount: g.ApproximateMemberCount,
+		OnlineCount: g.ApproximatePresenceCount,
+		Approximate: true,
+	}, nil
+}
+
+func (ms *MonitoringService) handleGuildStatsCreate(s *discordgo.Session, e *discordgo.GuildCreate) {
+	if e == nil || e.Guild == nil || e.ID == "" || e.Unavailable {
+		return
+	}
+	ms.guildStats.load(e.Guild)
+}
+
+func (ms *MonitoringService) handleGuildStatsDelete(s *discordgo.Session, e *discordgo.GuildDelete) {
+	if e == nil || e.Guild == nil || e.ID == "" {
+		return
+	}
+	ms.guildStats.remove(e.ID)
+}
+
+func (ms *MonitoringService) handleGuildStatsMemberAdd(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+	if m == nil || m.Member == nil {
+		return
+	}
+	ms.guildStats.memberAdded(m.GuildID, m.User)
+}
+
+func (ms *MonitoringService) handleGuildStatsMemberRemove(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+	if m == nil || m.Member == nil {
+		return
+	}
+	ms.guildStats.memberRemoved(m.GuildID, m.User)
+}
+
+func (ms *MonitoringService) handleGuildStatsPresence(s *discordgo.Session, p *discordgo.PresenceUpdate) {
+	if p == nil || p.User == nil || p.GuildID == "" {
+		return
+	}
+	ms.guildStats.presence(p.GuildID, p.User.ID, p.Status)
+}
@@ -1,12 +1,15 @@
 package logging
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
+	discordsession "github.com/small-frappuccino/discordcore/pkg/discord/session"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 	"github.com/small-frappuccino/discordcore/pkg/storage"
@@ -19,7 +22,9 @@ type MemberEventService struct {
 	configManager *files.ConfigManager
 	notifier      *NotificationSender
 	adapters      *task.NotificationAdapters
+	handlers      *discordsession.HandlerSet
 	isRunning     bool
+	runMu         sync.Mutex // protege isRunning e handlers (lidos também pelos timers de autorole)
 
 	// Cache para tempos de entrada (membro e bot)
 
@@ -29,6 +34,9 @@ type MemberEventService struct {
 	// Persistência complementar (SQLite)
 	store *storage.Store
 
+	// Rajadas de entradas por guild (agrupamento das boas-vindas do greeter)
+	greeter greeterState
+
 	// Cleanup control
 	cleanupStop chan struct{}
 }
@@ -51,6 +59,8 @@ func (mes *MemberEventService) SetAdapters(adapters *task.NotificationAdapters)
 
 // Start registra os handlers de eventos de membros
 func (mes *MemberEventService) Start() error {
+	mes.runMu.Lock()
+	defer mes.runMu.Unlock()
 	if mes.isRunning {
 		return fmt.Errorf("member event service is already running")
 	}
@@ -68,12 +78,13 @@ func (mes *MemberEventService) Start() error {
 		}
 	}
 
-	mes.session.AddHandler(mes.handleGuildMemberAdd)
-	mes.session.AddHandler(mes.handleGuildMemberRemove)
+	mes.handlers = discordsession.NewHandlerSet(mes.session)
+	mes.handlers.Add(mes.handleGuildMemberAdd)
+	mes.handlers.Add(mes.handleGuildMemberRemove)
 
 	// Start periodic cleanup of old joinTimes entries
 	mes.cleanupStop = make(chan struct{})
-	go mes.cleanupLoop()
+	go mes.cleanupLoop(mes.cleanupStop)
 
 	log.Info().Applicationf("Member event service started")
 	return nil
@@ -81,11 +92,17 @@ func (mes *MemberEventService) Start() error {
 
 // Stop para o serviço
 func (mes *MemberEventService) Stop() error {
+	mes.runMu.Lock()
+	defer mes.runMu.Unlock()
 	if !mes.isRunning {
 		return fmt.Errorf("member event service is not running")
 	}
 	mes.isRunning = false
 
+	if mes.handlers != nil {
+		mes.handlers.RemoveAll()
+	}
+
 	// Stop cleanup goroutine
 	if mes.cleanupStop != nil {
 		close(mes.cleanupStop)
@@ -96,8 +113,20 @@ func (mes *MemberEventService) Stop() error {
 	return nil
 }
 
+// ReinstallHandlers re-registra os handlers que não estão mais instalados; usado após
+// reconexões do gateway (sem efeito quando o discordgo os manteve).
+func (mes *MemberEventService) ReinstallHandlers() {
+	mes.runMu.Lock()
+	defer mes.runMu.Unlock()
+	if mes.isRunning && mes.handlers != nil {
+		mes.handlers.Reinstall()
+	}
+}
+
 // IsRunning retorna se o serviço está rodando
 func (mes *MemberEventService) IsRunning() bool {
+	mes.runMu.Lock()
+	defer mes.runMu.Unlock()
 	return mes.isRunning
 }
 
@@ -108,10 +137,18 @@ func (mes *MemberEventService) handleGuildMemberAdd(s *discordgo.Session, m *dis
 	}
 
 	mes.markEvent()
-	guildConfig := mes.configManager.GuildConfig(m.GuildID)
-	if guildConfig == nil {
+	guildConfig, ok := mes.configManager.GuildConfig(m.GuildID)
+	if !ok {
 		return
 	}
+	if mes.store != nil {
+		// Linha do tempo de entradas/saídas (rejoins), independente do canal de log
+		if err := mes.store.RecordJoin(m.GuildID, m.User.ID, m.JoinedAt); err != nil {
+			log.Warn().Applicationf("Failed to record member join: guildID=%s, userID=%s, error=%v", m.GuildID, m.User.ID, err)
+		}
+	}
+	mes.greetJoin(guildConfig, m.User)
+	mes.scheduleAutorole(guildConfig, m.User.ID)
 
 	// Prefer dedicated entry/leave channel; fallback to general user log channel
 	logChannelID := guildConfig.UserEntryLeaveChannelID
@@ -126,15 +163,13 @@ func (mes *MemberEventService) handleGuildMemberAdd(s *discordgo.Session, m *dis
 	// Calcular há quanto tempo a conta existe
 	accountAge := mes.calculateAccountAge(m.User.ID)
 
-	// Persistir também em SQLite (melhor esforço)
-	if mes.store != nil {
-		if member, err := mes.session.GuildMember(m.GuildID, m.User.ID); err == nil && !member.JoinedAt.IsZero() {
-			_ = mes.store.UpsertMemberJoin(m.GuildID, m.User.ID, member.JoinedAt)
-		}
+	// Horário de entrada do membro (state ou REST), persistido em SQLite (melhor esforço) e em memória
+	member, err := discord.ClientFor(mes.session).FetchMember(context.Background(), m.GuildID, m.User.ID)
+	if mes.store != nil && err == nil && !member.JoinedAt.IsZero() {
+		_ = mes.store.UpsertMemberJoin(m.GuildID, m.User.ID, member.JoinedAt)
 	}
 
-	// NEW: Registrar horário de entrada do membro (preciso) em memória
-	if member, err := mes.session.GuildMember(m.GuildID, m.User.ID); err == nil && !member.JoinedAt.IsZero() {
+	if err == nil && !member.JoinedAt.IsZero() {
 		mes.joinMu.Lock()
 		if mes.joinTimes == nil {
 			mes.joinTimes = make(map[string]time.Time)
@@ -165,10 +200,16 @@ func (mes *MemberEventService) handleGuildMemberRemove(s *discordgo.Session, m *
 	}
 
 	mes.markEvent()
-	guildConfig := mes.configManager.GuildConfig(m.GuildID)
-	if guildConfig == nil {
+	guildConfig, ok := mes.configManager.GuildConfig(m.GuildID)
+	if !ok {
 		return
 	}
+	if mes.store != nil {
+		if err := mes.store.RecordLeave(m.GuildID, m.User.ID, time.Now()); err != nil {
+			log.Warn().Applicationf("Failed to record member leave: guildID=%s, userID=%s, error=%v", m.GuildID, m.User.ID, err)
+		}
+	}
+	mes.greetLeave(guildConfig, m.User)
 
 	// Prefer dedicated entry/leave channel; fallback to general user log channel
 	logChannelID := guildConfig.UserEntryLeaveChannelID
@@ -183,7 +224,6 @@ func (mes *MemberEventService) handleGuildMemberRemove(s *discordgo.Session, m *
 	// Calcular há quanto tempo estava no servidor
 	var serverTime time.Duration
 	var t time.Time
-	var ok bool
 	mes.joinMu.RLock()
 	t, ok = mes.joinTimes[m.GuildID+":"+m.User.ID]
 	mes.joinMu.RUnlock()
@@ -250,7 +290,8 @@ func (mes *MemberEventService) calculateServerTime(guildID, userID string) time.
 }
 
 // cleanupLoop periodically removes old entries from joinTimes map
-func (mes *MemberEventService) cleanupLoop() {
+// (stop is passed by value because Stop clears mes.cleanupStop after closing it).
+func (mes *MemberEventService) cleanupLoop(stop <-chan struct{}) {
 	ticker := time.NewTicker(10 * time.Minute)
 	defer ticker.Stop()
 
@@ -258,7 +299,7 @@ func (mes *MemberEventService) cleanupLoop() {
 		select {
 		case <-ticker.C:
 			mes.cleanupJoinTimes()
-		case <-mes.cleanupStop:
+		case <-stop:
 			return
 		}
 	}
@@ -309,7 +350,7 @@ func (mes *MemberEventService) getBotTimeOnServer(guildID string) time.Duration
 		return 0
 	}
 	botID := mes.session.State.User.ID
-	member, err := mes.session.GuildMember(guildID, botID)
+	member, err := discord.ClientFor(mes.session).FetchMember(context.Background(), guildID, botID)
 	if err != nil || member == nil || member.JoinedAt.IsZero() {
 		return 0
 	}
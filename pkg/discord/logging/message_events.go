@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	discordsession "github.com/small-frappuccino/discordcore/pkg/discord/session"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 	"github.com/small-frappuccino/discordcore/pkg/storage"
@@ -20,6 +21,13 @@ type CachedMessage struct {
 	ChannelID string
 	GuildID   string
 	Timestamp time.Time
+
+	Truncated      bool
+	OriginalLength int
+
+	// Metadados de anexos e embeds guardados com a mensagem
+	Attachments []storage.MessageAttachment
+	Embeds      []storage.MessageEmbedSummary
 }
 
 // MessageEventService gerencia eventos de mensagens (deletar/editar)
@@ -28,6 +36,7 @@ type MessageEventService struct {
 	configManager *files.ConfigManager
 	notifier      *NotificationSender
 	adapters      *task.NotificationAdapters
+	handlers      *discordsession.HandlerSet
 	store         *storage.Store
 	isRunning     bool
 }
@@ -56,9 +65,10 @@ func (mes *MessageEventService) Start() error {
 		_ = mes.store.CleanupExpiredMessages()
 	}
 
-	mes.session.AddHandler(mes.handleMessageCreate)
-	mes.session.AddHandler(mes.handleMessageUpdate)
-	mes.session.AddHandler(mes.handleMessageDelete)
+	mes.handlers = discordsession.NewHandlerSet(mes.session)
+	mes.handlers.Add(mes.handleMessageCreate)
+	mes.handlers.Add(mes.handleMessageUpdate)
+	mes.handlers.Add(mes.handleMessageDelete)
 
 	// TTL cache handles cleanup internally
 
@@ -73,10 +83,22 @@ func (mes *MessageEventService) Stop() error {
 	}
 	mes.isRunning = false
 
+	if mes.handlers != nil {
+		mes.handlers.RemoveAll()
+	}
+
 	log.Info().Applicationf("Message event service stopped")
 	return nil
 }
 
+// ReinstallHandlers re-registra os handlers que não estão mais instalados; usado após
+// reconexões do gateway (sem efeito quando o discordgo os manteve).
+func (mes *MessageEventService) ReinstallHandlers() {
+	if mes.isRunning && mes.handlers != nil {
+		mes.handlers.Reinstall()
+	}
+}
+
 // IsRunning retorna se o serviço está rodando
 func (mes *MessageEventService) IsRunning() bool {
 	return mes.isRunning
@@ -96,6 +118,11 @@ func (mes *MessageEventService) handleMessageCreate(s *discordgo.Session, m *dis
 		log.Info().Applicationf("MessageCreate: ignoring bot message: channelID=%s, userID=%s", m.ChannelID, m.Author.ID)
 		return
 	}
+	if m.Content == "" && !discordsession.HasMessageContent(s) {
+		// Sem o intent MESSAGE_CONTENT, texto, anexos e embeds chegam vazios: nada útil para guardar
+		return
+	}
+	rawContent := m.Content
 	if m.Content == "" {
 		// Build a concise summary for non-text messages so we can still cache deletes/edits
 		extra := ""
@@ -135,17 +162,23 @@ func (mes *MessageEventService) handleMessageCreate(s *discordgo.Session, m *dis
 	}
 
 	// Verificar se o guild está configurado
-	guildConfig := mes.configManager.GuildConfig(guildID)
-	if guildConfig == nil {
+	guildConfig, ok := mes.configManager.GuildConfig(guildID)
+	if !ok {
 		log.Info().Applicationf("MessageCreate: no guild config; skipping cache: guildID=%s", guildID)
 		return
 	}
 
+	// Respeitar allowlist/denylist de canais e categorias
+	if !mes.shouldLogChannel(&guildConfig, m.ChannelID) {
+		log.Info().Applicationf("MessageCreate: channel excluded from message logging; skipping cache: guildID=%s, channelID=%s", guildID, m.ChannelID)
+		return
+	}
+
 	mes.markEvent()
 
 	// Persistir em SQLite (write-through; melhor esforço)
 	if mes.store != nil && m.Author != nil {
-		_ = mes.store.UpsertMessage(storage.MessageRecord{
+		rec := storage.MessageRecord{
 			GuildID:        guildID,
 			MessageID:      m.ID,
 			ChannelID:      m.ChannelID,
@@ -156,7 +189,12 @@ func (mes *MessageEventService) handleMessageCreate(s *discordgo.Session, m *dis
 			CachedAt:       time.Now(),
 			ExpiresAt:      time.Now().Add(24 * time.Hour),
 			HasExpiry:      true,
-		})
+		}
+		applyMessagePrivacy(&guildConfig, &rec, rawContent, len(m.Attachments))
+		_ = mes.store.UpsertMessage(rec)
+		if len(m.Attachments) > 0 || len(m.Embeds) > 0 {
+			mes.saveMessageMedia(&guildConfig, guildID, m.ID, m.Attachments, m.Embeds)
+		}
 	}
 
 	log.Info().Applicationf("Message cached for monitoring: guildID=%s, channelID=%s, messageID=%s, userID=%s", guildID, m.ChannelID, m.ID, m.Author.ID)
@@ -177,20 +215,33 @@ func (mes *MessageEventService) handleMessageUpdate(s *discordgo.Session, m *dis
 		return
 	}
 	log.Info().Applicationf("MessageUpdate received: messageID=%s, userID=%s, guildID=%s, channelID=%s", m.ID, m.Author.ID, m.GuildID, m.ChannelID)
+	if m.Content == "" && !discordsession.HasMessageContent(s) {
+		// Sem o intent o conteúdo novo é invisível (também via REST); não registrar uma edição para vazio
+		log.Info().Applicationf("MessageUpdate: content unavailable without the MESSAGE_CONTENT intent; skipping: messageID=%s", m.ID)
+		return
+	}
 
 	mes.markEvent()
 
 	// Consultar persistência (SQLite) para obter a mensagem original
 	var cached *CachedMessage
+	attachmentCount := 0
+	contentOmitted := false
 	if mes.store != nil && m.GuildID != "" {
 		if rec, err := mes.store.GetMessage(m.GuildID, m.ID); err == nil && rec != nil {
+			attachmentCount = rec.AttachmentCount
+			contentOmitted = rec.ContentOmitted
 			cached = &CachedMessage{
-				ID:        rec.MessageID,
-				Content:   rec.Content,
-				Author:    &discordgo.User{ID: rec.AuthorID, Username: rec.AuthorUsername, Avatar: rec.AuthorAvatar},
-				ChannelID: rec.ChannelID,
-				GuildID:   rec.GuildID,
-				Timestamp: rec.CachedAt,
+				ID:             rec.MessageID,
+				Content:        storedContent(rec),
+				Author:         &discordgo.User{ID: rec.AuthorID, Username: rec.AuthorUsername, Avatar: rec.AuthorAvatar},
+				ChannelID:      rec.ChannelID,
+				GuildID:        rec.GuildID,
+				Timestamp:      rec.CachedAt,
+				Truncated:      rec.Truncated,
+				OriginalLength: rec.OriginalLength,
+				Attachments:    rec.Attachments,
+				Embeds:         rec.Embeds,
 			}
 		}
 	}
@@ -200,6 +251,21 @@ func (mes *MessageEventService) handleMessageUpdate(s *discordgo.Session, m *dis
 		return
 	}
 
+	// Anexos removidos e embeds gerados depois (ex.: preview de links) chegam como update, às vezes sem mudar o texto
+	if m.Attachments != nil || m.Embeds != nil {
+		if gcfg, ok := mes.configManager.GuildConfig(cached.GuildID); ok {
+			mes.saveMessageMedia(&gcfg, cached.GuildID, m.ID, m.Attachments, m.Embeds)
+		}
+	}
+
+	// Em modo privacidade o original não foi gravado (cached.Content é o placeholder): não há como
+	// saber se o texto mudou, e comparar com o placeholder registraria toda atualização (previews,
+	// pins) como edição
+	if contentOmitted {
+		log.Info().Applicationf("MessageUpdate: original content not stored (privacy mode); skipping notification: guildID=%s, messageID=%s", cached.GuildID, m.ID)
+		return
+	}
+
 	// Ensure latest content; MessageUpdate may omit content. Also enrich empty content with context.
 	if m.Content == "" {
 		if msg, err := s.ChannelMessage(m.ChannelID, m.ID); err == nil && msg != nil {
@@ -208,19 +274,24 @@ func (mes *MessageEventService) handleMessageUpdate(s *discordgo.Session, m *dis
 			m.Content = mes.summarizeMessageContent(msg, m.Content)
 		}
 	}
-	// Verificar se realmente mudou o conteúdo (compare effective strings)
-	if cached.Content == m.Content {
+	// Verificar se realmente mudou o conteúdo (compare effective strings).
+	// Se o original foi truncado pelo store, comparar com o novo conteúdo truncado da mesma forma.
+	newContent := m.Content
+	if cached.Truncated && mes.store != nil {
+		newContent, _, _ = mes.store.ClampContent(m.Content)
+	}
+	if cached.Content == newContent {
 		log.Info().Applicationf("MessageUpdate: content unchanged; skipping notification: guildID=%s, channelID=%s, messageID=%s, userID=%s", cached.GuildID, cached.ChannelID, m.ID, cached.Author.ID)
 		return
 	}
 
-	guildConfig := mes.configManager.GuildConfig(cached.GuildID)
-	if guildConfig == nil {
+	guildConfig, ok := mes.configManager.GuildConfig(cached.GuildID)
+	if !ok {
 		log.Info().Applicationf("MessageUpdate: no guild config; skipping notification: guildID=%s, messageID=%s", cached.GuildID, m.ID)
 		return
 	}
 
-	logChannelID := mes.fallbackMessageLogChannel(guildConfig)
+	logChannelID := mes.fallbackMessageLogChannel(&guildConfig)
 	if logChannelID == "" {
 		log.Info().Applicationf("Message log channel not configured for guild; edit notification not sent: guildID=%s, messageID=%s", cached.GuildID, m.ID)
 		return
@@ -231,12 +302,16 @@ func (mes *MessageEventService) handleMessageUpdate(s *discordgo.Session, m *dis
 	// Enviar notificação de edição
 	if mes.adapters != nil {
 		tCached := &task.CachedMessage{
-			ID:        cached.ID,
-			Content:   cached.Content,
-			Author:    cached.Author,
-			ChannelID: cached.ChannelID,
-			GuildID:   cached.GuildID,
-			Timestamp: cached.Timestamp,
+			ID:             cached.ID,
+			Content:        cached.Content,
+			Author:         cached.Author,
+			ChannelID:      cached.ChannelID,
+			GuildID:        cached.GuildID,
+			Timestamp:      cached.Timestamp,
+			Truncated:      cached.Truncated,
+			OriginalLength: cached.OriginalLength,
+			Attachments:    cached.Attachments,
+			Embeds:         cached.Embeds,
 		}
 		if err := mes.adapters.EnqueueMessageEdit(logChannelID, tCached, m); err != nil {
 			log.Error().Errorf("Failed to send message edit notification: guildID=%s, messageID=%s, channelID=%s, error=%v", cached.GuildID, m.ID, logChannelID, err)
@@ -245,12 +320,16 @@ func (mes *MessageEventService) handleMessageUpdate(s *discordgo.Session, m *dis
 		}
 	} else {
 		tCached := &task.CachedMessage{
-			ID:        cached.ID,
-			Content:   cached.Content,
-			Author:    cached.Author,
-			ChannelID: cached.ChannelID,
-			GuildID:   cached.GuildID,
-			Timestamp: cached.Timestamp,
+			ID:             cached.ID,
+			Content:        cached.Content,
+			Author:         cached.Author,
+			ChannelID:      cached.ChannelID,
+			GuildID:        cached.GuildID,
+			Timestamp:      cached.Timestamp,
+			Truncated:      cached.Truncated,
+			OriginalLength: cached.OriginalLength,
+			Attachments:    cached.Attachments,
+			Embeds:         cached.Embeds,
 		}
 		if err := mes.notifier.SendMessageEditNotification(logChannelID, tCached, m); err != nil {
 			log.Error().Errorf("Failed to send message edit notification: guildID=%s, messageID=%s, channelID=%s, error=%v", cached.GuildID, m.ID, logChannelID, err)
@@ -269,7 +348,10 @@ func (mes *MessageEventService) handleMessageUpdate(s *discordgo.Session, m *dis
 		Timestamp: cached.Timestamp,
 	}
 	if mes.store != nil && updated.Author != nil {
-		_ = mes.store.UpsertMessage(storage.MessageRecord{
+		if m.Attachments != nil {
+			attachmentCount = len(m.Attachments)
+		}
+		rec := storage.MessageRecord{
 			GuildID:        updated.GuildID,
 			MessageID:      updated.ID,
 			ChannelID:      updated.ChannelID,
@@ -280,7 +362,9 @@ func (mes *MessageEventService) handleMessageUpdate(s *discordgo.Session, m *dis
 			CachedAt:       time.Now(),
 			ExpiresAt:      time.Now().Add(24 * time.Hour),
 			HasExpiry:      true,
-		})
+		}
+		applyMessagePrivacy(&guildConfig, &rec, updated.Content, attachmentCount)
+		_ = mes.store.UpsertMessage(rec)
 	}
 	log.Info().Applicationf("MessageUpdate: store updated with new content: guildID=%s, channelID=%s, messageID=%s", cached.GuildID, cached.ChannelID, m.ID)
 }
@@ -298,12 +382,16 @@ func (mes *MessageEventService) handleMessageDelete(s *discordgo.Session, m *dis
 	if mes.store != nil && m.GuildID != "" {
 		if rec, err := mes.store.GetMessage(m.GuildID, m.ID); err == nil && rec != nil {
 			cached = &CachedMessage{
-				ID:        rec.MessageID,
-				Content:   rec.Content,
-				Author:    &discordgo.User{ID: rec.AuthorID, Username: rec.AuthorUsername, Avatar: rec.AuthorAvatar},
-				ChannelID: rec.ChannelID,
-				GuildID:   rec.GuildID,
-				Timestamp: rec.CachedAt,
+				ID:             rec.MessageID,
+				Content:        storedContent(rec),
+				Author:         &discordgo.User{ID: rec.AuthorID, Username: rec.AuthorUsername, Avatar: rec.AuthorAvatar},
+				ChannelID:      rec.ChannelID,
+				GuildID:        rec.GuildID,
+				Timestamp:      rec.CachedAt,
+				Truncated:      rec.Truncated,
+				OriginalLength: rec.OriginalLength,
+				Attachments:    rec.Attachments,
+				Embeds:         rec.Embeds,
 			}
 		}
 	}
@@ -322,8 +410,8 @@ func (mes *MessageEventService) handleMessageDelete(s *discordgo.Session, m *dis
 		return
 	}
 
-	guildConfig := mes.configManager.GuildConfig(cached.GuildID)
-	if guildConfig == nil {
+	guildConfig, ok := mes.configManager.GuildConfig(cached.GuildID)
+	if !ok {
 		// no-op: cache removed; using SQLite only
 		if mes.store != nil {
 			_ = mes.store.DeleteMessage(m.GuildID, m.ID)
@@ -331,7 +419,7 @@ func (mes *MessageEventService) handleMessageDelete(s *discordgo.Session, m *dis
 		return
 	}
 
-	logChannelID := mes.fallbackMessageLogChannel(guildConfig)
+	logChannelID := mes.fallbackMessageLogChannel(&guildConfig)
 	if logChannelID == "" {
 		log.Info().Applicationf("Message log channel not configured for guild; delete notification not sent: guildID=%s, messageID=%s", cached.GuildID, m.ID)
 		// no-op: cache removed; using SQLite only
@@ -349,12 +437,16 @@ func (mes *MessageEventService) handleMessageDelete(s *discordgo.Session, m *dis
 	// Enviar notificação de deleção
 	if mes.adapters != nil {
 		tCached := &task.CachedMessage{
-			ID:        cached.ID,
-			Content:   cached.Content,
-			Author:    cached.Author,
-			ChannelID: cached.ChannelID,
-			GuildID:   cached.GuildID,
-			Timestamp: cached.Timestamp,
+			ID:             cached.ID,
+			Content:        cached.Content,
+			Author:         cached.Author,
+			ChannelID:      cached.ChannelID,
+			GuildID:        cached.GuildID,
+			Timestamp:      cached.Timestamp,
+			Truncated:      cached.Truncated,
+			OriginalLength: cached.OriginalLength,
+			Attachments:    cached.Attachments,
+			Embeds:         cached.Embeds,
 		}
 		if err := mes.adapters.EnqueueMessageDelete(logChannelID, tCached, deletedBy); err != nil {
 			log.Error().Errorf("Failed to send message delete notification: guildID=%s, messageID=%s, channelID=%s, error=%v", cached.GuildID, m.ID, logChannelID, err)
@@ -363,12 +455,16 @@ func (mes *MessageEventService) handleMessageDelete(s *discordgo.Session, m *dis
 		}
 	} else {
 		tCached := &task.CachedMessage{
-			ID:        cached.ID,
-			Content:   cached.Content,
-			Author:    cached.Author,
-			ChannelID: cached.ChannelID,
-			GuildID:   cached.GuildID,
-			Timestamp: cached.Timestamp,
+			ID:             cached.ID,
+			Content:        cached.Content,
+			Author:         cached.Author,
+			ChannelID:      cached.ChannelID,
+			GuildID:        cached.GuildID,
+			Timestamp:      cached.Timestamp,
+			Truncated:      cached.Truncated,
+			OriginalLength: cached.OriginalLength,
+			Attachments:    cached.Attachments,
+			Embeds:         cached.Embeds,
 		}
 		if err := mes.notifier.SendMessageDeleteNotification(logChannelID, tCached, deletedBy); err != nil {
 			log.Error().Errorf("Failed to send message delete notification: guildID=%s, messageID=%s, channelID=%s, error=%v", cached.GuildID, m.ID, logChannelID, err)
@@ -404,6 +500,57 @@ func (mes *MessageEventService) SetAdapters(adapters *task.NotificationAdapters)
 	mes.adapters = adapters
 }
 
+// warnIfMessageContentMissing loga um erro quando alguma guild tem message logging configurado
+// mas a sessão está sem o intent MESSAGE_CONTENT (o conteúdo não será registrado).
+func (ms *MonitoringService) warnIfMessageContentMissing() {
+	if discordsession.HasMessageContent(ms.session) {
+		return
+	}
+	var guilds []string
+	for _, gcfg := range ms.configManager.Guilds() {
+		if ms.messageEventService.fallbackMessageLogChannel(&gcfg) != "" {
+			guilds = append(guilds, gcfg.GuildID)
+		}
+	}
+	if len(guilds) == 0 {
+		return
+	}
+	log.Error().Errorf("❌ Message logging is configured for %d guild(s) (%s) but the session lacks the MESSAGE_CONTENT intent: edits and deletes will not be logged with content. To fix it, %s, then restart the bot (and unset %s if set).",
+		len(guilds), strings.Join(guilds, ", "), discordsession.MessageContentHint, discordsession.MessageContentEnv)
+}
+
+// shouldLogChannel resolve a hierarquia do canal (thread -> canal -> categoria) via state
+// e consulta os filtros de logging de mensagens da guild.
+func (mes *MessageEventService) shouldLogChannel(g *files.GuildConfig, channelID string) bool {
+	if g == nil {
+		return true
+	}
+	if len(g.MessageLogAllowedChannels) == 0 && len(g.MessageLogIgnoredChannels) == 0 && len(g.MessageLogIgnoredCategories) == 0 {
+		return true
+	}
+
+	ids := []string{channelID}
+	categoryID := ""
+	if mes.session != nil && mes.session.State != nil {
+		current := channelID
+		// No máximo dois saltos: thread -> canal pai -> categoria
+		for i := 0; i < 2 && current != ""; i++ {
+			ch, err := mes.session.State.Channel(current)
+			if err != nil || ch == nil || ch.ParentID == "" {
+				break
+			}
+			if ch.IsThread() {
+				ids = append(ids, ch.ParentID)
+				current = ch.ParentID
+				continue
+			}
+			categoryID = ch.ParentID
+			break
+		}
+	}
+	return g.ShouldLogMessageChannel(ids, categoryID)
+}
+
 // fallbackMessageLogChannel chooses the best available channel for message logs.
 func (mes *MessageEventService) fallbackMessageLogChannel(g *files.GuildConfig) string {
 	if g == nil {
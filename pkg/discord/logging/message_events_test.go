@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// sendCounter answers every REST call with an empty message and counts the messages posted
+// to each channel.
+type sendCounter struct {
+	mu    sync.Mutex
+	posts map[string]int
+}
+
+func (c *sendCounter) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/messages") {
+		c.mu.Lock()
+		c.posts[r.URL.Path]++
+		c.mu.Unlock()
+	}
+	body := `{"id":"900000000000000001"}`
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{"Content-Type": {"application/json"}}, Request: r}, nil
+}
+
+func (c *sendCounter) count(channelID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.posts["/api/v"+discordgo.APIVersion+"/channels/"+channelID+"/messages"]
+}
+
+func TestRepeatedIdenticalEditsNotifyOnce(t *testing.T) {
+	store := storage.NewStoreWithOptions(filepath.Join(t.TempDir(), "messages.db"), storage.Options{})
+	if err := store.Init(); err != nil {
+		t.Fatalf("init store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	config := files.NewConfigManagerWithPath(filepath.Join(t.TempDir(), "settings.json"))
+	if err := config.AddGuildConfig(files.GuildConfig{GuildID: "g1", MessageLogChannelID: "log"}); err != nil {
+		t.Fatalf("add guild config: %v", err)
+	}
+
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	counter := &sendCounter{posts: map[string]int{}}
+	s.Client = &http.Client{Transport: counter}
+	s.Identify.Intents = discordgo.IntentMessageContent
+
+	if err := store.UpsertMessage(storage.MessageRecord{
+		GuildID: "g1", MessageID: "m1", ChannelID: "c1", AuthorID: "u1", AuthorUsername: "user",
+		Content: "original", CachedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("store original: %v", err)
+	}
+
+	mes := NewMessageEventService(s, config, NewNotificationSender(s), store)
+	edit := func(content string) {
+		mes.handleMessageUpdate(s, &discordgo.MessageUpdate{Message: &discordgo.Message{
+			ID: "m1", ChannelID: "c1", GuildID: "g1", Content: content,
+			Author: &discordgo.User{ID: "u1", Username: "user"},
+		}})
+	}
+
+	// Discord may resend an update with the same text (e.g. when an embed unfurls)
+	for range 3 {
+		edit("edited")
+	}
+	if n := counter.count("log"); n != 1 {
+		t.Fatalf("sent %d edit notifications for one change, want 1", n)
+	}
+	rec, err := store.GetMessage("g1", "m1")
+	if err != nil || rec == nil {
+		t.Fatalf("get message: %v", err)
+	}
+	if rec.Content != "edited" {
+		t.Errorf("stored content = %q, want edited", rec.Content)
+	}
+
+	edit("edited again")
+	if n := counter.count("log"); n != 2 {
+		t.Errorf("sent %d notifications after a real second edit, want 2", n)
+	}
+}
@@ -0,0 +1,175 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+const (
+	attachmentDownloadTimeout = 30 * time.Second
+	embedSummaryMaxRunes      = 300
+)
+
+// attachmentHTTPClient baixa os anexos arquivados (CDN do Discord).
+var attachmentHTTPClient = &http.Client{Timeout: attachmentDownloadTimeout}
+
+// mediaFromMessage converte anexos e embeds do evento para o formato do store. Slices nil do
+// evento (campo ausente em updates parciais) continuam nil, para que o store não os apague.
+func mediaFromMessage(attachments []*discordgo.MessageAttachment, embeds []*discordgo.MessageEmbed) ([]storage.MessageAttachment, []storage.MessageEmbedSummary) {
+	var outAttachments []storage.MessageAttachment
+	if attachments != nil {
+		outAttachments = make([]storage.MessageAttachment, 0, len(attachments))
+		for _, a := range attachments {
+			if a == nil {
+				continue
+			}
+			outAttachments = append(outAttachments, storage.MessageAttachment{
+				ID:          a.ID,
+				Filename:    a.Filename,
+				URL:         a.URL,
+				ContentType: a.ContentType,
+				Size:        a.Size,
+			})
+		}
+	}
+	var outEmbeds []storage.MessageEmbedSummary
+	if embeds != nil {
+		outEmbeds = make([]storage.MessageEmbedSummary, 0, len(embeds))
+		for _, e := range embeds {
+			if e == nil {
+				continue
+			}
+			outEmbeds = append(outEmbeds, storage.MessageEmbedSummary{
+				Type:        string(e.Type),
+				Title:       truncateString(e.Title, embedSummaryMaxRunes),
+				Description: truncateString(e.Description, embedSummaryMaxRunes),
+				URL:         e.URL,
+			})
+		}
+	}
+	return outAttachments, outEmbeds
+}
+
+// saveMessageMedia grava os metadados de anexos/embeds e, se attachment_archive estiver
+// habilitado, arquiva os bytes em segundo plano (melhor esforço). Nada é gravado em modo privacidade.
+func (mes *MessageEventService) saveMessageMedia(gcfg *files.GuildConfig, guildID, messageID string, attachments []*discordgo.MessageAttachment, embeds []*discordgo.MessageEmbed) {
+	if mes.store == nil || gcfg.PrivacyModeEnabled() {
+		return
+	}
+	storedAttachments, storedEmbeds := mediaFromMessage(attachments, embeds)
+	if storedAttachments == nil && storedEmbeds == nil {
+		return
+	}
+	if err := mes.store.SaveMessageMedia(guildID, messageID, storedAttachments, storedEmbeds); err != nil {
+		log.Warn().Applicationf("Failed to store message media: guildID=%s, messageID=%s, error=%v", guildID, messageID, err)
+		return
+	}
+	if gcfg == nil || gcfg.AttachmentArchive == nil || !gcfg.AttachmentArchive.Enabled || len(storedAttachments) == 0 {
+		return
+	}
+	perAttachment, perMessage := gcfg.AttachmentArchive.Limits()
+	go mes.archiveAttachments(guildID, messageID, storedAttachments, perAttachment, perMessage)
+}
+
+// archiveAttachments baixa e grava os bytes dos anexos dentro dos limites; anexos acima do
+// limite ficam só com os metadados.
+func (mes *MessageEventService) archiveAttachments(guildID, messageID string, attachments []storage.MessageAttachment, perAttachment, perMessage int) {
+	total := 0
+	for _, a := range attachments {
+		if a.URL == "" || a.Size > perAttachment || total+a.Size > perMessage {
+			log.Info().Applicationf("Attachment not archived (over size limit): guildID=%s, messageID=%s, attachment=%s, size=%d", guildID, messageID, a.Filename, a.Size)
+			continue
+		}
+		data, err := downloadAttachment(a.URL, perAttachment)
+		if err != nil {
+			log.Warn().Applicationf("Failed to archive attachment: guildID=%s, messageID=%s, attachment=%s, error=%v", guildID, messageID, a.Filename, err)
+			continue
+		}
+		total += len(data)
+		if err := mes.store.ArchiveAttachmentData(guildID, messageID, a.ID, data); err != nil {
+			log.Warn().Applicationf("Failed to store archived attachment: guildID=%s, messageID=%s, attachment=%s, error=%v", guildID, messageID, a.Filename, err)
+		}
+	}
+}
+
+// downloadAttachment baixa até limit bytes; um arquivo maior é rejeitado em vez de truncado.
+func downloadAttachment(url string, limit int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), attachmentDownloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := attachmentHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > limit {
+		return nil, fmt.Errorf("larger than %d bytes", limit)
+	}
+	return data, nil
+}
+
+// mediaSummary descreve anexos e embeds guardados para os embeds de notificação.
+func mediaSummary(attachments []storage.MessageAttachment, embeds []storage.MessageEmbedSummary) string {
+	var lines []string
+	for _, a := range attachments {
+		line := fmt.Sprintf("📎 [%s](%s)", a.Filename, a.URL)
+		details := make([]string, 0, 3)
+		if a.ContentType != "" {
+			details = append(details, a.ContentType)
+		}
+		if a.Size > 0 {
+			details = append(details, formatBytes(a.Size))
+		}
+		if a.Archived {
+			details = append(details, "archived")
+		}
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	for _, e := range embeds {
+		label := e.Title
+		if label == "" {
+			label = e.URL
+		}
+		if label == "" {
+			label = truncateString(e.Description, 80)
+		}
+		if label == "" {
+			label = "(no title)"
+		}
+		lines = append(lines, fmt.Sprintf("🔗 %s embed: %s", e.Type, label))
+	}
+	return truncateString(strings.Join(lines, "\n"), 1000)
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
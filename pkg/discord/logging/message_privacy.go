@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// privacyOmittedContent substitui o conteúdo original nas notificações de edição/remoção de
+// mensagens gravadas em modo privacidade.
+const privacyOmittedContent = "*(content not stored: privacy mode)*"
+
+// applyMessagePrivacy prepara rec para gravação segundo message_privacy da guild. Fora do modo
+// privacidade só preenche AttachmentCount; nele, descarta o conteúdo e os metadados que não estão
+// em message_privacy.metadata. rawContent é o texto original da mensagem (antes de qualquer resumo).
+func applyMessagePrivacy(gcfg *files.GuildConfig, rec *storage.MessageRecord, rawContent string, attachments int) {
+	rec.AttachmentCount = attachments
+	if !gcfg.PrivacyModeEnabled() {
+		return
+	}
+	p := gcfg.MessagePrivacy
+	rec.Content, rec.ContentOmitted, rec.OriginalLength = "", true, 0
+	rec.AuthorUsername, rec.AuthorAvatar = "", ""
+	if p.Retains(files.MessageMetadataLength) {
+		rec.OriginalLength = utf8.RuneCountInString(rawContent)
+	}
+	if !p.Retains(files.MessageMetadataAuthor) {
+		rec.AuthorID = ""
+	}
+	if !p.Retains(files.MessageMetadataChannel) {
+		rec.ChannelID = ""
+	}
+	if !p.Retains(files.MessageMetadataTimestamp) {
+		rec.CachedAt = rec.CachedAt.Truncate(time.Hour)
+	}
+	if !p.Retains(files.MessageMetadataAttachments) {
+		rec.AttachmentCount = 0
+	}
+}
+
+// storedContent retorna o conteúdo de um registro para as notificações.
+func storedContent(rec *storage.MessageRecord) string {
+	if rec.ContentOmitted {
+		return privacyOmittedContent
+	}
+	return rec.Content
+}
+
+// privacyContent retorna content, ou vazio se a guild está em modo privacidade (usado ao gravar
+// o conteúdo de mensagens em outras tabelas, como automod_actions).
+func (as *AutomodService) privacyContent(guildID, content string) string {
+	if as.configManager == nil {
+		return content
+	}
+	if gcfg, ok := as.configManager.GuildConfig(guildID); ok && gcfg.PrivacyModeEnabled() {
+		return ""
+	}
+	return content
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/discord"
 	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
+	discordsession "github.com/small-frappuccino/discordcore/pkg/discord/session"
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 	"github.com/small-frappuccino/discordcore/pkg/storage"
@@ -28,6 +33,8 @@ type UserWatcher struct {
 	store         *storage.Store
 	notifier      *NotificationSender
 	cache         *cache.UnifiedCache
+	avatarEvents  *avatarBroker
+	digests       avatarDigests // trocas aguardando o resumo (AvatarNotificationConfig digest)
 }
 
 func NewUserWatcher(session *discordgo.Session, configManager *files.ConfigManager, store *storage.Store, notifier *NotificationSender, unifiedCache *cache.UnifiedCache) *UserWatcher {
@@ -72,8 +79,18 @@ type MonitoringService struct {
 	rolesTTL          time.Duration
 	rolesCacheCleanup chan struct{}
 
-	// Event handler references for cleanup
-	eventHandlers []interface{}
+	// Member/online/bot counts per guild, fed by gateway events
+	guildStats *guildStatsCache
+
+	// Subscribers of detected avatar changes (SubscribeAvatarChanges)
+	avatarEvents *avatarBroker
+
+	// Event handlers tracked for cleanup and reinstallation after gateway reconnects
+	eventHandlers   *discordsession.HandlerSet
+	reconnectCancel func()
+
+	// Modo de instabilidade do Discord: pausa refreshes e varreduras enquanto degradado
+	outage *discordsession.OutageMonitor
 
 	// Metrics counters
 	apiAuditLogCalls     uint64
@@ -101,6 +118,7 @@ func NewMonitoringService(session *discordgo.Session, configManager *files.Confi
 		return nil, fmt.Errorf("store is nil")
 	}
 	n := NewNotificationSender(session)
+	n.SetConfigManager(configManager)
 	router := task.NewRouter(task.Defaults())
 	adapters := task.NewNotificationAdapters(router, session, configManager, nil, n)
 
@@ -126,8 +144,11 @@ func NewMonitoringService(session *discordgo.Session, configManager *files.Confi
 		rolesCache:          make(map[string]cachedRoles),
 		rolesTTL:            5 * time.Minute,
 		rolesCacheCleanup:   make(chan struct{}),
-		eventHandlers:       make([]interface{}, 0),
+		eventHandlers:       discordsession.NewHandlerSet(session),
+		guildStats:          newGuildStatsCache(),
+		avatarEvents:        newAvatarBroker(),
 	}
+	ms.userWatcher.avatarEvents = ms.avatarEvents
 	// Wire task adapters into sub-services
 	ms.memberEventService.SetAdapters(adapters)
 	ms.messageEventService.SetAdapters(adapters)
@@ -146,10 +167,15 @@ func (ms *MonitoringService) Start() error {
 	// Recreate stopChan and reset stopOnce for restart
 	ms.stopChan = make(chan struct{})
 	ms.stopOnce = sync.Once{}
+	ms.avatarEvents.reopen()
 
 	// Unified cache warmup is performed in app runner; skipping here to prevent duplicate work
 
 	ms.ensureGuildsListed()
+	ms.notifier.ValidateNotificationMentions()
+	ms.notifier.ValidateLogDestinations()
+	// Settle voice sessions left open by the previous run (uses the last heartbeat, so before startHeartbeat)
+	ms.settleVoiceSessionsOnStartup()
 	// Detect downtime and refresh avatars silently before wiring handlers (no notifications)
 	ms.handleStartupDowntimeAndMaybeRefresh()
 	ms.setupEventHandlers()
@@ -179,17 +205,17 @@ func (ms *MonitoringService) Start() error {
 
 	// Register a daily roles DB refresh task and run once at startup
 	ms.router.RegisterHandler("monitor.refresh_roles", func(ctx context.Context, _ any) error {
-		cfg := ms.configManager.Config()
-		if cfg == nil || len(cfg.Guilds) == 0 || ms.store == nil {
+		guilds := ms.configManager.Guilds()
+		if len(guilds) == 0 || ms.store == nil || ms.pausedByOutage("roles refresh") {
 			return nil
 		}
 		start := time.Now()
-		totalUpdates := 0
-		for _, gcfg := range cfg.Guilds {
+		var totalUpdates int64
+		ms.forEachGuildBounded(guilds, func(gcfg files.GuildConfig) {
 			members, err := ms.fetchAllGuildMembers(gcfg.GuildID)
 			if err != nil {
 				log.Error().Errorf("Error refreshing roles for guild %s: %v", gcfg.GuildID, err)
-				continue
+				return
 			}
 			for _, member := range members {
 				if len(member.Roles) == 0 {
@@ -200,10 +226,10 @@ func (ms *MonitoringService) Start() error {
 					continue
 				}
 				ms.cacheRolesSet(gcfg.GuildID, member.User.ID, member.Roles)
-				totalUpdates++
+				atomic.AddInt64(&totalUpdates, 1)
 			}
-		}
-		log.Info().Applicationf("✅ Roles DB refresh completed: %d members updated in %s", totalUpdates, time.Since(start).Round(time.Second))
+		})
+		log.Info().Applicationf("✅ Roles DB refresh completed: %d members updated in %s", atomic.LoadInt64(&totalUpdates), time.Since(start).Round(time.Second))
 		return nil
 	})
 
@@ -259,6 +285,9 @@ func (ms *MonitoringService) Stop() error {
 
 	// Remove event handlers
 	ms.removeEventHandlers()
+	ms.closeVoiceSessionsOnShutdown()
+	ms.avatarEvents.close()
+	ms.userWatcher.flushAvatarDigests()
 
 	// Parar novos serviços
 	if err := ms.memberEventService.Stop(); err != nil {
@@ -282,22 +311,15 @@ func (ms *MonitoringService) Stop() error {
 
 // initializeCache carrega os usuários atuais dos membros em todos os guilds configurados.
 func (ms *MonitoringService) initializeCache() {
-	cfg := ms.configManager.Config()
-	if cfg == nil || len(cfg.Guilds) == 0 {
+	guilds := ms.configManager.Guilds()
+	if len(guilds) == 0 {
 		log.Info().Applicationf("No guild configured for monitoring")
 		return
 	}
-	var wg sync.WaitGroup
 	ms.markEvent()
-	for _, gcfg := range cfg.Guilds {
-		gid := gcfg.GuildID
-		wg.Add(1)
-		go func(guildID string) {
-			defer wg.Done()
-			ms.initializeGuildCache(guildID)
-		}(gid)
-	}
-	wg.Wait()
+	ms.forEachGuildBounded(guilds, func(gcfg files.GuildConfig) {
+		ms.initializeGuildCache(gcfg.GuildID)
+	})
 	// No-op: avatars are persisted per change in the SQLite store
 }
 
@@ -344,12 +366,27 @@ func (ms *MonitoringService) initializeGuildCache(guildID string) {
 		log.Error().Errorf("Error getting members for guild %s: %v", guildID, err)
 		return
 	}
+	// Avatares em lote: uma transação por bloco em vez de uma por membro
+	avatars := make([]storage.AvatarUpsert, 0, len(members))
+	now := time.Now()
+	active := ms.activeMembers(guildID)
 	for _, member := range members {
+		if !active.includes(member.User.ID) {
+			continue
+		}
 		avatarHash := member.User.Avatar
 		if avatarHash == "" {
 			avatarHash = "default"
 		}
-		_, _, _ = ms.store.UpsertAvatar(guildID, member.User.ID, avatarHash, time.Now())
+		avatars = append(avatars, storage.AvatarUpsert{UserID: member.User.ID, Hash: avatarHash, UpdatedAt: now})
+	}
+	active.logScope(guildID, len(avatars), len(members))
+	if inserted, updated, err := ms.store.UpsertAvatarsBatch(guildID, avatars); err != nil {
+		log.Error().Errorf("Error refreshing avatars for guild %s: %v", guildID, err)
+	} else if inserted > 0 || updated > 0 {
+		log.Info().Applicationf("Avatar refresh for guild %s: %d new, %d changed", guildID, inserted, updated)
+	}
+	for _, member := range members {
 		// Persist roles snapshot for the member to enable efficient role diffing later
 		if ms.store != nil && len(member.Roles) > 0 {
 			_ = ms.store.UpsertMemberRoles(guildID, member.User.ID, member.Roles, time.Now())
@@ -367,30 +404,45 @@ func (ms *MonitoringService) initializeGuildCache(guildID string) {
 
 // setupEventHandlers registra handlers do Discord.
 func (ms *MonitoringService) setupEventHandlers() {
-	// Store handler references for later removal
-	ms.eventHandlers = append(ms.eventHandlers,
-		ms.session.AddHandler(ms.handlePresenceUpdate),
-		ms.session.AddHandler(ms.handleMemberUpdate),
-		ms.session.AddHandler(ms.handleUserUpdate),
-		ms.session.AddHandler(ms.handleGuildCreate),
-		ms.session.AddHandler(ms.handleGuildUpdate),
-	)
+	ms.eventHandlers.Add(ms.handlePresenceUpdate)
+	ms.eventHandlers.Add(ms.handleMemberUpdate)
+	ms.eventHandlers.Add(ms.handleUserUpdate)
+	ms.eventHandlers.Add(ms.handleGuildCreate)
+	ms.eventHandlers.Add(ms.handleGuildUpdate)
+	ms.eventHandlers.Add(ms.handleGuildRemoved)
+	ms.eventHandlers.Add(ms.handleMessageReactionAdd)
+	ms.eventHandlers.Add(ms.handleMessageReactionRemove)
+	ms.eventHandlers.Add(ms.handleVoiceStateUpdate)
+	ms.eventHandlers.Add(ms.handleEmojiUsage)
+	ms.eventHandlers.Add(ms.handleGuildStatsCreate)
+	ms.eventHandlers.Add(ms.handleGuildStatsDelete)
+	ms.eventHandlers.Add(ms.handleGuildStatsMemberAdd)
+	ms.eventHandlers.Add(ms.handleGuildStatsMemberRemove)
+	ms.eventHandlers.Add(ms.handleGuildStatsPresence)
+	ms.warnIfReactionIntentMissing()
+	ms.warnIfVoiceIntentMissing()
+	ms.warnIfMessageContentMissing()
+
+	// Após reconexões do gateway, reinstalar handlers (sem efeito se já instalados) dos serviços filhos também
+	ms.reconnectCancel = discordsession.OnReconnect(ms.session, ms.handleReconnect)
 }
 
-// removeEventHandlers removes all registered event handlers
-// Note: discordgo returns an unsubscribe function from AddHandler; we capture those when registering and call them here
-// Handlers are explicitly removed; any remaining handlers will be dropped when the session is closed on shutdown
+// handleReconnect reinstala os handlers de todos os sub-serviços após uma reconexão.
+func (ms *MonitoringService) handleReconnect() {
+	ms.eventHandlers.Reinstall()
+	ms.memberEventService.ReinstallHandlers()
+	ms.messageEventService.ReinstallHandlers()
+	ms.markEvent()
+	log.Info().Applicationf("Monitoring handlers reinstalled after reconnect")
+}
+
+// removeEventHandlers removes all registered event handlers (and the reconnect hook).
 func (ms *MonitoringService) removeEventHandlers() {
-	// Call unsubscriber functions returned by AddHandler to deregister callbacks
-	for _, h := range ms.eventHandlers {
-		if h == nil {
-			continue
-		}
-		if fn, ok := h.(func()); ok {
-			fn()
-		}
+	if ms.reconnectCancel != nil {
+		ms.reconnectCancel()
+		ms.reconnectCancel = nil
 	}
-	ms.eventHandlers = nil
+	ms.eventHandlers.RemoveAll()
 }
 
 // ensureGuildsListed adiciona entradas mínimas de guild no discordcore.json
@@ -404,7 +456,7 @@ func (ms *MonitoringService) ensureGuildsListed() {
 		if g == nil || g.ID == "" {
 			continue
 		}
-		if ms.configManager.GuildConfig(g.ID) == nil {
+		if _, ok := ms.configManager.GuildConfig(g.ID); !ok {
 			if err := ms.configManager.AddGuildConfig(files.GuildConfig{GuildID: g.ID}); err != nil {
 				log.Error().Errorf("Error adding minimal guild entry for guild %s: %v", g.ID, err)
 				continue
@@ -424,7 +476,12 @@ func (ms *MonitoringService) handleGuildCreate(s *discordgo.Session, e *discordg
 		return
 	}
 
-	if ms.configManager.GuildConfig(guildID) == nil {
+	if ms.reactivateIfInactive(guildID) {
+		ms.initializeGuildCache(guildID)
+		return
+	}
+
+	if _, ok := ms.configManager.GuildConfig(guildID); !ok {
 		// Guild nova: adicionar no config e inicializar cache
 		if err := ms.configManager.RegisterGuild(s, guildID); err != nil {
 			log.Error().Errorf("Falling back to minimal guild entry for guild %s: %v", guildID, err)
@@ -460,7 +517,7 @@ func (ms *MonitoringService) handlePresenceUpdate(s *discordgo.Session, m *disco
 	if m.User == nil {
 		return
 	}
-	if ms.configManager.GuildConfig(m.GuildID) == nil {
+	if _, ok := ms.configManager.GuildConfig(m.GuildID); !ok {
 		return
 	}
 	if m.User.Username == "" {
@@ -476,8 +533,8 @@ func (ms *MonitoringService) handleMemberUpdate(s *discordgo.Session, m *discord
 	if m.User == nil {
 		return
 	}
-	gcfg := ms.configManager.GuildConfig(m.GuildID)
-	if gcfg == nil {
+	gcfg, ok := ms.configManager.GuildConfig(m.GuildID)
+	if !ok {
 		return
 	}
 
@@ -714,7 +771,7 @@ func (ms *MonitoringService) handleMemberUpdate(s *discordgo.Session, m *discord
 			}
 
 			atomic.AddUint64(&ms.apiMessagesSent, 1)
-			if _, sendErr := ms.session.ChannelMessageSendEmbed(channelID, embed); sendErr != nil {
+			if sendErr := ms.notifier.sendEmbeds(channelID, files.NotificationEventRoleUpdate, embed); sendErr != nil {
 				log.Error().Errorf("Failed to send role update notification: guildID=%s, userID=%s, channelID=%s, error=%v", m.GuildID, m.User.ID, channelID, sendErr)
 			} else {
 				log.Info().Applicationf("Role update notification sent successfully: guildID=%s, userID=%s, channelID=%s", m.GuildID, m.User.ID, channelID)
@@ -789,7 +846,7 @@ func (ms *MonitoringService) handleMemberUpdate(s *discordgo.Session, m *discord
 					},
 					Timestamp: time.Now().Format(time.RFC3339),
 				}
-				if _, sendErr := ms.session.ChannelMessageSendEmbed(channelID, embed); sendErr != nil {
+				if sendErr := ms.notifier.sendEmbeds(channelID, files.NotificationEventRoleUpdate, embed); sendErr != nil {
 					log.Error().Errorf("Failed to send fallback role update notification: guildID=%s, userID=%s, channelID=%s, error=%v", m.GuildID, m.User.ID, channelID, sendErr)
 				} else {
 					log.Info().Applicationf("Fallback role update notification sent successfully: guildID=%s, userID=%s, channelID=%s", m.GuildID, m.User.ID, channelID)
@@ -810,11 +867,11 @@ func (ms *MonitoringService) handleMemberUpdate(s *discordgo.Session, m *discord
 
 // handleUserUpdate processa updates de usuário em todos os guilds configurados.
 func (ms *MonitoringService) handleUserUpdate(s *discordgo.Session, m *discordgo.UserUpdate) {
-	cfg := ms.configManager.Config()
-	if cfg == nil || len(cfg.Guilds) == 0 {
+	guilds := ms.configManager.Guilds()
+	if len(guilds) == 0 {
 		return
 	}
-	for _, gcfg := range cfg.Guilds {
+	for _, gcfg := range guilds {
 		var member *discordgo.Member
 		// Use unified cache
 		if m2, err := ms.getGuildMember(gcfg.GuildID, m.User.ID); err == nil {
@@ -879,10 +936,11 @@ func (aw *UserWatcher) ProcessChange(guildID, userID, currentAvatar, username st
 		Timestamp: time.Now(),
 	}
 	log.Info().Applicationf("Avatar change detected for user %s in guild %s. Old avatar: %s, new avatar: %s", userID, guildID, oldAvatar, currentAvatar)
-	guildConfig := aw.configManager.GuildConfig(guildID)
-	if guildConfig != nil {
+	if guildConfig, ok := aw.configManager.GuildConfig(guildID); ok {
 		channelID := guildConfig.UserLogChannelID // Renamed from AvatarLogChannelID
-		if channelID == "" {
+		if guildConfig.AvatarNotifications.Digest() {
+			aw.queueAvatarDigest(guildID, change, guildConfig.AvatarNotifications.DigestInterval())
+		} else if channelID == "" {
 			log.Error().Errorf("UserLogChannelID not configured for guild %s. Notification not sent.", guildID)
 		} else {
 			if err := aw.notifier.SendAvatarChangeNotification(channelID, change); err != nil {
@@ -895,6 +953,15 @@ func (aw *UserWatcher) ProcessChange(guildID, userID, currentAvatar, username st
 	if _, _, err := aw.store.UpsertAvatar(guildID, userID, currentAvatar, time.Now()); err != nil {
 		log.Error().Errorf("Error saving avatar in store for guild %s: %v", guildID, err)
 	}
+	if aw.avatarEvents != nil {
+		aw.avatarEvents.publish(AvatarChangeEvent{
+			GuildID:   guildID,
+			UserID:    userID,
+			OldHash:   oldAvatar,
+			NewHash:   currentAvatar,
+			Timestamp: change.Timestamp,
+		})
+	}
 }
 
 func (aw *UserWatcher) getUsernameForNotification(guildID, userID string) string {
@@ -926,7 +993,7 @@ func (aw *UserWatcher) getUsernameForNotification(guildID, userID string) string
 	}
 
 	// Fallback: REST fetch
-	member, err := aw.session.GuildMember(guildID, userID)
+	member, err := discord.ClientFor(aw.session).FetchMember(context.Background(), guildID, userID)
 	if err != nil || member == nil {
 		log.Info().Applicationf("Error getting member for username for user %s in guild %s: %v - using ID", userID, guildID, err)
 		return userID
@@ -1029,7 +1096,7 @@ func (ms *MonitoringService) cacheRolesSet(guildID, userID string, roles []strin
 	// TTL: prefer guild-configured value, fallback to service default (5m)
 	ttl := ms.rolesTTL
 	if ms.configManager != nil {
-		if gcfg := ms.configManager.GuildConfig(guildID); gcfg != nil {
+		if gcfg, ok := ms.configManager.GuildConfig(guildID); ok {
 			if d := gcfg.RolesCacheTTLDuration(); d > 0 {
 				ttl = d
 			}
@@ -1064,6 +1131,51 @@ func (ms *MonitoringService) cacheRolesGet(guildID, userID string) ([]string, bo
 	return out, true
 }
 
+// Reload aplica uma configuração nova sem Stop/Start. Os handlers já leem a configuração a cada
+// evento; aqui o cache de cargos é ajustado (entradas de guilds removidas saem e as demais passam a
+// respeitar um roles_cache_ttl menor) e os destinos de log e menções são revalidados em background.
+func (ms *MonitoringService) Reload(cfg *files.BotConfig) error {
+	ms.runMu.Lock()
+	running := ms.isRunning
+	ms.runMu.Unlock()
+	if cfg == nil || !running {
+		return nil
+	}
+
+	ttls := make(map[string]time.Duration, len(cfg.Guilds))
+	for _, gcfg := range cfg.Guilds {
+		if gcfg.Inactive {
+			continue
+		}
+		ttl := ms.rolesTTL
+		if d := gcfg.RolesCacheTTLDuration(); d > 0 {
+			ttl = d
+		}
+		ttls[gcfg.GuildID] = ttl
+	}
+	now := time.Now()
+	ms.rolesCacheMu.Lock()
+	for key, entry := range ms.rolesCache {
+		guildID, _, _ := strings.Cut(key, ":")
+		ttl, ok := ttls[guildID]
+		if !ok {
+			delete(ms.rolesCache, key)
+			continue
+		}
+		if limit := now.Add(ttl); entry.expiresAt.After(limit) {
+			entry.expiresAt = limit
+			ms.rolesCache[key] = entry
+		}
+	}
+	ms.rolesCacheMu.Unlock()
+
+	go func() {
+		ms.notifier.ValidateNotificationMentions()
+		ms.notifier.ValidateLogDestinations()
+	}()
+	return nil
+}
+
 func (ms *MonitoringService) GetCacheStats() map[string]interface{} {
 	ms.rolesCacheMu.RLock()
 	size := len(ms.rolesCache)
@@ -1106,8 +1218,24 @@ func (ms *MonitoringService) GetCacheStats() map[string]interface{} {
 
 	return stats
 }
+
+// SetOutageMonitor conecta o monitor de instabilidade; enquanto degradado, o refresh silencioso,
+// a varredura de avatares (e o backfill de entradas) e o refresh de roles são pulados.
+func (ms *MonitoringService) SetOutageMonitor(m *discordsession.OutageMonitor) {
+	ms.outage = m
+}
+
+// pausedByOutage informa (e registra) que um trabalho não essencial foi pulado pelo modo degradado.
+func (ms *MonitoringService) pausedByOutage(work string) bool {
+	if !ms.outage.Degraded() {
+		return false
+	}
+	log.Info().Applicationf("⏸️ Skipping %s: Discord outage mode is active", work)
+	return true
+}
+
 func (ms *MonitoringService) handleStartupDowntimeAndMaybeRefresh() {
-	if ms.store == nil {
+	if ms.store == nil || ms.pausedByOutage("startup silent refresh") {
 		return
 	}
 	lastHB, okHB, err := ms.store.GetHeartbeat()
@@ -1116,21 +1244,14 @@ func (ms *MonitoringService) handleStartupDowntimeAndMaybeRefresh() {
 	} else {
 		if !okHB || time.Since(lastHB) > downtimeThreshold {
 			log.Info().Applicationf("⏱️ Detected downtime > threshold; performing silent avatar refresh before enabling notifications")
-			cfg := ms.configManager.Config()
-			if cfg == nil || len(cfg.Guilds) == 0 {
+			guilds := ms.configManager.Guilds()
+			if len(guilds) == 0 {
 				log.Info().Applicationf("No configured guilds for startup silent refresh")
 				return
 			}
-			var wg sync.WaitGroup
-			for _, gcfg := range cfg.Guilds {
-				gid := gcfg.GuildID
-				wg.Add(1)
-				go func(guildID string) {
-					defer wg.Done()
-					ms.initializeGuildCache(guildID) // Upserts avatars without sending notifications
-				}(gid)
-			}
-			wg.Wait()
+			ms.forEachGuildBounded(guilds, func(gcfg files.GuildConfig) {
+				ms.initializeGuildCache(gcfg.GuildID) // Upserts avatars without sending notifications
+			})
 			log.Info().Applicationf("✅ Silent avatar refresh completed")
 			return
 		}
@@ -1162,18 +1283,23 @@ func (ms *MonitoringService) fetchAllGuildMembers(guildID string) ([]*discordgo.
 }
 
 func (ms *MonitoringService) performPeriodicCheck() {
+	if ms.pausedByOutage("periodic avatar check") {
+		return
+	}
 	log.Info().Applicationf("Running periodic avatar check...")
-	cfg := ms.configManager.Config()
-	if cfg == nil || len(cfg.Guilds) == 0 {
+	guilds := ms.configManager.Guilds()
+	if len(guilds) == 0 {
 		log.Info().Applicationf("No configured guilds for periodic check")
 		return
 	}
-	for _, gcfg := range cfg.Guilds {
+	ms.forEachGuildBounded(guilds, func(gcfg files.GuildConfig) {
 		members, err := ms.fetchAllGuildMembers(gcfg.GuildID)
 		if err != nil {
 			log.Error().Errorf("Error getting members for guild %s: %v", gcfg.GuildID, err)
-			continue
+			return
 		}
+		active := ms.activeMembers(gcfg.GuildID)
+		checked := 0
 		for _, member := range members {
 			// Backfill missing member join date using Discord data
 			if ms.store != nil && !member.JoinedAt.IsZero() {
@@ -1183,12 +1309,34 @@ func (ms *MonitoringService) performPeriodicCheck() {
 			}
 
 			avatarHash := member.User.Avatar
-			if avatarHash == "" {
+			if avatarHash == "" || !active.includes(member.User.ID) {
 				continue
 			}
+			checked++
 			ms.checkAvatarChange(gcfg.GuildID, member.User.ID, avatarHash, member.User.Username)
 		}
+		active.logScope(gcfg.GuildID, checked, len(members))
+	})
+}
+
+// forEachGuildBounded executa fn para cada guild com no máximo RefreshConcurrency guilds em paralelo.
+// Falhas (inclusive panics) em uma guild são registradas e não interrompem as demais; o rate limiting
+// continua a cargo do limiter compartilhado da sessão discordgo.
+func (ms *MonitoringService) forEachGuildBounded(guilds []files.GuildConfig, fn func(gcfg files.GuildConfig)) {
+	limit := ms.configManager.RefreshConcurrency()
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, gcfg := range guilds {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(gcfg files.GuildConfig) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer errutil.RecoverPanic(errors.CategoryService, "guild refresh "+gcfg.GuildID, nil)
+			fn(gcfg)
+		}(gcfg)
 	}
+	wg.Wait()
 }
 
 // MemberEvents exposes the member event sub-service.
@@ -1240,7 +1388,7 @@ func (ms *MonitoringService) getGuildMember(guildID, userID string) (*discordgo.
 
 	// Fallback to API
 	atomic.AddUint64(&ms.apiGuildMemberCalls, 1)
-	member, err := ms.session.GuildMember(guildID, userID)
+	member, err := discord.ClientFor(ms.session).FetchMember(context.Background(), guildID, userID)
 	if err != nil {
 		return nil, err
 	}
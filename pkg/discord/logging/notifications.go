@@ -3,10 +3,14 @@ package logging
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
 	"github.com/small-frappuccino/discordcore/pkg/task"
+	"github.com/small-frappuccino/discordcore/pkg/util"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/small-frappuccino/discordcore/pkg/theme"
@@ -19,7 +23,12 @@ const (
 )
 
 type NotificationSender struct {
-	session *discordgo.Session
+	session       *discordgo.Session
+	configManager *files.ConfigManager
+
+	// threads usadas como destino de log -> canal pai (fallback quando a thread é deletada)
+	threadParents map[string]string
+	threadMu      sync.Mutex
 }
 
 func NewNotificationSender(session *discordgo.Session) *NotificationSender {
@@ -28,6 +37,99 @@ func NewNotificationSender(session *discordgo.Session) *NotificationSender {
 	}
 }
 
+// SetConfigManager habilita menções configuráveis por tipo de evento (GuildConfig.NotificationMentions).
+// Sem config manager, nenhuma notificação menciona ninguém.
+func (ns *NotificationSender) SetConfigManager(cm *files.ConfigManager) {
+	ns.configManager = cm
+}
+
+// sendEmbeds envia embeds com allowed_mentions explícito: apenas o cargo configurado para
+// eventType (se houver) pode ser mencionado, então menções contidas no conteúdo dos embeds
+// (ex.: mensagens editadas/deletadas) nunca disparam pings.
+func (ns *NotificationSender) sendEmbeds(channelID, eventType string, embeds ...*discordgo.MessageEmbed) error {
+	return ns.sendMessage(channelID, eventType, &discordgo.MessageSend{Embeds: embeds})
+}
+
+// sendMessage aplica a política de menções de sendEmbeds a uma mensagem completa (ex.: com componentes).
+func (ns *NotificationSender) sendMessage(channelID, eventType string, msg *discordgo.MessageSend) error {
+	msg.AllowedMentions = &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+	if roleID := ns.mentionRole(channelID, eventType); roleID != "" {
+		msg.Content = "<@&" + roleID + ">"
+		msg.AllowedMentions.Roles = []string{roleID}
+	}
+	return ns.sendToDestination(channelID, msg)
+}
+
+// mentionRole resolve o cargo a mencionar para um evento enviado em channelID.
+// Cargos que não existem mais na guild são ignorados (com aviso) em vez de gerar uma menção quebrada.
+func (ns *NotificationSender) mentionRole(channelID, eventType string) string {
+	if eventType == "" || ns.configManager == nil || ns.session.State == nil {
+		return ""
+	}
+	ch, err := ns.session.State.Channel(channelID)
+	if err != nil || ch == nil || ch.GuildID == "" {
+		return ""
+	}
+	gcfg, ok := ns.configManager.GuildConfig(ch.GuildID)
+	if !ok {
+		return ""
+	}
+	roleID := gcfg.MentionRoleFor(eventType)
+	if roleID == "" {
+		return ""
+	}
+	if _, err := ns.session.State.Role(ch.GuildID, roleID); err != nil {
+		log.Warn().Applicationf("Mention role %s for %s notifications not found in guild %s; sending without ping", roleID, eventType, ch.GuildID)
+		return ""
+	}
+	return roleID
+}
+
+// ValidateNotificationMentions verifica se os cargos configurados em NotificationMentions existem,
+// registrando um aviso para cada cargo ausente ou tipo de evento desconhecido.
+func (ns *NotificationSender) ValidateNotificationMentions() {
+	if ns.configManager == nil {
+		return
+	}
+	for _, gcfg := range ns.configManager.Guilds() {
+		if len(gcfg.NotificationMentions) == 0 {
+			continue
+		}
+		roles, err := ns.session.GuildRoles(gcfg.GuildID)
+		if err != nil {
+			log.Warn().Applicationf("Could not validate notification mention roles for guild %s: %v", gcfg.GuildID, err)
+			continue
+		}
+		known := make(map[string]bool, len(roles))
+		for _, r := range roles {
+			known[r.ID] = true
+		}
+		for eventType, roleID := range gcfg.NotificationMentions {
+			if !knownNotificationEvent(eventType) {
+				log.Warn().Applicationf("Unknown notification event type %q in notification_mentions for guild %s", eventType, gcfg.GuildID)
+				continue
+			}
+			if roleID != "" && roleID != files.MentionNone && !known[roleID] {
+				log.Warn().Applicationf("Notification mention role %s for %s does not exist in guild %s; it will not be pinged", roleID, eventType, gcfg.GuildID)
+			}
+		}
+	}
+}
+
+func knownNotificationEvent(eventType string) bool {
+	switch eventType {
+	case files.NotificationEventAvatarChange,
+		files.NotificationEventMemberJoin,
+		files.NotificationEventMemberLeave,
+		files.NotificationEventMessageEdit,
+		files.NotificationEventMessageDelete,
+		files.NotificationEventRoleUpdate,
+		files.NotificationEventAutomod:
+		return true
+	}
+	return false
+}
+
 func (ns *NotificationSender) SendAvatarChangeNotification(channelID string, change files.AvatarChange) error {
 	// Check if username is empty, ignore if so
 	if change.Username == "" {
@@ -42,7 +144,7 @@ func (ns *NotificationSender) SendAvatarChangeNotification(channelID string, cha
 
 	embeds := ns.createAvatarChangeEmbeds(change)
 
-	_, err := ns.session.ChannelMessageSendEmbeds(channelID, embeds)
+	err := ns.sendEmbeds(channelID, files.NotificationEventAvatarChange, embeds...)
 	if err != nil {
 		return fmt.Errorf(ErrSendMessage, err)
 	}
@@ -132,8 +234,7 @@ func (ns *NotificationSender) SendMemberJoinNotification(channelID string, membe
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, files.NotificationEventMemberJoin, embed)
 }
 
 // SendMemberLeaveNotification envia notificação de saída de membro
@@ -186,8 +287,7 @@ func (ns *NotificationSender) SendMemberLeaveNotification(channelID string, memb
 		embed.Fields = fields
 	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, files.NotificationEventMemberLeave, embed)
 }
 
 // SendMessageEditNotification envia notificação de edição de mensagem
@@ -208,7 +308,7 @@ func (ns *NotificationSender) SendMessageEditNotification(channelID string, orig
 
 	userField := fmt.Sprintf("Name: %s\nMention: <@%s>\nID: `%s`", original.Author.Username, original.Author.ID, original.Author.ID)
 	channelField := fmt.Sprintf("Name: #%s\nMention: <#%s>\nID: `%s`", channelName, original.ChannelID, original.ChannelID)
-	messageTime := original.Timestamp.Format("January 2, 2006 at 3:04 PM")
+	messageTime := util.FormatDisplayTime(original.Timestamp, ns.guildTimezone(original.GuildID))
 
 	desc := ""
 	if jumpURL != "" {
@@ -236,7 +336,7 @@ func (ns *NotificationSender) SendMessageEditNotification(channelID string, orig
 				Inline: true,
 			},
 			{
-				Name:   "Before",
+				Name:   storedContentLabel("Before", original),
 				Value:  truncateString(original.Content, 1000),
 				Inline: false,
 			},
@@ -255,8 +355,7 @@ func (ns *NotificationSender) SendMessageEditNotification(channelID string, orig
 		},
 	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, files.NotificationEventMessageEdit, embed)
 }
 
 // SendMessageDeleteNotification envia notificação de deleção de mensagem
@@ -271,7 +370,7 @@ func (ns *NotificationSender) SendMessageDeleteNotification(channelID string, de
 
 	userField := fmt.Sprintf("Name: %s\nMention: <@%s>\nID: `%s`", deleted.Author.Username, deleted.Author.ID, deleted.Author.ID)
 	channelField := fmt.Sprintf("Name: #%s\nMention: <#%s>\nID: `%s`", channelName, deleted.ChannelID, deleted.ChannelID)
-	messageTime := deleted.Timestamp.Format("January 2, 2006 at 3:04 PM")
+	messageTime := util.FormatDisplayTime(deleted.Timestamp, ns.guildTimezone(deleted.GuildID))
 
 	embed := &discordgo.MessageEmbed{
 		Title: "🗑️ Message Deleted",
@@ -293,7 +392,7 @@ func (ns *NotificationSender) SendMessageDeleteNotification(channelID string, de
 				Inline: true,
 			},
 			{
-				Name:   "Message",
+				Name:   storedContentLabel("Message", deleted),
 				Value:  truncateString(deleted.Content, 1000),
 				Inline: false,
 			},
@@ -311,9 +410,22 @@ func (ns *NotificationSender) SendMessageDeleteNotification(channelID string, de
 			Text: "Message ID: " + deleted.ID,
 		},
 	}
+	if len(deleted.Attachments) > 0 || len(deleted.Embeds) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Attachments & Embeds",
+			Value: mediaSummary(deleted.Attachments, deleted.Embeds),
+		})
+	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, files.NotificationEventMessageDelete, embed)
+}
+
+// storedContentLabel sinaliza no título do campo quando o conteúdo armazenado foi truncado pelo store.
+func storedContentLabel(label string, msg *task.CachedMessage) string {
+	if msg == nil || !msg.Truncated {
+		return label
+	}
+	return fmt.Sprintf("%s (truncated, originally %d chars)", label, msg.OriginalLength)
 }
 
 // formatDurationFull mostra a duração no formato completo, omitindo unidades iniciais iguais a zero.
@@ -356,6 +468,15 @@ func formatDurationFull(d time.Duration) string {
 	return out
 }
 
+// guildTimezone retorna o fuso configurado para a guild (vazio = UTC).
+func (ns *NotificationSender) guildTimezone(guildID string) string {
+	if ns.configManager == nil || guildID == "" {
+		return ""
+	}
+	gcfg, _ := ns.configManager.GuildConfig(guildID)
+	return gcfg.Timezone
+}
+
 // formatDurationSmart formata listando todas as unidades com valor diferente de zero (sem abreviações).
 func formatDurationSmart(d time.Duration) string {
 	if d < 0 {
@@ -465,6 +586,16 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// truncateRunes corta s em até maxRunes caracteres, terminando em "..." quando corta, sem partir
+// um caractere UTF-8 ao meio (o limite de 2000 do conteúdo de mensagens conta caracteres, não bytes).
+func truncateRunes(s string, maxRunes int) string {
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:maxRunes-3]) + "..."
+}
+
 func (ns *NotificationSender) SendInfoMessage(channelID, message string) error {
 	embed := &discordgo.MessageEmbed{
 		Title:       "ℹ️ Info",
@@ -472,8 +603,7 @@ func (ns *NotificationSender) SendInfoMessage(channelID, message string) error {
 		Color:       theme.Info(),
 	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, "", embed)
 }
 
 // SendMemberRoleUpdateNotification envia notificação de atualização de cargo (add/remove)
@@ -534,8 +664,7 @@ func (ns *NotificationSender) SendMemberRoleUpdateNotification(
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, files.NotificationEventRoleUpdate, embed)
 }
 
 func (ns *NotificationSender) SendErrorMessage(channelID, message string) error {
@@ -545,8 +674,7 @@ func (ns *NotificationSender) SendErrorMessage(channelID, message string) error
 		Color:       theme.Error(),
 	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, "", embed)
 }
 
 func (ns *NotificationSender) SendSuccessMessage(channelID, message string) error {
@@ -556,20 +684,21 @@ func (ns *NotificationSender) SendSuccessMessage(channelID, message string) erro
 		Color:       theme.Success(),
 	}
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	return ns.sendEmbeds(channelID, "", embed)
 }
 
-func (ns *NotificationSender) SendAutomodActionNotification(channelID string, e *discordgo.AutoModerationActionExecution) error {
+// SendAutomodActionNotification envia o log de uma ação do AutoMod. Quando actionID > 0 (ação
+// registrada no store), inclui o botão "Mark false positive" para feedback dos moderadores.
+// simulated, quando não vazio, marca a notificação como "[DRY RUN]" com as ações não executadas.
+func (ns *NotificationSender) SendAutomodActionNotification(channelID string, e *discordgo.AutoModerationActionExecution, actionID int64, simulated []string) error {
 	if e == nil || channelID == "" {
 		return nil
 	}
 
 	title := "AutoMod action executed"
-	desc := "A native AutoMod rule was triggered."
 	embed := &discordgo.MessageEmbed{
 		Title:       title,
-		Description: desc,
+		Description: automodDescription(e.RuleID),
 		Color:       theme.AutomodAction(),
 		Timestamp:   time.Now().Format(time.RFC3339),
 		Fields: []*discordgo.MessageEmbedField{
@@ -621,7 +750,11 @@ func (ns *NotificationSender) SendAutomodActionNotification(channelID string, e
 			Inline: false,
 		})
 	}
+	markDryRun(embed, simulated)
 
-	_, err := ns.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+	msg := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+	if actionID > 0 {
+		msg.Components = AutomodFeedbackComponents(e.GuildID, actionID)
+	}
+	return ns.sendMessage(channelID, files.NotificationEventAutomod, msg)
 }
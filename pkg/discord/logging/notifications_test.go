@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{"short", "olá", 10, "olá"},
+		{"exact", strings.Repeat("é", 5), 5, strings.Repeat("é", 5)},
+		{"multibyte cut", strings.Repeat("é", 10), 6, "ééé..."},
+		{"emoji", strings.Repeat("👋", 4), 3, "..."},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := truncateRunes(tc.in, tc.max)
+			if got != tc.want {
+				t.Fatalf("truncateRunes(%q, %d) = %q, want %q", tc.in, tc.max, got, tc.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Fatalf("result is not valid UTF-8: %q", got)
+			}
+		})
+	}
+}
+
+func TestGreeterTextFitsDiscordLimit(t *testing.T) {
+	// 1500 two-byte characters: over 2000 bytes but within the 2000-character limit.
+	text := strings.Repeat("ã", 1500)
+	if got := truncateRunes(text, 2000); got != text {
+		t.Fatal("text within the character limit was cut")
+	}
+	long := strings.Repeat("ã", 2500)
+	got := truncateRunes(long, 2000)
+	if n := utf8.RuneCountInString(got); n != 2000 || !utf8.ValidString(got) {
+		t.Fatalf("truncated to %d runes (valid=%t), want 2000", n, utf8.ValidString(got))
+	}
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// handleMessageReactionAdd persiste reações adicionadas em guilds com ReactionLogEnabled.
+func (ms *MonitoringService) handleMessageReactionAdd(s *discordgo.Session, e *discordgo.MessageReactionAdd) {
+	if e == nil || e.MessageReaction == nil {
+		return
+	}
+	ms.recordReaction(e.MessageReaction, storage.ReactionAdd)
+}
+
+// handleMessageReactionRemove persiste reações removidas em guilds com ReactionLogEnabled.
+func (ms *MonitoringService) handleMessageReactionRemove(s *discordgo.Session, e *discordgo.MessageReactionRemove) {
+	if e == nil || e.MessageReaction == nil {
+		return
+	}
+	ms.recordReaction(e.MessageReaction, storage.ReactionRemove)
+}
+
+func (ms *MonitoringService) recordReaction(r *discordgo.MessageReaction, action string) {
+	if r.GuildID == "" || ms.store == nil {
+		return
+	}
+	gcfg, ok := ms.configManager.GuildConfig(r.GuildID)
+	if !ok || !gcfg.ReactionLogEnabled {
+		return
+	}
+	// Mesmos filtros de canal usados no logging de mensagens
+	if !ms.messageEventService.shouldLogChannel(&gcfg, r.ChannelID) {
+		return
+	}
+	ms.markEvent()
+	if err := ms.store.RecordReaction(storage.ReactionEvent{
+		GuildID:   r.GuildID,
+		ChannelID: r.ChannelID,
+		MessageID: r.MessageID,
+		UserID:    r.UserID,
+		Emoji:     r.Emoji.APIName(),
+		Action:    action,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		log.Warn().Applicationf("Failed to record reaction %s: guildID=%s, messageID=%s, err=%v", action, r.GuildID, r.MessageID, err)
+	}
+}
+
+// warnIfReactionIntentMissing avisa quando alguma guild habilita reaction logging mas a sessão
+// não solicita o intent GUILD_MESSAGE_REACTIONS (os eventos nunca chegarão).
+func (ms *MonitoringService) warnIfReactionIntentMissing() {
+	if ms.session.Identify.Intents&discordgo.IntentsGuildMessageReactions != 0 {
+		return
+	}
+	for _, gcfg := range ms.configManager.Guilds() {
+		if gcfg.ReactionLogEnabled {
+			log.Warn().Applicationf("⚠️ Reaction logging enabled for guild %s but the session lacks the GUILD_MESSAGE_REACTIONS intent; no reaction events will be received", gcfg.GuildID)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// handleVoiceStateUpdate registra entradas, saídas e trocas de canal de voz em guilds com VoiceLogEnabled.
+// Mudanças de mute/deafen no mesmo canal são ignoradas.
+func (ms *MonitoringService) handleVoiceStateUpdate(s *discordgo.Session, e *discordgo.VoiceStateUpdate) {
+	if e == nil || e.VoiceState == nil || e.GuildID == "" || e.UserID == "" || ms.store == nil {
+		return
+	}
+	gcfg, ok := ms.configManager.GuildConfig(e.GuildID)
+	if !ok || !gcfg.VoiceLogEnabled {
+		return
+	}
+	if e.BeforeUpdate != nil && e.BeforeUpdate.ChannelID == e.ChannelID {
+		return
+	}
+	ms.markEvent()
+
+	now := time.Now()
+	// Saída ou troca: fecha a sessão aberta (duração calculada no store)
+	if _, err := ms.store.EndVoiceSession(e.GuildID, e.UserID, now); err != nil {
+		log.Warn().Applicationf("Failed to close voice session: guildID=%s, userID=%s, err=%v", e.GuildID, e.UserID, err)
+	}
+	// Entrada ou troca: abre uma nova sessão no canal atual
+	if e.ChannelID != "" {
+		if err := ms.store.StartVoiceSession(e.GuildID, e.UserID, e.ChannelID, now); err != nil {
+			log.Warn().Applicationf("Failed to open voice session: guildID=%s, userID=%s, channelID=%s, err=%v", e.GuildID, e.UserID, e.ChannelID, err)
+		}
+	}
+}
+
+// settleVoiceSessionsOnStartup fecha sessões deixadas abertas por uma parada não limpa, usando o
+// último heartbeat como horário de saída, e abre sessões para quem já está em voz segundo o State.
+func (ms *MonitoringService) settleVoiceSessionsOnStartup() {
+	if ms.store == nil {
+		return
+	}
+	closeAt := time.Now()
+	if hb, ok, err := ms.store.GetHeartbeat(); err == nil && ok {
+		closeAt = hb
+	}
+	if n, err := ms.store.CloseOpenVoiceSessions(closeAt); err != nil {
+		log.Warn().Applicationf("Failed to close stale voice sessions: %v", err)
+	} else if n > 0 {
+		log.Info().Applicationf("Closed %d stale voice sessions left open by the previous run", n)
+	}
+
+	if ms.session.State == nil {
+		return
+	}
+	now := time.Now()
+	for _, gcfg := range ms.configManager.Guilds() {
+		if !gcfg.VoiceLogEnabled {
+			continue
+		}
+		g, err := ms.session.State.Guild(gcfg.GuildID)
+		if err != nil || g == nil {
+			continue
+		}
+		for _, vs := range g.VoiceStates {
+			if vs == nil || vs.ChannelID == "" {
+				continue
+			}
+			if err := ms.store.StartVoiceSession(gcfg.GuildID, vs.UserID, vs.ChannelID, now); err != nil {
+				log.Warn().Applicationf("Failed to open voice session: guildID=%s, userID=%s, err=%v", gcfg.GuildID, vs.UserID, err)
+			}
+		}
+	}
+}
+
+// closeVoiceSessionsOnShutdown fecha todas as sessões abertas para que a duração fique registrada.
+func (ms *MonitoringService) closeVoiceSessionsOnShutdown() {
+	if ms.store == nil {
+		return
+	}
+	if n, err := ms.store.CloseOpenVoiceSessions(time.Now()); err != nil {
+		log.Warn().Applicationf("Failed to close open voice sessions on shutdown: %v", err)
+	} else if n > 0 {
+		log.Info().Applicationf("Closed %d open voice sessions on shutdown", n)
+	}
+}
+
+// warnIfVoiceIntentMissing avisa quando alguma guild habilita voice logging mas a sessão
+// não solicita o intent GUILD_VOICE_STATES.
+func (ms *MonitoringService) warnIfVoiceIntentMissing() {
+	if ms.session.Identify.Intents&discordgo.IntentsGuildVoiceStates != 0 {
+		return
+	}
+	for _, gcfg := range ms.configManager.Guilds() {
+		if gcfg.VoiceLogEnabled {
+			log.Warn().Applicationf("⚠️ Voice logging enabled for guild %s but the session lacks the GUILD_VOICE_STATES intent; no voice events will be received", gcfg.GuildID)
+		}
+	}
+}
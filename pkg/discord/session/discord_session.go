@@ -0,0 +1,167 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DiscordSession wraps a connected *discordgo.Session and exposes paced helpers for
+// the operations extensions use most. Every helper waits on the shared Pacer before
+// hitting the REST API and forwards ctx to discordgo so cancellation is honoured.
+type DiscordSession struct {
+	s     *discordgo.Session
+	pacer *Pacer
+}
+
+// Wrap builds a DiscordSession around an existing discordgo session using the default pacer.
+func Wrap(s *discordgo.Session) *DiscordSession {
+	return WrapWithPacer(s, NewPacer(DefaultPacerInterval))
+}
+
+// WrapWithPacer builds a DiscordSession sharing the provided pacer.
+func WrapWithPacer(s *discordgo.Session, pacer *Pacer) *DiscordSession {
+	if pacer == nil {
+		pacer = NewPacer(DefaultPacerInterval)
+	}
+	return &DiscordSession{s: s, pacer: pacer}
+}
+
+// Raw returns the underlying *discordgo.Session.
+//
+// This is an escape hatch for API calls not covered by the helpers below. Calls made
+// through Raw BYPASS the pacer (discordgo's own per-route bucket handling still applies),
+// so prefer the helpers for anything issued in bulk.
+func (ds *DiscordSession) Raw() *discordgo.Session {
+	return ds.s
+}
+
+// Pacer returns the pacer shared by the helpers, so callers using Raw can opt in manually.
+func (ds *DiscordSession) Pacer() *Pacer {
+	return ds.pacer
+}
+
+// SendMessage sends a plain text message to a channel (paced).
+func (ds *DiscordSession) SendMessage(ctx context.Context, channelID, content string) (*discordgo.Message, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	return ds.s.ChannelMessageSend(channelID, content, discordgo.WithContext(ctx))
+}
+
+// SendEmbed sends an embed to a channel (paced).
+func (ds *DiscordSession) SendEmbed(ctx context.Context, channelID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	return ds.s.ChannelMessageSendEmbed(channelID, embed, discordgo.WithContext(ctx))
+}
+
+// SendComplex sends a fully specified message to a channel (paced).
+func (ds *DiscordSession) SendComplex(ctx context.Context, channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	return ds.s.ChannelMessageSendComplex(channelID, data, discordgo.WithContext(ctx))
+}
+
+// AddRole adds a role to a guild member (paced). Extra options (e.g. discordgo.WithAuditLogReason)
+// are passed along with the request.
+func (ds *DiscordSession) AddRole(ctx context.Context, guildID, userID, roleID string, opts ...discordgo.RequestOption) error {
+	if err := ds.ready(ctx); err != nil {
+		return err
+	}
+	return ds.s.GuildMemberRoleAdd(guildID, userID, roleID, append(opts, discordgo.WithContext(ctx))...)
+}
+
+// RemoveRole removes a role from a guild member (paced). Extra options are passed along with the request.
+func (ds *DiscordSession) RemoveRole(ctx context.Context, guildID, userID, roleID string, opts ...discordgo.RequestOption) error {
+	if err := ds.ready(ctx); err != nil {
+		return err
+	}
+	return ds.s.GuildMemberRoleRemove(guildID, userID, roleID, append(opts, discordgo.WithContext(ctx))...)
+}
+
+// TimeoutMember times a member out for the given duration (paced). A non-positive
+// duration clears an existing timeout.
+func (ds *DiscordSession) TimeoutMember(ctx context.Context, guildID, userID string, d time.Duration) error {
+	if err := ds.ready(ctx); err != nil {
+		return err
+	}
+	var until *time.Time
+	if d > 0 {
+		t := time.Now().Add(d)
+		until = &t
+	}
+	return ds.s.GuildMemberTimeout(guildID, userID, until, discordgo.WithContext(ctx))
+}
+
+// GetMember fetches a guild member from the REST API (paced).
+func (ds *DiscordSession) GetMember(ctx context.Context, guildID, userID string) (*discordgo.Member, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	return ds.s.GuildMember(guildID, userID, discordgo.WithContext(ctx))
+}
+
+// ListMembers fetches one page of guild members with IDs greater than after (paced).
+// Requires the GUILD_MEMBERS intent; limit is capped at 1000 by Discord.
+func (ds *DiscordSession) ListMembers(ctx context.Context, guildID, after string, limit int) ([]*discordgo.Member, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	return ds.s.GuildMembers(guildID, after, limit, discordgo.WithContext(ctx))
+}
+
+// SendDM opens (or reuses) the DM channel with a user and sends data there (paced).
+func (ds *DiscordSession) SendDM(ctx context.Context, userID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	ch, err := ds.s.UserChannelCreate(userID, discordgo.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return ds.SendComplex(ctx, ch.ID, data)
+}
+
+// EditMessage replaces the content of a message sent by the bot (paced).
+func (ds *DiscordSession) EditMessage(ctx context.Context, channelID, messageID, content string) (*discordgo.Message, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	return ds.s.ChannelMessageEdit(channelID, messageID, content, discordgo.WithContext(ctx))
+}
+
+// DeleteMessage deletes a message (paced).
+func (ds *DiscordSession) DeleteMessage(ctx context.Context, channelID, messageID string) error {
+	if err := ds.ready(ctx); err != nil {
+		return err
+	}
+	return ds.s.ChannelMessageDelete(channelID, messageID, discordgo.WithContext(ctx))
+}
+
+// BulkDeleteMessages deletes 2 to 100 messages of a channel in one request (paced).
+func (ds *DiscordSession) BulkDeleteMessages(ctx context.Context, channelID string, messageIDs []string) error {
+	if err := ds.ready(ctx); err != nil {
+		return err
+	}
+	return ds.s.ChannelMessagesBulkDelete(channelID, messageIDs, discordgo.WithContext(ctx))
+}
+
+// EditChannel edits a channel or thread (paced).
+func (ds *DiscordSession) EditChannel(ctx context.Context, channelID string, data *discordgo.ChannelEdit) (*discordgo.Channel, error) {
+	if err := ds.ready(ctx); err != nil {
+		return nil, err
+	}
+	return ds.s.ChannelEdit(channelID, data, discordgo.WithContext(ctx))
+}
+
+func (ds *DiscordSession) ready(ctx context.Context) error {
+	if ds == nil || ds.s == nil {
+		return fmt.Errorf("discord session not initialized")
+	}
+	return ds.pacer.Wait(ctx)
+}
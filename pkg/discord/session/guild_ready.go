@@ -0,0 +1,67 @@
+package session
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildAvailability classifies guilds by what the gateway sent after READY.
+type GuildAvailability struct {
+	Available []string // GUILD_CREATE received
+	Pending   []string // in READY but still unavailable
+	Missing   []string // not in READY
+}
+
+// WaitForGuilds waits up to timeout for pending guilds; timeout <= 0 only classifies.
+func WaitForGuilds(s *discordgo.Session, guildIDs []string, timeout time.Duration) GuildAvailability {
+	if s == nil || len(guildIDs) == 0 {
+		return GuildAvailability{}
+	}
+	arrived := make(chan struct{}, 1)
+	cancel := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.GuildCreate) {
+		select {
+		case arrived <- struct{}{}:
+		default:
+		}
+	})
+	defer cancel()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		result := classifyGuilds(s, guildIDs)
+		if len(result.Pending) == 0 || deadline == nil {
+			return result
+		}
+		select {
+		case <-arrived:
+		case <-deadline:
+			return classifyGuilds(s, guildIDs)
+		}
+	}
+}
+
+func classifyGuilds(s *discordgo.Session, guildIDs []string) GuildAvailability {
+	var out GuildAvailability
+	for _, id := range guildIDs {
+		if s.State == nil {
+			out.Missing = append(out.Missing, id)
+			continue
+		}
+		g, err := s.State.Guild(id)
+		switch {
+		case err != nil || g == nil:
+			out.Missing = append(out.Missing, id)
+		case g.Unavailable:
+			out.Pending = append(out.Pending, id)
+		default:
+			out.Available = append(out.Available, id)
+		}
+	}
+	return out
+}
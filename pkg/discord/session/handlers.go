@@ -0,0 +1,130 @@
+package session
+
+import (
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// HandlerSet tracks the event handlers a service registers on a session so they can be
+// removed together on Stop and reinstalled after a gateway reconnect.
+//
+// discordgo keeps handlers across reconnects, so Reinstall only re-registers handlers that are
+// not currently subscribed; on a session that kept its handlers it does nothing.
+type HandlerSet struct {
+	mu       sync.Mutex
+	session  *discordgo.Session
+	handlers []interface{}
+	cancels  []func()
+}
+
+// NewHandlerSet creates an empty handler set bound to a session.
+func NewHandlerSet(s *discordgo.Session) *HandlerSet {
+	return &HandlerSet{session: s}
+}
+
+// Add registers handler on the session and tracks it for removal/reinstallation. The handler is
+// wrapped with Guard, so a panic in it is logged and does not bring the bot down.
+func (hs *HandlerSet) Add(handler interface{}) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	handler = Guard(handler)
+	hs.handlers = append(hs.handlers, handler)
+	hs.cancels = append(hs.cancels, hs.session.AddHandler(handler))
+}
+
+// Len returns how many handlers are tracked.
+func (hs *HandlerSet) Len() int {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return len(hs.handlers)
+}
+
+// Reinstall registers every tracked handler that is not subscribed on the session. Handlers
+// that are still installed are left alone, so calling it after a reconnect is a no-op.
+func (hs *HandlerSet) Reinstall() {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	for i, h := range hs.handlers {
+		if hs.cancels[i] == nil {
+			hs.cancels[i] = hs.session.AddHandler(h)
+		}
+	}
+}
+
+// RemoveAll unsubscribes and forgets every tracked handler.
+func (hs *HandlerSet) RemoveAll() {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	for _, cancel := range hs.cancels {
+		if cancel != nil {
+			cancel()
+		}
+	}
+	hs.cancels = nil
+	hs.handlers = nil
+}
+
+// Guard wraps an event handler (any signature accepted by discordgo.Session.AddHandler, e.g.
+// func(*discordgo.Session, *discordgo.MessageCreate)) so that a panic is recovered with
+// errutil.RecoverPanic instead of crashing the process. The wrapper keeps the handler's type, so
+// discordgo still dispatches it by event. Values that are not such functions are returned as is.
+func Guard(handler interface{}) interface{} {
+	v := reflect.ValueOf(handler)
+	if v.Kind() != reflect.Func || v.IsNil() || v.Type().NumIn() != 2 || v.Type().NumOut() != 0 {
+		return handler
+	}
+	event := strings.TrimPrefix(v.Type().In(1).String(), "*discordgo.")
+	operation := "event " + event
+	if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+		operation += " " + strings.TrimSuffix(path.Base(fn.Name()), "-fm")
+	}
+	return reflect.MakeFunc(v.Type(), func(args []reflect.Value) []reflect.Value {
+		defer errutil.RecoverPanic(errors.CategoryDiscord, operation, nil)
+		return v.Call(args)
+	}).Interface()
+}
+
+// OnReconnect calls fn every time the gateway comes back after a disconnect
+// (a fresh Connect following a Disconnect, or a Resumed session). The initial
+// connection does not trigger it. Returns a function that removes the hook.
+func OnReconnect(s *discordgo.Session, fn func()) func() {
+	var mu sync.Mutex
+	disconnected := false
+
+	cancelDisconnect := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) {
+		mu.Lock()
+		disconnected = true
+		mu.Unlock()
+	})
+	cancelConnect := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Connect) {
+		mu.Lock()
+		was := disconnected
+		disconnected = false
+		mu.Unlock()
+		if was {
+			log.Info().Discordf("🔄 Gateway reconnected; running reconnect hooks")
+			fn()
+		}
+	})
+	cancelResumed := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Resumed) {
+		mu.Lock()
+		disconnected = false
+		mu.Unlock()
+		log.Info().Discordf("🔄 Gateway session resumed; running reconnect hooks")
+		fn()
+	})
+
+	return func() {
+		cancelDisconnect()
+		cancelConnect()
+		cancelResumed()
+	}
+}
@@ -0,0 +1,163 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// fakeGateway is a minimal Discord gateway: it answers Identify with READY and Resume with
+// RESUMED, and lets the test push dispatch events or drop the connection.
+type fakeGateway struct {
+	srv   *httptest.Server
+	conns chan *websocket.Conn
+}
+
+func newFakeGateway(t *testing.T) *fakeGateway {
+	t.Helper()
+	g := &fakeGateway{conns: make(chan *websocket.Conn, 4)}
+	upgrader := websocket.Upgrader{}
+	g.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gateway" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"url":"ws://` + r.Host + `/"}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.WriteJSON(map[string]any{"op": 10, "d": map[string]any{"heartbeat_interval": 60000}})
+		var hello struct {
+			Op int `json:"op"`
+		}
+		if err := conn.ReadJSON(&hello); err != nil {
+			conn.Close()
+			return
+		}
+		if hello.Op == 6 {
+			conn.WriteJSON(map[string]any{"op": 0, "t": "RESUMED", "s": 2, "d": map[string]any{}})
+		} else {
+			conn.WriteJSON(map[string]any{"op": 0, "t": "READY", "s": 1, "d": map[string]any{
+				"session_id": "fake-session",
+				"user":       map[string]any{"id": "1", "username": "bot"},
+			}})
+		}
+		g.conns <- conn
+		// Drain client frames (heartbeats) until the connection is dropped.
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+	}))
+	t.Cleanup(g.srv.Close)
+
+	prev := discordgo.EndpointGateway
+	discordgo.EndpointGateway = g.srv.URL + "/gateway"
+	t.Cleanup(func() { discordgo.EndpointGateway = prev })
+	return g
+}
+
+func (g *fakeGateway) next(t *testing.T) *websocket.Conn {
+	t.Helper()
+	select {
+	case conn := <-g.conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not connect to the fake gateway")
+		return nil
+	}
+}
+
+func typingStart(conn *websocket.Conn, seq int) error {
+	return conn.WriteJSON(map[string]any{"op": 0, "t": "TYPING_START", "s": seq, "d": map[string]any{
+		"user_id": "2", "channel_id": "3", "timestamp": 0,
+	}})
+}
+
+func waitCount(t *testing.T, n *atomic.Int32, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for n.Load() < want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// Give a duplicated handler the chance to fire before checking the exact count.
+	time.Sleep(50 * time.Millisecond)
+	if got := n.Load(); got != want {
+		t.Fatalf("handler ran %d times, want %d", got, want)
+	}
+}
+
+func TestHandlerSetSurvivesReconnectWithoutDuplicates(t *testing.T) {
+	g := newFakeGateway(t)
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	s.LogLevel = -1
+
+	var calls, reconnects atomic.Int32
+	hs := NewHandlerSet(s)
+	hs.Add(func(_ *discordgo.Session, _ *discordgo.TypingStart) { calls.Add(1) })
+	cancel := OnReconnect(s, func() {
+		hs.Reinstall()
+		reconnects.Add(1)
+	})
+	defer cancel()
+
+	if err := s.Open(); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer s.Close()
+	conn := g.next(t)
+	if err := typingStart(conn, 2); err != nil {
+		t.Fatalf("send event: %v", err)
+	}
+	waitCount(t, &calls, 1)
+
+	// Drop the connection: discordgo emits Disconnect, resumes and emits Resumed/Connect.
+	conn.Close()
+	conn = g.next(t)
+	deadline := time.Now().Add(5 * time.Second)
+	for reconnects.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if reconnects.Load() == 0 {
+		t.Fatal("reconnect hook did not run")
+	}
+
+	if err := typingStart(conn, 3); err != nil {
+		t.Fatalf("send event: %v", err)
+	}
+	waitCount(t, &calls, 2)
+	if hs.Len() != 1 {
+		t.Fatalf("tracked handlers = %d, want 1", hs.Len())
+	}
+}
+
+func TestHandlerSetReinstallAfterRemoveAll(t *testing.T) {
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("new session: %v", err)
+	}
+	hs := NewHandlerSet(s)
+	hs.Add(func(_ *discordgo.Session, _ *discordgo.TypingStart) {})
+	hs.Reinstall()
+	hs.Reinstall()
+	if hs.Len() != 1 {
+		t.Fatalf("tracked handlers = %d, want 1", hs.Len())
+	}
+	hs.RemoveAll()
+	hs.Reinstall()
+	if hs.Len() != 0 {
+		t.Fatalf("tracked handlers after RemoveAll = %d, want 0", hs.Len())
+	}
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// Application flags for each privileged intent enabled in the portal.
+const (
+	applicationFlagGatewayPresence              = 1 << 12
+	applicationFlagGatewayPresenceLimited       = 1 << 13
+	applicationFlagGatewayGuildMembers          = 1 << 14
+	applicationFlagGatewayGuildMembersLimited   = 1 << 15
+	applicationFlagGatewayMessageContent        = 1 << 19
+	applicationFlagGatewayMessageContentLimited = 1 << 18
+)
+
+// DefaultIntents are the intents used by the discordcore services.
+const DefaultIntents = discordgo.IntentsGuilds |
+	discordgo.IntentsGuildMembers |
+	discordgo.IntentsGuildPresences |
+	discordgo.IntentsGuildMessages |
+	discordgo.IntentsGuildMessageReactions |
+	discordgo.IntentsGuildVoiceStates |
+	discordgo.IntentAutoModerationConfiguration |
+	discordgo.IntentAutoModerationExecution |
+	discordgo.IntentMessageContent
+
+// privilegedIntents maps each privileged intent to its portal name and flags.
+var privilegedIntents = []struct {
+	intent discordgo.Intent
+	name   string
+	flags  int
+}{
+	{discordgo.IntentsGuildPresences, "Presence Intent", applicationFlagGatewayPresence | applicationFlagGatewayPresenceLimited},
+	{discordgo.IntentsGuildMembers, "Server Members Intent", applicationFlagGatewayGuildMembers | applicationFlagGatewayGuildMembersLimited},
+	{discordgo.IntentMessageContent, "Message Content Intent", applicationFlagGatewayMessageContent | applicationFlagGatewayMessageContentLimited},
+}
+
+// PrivilegedIntentsHint explains how to enable privileged intents.
+const PrivilegedIntentsHint = `open https://discord.com/developers/applications, select the bot's application, go to "Bot" → "Privileged Gateway Intents" and enable them (verified bots in 100+ servers must also be approved by Discord)`
+
+// MissingPrivilegedIntentsError lists requested privileged intents not enabled in the portal.
+type MissingPrivilegedIntentsError struct {
+	Intents discordgo.Intent
+	Names   []string
+}
+
+func (e *MissingPrivilegedIntentsError) Error() string {
+	return fmt.Sprintf("privileged intents not enabled for this application: %s; to fix it, %s", strings.Join(e.Names, ", "), PrivilegedIntentsHint)
+}
+
+// missingPrivilegedIntents returns the privileged intents not enabled for the app, or nil.
+func missingPrivilegedIntents(s *discordgo.Session, intents discordgo.Intent) (*MissingPrivilegedIntentsError, error) {
+	app, err := s.Application("@me")
+	if err != nil {
+		return nil, err
+	}
+	var missing MissingPrivilegedIntentsError
+	for _, p := range privilegedIntents {
+		if intents&p.intent != 0 && app.Flags&p.flags == 0 {
+			missing.Intents |= p.intent
+			missing.Names = append(missing.Names, p.name)
+		}
+	}
+	if missing.Intents == 0 {
+		return nil, nil
+	}
+	return &missing, nil
+}
+
+// checkIntents checks privileged intents before connecting to avoid close 4014.
+func checkIntents(s *discordgo.Session, explicit bool) error {
+	privileged := discordgo.Intent(0)
+	for _, p := range privilegedIntents {
+		privileged |= p.intent
+	}
+	if s.Identify.Intents&privileged == 0 {
+		return nil
+	}
+	missing, err := missingPrivilegedIntents(s, s.Identify.Intents)
+	if err != nil {
+		log.Warn().Discordf("Could not verify the privileged intents (still requesting them): %v", err)
+		return nil
+	}
+	if missing == nil {
+		return nil
+	}
+	if !explicit && missing.Intents == discordgo.IntentMessageContent {
+		gateMessageContent(s)
+		return nil
+	}
+	return missing
+}
+
+// MessageContentHint explains how to enable the MESSAGE_CONTENT intent.
+const MessageContentHint = `open https://discord.com/developers/applications, select the bot's application, go to "Bot" → "Privileged Gateway Intents" and enable "Message Content Intent" (verified bots in 100+ servers must also be approved by Discord)`
+
+// HasMessageContent reports whether the session requests MESSAGE_CONTENT.
+func HasMessageContent(s *discordgo.Session) bool {
+	return s != nil && s.Identify.Intents&discordgo.IntentMessageContent != 0
+}
+
+// MessageContentGranted reports whether MESSAGE_CONTENT is enabled for the app.
+func MessageContentGranted(s *discordgo.Session) (bool, error) {
+	app, err := s.Application("@me")
+	if err != nil {
+		return false, err
+	}
+	return app.Flags&(applicationFlagGatewayMessageContent|applicationFlagGatewayMessageContentLimited) != 0, nil
+}
+
+// gateMessageContent drops the ungranted MESSAGE_CONTENT intent and logs how to enable it.
+func gateMessageContent(s *discordgo.Session) {
+	s.Identify.Intents &^= discordgo.IntentMessageContent
+	log.Error().Errorf("❌ The MESSAGE_CONTENT privileged intent is NOT enabled for this application, so the bot connects without it: message logging will not store content and automod link/duplicate checks won't see text. To fix it, %s, then restart the bot.", MessageContentHint)
+}
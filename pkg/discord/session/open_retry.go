@@ -0,0 +1,188 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// OpenRetryEnv sets the initial connection retry as "attempts[:base[:max]]", e.g. "8:2s:1m".
+const OpenRetryEnv = "DISCORDCORE_OPEN_RETRY"
+
+// OpenRetry defaults.
+const (
+	DefaultOpenAttempts  = 5
+	DefaultOpenBaseDelay = time.Second
+	DefaultOpenMaxDelay  = 30 * time.Second
+)
+
+// Gateway close codes for configuration errors that retrying won't fix.
+const (
+	closeAuthenticationFailed = 4004
+	closeInvalidShard         = 4010
+	closeShardingRequired     = 4011
+	closeInvalidAPIVersion    = 4012
+	closeInvalidIntents       = 4013
+	closeDisallowedIntents    = 4014
+)
+
+// OpenRetry retries transient gateway connection failures with exponential backoff.
+// Auth and intents/shard errors fail at once. The zero value tries once.
+type OpenRetry struct {
+	Attempts  int           // Total attempts (<= 1: no retry)
+	BaseDelay time.Duration // Delay before the 2nd attempt, doubled after each failure
+	MaxDelay  time.Duration // Cap on the delay between attempts
+}
+
+// DefaultOpenRetry returns the Bootstrap's default retry.
+func DefaultOpenRetry() OpenRetry {
+	return OpenRetry{Attempts: DefaultOpenAttempts, BaseDelay: DefaultOpenBaseDelay, MaxDelay: DefaultOpenMaxDelay}
+}
+
+// ParseOpenRetry parses the OpenRetryEnv format; omitted fields use the defaults.
+func ParseOpenRetry(raw string) (OpenRetry, error) {
+	r := DefaultOpenRetry()
+	parts := strings.Split(strings.TrimSpace(raw), ":")
+	if len(parts) > 3 {
+		return r, fmt.Errorf("invalid open retry %q: want attempts[:base[:max]]", raw)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || n < 1 {
+		return r, fmt.Errorf("invalid open retry attempts %q", parts[0])
+	}
+	r.Attempts = n
+	for i, dst := range []*time.Duration{&r.BaseDelay, &r.MaxDelay} {
+		if len(parts) <= i+1 {
+			break
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(parts[i+1]))
+		if err != nil || d <= 0 {
+			return r, fmt.Errorf("invalid open retry delay %q", parts[i+1])
+		}
+		*dst = d
+	}
+	if r.MaxDelay < r.BaseDelay {
+		return r, fmt.Errorf("invalid open retry %q: max delay below base delay", raw)
+	}
+	return r, nil
+}
+
+// openRetryFromEnv reads OpenRetryEnv, falling back to DefaultOpenRetry.
+func openRetryFromEnv() OpenRetry {
+	raw := strings.TrimSpace(os.Getenv(OpenRetryEnv))
+	if raw == "" {
+		return DefaultOpenRetry()
+	}
+	r, err := ParseOpenRetry(raw)
+	if err != nil {
+		log.Warn().Discordf("Ignoring %s: %v (using %d attempts)", OpenRetryEnv, err, DefaultOpenAttempts)
+		return DefaultOpenRetry()
+	}
+	return r
+}
+
+func (r OpenRetry) withDefaults() OpenRetry {
+	if r.Attempts < 1 {
+		r.Attempts = 1
+	}
+	if r.BaseDelay <= 0 {
+		r.BaseDelay = DefaultOpenBaseDelay
+	}
+	if r.MaxDelay <= 0 {
+		r.MaxDelay = DefaultOpenMaxDelay
+	}
+	r.MaxDelay = max(r.MaxDelay, r.BaseDelay)
+	return r
+}
+
+// delay returns the wait after failed attempt (1-based).
+func (r OpenRetry) delay(attempt int) time.Duration {
+	return backoffDelay(r.BaseDelay, r.MaxDelay, attempt)
+}
+
+// backoffDelay doubles base per failed attempt, up to maxDelay.
+func backoffDelay(base, maxDelay time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxDelay)
+}
+
+// IsAuthError reports whether Discord rejected the token (HTTP 401 or close 4004).
+func IsAuthError(err error) bool {
+	if errors.Is(err, discordgo.ErrUnauthorized) {
+		return true
+	}
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	var closeErr *websocket.CloseError
+	return errors.As(err, &closeErr) && closeErr.Code == closeAuthenticationFailed
+}
+
+// IsIntentsError reports whether the gateway rejected the intents (close 4013 or 4014).
+func IsIntentsError(err error) bool {
+	var closeErr *websocket.CloseError
+	return errors.As(err, &closeErr) && (closeErr.Code == closeInvalidIntents || closeErr.Code == closeDisallowedIntents)
+}
+
+// retryableOpenError reports whether a connection retry can help after err.
+func retryableOpenError(err error) bool {
+	if IsAuthError(err) {
+		return false
+	}
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		switch closeErr.Code {
+		case closeInvalidShard, closeShardingRequired, closeInvalidAPIVersion, closeInvalidIntents, closeDisallowedIntents:
+			return false
+		}
+	}
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		// 4xx other than 429 are final
+		code := restErr.Response.StatusCode
+		return code >= 500 || code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// openWithRetry opens s following retry and returns the last error.
+func openWithRetry(s *discordgo.Session, retry OpenRetry) error {
+	retry = retry.withDefaults()
+	var err error
+	for attempt := 1; attempt <= retry.Attempts; attempt++ {
+		log.Info().Discordf("🔗 Connecting to Discord (attempt %d/%d)...", attempt, retry.Attempts)
+		if err = s.Open(); err == nil {
+			return nil
+		}
+		// Clean up the failed attempt before the next one
+		_ = s.Close()
+		if IsAuthError(err) {
+			log.Error().Errorf("❌ Discord rejected the bot token (attempt %d/%d): %v; not retrying", attempt, retry.Attempts, err)
+			return err
+		}
+		if !retryableOpenError(err) {
+			log.Error().Errorf("❌ Failed to connect to Discord (attempt %d/%d): %v; not retrying", attempt, retry.Attempts, err)
+			return err
+		}
+		if attempt == retry.Attempts {
+			log.Error().Errorf("❌ Failed to connect to Discord (attempt %d/%d): %v; giving up", attempt, retry.Attempts, err)
+			break
+		}
+		wait := retry.delay(attempt)
+		log.Warn().Discordf("⚠️ Failed to connect to Discord (attempt %d/%d): %v; retrying in %s", attempt, retry.Attempts, err, wait)
+		time.Sleep(wait)
+	}
+	return err
+}
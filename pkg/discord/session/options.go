@@ -0,0 +1,172 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Environment variables read by SessionOptionsFromEnv. The endpoint ones are for mock tests only.
+const (
+	APIBaseURLEnv     = "DISCORDCORE_API_BASE_URL"
+	GatewayURLEnv     = "DISCORDCORE_GATEWAY_URL"
+	MessageContentEnv = "DISCORDCORE_MESSAGE_CONTENT"
+)
+
+// DefaultAPIBaseURL is the REST base used when SessionOptions.APIBaseURL is empty.
+var DefaultAPIBaseURL = "https://discord.com/api/v" + discordgo.APIVersion + "/"
+
+// SessionOptions configures NewDiscordSessionWithOptions; the zero value matches NewDiscordSession.
+// APIBaseURL and GatewayURL are for tests against a mock server only.
+type SessionOptions struct {
+	// APIBaseURL replaces the REST base process-wide, e.g. "http://127.0.0.1:8080/api/v9/".
+	APIBaseURL string
+	// GatewayURL replaces the websocket URL returned by GET /gateway(/bot).
+	GatewayURL string
+
+	// Intents requested from the gateway (zero: DefaultIntents).
+	Intents discordgo.Intent
+
+	// DisableMessageContent drops the MESSAGE_CONTENT intent.
+	DisableMessageContent bool
+
+	// OpenRetry retries the initial connection (zero: one attempt).
+	OpenRetry OpenRetry
+
+	// RateLimit controls retries of 429 responses.
+	RateLimit RateLimit
+	// RateLimitHook is called on every 429.
+	RateLimitHook RateLimitHook
+
+	// Reconnect controls reconnection after a gateway drop.
+	Reconnect Reconnect
+	// ReconnectHook is called on every reconnect attempt.
+	ReconnectHook ReconnectHook
+}
+
+// Options is short for SessionOptions.
+type Options = SessionOptions
+
+// SessionOptionsFromEnv builds SessionOptions from the environment.
+func SessionOptionsFromEnv() SessionOptions {
+	opts := SessionOptions{
+		APIBaseURL: strings.TrimSpace(os.Getenv(APIBaseURLEnv)),
+		GatewayURL: strings.TrimSpace(os.Getenv(GatewayURLEnv)),
+		OpenRetry:  openRetryFromEnv(),
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(MessageContentEnv))) {
+	case "0", "false", "off":
+		opts.DisableMessageContent = true
+	}
+	return opts
+}
+
+func (o SessionOptions) validate() error {
+	for name, raw := range map[string]string{"api base url": o.APIBaseURL, "gateway url": o.GatewayURL} {
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid %s %q", name, raw)
+		}
+	}
+	return nil
+}
+
+var (
+	endpointsMu         sync.Mutex
+	endpointsOverridden bool
+)
+
+// applyAPIBaseURL points discordgo's global REST endpoints at base, or restores the default.
+func applyAPIBaseURL(base string) {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+	if base == "" {
+		if !endpointsOverridden {
+			return
+		}
+		base = DefaultAPIBaseURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	endpointsOverridden = base != DefaultAPIBaseURL
+
+	discordgo.EndpointAPI = base
+	discordgo.EndpointGuilds = base + "guilds/"
+	discordgo.EndpointChannels = base + "channels/"
+	discordgo.EndpointUsers = base + "users/"
+	discordgo.EndpointGateway = base + "gateway"
+	discordgo.EndpointGatewayBot = discordgo.EndpointGateway + "/bot"
+	discordgo.EndpointWebhooks = base + "webhooks/"
+	discordgo.EndpointStickers = base + "stickers/"
+	discordgo.EndpointStageInstances = base + "stage-instances"
+	discordgo.EndpointSKUs = base + "skus"
+	discordgo.EndpointVoice = base + "/voice/"
+	discordgo.EndpointVoiceRegions = discordgo.EndpointVoice + "regions"
+	discordgo.EndpointNitroStickersPacks = base + "/sticker-packs"
+	discordgo.EndpointGuildCreate = base + "guilds"
+	discordgo.EndpointApplications = base + "applications"
+	discordgo.EndpointOAuth2 = base + "oauth2/"
+	discordgo.EndpointOAuth2Applications = discordgo.EndpointOAuth2 + "applications"
+	discordgo.EndpointOauth2 = discordgo.EndpointOAuth2
+	discordgo.EndpointOauth2Applications = discordgo.EndpointOAuth2Applications
+}
+
+// gatewayOverrideTransport rewrites the url of gateway discovery responses.
+type gatewayOverrideTransport struct {
+	base    http.RoundTripper
+	gateway string
+}
+
+func (t *gatewayOverrideTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	target := req.URL.String()
+	if target != discordgo.EndpointGateway && target != discordgo.EndpointGatewayBot {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]any{}
+	_ = json.Unmarshal(body, &payload) // a mock may not answer JSON
+	payload["url"] = t.gateway
+	if body, err = json.Marshal(payload); err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// applyGatewayURL installs gatewayOverrideTransport on the session client.
+func applyGatewayURL(s *discordgo.Session, gateway string) {
+	if gateway == "" {
+		return
+	}
+	base := http.DefaultTransport
+	if s.Client != nil && s.Client.Transport != nil {
+		base = s.Client.Transport
+	}
+	client := &http.Client{Transport: &gatewayOverrideTransport{base: base, gateway: gateway}}
+	if s.Client != nil {
+		client.Timeout = s.Client.Timeout
+	}
+	s.Client = client
+}
@@ -0,0 +1,249 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// OutageMonitor defaults.
+const (
+	DefaultOutageThreshold     = 10
+	DefaultOutageWindow        = time.Minute
+	DefaultOutageBackoff       = 2 * time.Second
+	DefaultOutageRecoveryQuiet = 2 * time.Minute
+)
+
+// OutageConfig controls when degraded mode starts and how it behaves.
+type OutageConfig struct {
+	Threshold     int           // Failures within Window that trigger degraded mode
+	Window        time.Duration // Sliding window for counting failures
+	Backoff       time.Duration // Delay before each REST call while degraded
+	RecoveryQuiet time.Duration // Failure-free time before a success ends degraded mode
+}
+
+// OutageState is a snapshot of the monitor.
+type OutageState struct {
+	Degraded  bool
+	Since     time.Time // start of the current state
+	Failures  int       // failures within the window
+	LastError string
+}
+
+// OutageMonitor enters degraded mode after Threshold gateway/REST failures within Window.
+// Degraded REST calls wait Backoff; 4xx responses are not failures.
+type OutageMonitor struct {
+	cfg OutageConfig
+
+	mu          sync.Mutex
+	failures    []time.Time
+	degraded    bool
+	since       time.Time
+	lastFailure time.Time
+	lastError   string
+	listeners   map[int]func(OutageState)
+	nextID      int
+}
+
+// NewOutageMonitor creates a monitor, defaulting non-positive fields of cfg.
+func NewOutageMonitor(cfg OutageConfig) *OutageMonitor {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultOutageThreshold
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultOutageWindow
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultOutageBackoff
+	}
+	if cfg.RecoveryQuiet <= 0 {
+		cfg.RecoveryQuiet = DefaultOutageRecoveryQuiet
+	}
+	return &OutageMonitor{cfg: cfg, since: time.Now(), listeners: make(map[int]func(OutageState))}
+}
+
+// Degraded reports whether degraded mode is active. Safe on a nil receiver.
+func (m *OutageMonitor) Degraded() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.degraded
+}
+
+// State returns the current state.
+func (m *OutageMonitor) State() OutageState {
+	if m == nil {
+		return OutageState{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked(time.Now())
+	return m.stateLocked()
+}
+
+// OnChange calls fn in a goroutine on every state change; the returned func removes it.
+func (m *OutageMonitor) OnChange(fn func(OutageState)) func() {
+	if m == nil || fn == nil {
+		return func() {}
+	}
+	m.mu.Lock()
+	id := m.nextID
+	m.nextID++
+	m.listeners[id] = fn
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		delete(m.listeners, id)
+		m.mu.Unlock()
+	}
+}
+
+// RecordFailure counts a gateway/REST failure.
+func (m *OutageMonitor) RecordFailure(err error) {
+	if m == nil {
+		return
+	}
+	now := time.Now()
+	m.mu.Lock()
+	m.failures = append(m.failures, now)
+	m.lastFailure = now
+	if err != nil {
+		m.lastError = err.Error()
+	}
+	m.pruneLocked(now)
+	if m.degraded || len(m.failures) < m.cfg.Threshold {
+		m.mu.Unlock()
+		return
+	}
+	m.degraded, m.since = true, now
+	state, listeners := m.stateLocked(), m.listenersLocked()
+	m.mu.Unlock()
+
+	log.Warn().Discordf("🌩️ Discord outage detected (%d failures in %s, last: %s); backing off and pausing background work", state.Failures, m.cfg.Window, state.LastError)
+	notifyOutage(listeners, state)
+}
+
+// RecordSuccess ends degraded mode once RecoveryQuiet has passed since the last failure.
+func (m *OutageMonitor) RecordSuccess() {
+	if m == nil {
+		return
+	}
+	now := time.Now()
+	m.mu.Lock()
+	if !m.degraded || now.Sub(m.lastFailure) < m.cfg.RecoveryQuiet {
+		m.mu.Unlock()
+		return
+	}
+	duration := now.Sub(m.since)
+	m.degraded, m.since, m.failures, m.lastError = false, now, nil, ""
+	state, listeners := m.stateLocked(), m.listenersLocked()
+	m.mu.Unlock()
+
+	log.Info().Discordf("☀️ Discord connectivity recovered after %s; resuming normal operation", duration.Round(time.Second))
+	notifyOutage(listeners, state)
+}
+
+// Wait sleeps for Backoff while degraded.
+func (m *OutageMonitor) Wait(ctx context.Context) error {
+	if !m.Degraded() {
+		return nil
+	}
+	timer := time.NewTimer(m.cfg.Backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Attach feeds the monitor from s's REST responses and gateway events.
+// The returned func removes the gateway handlers; the transport stays installed.
+func (m *OutageMonitor) Attach(s *discordgo.Session) func() {
+	if m == nil || s == nil {
+		return func() {}
+	}
+	base := http.DefaultTransport
+	if s.Client != nil && s.Client.Transport != nil {
+		base = s.Client.Transport
+	}
+	client := &http.Client{Transport: &outageTransport{base: base, monitor: m}}
+	if s.Client != nil {
+		client.Timeout = s.Client.Timeout
+	}
+	s.Client = client
+
+	cancelDisconnect := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) {
+		m.RecordFailure(fmt.Errorf("gateway disconnected"))
+	})
+	cancelConnect := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Connect) { m.RecordSuccess() })
+	cancelResumed := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Resumed) { m.RecordSuccess() })
+	cancelEvent := s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Event) { m.RecordSuccess() })
+	return func() {
+		cancelDisconnect()
+		cancelConnect()
+		cancelResumed()
+		cancelEvent()
+	}
+}
+
+func (m *OutageMonitor) pruneLocked(now time.Time) {
+	cutoff := now.Add(-m.cfg.Window)
+	i := 0
+	for i < len(m.failures) && m.failures[i].Before(cutoff) {
+		i++
+	}
+	m.failures = m.failures[i:]
+}
+
+func (m *OutageMonitor) stateLocked() OutageState {
+	return OutageState{Degraded: m.degraded, Since: m.since, Failures: len(m.failures), LastError: m.lastError}
+}
+
+func (m *OutageMonitor) listenersLocked() []func(OutageState) {
+	out := make([]func(OutageState), 0, len(m.listeners))
+	for _, fn := range m.listeners {
+		out = append(out, fn)
+	}
+	return out
+}
+
+func notifyOutage(listeners []func(OutageState), state OutageState) {
+	for _, fn := range listeners {
+		go fn(state)
+	}
+}
+
+// outageTransport applies the degraded backoff and classifies REST responses.
+type outageTransport struct {
+	base    http.RoundTripper
+	monitor *OutageMonitor
+}
+
+func (t *outageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.monitor.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil:
+		// Caller cancellations are not outages
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			t.monitor.RecordFailure(err)
+		}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		t.monitor.RecordFailure(fmt.Errorf("%s %s: HTTP %d", req.Method, req.URL.Path, resp.StatusCode))
+	default:
+		t.monitor.RecordSuccess()
+	}
+	return resp, err
+}
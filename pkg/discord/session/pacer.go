@@ -0,0 +1,53 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultPacerInterval is the minimum spacing between paced REST calls.
+// discordgo already honours per-route buckets; the pacer smooths bursts across routes
+// so that fan-out code (e.g. notifying many channels) doesn't trip the global limit.
+const DefaultPacerInterval = 25 * time.Millisecond
+
+// Pacer spaces out calls so that at most one is released per interval.
+type Pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewPacer creates a pacer releasing at most one call per interval.
+// A non-positive interval falls back to DefaultPacerInterval.
+func NewPacer(interval time.Duration) *Pacer {
+	if interval <= 0 {
+		interval = DefaultPacerInterval
+	}
+	return &Pacer{interval: interval}
+}
+
+// Wait blocks until the caller may proceed or ctx is done.
+func (p *Pacer) Wait(ctx context.Context) error {
+	p.mu.Lock()
+	now := time.Now()
+	slot := p.next
+	if slot.Before(now) {
+		slot = now
+	}
+	p.next = slot.Add(p.interval)
+	p.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
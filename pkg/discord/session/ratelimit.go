@@ -0,0 +1,166 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	dcerrors "github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// RateLimit defaults.
+const (
+	DefaultRateLimitRetries = 5
+	DefaultRateLimitMaxWait = time.Minute
+)
+
+// RateLimit controls retries of REST 429 responses, honouring Retry-After.
+// Past MaxRetries or MaxWait the call fails with *discordgo.RateLimitError.
+// Disabled keeps discordgo's unbounded retry.
+type RateLimit struct {
+	Disabled   bool
+	MaxRetries int           // Retries per request (<= 0: DefaultRateLimitRetries)
+	MaxWait    time.Duration // Longest Retry-After honoured (<= 0: DefaultRateLimitMaxWait)
+}
+
+func (r RateLimit) withDefaults() RateLimit {
+	if r.MaxRetries <= 0 {
+		r.MaxRetries = DefaultRateLimitRetries
+	}
+	if r.MaxWait <= 0 {
+		r.MaxWait = DefaultRateLimitMaxWait
+	}
+	return r
+}
+
+// RateLimitHook is called on every 429 with the bucket and the requested wait.
+type RateLimitHook func(bucket string, retryAfter time.Duration)
+
+// applyRateLimit installs rateLimitTransport and turns off discordgo's own 429 retry.
+func applyRateLimit(s *discordgo.Session, policy RateLimit, hook RateLimitHook) {
+	if policy.Disabled {
+		if hook != nil {
+			s.AddHandler(func(_ *discordgo.Session, rl *discordgo.RateLimit) {
+				notifyRateLimit(hook, rl.URL, rl.RetryAfter)
+			})
+		}
+		return
+	}
+	base := http.DefaultTransport
+	if s.Client != nil && s.Client.Transport != nil {
+		base = s.Client.Transport
+	}
+	client := &http.Client{Transport: &rateLimitTransport{base: base, policy: policy.withDefaults(), hook: hook}}
+	if s.Client != nil {
+		client.Timeout = s.Client.Timeout
+	}
+	s.Client = client
+	s.ShouldRetryOnRateLimit = false
+}
+
+// rateLimitTransport retries 429 responses after Retry-After.
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	policy RateLimit
+	hook   RateLimitHook
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		wait, err := retryAfter(resp)
+		if err != nil {
+			return nil, err
+		}
+		bucket := resp.Header.Get("X-RateLimit-Bucket")
+		if bucket == "" {
+			bucket = req.Method + " " + req.URL.Path
+		}
+		notifyRateLimit(t.hook, bucket, wait)
+
+		scope := "bucket " + bucket
+		if resp.Header.Get("X-RateLimit-Global") == "true" {
+			scope = "global limit"
+		}
+		if retry >= t.policy.MaxRetries || wait > t.policy.MaxWait {
+			log.Warn().Discordf("⛔ Rate limited on %s %s (%s, retry after %s); giving up after %d retries", req.Method, req.URL.Path, scope, wait, retry)
+			return resp, nil
+		}
+		next, err := rewindRequest(req)
+		if err != nil {
+			// Body can't be replayed: let discordgo return RateLimitError
+			return resp, nil
+		}
+		resp.Body.Close()
+		log.Warn().Discordf("⏳ Rate limited on %s %s (%s); retrying in %s (%d/%d)", req.Method, req.URL.Path, scope, wait, retry+1, t.policy.MaxRetries)
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// retryAfter reads the wait from a 429 body or header, restoring the body.
+func retryAfter(resp *http.Response) (time.Duration, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return 0, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var rl discordgo.TooManyRequests
+	if json.Unmarshal(body, &rl) == nil && rl.RetryAfter > 0 {
+		return rl.RetryAfter, nil
+	}
+	if secs, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.Second, nil
+}
+
+// rewindRequest clones req with a fresh body.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, http.ErrBodyNotAllowed
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func notifyRateLimit(hook RateLimitHook, bucket string, wait time.Duration) {
+	if hook == nil {
+		return
+	}
+	defer errutil.RecoverPanic(dcerrors.CategoryDiscord, "rate limit hook", nil)
+	hook(bucket, wait)
+}
@@ -0,0 +1,199 @@
+package session
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	dcerrors "github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// Reconnect defaults.
+const (
+	DefaultReconnectBaseDelay = time.Second
+	DefaultReconnectMaxDelay  = 2 * time.Minute
+)
+
+// Reconnect controls reconnection after a connected session drops.
+// The first attempt is immediate, then backoff from BaseDelay to MaxDelay.
+// Disabled keeps discordgo's internal reconnect loop.
+type Reconnect struct {
+	Disabled    bool
+	MaxAttempts int           // Attempts per drop (<= 0: unlimited)
+	BaseDelay   time.Duration // Delay after the 1st failed attempt
+	MaxDelay    time.Duration // Cap on the delay between attempts
+}
+
+func (r Reconnect) withDefaults() Reconnect {
+	if r.BaseDelay <= 0 {
+		r.BaseDelay = DefaultReconnectBaseDelay
+	}
+	if r.MaxDelay <= 0 {
+		r.MaxDelay = DefaultReconnectMaxDelay
+	}
+	r.MaxDelay = max(r.MaxDelay, r.BaseDelay)
+	return r
+}
+
+// ReconnectHook is called after each reconnect attempt; err is nil on success.
+type ReconnectHook func(attempt int, err error)
+
+// reconnector replaces discordgo's reconnect loop for a session.
+type reconnector struct {
+	s      *discordgo.Session
+	policy Reconnect
+	hook   ReconnectHook
+
+	mu      sync.Mutex
+	running bool
+	stopped bool
+	stop    chan struct{}
+	cancel  func()
+}
+
+var (
+	reconnectorsMu sync.Mutex
+	reconnectors   = map[*discordgo.Session]*reconnector{}
+	closeHooks     = map[*discordgo.Session][]func(){}
+)
+
+// OnClose registers fn to run when s is closed with Close.
+func OnClose(s *discordgo.Session, fn func()) {
+	if s == nil || fn == nil {
+		return
+	}
+	reconnectorsMu.Lock()
+	closeHooks[s] = append(closeHooks[s], fn)
+	reconnectorsMu.Unlock()
+}
+
+// enableReconnect installs the reconnector on s.
+func enableReconnect(s *discordgo.Session, policy Reconnect, hook ReconnectHook) {
+	if policy.Disabled {
+		return
+	}
+	r := &reconnector{s: s, policy: policy.withDefaults(), hook: hook, stop: make(chan struct{})}
+	s.ShouldReconnectOnError = false
+	r.cancel = s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) { r.disconnected() })
+
+	reconnectorsMu.Lock()
+	reconnectors[s] = r
+	reconnectorsMu.Unlock()
+}
+
+// Close stops reconnecting and closes s; use it instead of s.Close() on shutdown.
+func Close(s *discordgo.Session) error {
+	if s == nil {
+		return nil
+	}
+	reconnectorsMu.Lock()
+	r := reconnectors[s]
+	delete(reconnectors, s)
+	hooks := closeHooks[s]
+	delete(closeHooks, s)
+	reconnectorsMu.Unlock()
+	if r != nil {
+		r.shutdown()
+	}
+	err := s.Close()
+	for _, fn := range hooks {
+		fn()
+	}
+	return err
+}
+
+func (r *reconnector) shutdown() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+	r.stopped = true
+	r.cancel()
+	close(r.stop)
+}
+
+// disconnected starts the reconnect loop unless one is already running.
+func (r *reconnector) disconnected() {
+	r.mu.Lock()
+	if r.stopped || r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.mu.Unlock()
+
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			r.running = false
+			r.mu.Unlock()
+		}()
+		r.run()
+	}()
+}
+
+func (r *reconnector) run() {
+	limit := "∞"
+	if r.policy.MaxAttempts > 0 {
+		limit = strconv.Itoa(r.policy.MaxAttempts)
+	}
+	for attempt := 1; r.policy.MaxAttempts <= 0 || attempt <= r.policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(backoffDelay(r.policy.BaseDelay, r.policy.MaxDelay, attempt-1))
+			select {
+			case <-r.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		log.Info().Discordf("🔁 Reconnecting to Discord gateway (attempt %d/%s)...", attempt, limit)
+		err := r.s.Open()
+		if errors.Is(err, discordgo.ErrWSAlreadyOpen) {
+			// Already reopened elsewhere
+			return
+		}
+		r.notify(attempt, err)
+		if err == nil {
+			select {
+			case <-r.stop:
+				// Closed during the attempt
+				_ = r.s.Close()
+				return
+			default:
+			}
+			log.Info().Discordf("✅ Reconnected to Discord gateway after %d attempt(s)", attempt)
+			return
+		}
+		// Clean up the failed attempt before the next one
+		_ = r.s.Close()
+		if !retryableOpenError(err) {
+			log.Error().Errorf("❌ Gateway reconnect failed (attempt %d/%s): %v; not retrying", attempt, limit, err)
+			return
+		}
+		if r.policy.MaxAttempts > 0 && attempt == r.policy.MaxAttempts {
+			log.Error().Errorf("❌ Gateway reconnect failed (attempt %d/%s): %v; giving up, the bot stays disconnected", attempt, limit, err)
+			return
+		}
+		log.Warn().Discordf("⚠️ Gateway reconnect failed (attempt %d/%s): %v; retrying in %s", attempt, limit, err, backoffDelay(r.policy.BaseDelay, r.policy.MaxDelay, attempt))
+	}
+}
+
+func (r *reconnector) notify(attempt int, err error) {
+	if r.hook == nil {
+		return
+	}
+	defer errutil.RecoverPanic(dcerrors.CategoryDiscord, "reconnect hook", nil)
+	r.hook(attempt, err)
+}
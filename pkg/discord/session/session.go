@@ -14,8 +14,14 @@ const (
 	ErrSessionConnectionFailed = "failed to connect to Discord: %w"
 )
 
-// NewDiscordSession creates a new Discord session
+// NewDiscordSession creates a new Discord session with the default options (automatic
+// reconnect included; close it with Close).
 func NewDiscordSession(token string) (*discordgo.Session, error) {
+	return NewDiscordSessionWithOptions(token, SessionOptions{})
+}
+
+// NewDiscordSessionWithOptions creates a new Discord session applying opts (see SessionOptions).
+func NewDiscordSessionWithOptions(token string, opts SessionOptions) (*discordgo.Session, error) {
 	var s *discordgo.Session
 
 	// Validate token before creating session
@@ -24,6 +30,14 @@ func NewDiscordSession(token string) (*discordgo.Session, error) {
 		return nil, fmt.Errorf("discord bot token is empty")
 	}
 
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf(ErrSessionCreationFailed, err)
+	}
+	applyAPIBaseURL(opts.APIBaseURL)
+	if opts.APIBaseURL != "" || opts.GatewayURL != "" {
+		log.Warn().Discordf("🧪 Using overridden Discord endpoints (api=%q gateway=%q) — for testing only", opts.APIBaseURL, opts.GatewayURL)
+	}
+
 	// Add detailed logging for session creation
 	log.Info().Discordf("🔑 Creating Discord session (token redacted)")
 
@@ -40,23 +54,28 @@ func NewDiscordSession(token string) (*discordgo.Session, error) {
 	}
 
 	log.Info().Discordf("✅ Discord session created successfully")
-	s.Identify.Intents = discordgo.IntentsGuilds |
-		discordgo.IntentsGuildMembers |
-		discordgo.IntentsGuildPresences |
-		discordgo.IntentsGuildMessages |
-		discordgo.IntentAutoModerationConfiguration |
-		discordgo.IntentAutoModerationExecution |
-		discordgo.IntentMessageContent
+	applyGatewayURL(s, opts.GatewayURL)
+	applyRateLimit(s, opts.RateLimit, opts.RateLimitHook)
+	s.Identify.Intents = DefaultIntents
+	if opts.Intents != 0 {
+		s.Identify.Intents = opts.Intents
+	}
+	if opts.DisableMessageContent {
+		s.Identify.Intents &^= discordgo.IntentMessageContent
+		log.Warn().Discordf("✂️ MESSAGE_CONTENT intent disabled by configuration (%s); message content will not be logged", MessageContentEnv)
+	}
+	if err := checkIntents(s, opts.Intents != 0); err != nil {
+		log.Error().Errorf("❌ %v", err)
+		return nil, fmt.Errorf(ErrSessionConnectionFailed, err)
+	}
 
-	// Add logging for connection
-	log.Info().Discordf("🔗 Connecting to Discord...")
+	// Connect, retrying transient failures
 	if err := errutil.HandleDiscordError("connect", func() error {
-		connectErr := s.Open()
-		if connectErr != nil {
-			log.Error().Errorf("❌ Failed to connect to Discord: %v", connectErr)
-		}
-		return connectErr
+		return openWithRetry(s, opts.OpenRetry)
 	}); err != nil {
+		if IsIntentsError(err) {
+			err = fmt.Errorf("discord rejected the requested gateway intents (%d): %w; check SessionOptions.Intents and, for privileged intents, %s", s.Identify.Intents, err, PrivilegedIntentsHint)
+		}
 		log.Error().Errorf("❌ Error during connection: %v", err)
 		// Clean up session if connection failed
 		if s != nil {
@@ -65,6 +84,7 @@ func NewDiscordSession(token string) (*discordgo.Session, error) {
 		return nil, fmt.Errorf(ErrSessionConnectionFailed, err)
 	}
 
+	enableReconnect(s, opts.Reconnect, opts.ReconnectHook)
 	log.Info().Discordf("✅ Connected to Discord successfully")
 	return s, nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -34,6 +36,26 @@ const (
 	SeverityCritical ErrorSeverity = "critical"
 )
 
+// severityRank orders severities for threshold comparisons.
+var severityRank = map[ErrorSeverity]int{
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// AtLeast reports whether s is at or above min. Unknown severities rank below low.
+func (s ErrorSeverity) AtLeast(min ErrorSeverity) bool {
+	return severityRank[s] >= severityRank[min]
+}
+
+// ParseSeverity converts a config value ("low", "medium", "high", "critical") into a severity.
+func ParseSeverity(v string) (ErrorSeverity, bool) {
+	sev := ErrorSeverity(strings.ToLower(strings.TrimSpace(v)))
+	_, ok := severityRank[sev]
+	return sev, ok
+}
+
 // ErrorAction represents what action should be taken for an error
 type ErrorAction string
 
@@ -74,6 +96,10 @@ func (e ServiceError) Unwrap() error {
 type ErrorHandler struct {
 	logger          *log.Logger
 	notifiers       []ErrorNotifier
+	severityMu      sync.RWMutex
+	severityNotify  []severityNotifier
+	suppress        func(*ServiceError) bool
+	suppressed      uint64
 	retryStrategies map[ErrorCategory]RetryStrategy
 	circuits        map[string]*CircuitBreaker
 }
@@ -83,6 +109,12 @@ type ErrorNotifier interface {
 	NotifyError(ctx context.Context, err *ServiceError) error
 }
 
+// severityNotifier receives every error with severity >= min, with or without ActionNotify.
+type severityNotifier struct {
+	notifier ErrorNotifier
+	min      ErrorSeverity
+}
+
 // RetryStrategy defines retry behavior for different error categories
 type RetryStrategy struct {
 	MaxAttempts int
@@ -143,6 +175,71 @@ func (eh *ErrorHandler) AddNotifier(notifier ErrorNotifier) {
 	eh.notifiers = append(eh.notifiers, notifier)
 }
 
+// AddSeverityNotifier registers a notifier that receives every handled error at or above
+// min, regardless of its actions (e.g. forwarding to an ops channel). It may be called at
+// any time, including after the handler is in use.
+func (eh *ErrorHandler) AddSeverityNotifier(notifier ErrorNotifier, min ErrorSeverity) {
+	if notifier == nil {
+		return
+	}
+	eh.severityMu.Lock()
+	eh.severityNotify = append(eh.severityNotify, severityNotifier{notifier: notifier, min: min})
+	eh.severityMu.Unlock()
+}
+
+// SetSuppressFilter installs a predicate for errors that should be counted but neither
+// logged nor notified (e.g. Discord/network errors while in outage mode). nil removes it.
+func (eh *ErrorHandler) SetSuppressFilter(filter func(*ServiceError) bool) {
+	eh.severityMu.Lock()
+	eh.suppress = filter
+	eh.severityMu.Unlock()
+}
+
+// SuppressedCount returns how many errors the suppress filter has swallowed.
+func (eh *ErrorHandler) SuppressedCount() uint64 {
+	return atomic.LoadUint64(&eh.suppressed)
+}
+
+// shouldSuppress applies the suppress filter, counting the errors it swallows.
+func (eh *ErrorHandler) shouldSuppress(err *ServiceError) bool {
+	eh.severityMu.RLock()
+	filter := eh.suppress
+	eh.severityMu.RUnlock()
+	if filter == nil || !filter(err) {
+		return false
+	}
+	atomic.AddUint64(&eh.suppressed, 1)
+	return true
+}
+
+// Notify forwards an already-logged error to the severity notifiers, without logging it
+// again or running its actions. Used by errutil to surface errors handled outside the
+// service manager.
+func (eh *ErrorHandler) Notify(ctx context.Context, err error) {
+	if eh == nil || err == nil {
+		return
+	}
+	serviceErr := eh.normalizeError(err)
+	if eh.shouldSuppress(serviceErr) {
+		return
+	}
+	eh.notifyBySeverity(ctx, serviceErr)
+}
+
+func (eh *ErrorHandler) notifyBySeverity(ctx context.Context, err *ServiceError) {
+	eh.severityMu.RLock()
+	targets := append([]severityNotifier(nil), eh.severityNotify...)
+	eh.severityMu.RUnlock()
+	for _, t := range targets {
+		if !err.Severity.AtLeast(t.min) {
+			continue
+		}
+		if notifyErr := t.notifier.NotifyError(ctx, err); notifyErr != nil {
+			eh.logger.Error().Errorf("Failed to notify error. Notifier Error: %v", notifyErr)
+		}
+	}
+}
+
 // Handle processes an error according to the unified strategy
 func (eh *ErrorHandler) Handle(ctx context.Context, err error) error {
 	if err == nil {
@@ -150,7 +247,11 @@ func (eh *ErrorHandler) Handle(ctx context.Context, err error) error {
 	}
 
 	serviceErr := eh.normalizeError(err)
+	if eh.shouldSuppress(serviceErr) {
+		return serviceErr
+	}
 	eh.logError(serviceErr)
+	eh.notifyBySeverity(ctx, serviceErr)
 
 	// Execute actions based on error configuration
 	for _, action := range serviceErr.Actions {
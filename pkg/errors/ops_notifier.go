@@ -0,0 +1,258 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// OpsNotifier defaults.
+const (
+	DefaultOpsDedupWindow = 10 * time.Minute
+	DefaultOpsMaxPerHour  = 20
+	opsStackFrames        = 6
+)
+
+// OpsNotifierConfig configures forwarding errors to an ops channel or webhook.
+type OpsNotifierConfig struct {
+	ChannelID   string        // Channel the bot posts alerts to
+	WebhookURL  string        // Instead of a channel: https://discord.com/api/webhooks/{id}/{token}
+	DedupWindow time.Duration // Identical errors within the window are counted, not resent
+	MaxPerHour  int           // Alerts per hour; the excess is counted and reported in the next one
+}
+
+// OpsNotifier is an ErrorNotifier that posts errors as embeds to an ops channel, deduplicated
+// by signature (category, component, operation and message) and rate limited per hour so an
+// error storm doesn't flood the channel. Sending is asynchronous.
+type OpsNotifier struct {
+	session      *discordgo.Session
+	channelID    string
+	webhookID    string
+	webhookToken string
+	dedupWindow  time.Duration
+	maxPerHour   int
+
+	mu         sync.Mutex
+	seen       map[string]*opsSeen
+	windowFrom time.Time
+	sent       int
+	dropped    int
+}
+
+type opsSeen struct {
+	last       time.Time
+	suppressed int
+}
+
+// NewOpsNotifier validates cfg and creates the notifier. A ChannelID or WebhookURL is required.
+func NewOpsNotifier(session *discordgo.Session, cfg OpsNotifierConfig) (*OpsNotifier, error) {
+	n := &OpsNotifier{
+		session:     session,
+		channelID:   cfg.ChannelID,
+		dedupWindow: cfg.DedupWindow,
+		maxPerHour:  cfg.MaxPerHour,
+		seen:        make(map[string]*opsSeen),
+	}
+	if cfg.WebhookURL != "" {
+		id, token, err := parseWebhookURL(cfg.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		n.webhookID, n.webhookToken = id, token
+	}
+	if n.channelID == "" && n.webhookID == "" {
+		return nil, fmt.Errorf("ops notifier requires a channel ID or a webhook URL")
+	}
+	if session == nil {
+		return nil, fmt.Errorf("ops notifier requires a Discord session")
+	}
+	if n.dedupWindow <= 0 {
+		n.dedupWindow = DefaultOpsDedupWindow
+	}
+	if n.maxPerHour <= 0 {
+		n.maxPerHour = DefaultOpsMaxPerHour
+	}
+	return n, nil
+}
+
+// NotifyError implements ErrorNotifier.
+func (n *OpsNotifier) NotifyError(ctx context.Context, err *ServiceError) error {
+	if err == nil {
+		return nil
+	}
+	suppressed, dropped, ok := n.admit(opsSignature(err), time.Now())
+	if !ok {
+		return nil
+	}
+	embed := opsEmbed(err, callerStack(), suppressed, dropped)
+	go n.send(embed)
+	return nil
+}
+
+// admit applies dedup and the hourly limit. It returns how many identical errors were
+// suppressed since this signature's last alert and how many alerts the limit dropped.
+func (n *OpsNotifier) admit(sig string, now time.Time) (suppressed, dropped int, ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if s, exists := n.seen[sig]; exists && now.Sub(s.last) < n.dedupWindow {
+		s.suppressed++
+		return 0, 0, false
+	}
+	if now.Sub(n.windowFrom) >= time.Hour {
+		n.windowFrom, n.sent = now, 0
+	}
+	if n.sent >= n.maxPerHour {
+		n.dropped++
+		return 0, 0, false
+	}
+	n.sent++
+	if s, exists := n.seen[sig]; exists {
+		suppressed = s.suppressed
+	}
+	n.seen[sig] = &opsSeen{last: now}
+	dropped, n.dropped = n.dropped, 0
+
+	// Opportunistically drop signatures outside the window
+	for k, s := range n.seen {
+		if now.Sub(s.last) >= n.dedupWindow && s.suppressed == 0 {
+			delete(n.seen, k)
+		}
+	}
+	return suppressed, dropped, true
+}
+
+func (n *OpsNotifier) send(embed *discordgo.MessageEmbed) {
+	var err error
+	if n.webhookID != "" {
+		_, err = n.session.WebhookExecute(n.webhookID, n.webhookToken, false, &discordgo.WebhookParams{
+			Embeds:          []*discordgo.MessageEmbed{embed},
+			AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+		})
+	} else {
+		_, err = n.session.ChannelMessageSendComplex(n.channelID, &discordgo.MessageSend{
+			Embeds:          []*discordgo.MessageEmbed{embed},
+			AllowedMentions: &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}},
+		})
+	}
+	if err != nil {
+		// Only log: reporting to the ErrorHandler could loop alerts
+		log.Warn().Discordf("Failed to forward error to ops channel: %v", err)
+	}
+}
+
+func opsSignature(err *ServiceError) string {
+	msg := err.Message
+	if len(msg) > 200 {
+		msg = msg[:200]
+	}
+	return string(err.Category) + "|" + err.Component + "|" + err.Operation + "|" + msg
+}
+
+func opsEmbed(err *ServiceError, stack string, suppressed, dropped int) *discordgo.MessageEmbed {
+	color := 0xF1C40F
+	switch err.Severity {
+	case SeverityHigh:
+		color = 0xE67E22
+	case SeverityCritical:
+		color = 0xE74C3C
+	}
+	// Messages, causes and context may carry tokens or webhook URLs: redact them like the logs
+	description := truncateOps(log.Redact(err.Message), 1000)
+	if err.Cause != nil && !strings.Contains(err.Message, err.Cause.Error()) {
+		description += "\n**Cause:** " + truncateOps(log.Redact(err.Cause.Error()), 500)
+	}
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Category", Value: string(err.Category), Inline: true},
+		{Name: "Severity", Value: string(err.Severity), Inline: true},
+		{Name: "Where", Value: fmt.Sprintf("`%s.%s`", err.Component, err.Operation), Inline: true},
+	}
+	if len(err.Context) > 0 {
+		keys := make([]string, 0, len(err.Context))
+		for k := range err.Context {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		lines := make([]string, 0, len(keys))
+		for _, k := range keys {
+			lines = append(lines, fmt.Sprintf("%s: %v", k, err.Context[k]))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Context", Value: truncateOps(log.Redact(strings.Join(lines, "\n")), 1000)})
+	}
+	if stack != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Stack", Value: "```\n" + truncateOps(stack, 900) + "\n```"})
+	}
+	if suppressed > 0 || dropped > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Suppressed",
+			Value: fmt.Sprintf("%d identical since the last alert, %d other alerts over the hourly limit", suppressed, dropped),
+		})
+	}
+	ts := err.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "🚨 Error: " + string(err.Category),
+		Description: description,
+		Color:       color,
+		Fields:      fields,
+		Timestamp:   ts.Format(time.RFC3339),
+	}
+}
+
+// callerStack summarizes the first frames outside this package (the ErrorHandler's caller).
+func callerStack() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var lines []string
+	for {
+		f, more := frames.Next()
+		if !strings.Contains(f.Function, "/pkg/errors.") && !strings.Contains(f.Function, "/pkg/errutil.") && f.Function != "" {
+			file := f.File
+			if i := strings.LastIndex(file, "/pkg/"); i >= 0 {
+				file = file[i+1:]
+			}
+			lines = append(lines, fmt.Sprintf("%s:%d %s", file, f.Line, f.Function[strings.LastIndex(f.Function, "/")+1:]))
+			if len(lines) == opsStackFrames {
+				break
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func truncateOps(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
+
+// parseWebhookURL extracts the ID and token from a Discord webhook URL.
+func parseWebhookURL(raw string) (id, token string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] == "webhooks" {
+			return parts[i+1], parts[i+2], nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid webhook URL: expected .../webhooks/{id}/{token}")
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOpsEmbedRedactsSecrets(t *testing.T) {
+	const token = "MTAwMDAwMDAwMDAwMDAwMDAw.GabcDE.abcdefghijklmnopqrstuvwxyz0123456789"
+	const webhook = "https://discord.com/api/webhooks/123456/s3cr3t-webhook-token"
+	err := &ServiceError{
+		Category:  CategoryDiscord,
+		Severity:  SeverityHigh,
+		Message:   "request failed with Authorization: Bot " + token,
+		Component: "notifier",
+		Operation: "send",
+		Cause:     fmt.Errorf("POST %s: 401", webhook),
+		Context:   map[string]any{"password": "hunter2", "url": webhook},
+	}
+	embed := opsEmbed(err, "", 0, 0)
+
+	var all strings.Builder
+	all.WriteString(embed.Title + "\n" + embed.Description)
+	for _, f := range embed.Fields {
+		all.WriteString("\n" + f.Value)
+	}
+	text := all.String()
+	for _, secret := range []string{token, "s3cr3t-webhook-token", "hunter2"} {
+		if strings.Contains(text, secret) {
+			t.Errorf("ops embed leaks %q:\n%s", secret, text)
+		}
+	}
+	if !strings.Contains(text, "[REDACTED") {
+		t.Errorf("ops embed has no redaction marker:\n%s", text)
+	}
+}
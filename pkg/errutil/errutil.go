@@ -1,9 +1,11 @@
 package errutil
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
+	"github.com/small-frappuccino/discordcore/pkg/errors"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 )
 
@@ -18,10 +20,30 @@ import (
 // and return a wrapped/formatted error where appropriate.
 
 var (
-	mu     sync.RWMutex
-	logger *log.Logger
+	mu      sync.RWMutex
+	logger  *log.Logger
+	handler *errors.ErrorHandler
 )
 
+// SetErrorHandler makes the helpers forward the errors they log to the handler's severity
+// notifiers (e.g. the ops channel), categorized as Discord or config errors. nil disables it.
+func SetErrorHandler(eh *errors.ErrorHandler) {
+	mu.Lock()
+	handler = eh
+	mu.Unlock()
+}
+
+// forward passes an already logged error to the configured ErrorHandler's notifiers, if any.
+func forward(category errors.ErrorCategory, severity errors.ErrorSeverity, component, operation string, err error) {
+	mu.RLock()
+	eh := handler
+	mu.RUnlock()
+	if eh == nil {
+		return
+	}
+	eh.Notify(context.Background(), errors.NewServiceError(category, severity, component, operation, err.Error(), err))
+}
+
 // InitializeGlobalErrorHandler sets the package-level logger used by the error helpers.
 // It is safe to call multiple times; the last non-nil logger wins.
 // Returns an error if the supplied logger is nil.
@@ -58,6 +80,7 @@ func HandleDiscordError(operation string, fn func() error) error {
 		// This ensures some logging even if InitializeGlobalErrorHandler wasn't called.
 		log.Error().Errorf("Discord operation failed: %s, Error: %v", operation, err)
 	}
+	forward(errors.CategoryDiscord, errors.SeverityHigh, "discord", operation, err)
 
 	return err
 }
@@ -83,6 +106,7 @@ func HandleConfigError(operation, path string, fn func() error) error {
 	} else {
 		log.Error().Errorf("Config operation failed: %s %s, Error: %v", operation, path, err)
 	}
+	forward(errors.CategoryConfig, errors.SeverityHigh, "config", operation+" "+path, err)
 
 	return fmt.Errorf("config %s %s: %w", operation, path, err)
 }
@@ -0,0 +1,37 @@
+package errutil
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+var recoveredPanics atomic.Int64
+
+// RecoverPanic recovers a panic in progress; it must be deferred directly:
+//
+//	defer errutil.RecoverPanic(errors.CategoryCommand, "/admin metrics", nil)
+//
+// The panic is logged with its stack trace, counted in RecoveredPanics and reported as critical
+// to the configured ErrorHandler's notifiers. onPanic, if not nil, receives the recovered value
+// (e.g. to turn it into an error or answer the user).
+func RecoverPanic(category errors.ErrorCategory, operation string, onPanic func(recovered any)) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	recoveredPanics.Add(1)
+	log.Error().Errorf("Recovered panic: category=%s, operation=%s, panic=%v\n%s", category, operation, r, debug.Stack())
+	forward(category, errors.SeverityCritical, string(category), operation, fmt.Errorf("panic: %v", r))
+	if onPanic != nil {
+		onPanic(r)
+	}
+}
+
+// RecoveredPanics returns how many panics RecoverPanic has recovered since the process started.
+func RecoveredPanics() int64 {
+	return recoveredPanics.Load()
+}
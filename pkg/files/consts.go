@@ -32,7 +32,7 @@ const (
 	ErrNoSuitableChannelMsg = "no suitable channel found in guild %s"
 	ErrChannelNotFound      = "channel not found"
 	ErrChannelWrongGuild    = "channel does not belong to this guild"
-	ErrChannelWrongType     = "channel must be a text channel"
+	ErrChannelWrongType     = "channel must be a text channel or thread"
 	ErrChannelNoPermissions = "bot lacks permissions to send messages in channel"
 
 	// General errors
@@ -64,6 +64,9 @@ const (
 	LogNoConfiguredGuilds    = "No configured guilds. Use /setup to configure."
 	LogGuildNotAccessible    = "Guild not accessible; skipping"
 	LogFoundConfiguredGuilds = "%d configured guild(s) found"
+	LogWaitingForGuilds      = "⏳ Waiting up to %s for %d unavailable guild(s) to load"
+	LogGuildStillUnavailable = "Guild still unavailable after the startup wait (not loaded yet or Discord outage); will monitor once it arrives"
+	LogGuildBecameAvailable  = "🔎 Guild became available; will monitor it"
 
 	// Specific loading and saving logs
 	LogLoadConfigFailedJoinPaths   = "Failed to join paths: %s, error: %v"
@@ -0,0 +1,70 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// BotOperatorsEnv lists comma-separated bot operator IDs, added to bot_operators.
+const BotOperatorsEnv = "DISCORDCORE_BOT_OPERATORS"
+
+// isSnowflake accepts Discord IDs: 17 to 20 digits.
+func isSnowflake(id string) bool {
+	if len(id) < 17 || len(id) > 20 {
+		return false
+	}
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// BotOperators returns the bot operators from bot_operators and BotOperatorsEnv, without
+// duplicates. IDs that are not valid snowflakes are an error.
+func (mgr *ConfigManager) BotOperators() ([]string, error) {
+	var raw []string
+	mgr.mu.RLock()
+	if mgr.config != nil {
+		raw = append(raw, mgr.config.BotOperators...)
+	}
+	mgr.mu.RUnlock()
+	if env := os.Getenv(BotOperatorsEnv); env != "" {
+		raw = append(raw, strings.Split(env, ",")...)
+	}
+
+	return normalizeOperatorIDs(raw)
+}
+
+// normalizeOperatorIDs trims spaces and drops empty and duplicate IDs, rejecting invalid ones.
+func normalizeOperatorIDs(raw []string) ([]string, error) {
+	out := make([]string, 0, len(raw))
+	for _, id := range raw {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !isSnowflake(id) {
+			return nil, fmt.Errorf("invalid bot operator ID %q (from bot_operators or %s)", id, BotOperatorsEnv)
+		}
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+// IsBotOperator reports whether userID is a bot operator. An invalid list authorizes nobody.
+func (mgr *ConfigManager) IsBotOperator(userID string) bool {
+	if mgr == nil || userID == "" {
+		return false
+	}
+	operators, err := mgr.BotOperators()
+	if err != nil {
+		return false
+	}
+	return slices.Contains(operators, userID)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/small-frappuccino/discordcore/pkg/errutil"
@@ -16,6 +17,8 @@ import (
 
 // --- Initialization & Persistence ---
 
+// NewConfigManager creates a configuration manager for the default settings path
+// (the local development settings file when util.IsDevMode()).
 func NewConfigManager() *ConfigManager {
 	configFilePath := util.GetSettingsFilePath()
 	return &ConfigManager{
@@ -40,9 +43,13 @@ func (mgr *ConfigManager) LoadConfig() error {
 
 	if mgr.config == nil {
 		mgr.config = &BotConfig{Guilds: []GuildConfig{}}
+		mgr.rebuildGuildIndexLocked()
 	}
 
-	err := mgr.jsonManager.Load(mgr.config)
+	// Decode into a fresh value and swap config+index together so readers never
+	// observe a slice and an index that disagree.
+	loaded := &BotConfig{Guilds: []GuildConfig{}}
+	err := mgr.jsonManager.Load(loaded)
 	if err != nil {
 		if os.IsNotExist(err) {
 			log.Info().Applicationf(LogLoadConfigFileNotFound, mgr.configFilePath)
@@ -50,6 +57,9 @@ func (mgr *ConfigManager) LoadConfig() error {
 		}
 		return errutil.HandleConfigError("read", mgr.configFilePath, func() error { return err })
 	}
+	mgr.config = loaded
+	mgr.rebuildGuildIndexLocked()
+	mgr.recordStamp()
 
 	if len(mgr.config.Guilds) == 0 {
 		log.Info().Applicationf(LogLoadConfigNoGuilds, mgr.configFilePath)
@@ -62,7 +72,11 @@ func (mgr *ConfigManager) LoadConfig() error {
 func (mgr *ConfigManager) SaveConfig() error {
 	mgr.mu.RLock()
 	defer mgr.mu.RUnlock()
+	return mgr.saveConfigLocked()
+}
 
+// saveConfigLocked persists the configuration. Caller must hold mgr.mu (read or write).
+func (mgr *ConfigManager) saveConfigLocked() error {
 	if mgr.config == nil {
 		return errors.New(ErrCannotSaveNilConfig)
 	}
@@ -71,6 +85,7 @@ func (mgr *ConfigManager) SaveConfig() error {
 	if err != nil {
 		return errutil.HandleConfigError("write", mgr.configFilePath, func() error { return err })
 	}
+	mgr.recordStamp()
 
 	log.Info().Applicationf(LogSaveConfigSuccess, mgr.configFilePath)
 	return nil
@@ -97,21 +112,163 @@ func (mgr *ConfigManager) HasAnyGuilds() bool {
 
 // --- Guild Config Management ---
 
-// GuildConfig returns the configuration for a specific guild.
-func (mgr *ConfigManager) GuildConfig(guildID string) *GuildConfig {
+// GuildConfig returns a copy of the configuration for a specific guild.
+// The boolean reports whether the guild is configured.
+func (mgr *ConfigManager) GuildConfig(guildID string) (GuildConfig, bool) {
 	mgr.mu.RLock()
 	defer mgr.mu.RUnlock()
-	if mgr.config == nil {
+	gc := mgr.guildConfigLocked(guildID)
+	if gc == nil {
+		return GuildConfig{}, false
+	}
+	return *gc, true
+}
+
+// GuildConfigOrDefault returns the configuration for a specific guild or a zero-valued
+// default (only GuildID set) when the guild is not configured.
+func (mgr *ConfigManager) GuildConfigOrDefault(guildID string) GuildConfig {
+	if gc, ok := mgr.GuildConfig(guildID); ok {
+		return gc
+	}
+	return GuildConfig{GuildID: guildID}
+}
+
+// Guilds returns a snapshot of the active configured guilds, safe to iterate without holding
+// locks. Guilds deactivated after the bot was removed (see GuildConfig.Inactive) are skipped,
+// so per-guild processing (refreshes, scans) stops for them; AllGuilds includes them.
+func (mgr *ConfigManager) Guilds() []GuildConfig {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.config == nil || len(mgr.config.Guilds) == 0 {
 		return nil
 	}
-	for i := range mgr.config.Guilds {
-		if mgr.config.Guilds[i].GuildID == guildID {
-			return &mgr.config.Guilds[i]
+	out := make([]GuildConfig, 0, len(mgr.config.Guilds))
+	for _, g := range mgr.config.Guilds {
+		if !g.Inactive {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// AllGuilds returns a snapshot of every configured guild, including inactive ones.
+func (mgr *ConfigManager) AllGuilds() []GuildConfig {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.config == nil || len(mgr.config.Guilds) == 0 {
+		return nil
+	}
+	out := make([]GuildConfig, len(mgr.config.Guilds))
+	copy(out, mgr.config.Guilds)
+	return out
+}
+
+// InactiveGuilds returns the guilds deactivated after the bot was removed from them.
+func (mgr *ConfigManager) InactiveGuilds() []GuildConfig {
+	var out []GuildConfig
+	for _, g := range mgr.AllGuilds() {
+		if g.Inactive {
+			out = append(out, g)
 		}
 	}
+	return out
+}
+
+// SetGuildActive marks the guild active or inactive and saves the config. Deactivating records
+// the time in InactiveSince; reactivating clears it.
+func (mgr *ConfigManager) SetGuildActive(guildID string, active bool) error {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	gcfg := mgr.guildConfigLocked(guildID)
+	if gcfg == nil {
+		return fmt.Errorf("guild not found")
+	}
+	if gcfg.Inactive == !active {
+		return nil
+	}
+	gcfg.Inactive = !active
+	gcfg.InactiveSince = ""
+	if !active {
+		gcfg.InactiveSince = time.Now().UTC().Format(time.RFC3339)
+	}
+	return mgr.saveConfigLocked()
+}
+
+// UpdateGuild applies mutator to a copy of the guild config, validates the result and only then
+// publishes and saves it, all under mgr.mu, so concurrent commands are serialized and none
+// overwrites another's change. If the mutator or validation fails nothing changes; if the save
+// fails the previous entry is restored. The mutator cannot change GuildID.
+func (mgr *ConfigManager) UpdateGuild(guildID string, mutator func(*GuildConfig) error) error {
+	if mutator == nil {
+		return fmt.Errorf("nil mutator")
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	live := mgr.guildConfigLocked(guildID)
+	if live == nil {
+		return fmt.Errorf("guild not found")
+	}
+	draft, err := cloneGuildConfig(*live)
+	if err != nil {
+		return fmt.Errorf("copy guild config: %w", err)
+	}
+	if err := mutator(&draft); err != nil {
+		return err
+	}
+	if draft.GuildID != guildID {
+		return NewValidationError("guild_id", draft.GuildID, "cannot be changed")
+	}
+	if err := draft.Validate(); err != nil {
+		return err
+	}
+
+	previous := *live
+	*live = draft
+	if err := mgr.saveConfigLocked(); err != nil {
+		*live = previous
+		return err
+	}
 	return nil
 }
 
+// cloneGuildConfig deep-copies a guild config (slices, maps and sub-configs) so UpdateGuild's
+// mutator cannot touch the published entry before validation.
+func cloneGuildConfig(gc GuildConfig) (GuildConfig, error) {
+	var out GuildConfig
+	data, err := json.Marshal(gc)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// guildConfigLocked returns a pointer to the live guild entry. Caller must hold mgr.mu.
+func (mgr *ConfigManager) guildConfigLocked(guildID string) *GuildConfig {
+	if mgr.config == nil {
+		return nil
+	}
+	return mgr.guildIndex[guildID]
+}
+
+// rebuildGuildIndexLocked rebuilds the guildID -> *GuildConfig index from the current slice.
+// Call it (with mgr.mu write-locked) whenever config.Guilds is replaced or appended to, since
+// the pointers refer to the slice's backing array.
+func (mgr *ConfigManager) rebuildGuildIndexLocked() {
+	if mgr.config == nil {
+		mgr.guildIndex = nil
+		return
+	}
+	idx := make(map[string]*GuildConfig, len(mgr.config.Guilds))
+	for i := range mgr.config.Guilds {
+		idx[mgr.config.Guilds[i].GuildID] = &mgr.config.Guilds[i]
+	}
+	mgr.guildIndex = idx
+}
+
 // AddGuildConfig adds or replaces a guild configuration.
 func (mgr *ConfigManager) AddGuildConfig(guildCfg GuildConfig) error {
 	mgr.mu.Lock()
@@ -126,6 +283,7 @@ func (mgr *ConfigManager) AddGuildConfig(guildCfg GuildConfig) error {
 		}
 	}
 	mgr.config.Guilds = append(guilds, guildCfg)
+	mgr.rebuildGuildIndexLocked()
 	return nil
 }
 
@@ -143,6 +301,7 @@ func (mgr *ConfigManager) RemoveGuildConfig(guildID string) {
 		}
 	}
 	mgr.config.Guilds = guilds
+	mgr.rebuildGuildIndexLocked()
 }
 
 // --- Guild Detection & Addition ---
@@ -154,6 +313,7 @@ func (mgr *ConfigManager) DetectGuilds(session *discordgo.Session) error {
 		mgr.config = &BotConfig{Guilds: []GuildConfig{}}
 	}
 	mgr.config.Guilds = []GuildConfig{}
+	mgr.rebuildGuildIndexLocked()
 	mgr.mu.Unlock()
 
 	for _, g := range session.State.Guilds {
@@ -183,6 +343,7 @@ func (mgr *ConfigManager) DetectGuilds(session *discordgo.Session) error {
 		}
 		mgr.mu.Lock()
 		mgr.config.Guilds = append(mgr.config.Guilds, guildCfg)
+		mgr.rebuildGuildIndexLocked()
 		mgr.mu.Unlock()
 		log.Info().Applicationf("Guild added: %s (%s) with channel %s", fullGuild.Name, g.ID, channelID)
 	}
@@ -203,14 +364,12 @@ func (mgr *ConfigManager) RegisterGuild(session *discordgo.Session, guildID stri
 		mgr.mu.Unlock()
 	} else {
 		mgr.mu.RLock()
-		for _, g := range mgr.config.Guilds {
-			if g.GuildID == guildID {
-				mgr.mu.RUnlock()
-				log.Info().Applicationf("Guild %s already configured, skipping", guildID)
-				return nil
-			}
-		}
+		exists := mgr.guildConfigLocked(guildID) != nil
 		mgr.mu.RUnlock()
+		if exists {
+			log.Info().Applicationf("Guild %s already configured, skipping", guildID)
+			return nil
+		}
 	}
 	guild, err := session.Guild(guildID)
 	if err != nil {
@@ -235,6 +394,7 @@ func (mgr *ConfigManager) RegisterGuild(session *discordgo.Session, guildID stri
 	}
 	mgr.mu.Lock()
 	mgr.config.Guilds = append(mgr.config.Guilds, guildCfg)
+	mgr.rebuildGuildIndexLocked()
 	mgr.mu.Unlock()
 	channelName := channelID
 	if ch, err := session.Channel(channelID); err == nil {
@@ -248,11 +408,11 @@ func (mgr *ConfigManager) RegisterGuild(session *discordgo.Session, guildID stri
 
 // ShowConfiguredGuilds logs the configured guilds (no active guild concept).
 func ShowConfiguredGuilds(s *discordgo.Session, configManager *ConfigManager) {
-	configuration := configManager.Config()
-	if configuration == nil || len(configuration.Guilds) == 0 {
+	guilds := configManager.Guilds()
+	if len(guilds) == 0 {
 		return
 	}
-	for _, guildConfig := range configuration.Guilds {
+	for _, guildConfig := range guilds {
 		if guild, err := s.Guild(guildConfig.GuildID); err == nil {
 			log.Info().Applicationf("%s: %s (%s)", LogMonitorGuild, guild.Name, guild.ID)
 		} else {
@@ -412,14 +572,19 @@ func ValidateChannel(session *discordgo.Session, guildID, channelID string) erro
 	if channel.GuildID != guildID {
 		return errors.New(ErrChannelWrongGuild)
 	}
-	if channel.Type != discordgo.ChannelTypeGuildText {
+	// Threads are valid log destinations too
+	if channel.Type != discordgo.ChannelTypeGuildText && !channel.IsThread() {
 		return errors.New(ErrChannelWrongType)
 	}
 	permissions, err := session.UserChannelPermissions(session.State.User.ID, channelID)
 	if err != nil {
 		return fmt.Errorf(ErrFailedCheckPerms, err)
 	}
-	if (permissions & discordgo.PermissionSendMessages) == 0 {
+	required := int64(discordgo.PermissionSendMessages)
+	if channel.IsThread() {
+		required = discordgo.PermissionSendMessagesInThreads
+	}
+	if (permissions & required) == 0 {
 		return errors.New(ErrChannelNoPermissions)
 	}
 	return nil
@@ -446,8 +611,9 @@ func EnsureSettingsFile() error {
 	if err := os.MkdirAll(util.ApplicationSupportPath, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
-	// Ensure preferences subdirectory (explicit layout: ~/.config/<BotName>/preferences/settings.json)
-	preferencesDir := filepath.Join(util.ApplicationSupportPath, "preferences")
+	// Ensure preferences subdirectory (explicit layout: ~/.config/<BotName>/preferences/settings.json;
+	// in development mode, the directory of the local settings file)
+	preferencesDir := filepath.Dir(util.GetSettingsFilePath())
 	if err := os.MkdirAll(preferencesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create preferences directory: %w", err)
 	}
@@ -587,30 +753,6 @@ func SaveSettingsFile(config *BotConfig) error {
 // - Throttled saves
 // - Guild-specific operations
 
-// LogConfiguredGuilds logs a summary of configured guilds. Returns error if any guilds are inaccessible.
-func LogConfiguredGuilds(configManager *ConfigManager, session *discordgo.Session) error {
-	cfg := configManager.Config()
-	if cfg == nil || len(cfg.Guilds) == 0 {
-		log.Warn().Applicationf(LogNoConfiguredGuilds)
-		return nil
-	}
-	log.Info().Applicationf(LogFoundConfiguredGuilds, len(cfg.Guilds))
-	var errCount int
-	for _, g := range cfg.Guilds {
-		guild, err := session.Guild(g.GuildID)
-		if err == nil {
-			log.Info().Applicationf("🔎 Will monitor this guild: %s (%s)", guild.Name, guild.ID)
-		} else {
-			log.Warn().Applicationf("%s: %s", LogGuildNotAccessible, g.GuildID)
-			errCount++
-		}
-	}
-	if errCount > 0 {
-		return fmt.Errorf(ErrGuildsNotAccessible, errCount)
-	}
-	return nil
-}
-
 // FindRulesetByID searches for a ruleset by its ID in the guild configuration.
 func (cfg *GuildConfig) FindRulesetByID(id string) (*Ruleset, int) {
 	for idx, rs := range cfg.Rulesets {
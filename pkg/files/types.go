@@ -3,6 +3,10 @@ package files
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +29,104 @@ type GuildConfig struct {
 	LooseLists              []Rule    `json:"loose_rules,omitempty"` // Regras soltas, não associadas a nenhuma ruleset
 	Blocklist               []string  `json:"blocklist,omitempty"`
 
+	// Filtros de canais para logging de mensagens (consultados antes de persistir)
+	MessageLogAllowedChannels   []string `json:"message_log_allowed_channels,omitempty"`   // Se definido, apenas estes canais são registrados
+	MessageLogIgnoredChannels   []string `json:"message_log_ignored_channels,omitempty"`   // Canais excluídos quando não há allowlist
+	MessageLogIgnoredCategories []string `json:"message_log_ignored_categories,omitempty"` // Categorias inteiras excluídas
+
+	// Metadados de anexos e resumos de embeds são sempre gravados com a mensagem; com attachment_archive
+	// habilitado, os bytes dos anexos também são arquivados (até o limite de tamanho)
+	AttachmentArchive *AttachmentArchiveConfig `json:"attachment_archive,omitempty"`
+
+	// Modo privacidade do armazenamento de mensagens: texto, anexos e embeds não são gravados, só os
+	// metadados listados em message_privacy.metadata (ver MessagePrivacyConfig)
+	MessagePrivacy *MessagePrivacyConfig `json:"message_privacy,omitempty"`
+
+	// Isenções de automod (consultadas a cada evento, então alterações valem sem reiniciar)
+	AutomodExemptRoles               []string `json:"automod_exempt_roles,omitempty"`                // Membros com qualquer um destes cargos são ignorados
+	AutomodExemptChannels            []string `json:"automod_exempt_channels,omitempty"`             // Canais (e threads deles) ignorados
+	AutomodDisableImplicitExemptions bool     `json:"automod_disable_implicit_exemptions,omitempty"` // Por padrão, dono e membros com MANAGE_MESSAGES são isentos
+	AutomodSyncNativeExemptions      bool     `json:"automod_sync_native_exemptions,omitempty"`      // Mescla as isenções nas regras nativas do AutoMod ao iniciar
+
+	// Normalização do conteúdo nas verificações locais de automod (spam e links). AutomodLanguage é uma
+	// dica de idioma (ex.: "en", "pt-BR"): idiomas de alfabeto latino ativam também a remoção de acentos e
+	// a troca de homóglifos cirílicos/gregos; vazio usa só o conjunto seguro, agnóstico de idioma.
+	// AutomodNormalization, se definido, substitui a lista de passes (ver logging.Normalize*).
+	AutomodLanguage      string   `json:"automod_language,omitempty"`
+	AutomodNormalization []string `json:"automod_normalization,omitempty"`
+
+	// Cooldown de ações de automod por usuário (ex.: "2m"): depois de agir sobre um membro, novas detecções
+	// locais (spam, links) dentro da janela só removem o conteúdo, sem timeout, DM ou notificação. Vazio desativa.
+	AutomodActionCooldown string `json:"automod_action_cooldown,omitempty"`
+
+	// Dry-run das detecções locais (spam, links, anexos): as regras são avaliadas e registradas em
+	// automod_actions como simuladas, com notificação "[DRY RUN]", mas nada é removido nem punido.
+	// Também configurável por detecção (automod_spam.dry_run, automod_links.dry_run).
+	AutomodDryRun bool `json:"automod_dry_run,omitempty"`
+
+	// Shadow-delete das detecções locais: a mensagem é removida em silêncio (sem timeout, DM nem
+	// notificação, para não alertar spammers) e a ação só fica em automod_actions como
+	// "shadow_delete"; eventos do AutoMod nativo também não notificam. Configurável por canal
+	// (automod_channel_overrides[].shadow). O dry-run tem precedência.
+	AutomodShadow bool `json:"automod_shadow,omitempty"`
+
+	// Detecção de spam por taxa (flood e mensagens repetidas); lida a cada mensagem, então vale sem reiniciar
+	AutomodSpam *AutomodSpamConfig `json:"automod_spam,omitempty"`
+	// DM ao usuário afetado quando o automod age (bloqueio, remoção ou timeout)
+	AutomodDM *AutomodDMConfig `json:"automod_dm,omitempty"`
+
+	// Varredura de links e anexos (listas lidas a cada mensagem, então valem sem reiniciar)
+	AutomodLinks *AutomodLinkConfig `json:"automod_links,omitempty"`
+
+	// Ajustes de automod por canal, aplicados sobre as regras da guild (ver AutomodForChannel)
+	AutomodChannelOverrides []AutomodChannelOverride `json:"automod_channel_overrides,omitempty"`
+
+	// Escalonamento dos timeouts do automod para reincidentes, com perdão após um período sem ofensas
+	AutomodEscalation *AutomodEscalationConfig `json:"automod_escalation,omitempty"`
+
+	// Mensagens de boas-vindas e despedida (lidas a cada evento, então valem sem reiniciar)
+	Greeter *GreeterConfig `json:"greeter,omitempty"`
+
+	// Cargos atribuídos automaticamente a novos membros (lidos a cada entrada, então valem sem reiniciar)
+	Autorole *AutoroleConfig `json:"autorole,omitempty"`
+
+	// Relatório periódico de atividade (mensagens por canal, emojis, entradas, automod) postado num canal
+	ActivityReport *ActivityReportConfig `json:"activity_report,omitempty"`
+
+	// Escopo do refresh de avatares (startup e verificação periódica); sem configuração, todos os membros
+	AvatarRefresh *AvatarRefreshConfig `json:"avatar_refresh,omitempty"`
+	// Notificações de troca de avatar: uma por troca (padrão) ou resumo periódico
+	AvatarNotifications *AvatarNotificationConfig `json:"avatar_notifications,omitempty"`
+
+	// Reaction logging (alto volume; desativado por padrão). Requer o intent GUILD_MESSAGE_REACTIONS.
+	ReactionLogEnabled bool `json:"reaction_log_enabled,omitempty"`
+	// Voice logging (sessões de voz com duração). Requer o intent GUILD_VOICE_STATES.
+	VoiceLogEnabled bool `json:"voice_log_enabled,omitempty"`
+	// Estatísticas de uso de emojis e figurinhas customizados (contadores diários por guild). Requer MESSAGE_CONTENT.
+	EmojiStatsEnabled bool `json:"emoji_stats_enabled,omitempty"`
+
+	// Formatação dos embeds: Locale define separadores de milhar/decimal (ex.: "pt-BR", "en"; padrão "en")
+	// e Timezone o fuso IANA das datas absolutas (ex.: "America/Sao_Paulo"; padrão UTC). Ver util.FormatInt/FormatDisplayTime.
+	Locale   string `json:"locale,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+
+	// Menções por tipo de evento (chave: NotificationEvent*, valor: ID do cargo ou MentionNone).
+	// Eventos ausentes nunca mencionam ninguém; menções no conteúdo dos embeds nunca disparam pings.
+	NotificationMentions map[string]string `json:"notification_mentions,omitempty"`
+
+	// Comandos desativados nesta guild: nome de topo ("stats") ou caminho de subcomando
+	// ("admin emoji-stats"). Comandos afetados passam a ser registrados por guild, só onde estão ativos.
+	DisabledCommands []string `json:"disabled_commands,omitempty"`
+
+	// Roles de admin da guild: liberam os comandos restritos por core.RequirePermissions/RequireRoles
+	// (ex.: /admin), além de quem tem as permissões exigidas.
+	AdminRoles []string `json:"admin_roles,omitempty"`
+
+	// Marcada quando o bot é removido da guild com guild_removal_policy "deactivate": a guild sai de
+	// ConfigManager.Guilds() (refreshes e scans param) até ser reativada (/admin inactive-guilds ou ao voltar).
+	Inactive      bool   `json:"inactive,omitempty"`
+	InactiveSince string `json:"inactive_since,omitempty"` // RFC3339
+
 	// Cache TTL configuration (per-guild tuning)
 	RolesCacheTTL   string `json:"roles_cache_ttl,omitempty"`   // Ex.: "5m", "1h" (padrão: "5m")
 	MemberCacheTTL  string `json:"member_cache_ttl,omitempty"`  // Ex.: "5m", "10m" (padrão: "5m")
@@ -36,15 +138,151 @@ type GuildConfig struct {
 type BotConfig struct {
 	Guilds      []GuildConfig `json:"guilds"`
 	ActiveGuild string        `json:"active_guild,omitempty"`
+
+	// Número máximo de guilds processadas em paralelo no refresh silencioso de startup,
+	// no scan periódico de avatares e no refresh de roles (padrão: DefaultRefreshConcurrency).
+	// As chamadas continuam passando pelo rate limiter compartilhado da sessão.
+	RefreshConcurrency int `json:"refresh_concurrency,omitempty"`
+
+	// Número máximo de interações (slash commands e componentes) executando ao mesmo tempo; as
+	// excedentes esperam uma vaga por pouco tempo e depois são recusadas com um aviso efêmero.
+	// 0 usa DefaultMaxInFlightCommands, negativo desativa o limite. Lido ao criar o router.
+	MaxInFlightCommands int `json:"max_in_flight_commands,omitempty"`
+
+	// Resposta efêmera para comandos que o Discord ainda envia mas o bot não registra mais
+	// (registro obsoleto); vazio usa a mensagem padrão do router. Lida a cada ocorrência.
+	UnknownCommandMessage string `json:"unknown_command_message,omitempty"`
+
+	// Tempo máximo do shutdown gracioso (StopAll + drain do task router), ex.: "45s".
+	// A variável de ambiente ShutdownTimeoutEnv tem precedência (padrão: DefaultShutdownTimeout).
+	ShutdownTimeout string `json:"shutdown_timeout,omitempty"`
+
+	// Quanto o startup espera as guilds que o READY listou como indisponíveis receberem
+	// GUILD_CREATE antes de checar o acesso, ex.: "45s"; "0s" não espera. A variável de
+	// ambiente GuildAvailabilityTimeoutEnv tem precedência (padrão: DefaultGuildAvailabilityTimeout).
+	GuildAvailabilityTimeout string `json:"guild_availability_timeout,omitempty"`
+
+	// O que fazer quando o bot é removido de uma guild (GUILD_DELETE sem indisponibilidade):
+	// GuildRemovalRetain (padrão), GuildRemovalDeactivate ou GuildRemovalPurge.
+	GuildRemovalPolicy string `json:"guild_removal_policy,omitempty"`
+
+	// Auto-teste de inicialização (store, comandos e canais de log); sem configuração, roda só as checagens passivas
+	SelfTest *SelfTestConfig `json:"self_test,omitempty"`
+
+	// Rotação de status (presence) do bot; sem statuses, o presence não é alterado
+	Presence *PresenceConfig `json:"presence,omitempty"`
+
+	// Encaminhamento de erros do ErrorHandler global para um canal/webhook de operações
+	ErrorNotifications *ErrorNotificationConfig `json:"error_notifications,omitempty"`
+
+	// Arquivamento das mensagens expiradas em arquivos JSONL.gz antes de removê-las do SQLite.
+	// Lido ao abrir o store (startup).
+	MessageArchive *MessageArchiveConfig `json:"message_archive,omitempty"`
+
+	// IDs de usuário dos operadores do bot: autorizados em comandos restritos ao dono em
+	// qualquer guild, independentemente das roles. Somados aos de BotOperatorsEnv.
+	BotOperators []string `json:"bot_operators,omitempty"`
+}
+
+// ErrorNotificationConfig configura o alerta de erros em um canal de operações. É preciso
+// channel_id ou webhook_url (OpsWebhookEnv tem precedência sobre webhook_url).
+type ErrorNotificationConfig struct {
+	ChannelID   string `json:"channel_id,omitempty"`
+	WebhookURL  string `json:"webhook_url,omitempty"`
+	MinSeverity string `json:"min_severity,omitempty"` // low, medium, high (padrão) ou critical
+	DedupWindow string `json:"dedup_window,omitempty"` // Erros iguais dentro da janela são agrupados, ex.: "10m"
+	MaxPerHour  int    `json:"max_per_hour,omitempty"` // Limite de alertas por hora (padrão: 20)
+}
+
+// MessageArchiveConfig faz a limpeza de mensagens expiradas exportá-las antes para arquivos
+// JSONL.gz em Dir, um por guild e limpeza ("messages_<guild>_<de>_<até>.jsonl.gz"). Sem Dir as
+// mensagens expiradas são apenas removidas.
+type MessageArchiveConfig struct {
+	Dir      string   `json:"dir"`
+	GuildIDs []string `json:"guild_ids,omitempty"` // guilds arquivadas (vazio: todas)
+}
+
+// OpsWebhookEnv define a URL do webhook de operações sem gravá-la no settings.json.
+const OpsWebhookEnv = "DISCORDCORE_OPS_WEBHOOK_URL"
+
+// PresenceConfig configura a rotação de status do bot. Cada item de Statuses é um text/template
+// resolvido contra estatísticas ao vivo ({{.GuildCount}}, {{.MemberCount}}, {{.OnlineCount}}, {{.Uptime}}).
+type PresenceConfig struct {
+	Statuses []string `json:"statuses,omitempty"`
+	Interval string   `json:"interval,omitempty"` // Intervalo entre trocas, ex.: "5m" (padrão: DefaultPresenceInterval; mínimo MinPresenceInterval)
+	Activity string   `json:"activity,omitempty"` // "custom" (padrão), "playing", "watching", "listening" ou "competing"
+	Status   string   `json:"status,omitempty"`   // "online" (padrão), "idle" ou "dnd"
 }
 
+const (
+	// DefaultPresenceInterval é o intervalo padrão entre trocas de status.
+	DefaultPresenceInterval = 5 * time.Minute
+	// MinPresenceInterval respeita o limite de atualizações de presence do gateway.
+	MinPresenceInterval = 30 * time.Second
+)
+
+// RotationInterval retorna o intervalo efetivo entre trocas de status.
+func (c PresenceConfig) RotationInterval() time.Duration {
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return DefaultPresenceInterval
+	}
+	if d < MinPresenceInterval {
+		return MinPresenceInterval
+	}
+	return d
+}
+
+// Políticas de remoção do bot de uma guild (BotConfig.GuildRemovalPolicy).
+const (
+	GuildRemovalRetain     = "retain"     // mantém config e dados; a guild continua sendo processada
+	GuildRemovalDeactivate = "deactivate" // marca a guild como inativa e para o processamento
+	GuildRemovalPurge      = "purge"      // remove a guild do config e apaga seus dados do store
+)
+
+// SelfTestConfig configura o auto-teste executado antes de declarar o bot pronto.
+type SelfTestConfig struct {
+	Disabled        bool   `json:"disabled,omitempty"`          // Pula o auto-teste
+	SendMessages    bool   `json:"send_messages,omitempty"`     // Envia (e apaga) uma mensagem de teste em cada canal de log, além de checar permissões
+	ReportChannelID string `json:"report_channel_id,omitempty"` // Canal onde o relatório é publicado
+	AbortOnFailure  bool   `json:"abort_on_failure,omitempty"`  // Aborta a inicialização se uma checagem crítica falhar
+}
+
+// DefaultRefreshConcurrency é o limite padrão de guilds processadas em paralelo nos refreshes.
+const DefaultRefreshConcurrency = 4
+
+// DefaultMaxInFlightCommands é o limite padrão de interações executando ao mesmo tempo.
+const DefaultMaxInFlightCommands = 64
+
+const (
+	// DefaultShutdownTimeout é o tempo padrão do shutdown gracioso.
+	DefaultShutdownTimeout = 30 * time.Second
+	// ShutdownTimeoutEnv sobrescreve BotConfig.ShutdownTimeout (ex.: "10s" em CI).
+	ShutdownTimeoutEnv = "DISCORDCORE_SHUTDOWN_TIMEOUT"
+)
+
+const (
+	// DefaultGuildAvailabilityTimeout é a espera padrão pelas guilds ainda indisponíveis no startup.
+	DefaultGuildAvailabilityTimeout = 30 * time.Second
+	// GuildAvailabilityTimeoutEnv sobrescreve BotConfig.GuildAvailabilityTimeout.
+	GuildAvailabilityTimeoutEnv = "DISCORDCORE_GUILD_AVAILABILITY_TIMEOUT"
+)
+
 // ConfigManager handles bot configuration management.
 type ConfigManager struct {
 	configFilePath string
 	logsDirPath    string
 	config         *BotConfig
+	guildIndex     map[string]*GuildConfig // guildID -> entry em config.Guilds; reconstruído a cada mutação do slice
 	mu             sync.RWMutex
 	jsonManager    *util.JSONManager
+
+	// Recarga a quente (ver watcher.go): versão do arquivo já aplicada e listeners de OnReload
+	stampMu        sync.Mutex
+	stamp          configStamp
+	reloadMu       sync.Mutex
+	reloadHooks    map[int]func(*BotConfig)
+	nextReloadHook int
 }
 
 // AvatarChange holds information about a user's avatar change.
@@ -105,7 +343,7 @@ func (mgr *ConfigManager) AddList(guildID string, list List) error {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 
-	guildConfig := mgr.GuildConfig(guildID)
+	guildConfig := mgr.guildConfigLocked(guildID)
 	if guildConfig == nil {
 		log.Error().Errorf("GuildConfig not found for guildID: %s", guildID)
 		return fmt.Errorf("guild not found")
@@ -117,7 +355,7 @@ func (mgr *ConfigManager) AddList(guildID string, list List) error {
 		Enabled: true,
 	})
 	log.Info().Databasef("List appended successfully for guildID: %s", guildID)
-	return mgr.SaveConfig()
+	return mgr.saveConfigLocked()
 }
 
 // AddRule adds a rule to the LooseLists of a guild.
@@ -125,13 +363,13 @@ func (mgr *ConfigManager) AddRule(guildID string, rule Rule) error {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 
-	guildConfig := mgr.GuildConfig(guildID)
+	guildConfig := mgr.guildConfigLocked(guildID)
 	if guildConfig == nil {
 		return fmt.Errorf("guild not found")
 	}
 
 	guildConfig.LooseLists = append(guildConfig.LooseLists, rule)
-	return mgr.SaveConfig()
+	return mgr.saveConfigLocked()
 }
 
 // AddRuleset adds a ruleset to a guild.
@@ -139,13 +377,13 @@ func (mgr *ConfigManager) AddRuleset(guildID string, ruleset Ruleset) error {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 
-	guildConfig := mgr.GuildConfig(guildID)
+	guildConfig := mgr.guildConfigLocked(guildID)
 	if guildConfig == nil {
 		return fmt.Errorf("guild not found")
 	}
 
 	guildConfig.Rulesets = append(guildConfig.Rulesets, ruleset)
-	return mgr.SaveConfig()
+	return mgr.saveConfigLocked()
 }
 
 // AddListToRule adds a list to a specific rule in a guild.
@@ -154,7 +392,7 @@ func (mgr *ConfigManager) AddListToRule(guildID string, ruleID string, list List
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 
-	guildConfig := mgr.GuildConfig(guildID)
+	guildConfig := mgr.guildConfigLocked(guildID)
 	if guildConfig == nil {
 		log.Error().Errorf("GuildConfig not found for guildID: %s", guildID)
 		return fmt.Errorf("guild not found")
@@ -165,7 +403,7 @@ func (mgr *ConfigManager) AddListToRule(guildID string, ruleID string, list List
 			log.Info().Databasef("Rule found for ruleID: %s, appending list", ruleID)
 			guildConfig.LooseLists[i].Lists = append(guildConfig.LooseLists[i].Lists, list)
 			log.Info().Databasef("List appended successfully to ruleID: %s", ruleID)
-			return mgr.SaveConfig()
+			return mgr.saveConfigLocked()
 		}
 	}
 
@@ -227,37 +465,1086 @@ func (gc *GuildConfig) ChannelCacheTTLDuration() time.Duration {
 	return d
 }
 
+// Validate verifica os campos de formato livre da guild (durações, fuso, ações de automod e
+// menções). Retorna um ValidationError para o primeiro campo inválido; valores vazios usam os
+// padrões e são sempre válidos.
+func (gc *GuildConfig) Validate() error {
+	if gc == nil {
+		return NewValidationError("guild", nil, "config is nil")
+	}
+	if strings.TrimSpace(gc.GuildID) == "" {
+		return NewValidationError("guild_id", gc.GuildID, "must not be empty")
+	}
+
+	durations := []struct {
+		field, value string
+	}{
+		{"roles_cache_ttl", gc.RolesCacheTTL},
+		{"member_cache_ttl", gc.MemberCacheTTL},
+		{"guild_cache_ttl", gc.GuildCacheTTL},
+		{"channel_cache_ttl", gc.ChannelCacheTTL},
+		{"automod_action_cooldown", gc.AutomodActionCooldown},
+	}
+	if gc.AutomodDM != nil {
+		durations = append(durations,
+			struct{ field, value string }{"automod_dm.cooldown", gc.AutomodDM.Cooldown})
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return NewValidationError(d.field, d.value, "invalid duration")
+		}
+		if v < 0 {
+			return NewValidationError(d.field, d.value, "must not be negative")
+		}
+	}
+
+	if gc.Timezone != "" {
+		if _, err := time.LoadLocation(gc.Timezone); err != nil {
+			return NewValidationError("timezone", gc.Timezone, "unknown IANA timezone")
+		}
+	}
+
+	if err := validateAutomodSpam("automod_spam", gc.AutomodSpam); err != nil {
+		return err
+	}
+	if err := validateAutomodLinks("automod_links", gc.AutomodLinks); err != nil {
+		return err
+	}
+	seenOverrides := make(map[string]bool, len(gc.AutomodChannelOverrides))
+	for i, o := range gc.AutomodChannelOverrides {
+		prefix := fmt.Sprintf("automod_channel_overrides[%d]", i)
+		if strings.TrimSpace(o.ChannelID) == "" {
+			return NewValidationError(prefix+".channel_id", o.ChannelID, "must not be empty")
+		}
+		if seenOverrides[o.ChannelID] {
+			return NewValidationError(prefix+".channel_id", o.ChannelID, "duplicate channel override")
+		}
+		seenOverrides[o.ChannelID] = true
+		if err := validateAutomodSpam(prefix+".automod_spam", o.Spam); err != nil {
+			return err
+		}
+		if err := validateAutomodLinks(prefix+".automod_links", o.Links); err != nil {
+			return err
+		}
+	}
+
+	if c := gc.AutomodEscalation; c != nil {
+		switch c.Decay {
+		case "", EscalationDecayReset, EscalationDecayStep:
+		default:
+			return NewValidationError("automod_escalation.decay", c.Decay, "unknown decay mode")
+		}
+		if err := validateDurations(map[string]string{
+			"automod_escalation.reset_after": c.ResetAfter,
+			"automod_escalation.max_timeout": c.MaxTimeout,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if c := gc.Greeter; c != nil {
+		if w := c.Welcome; w != nil && w.Enabled && !w.DM && strings.TrimSpace(w.ChannelID) == "" {
+			return NewValidationError("greeter.welcome.channel_id", w.ChannelID, "must not be empty when enabled (or set dm)")
+		}
+		if l := c.Leave; l != nil && l.Enabled {
+			if l.DM {
+				return NewValidationError("greeter.leave.dm", l.DM, "members who left cannot be messaged")
+			}
+			if strings.TrimSpace(l.ChannelID) == "" {
+				return NewValidationError("greeter.leave.channel_id", l.ChannelID, "must not be empty when enabled")
+			}
+		}
+		if c.BurstLimit < 0 {
+			return NewValidationError("greeter.burst_limit", c.BurstLimit, "must not be negative")
+		}
+		if err := validateDurations(map[string]string{"greeter.burst_window": c.BurstWindow}); err != nil {
+			return err
+		}
+	}
+
+	if c := gc.Autorole; c != nil {
+		if c.Enabled && len(c.RoleIDs) == 0 {
+			return NewValidationError("autorole.role_ids", c.RoleIDs, "must not be empty when enabled")
+		}
+		for i, id := range c.RoleIDs {
+			if strings.TrimSpace(id) == "" {
+				return NewValidationError(fmt.Sprintf("autorole.role_ids[%d]", i), id, "must not be empty")
+			}
+		}
+		if err := validateDurations(map[string]string{
+			"autorole.delay":           c.Delay,
+			"autorole.min_account_age": c.MinAccountAge,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if c := gc.AvatarRefresh; c != nil {
+		if err := validateDurations(map[string]string{"avatar_refresh.active_window": c.ActiveWindow}); err != nil {
+			return err
+		}
+	}
+
+	if c := gc.AvatarNotifications; c != nil {
+		switch c.Mode {
+		case "", AvatarNotifyImmediate, AvatarNotifyDigest:
+		default:
+			return NewValidationError("avatar_notifications.mode", c.Mode, "must be immediate or digest")
+		}
+		if c.Interval != "" {
+			d, err := time.ParseDuration(c.Interval)
+			if err != nil {
+				return NewValidationError("avatar_notifications.interval", c.Interval, "invalid duration")
+			}
+			if d < MinAvatarDigestInterval {
+				return NewValidationError("avatar_notifications.interval", c.Interval, "must be at least "+MinAvatarDigestInterval.String())
+			}
+		}
+	}
+
+	if c := gc.ActivityReport; c != nil && c.Enabled {
+		if strings.TrimSpace(c.ChannelID) == "" {
+			return NewValidationError("activity_report.channel_id", c.ChannelID, "must not be empty when enabled")
+		}
+		if c.Period != "" {
+			d, err := time.ParseDuration(c.Period)
+			if err != nil {
+				return NewValidationError("activity_report.period", c.Period, "invalid duration")
+			}
+			if d < MinActivityReportPeriod {
+				return NewValidationError("activity_report.period", c.Period, "must be at least "+MinActivityReportPeriod.String())
+			}
+		}
+	}
+
+	if c := gc.AttachmentArchive; c != nil {
+		if c.MaxBytes < 0 {
+			return NewValidationError("attachment_archive.max_bytes", c.MaxBytes, "must not be negative")
+		}
+		if c.MaxTotalBytes < 0 {
+			return NewValidationError("attachment_archive.max_total_bytes", c.MaxTotalBytes, "must not be negative")
+		}
+	}
+
+	if c := gc.MessagePrivacy; c != nil {
+		for _, field := range c.Metadata {
+			if !slices.Contains(MessageMetadataFields, field) {
+				return NewValidationError("message_privacy.metadata", field, "unknown field (use "+strings.Join(MessageMetadataFields, ", ")+")")
+			}
+		}
+	}
+
+	for event, role := range gc.NotificationMentions {
+		if role == "" {
+			return NewValidationError("notification_mentions."+event, role, "role must not be empty (use \"none\")")
+		}
+	}
+
+	for i, path := range gc.DisabledCommands {
+		if NormalizeCommandPath(path) == "" {
+			return NewValidationError(fmt.Sprintf("disabled_commands[%d]", i), path, "must not be empty")
+		}
+	}
+
+	for i, role := range gc.AdminRoles {
+		if strings.TrimSpace(role) == "" {
+			return NewValidationError(fmt.Sprintf("admin_roles[%d]", i), role, "must not be empty")
+		}
+	}
+	return nil
+}
+
+// NormalizeCommandPath normaliza um caminho de comando ("Admin  emoji-stats" -> "admin emoji-stats").
+func NormalizeCommandPath(path string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(path), "/")), " "))
+}
+
+// CommandEnabled informa se o comando em path (ex.: "admin emoji-stats") está ativo na guild.
+// Desativar um comando de topo desativa todos os seus subcomandos.
+func (gc *GuildConfig) CommandEnabled(path string) bool {
+	if gc == nil || len(gc.DisabledCommands) == 0 {
+		return true
+	}
+	path = NormalizeCommandPath(path)
+	for _, disabled := range gc.DisabledCommands {
+		disabled = NormalizeCommandPath(disabled)
+		if path == disabled || strings.HasPrefix(path, disabled+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAutomodSpam valida um bloco automod_spam (da guild ou de um override de canal).
+func validateAutomodSpam(field string, c *AutomodSpamConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxMessages < 0 {
+		return NewValidationError(field+".max_messages", c.MaxMessages, "must not be negative")
+	}
+	if c.MaxDuplicates < 0 {
+		return NewValidationError(field+".max_duplicates", c.MaxDuplicates, "must not be negative")
+	}
+	switch c.Action {
+	case "", SpamActionDelete, SpamActionTimeout, SpamActionDeleteTimeout:
+	default:
+		return NewValidationError(field+".action", c.Action, "unknown action")
+	}
+	return validateDurations(map[string]string{
+		field + ".window":           c.Window,
+		field + ".timeout_duration": c.TimeoutDuration,
+	})
+}
+
+// validateAutomodLinks valida um bloco automod_links (da guild ou de um override de canal).
+func validateAutomodLinks(field string, c *AutomodLinkConfig) error {
+	if c == nil {
+		return nil
+	}
+	for name, action := range map[string]string{
+		field + ".action":             c.Action,
+		field + ".new_account_action": c.NewAccountAction,
+	} {
+		switch action {
+		case "", LinkActionFlag, LinkActionDelete, LinkActionDeleteTimeout:
+		default:
+			return NewValidationError(name, action, "unknown action")
+		}
+	}
+	return validateDurations(map[string]string{
+		field + ".new_account_link_age": c.NewAccountAge,
+		field + ".timeout_duration":     c.TimeoutDuration,
+	})
+}
+
+// AutomodPatternErrors retorna todos os padrões de domínio inválidos de automod_links (da guild
+// e dos overrides de canal): vazios ou globs malformados, que nunca casariam com nenhum link.
+// Não faz parte de Validate; é usado pela validação offline da configuração (app.Validate).
+func (gc *GuildConfig) AutomodPatternErrors() []error {
+	if gc == nil {
+		return nil
+	}
+	var errs []error
+	check := func(field string, patterns []string) {
+		for i, p := range patterns {
+			name := fmt.Sprintf("%s[%d]", field, i)
+			p = strings.ToLower(strings.TrimSpace(p))
+			if p == "" {
+				errs = append(errs, NewValidationError(name, patterns[i], "must not be empty"))
+				continue
+			}
+			if strings.Contains(p, "*") && !strings.Contains(p, "/") {
+				if _, err := path.Match(p, ""); err != nil {
+					errs = append(errs, NewValidationError(name, patterns[i], "malformed glob pattern"))
+				}
+			}
+		}
+	}
+	links := func(field string, c *AutomodLinkConfig) {
+		if c != nil {
+			check(field+".blocked_domains", c.BlockedDomains)
+			check(field+".allowed_domains", c.AllowedDomains)
+		}
+	}
+	links("automod_links", gc.AutomodLinks)
+	for i, o := range gc.AutomodChannelOverrides {
+		prefix := fmt.Sprintf("automod_channel_overrides[%d]", i)
+		links(prefix+".automod_links", o.Links)
+		check(prefix+".blocked_domains", o.BlockedDomains)
+		check(prefix+".allowed_domains", o.AllowedDomains)
+	}
+	return errs
+}
+
+// validateDurations valida durações opcionais (vazio é aceito) e não negativas.
+func validateDurations(fields map[string]string) error {
+	for field, value := range fields {
+		if value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return NewValidationError(field, value, "invalid duration")
+		}
+		if v < 0 {
+			return NewValidationError(field, value, "must not be negative")
+		}
+	}
+	return nil
+}
+
 // SetRolesCacheTTL define o TTL do cache de roles por guild (ex.: "5m", "1h") e persiste a configuração.
 func (mgr *ConfigManager) SetRolesCacheTTL(guildID string, ttl string) error {
 	if guildID == "" {
 		return fmt.Errorf("guild not found")
 	}
-	// Validar formato (permite vazio para resetar ao padrão)
-	if ttl != "" {
-		if _, err := time.ParseDuration(ttl); err != nil {
-			return fmt.Errorf("invalid ttl: %w", err)
+	return mgr.UpdateGuild(guildID, func(gcfg *GuildConfig) error {
+		gcfg.RolesCacheTTL = ttl // vazio reseta ao padrão; o formato é checado por Validate
+		return nil
+	})
+}
+
+// GuildRemovalPolicy retorna a política aplicada quando o bot sai de uma guild (padrão: retain).
+func (mgr *ConfigManager) GuildRemovalPolicy() string {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.config == nil {
+		return GuildRemovalRetain
+	}
+	switch p := strings.ToLower(strings.TrimSpace(mgr.config.GuildRemovalPolicy)); p {
+	case GuildRemovalDeactivate, GuildRemovalPurge:
+		return p
+	default:
+		return GuildRemovalRetain
+	}
+}
+
+// SelfTest retorna a configuração do auto-teste (valor zero quando ausente).
+func (mgr *ConfigManager) SelfTest() SelfTestConfig {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.config == nil || mgr.config.SelfTest == nil {
+		return SelfTestConfig{}
+	}
+	return *mgr.config.SelfTest
+}
+
+// ErrorNotifications retorna a configuração de alertas de erro, com OpsWebhookEnv aplicado.
+// O bool é false quando não há destino configurado.
+func (mgr *ConfigManager) ErrorNotifications() (ErrorNotificationConfig, bool) {
+	mgr.mu.RLock()
+	var cfg ErrorNotificationConfig
+	if mgr.config != nil && mgr.config.ErrorNotifications != nil {
+		cfg = *mgr.config.ErrorNotifications
+	}
+	mgr.mu.RUnlock()
+	if env := strings.TrimSpace(os.Getenv(OpsWebhookEnv)); env != "" {
+		cfg.WebhookURL = env
+	}
+	return cfg, cfg.ChannelID != "" || cfg.WebhookURL != ""
+}
+
+// MessageArchive retorna a configuração de arquivamento de mensagens; ok=false sem diretório.
+func (mgr *ConfigManager) MessageArchive() (MessageArchiveConfig, bool) {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	var cfg MessageArchiveConfig
+	if mgr.config != nil && mgr.config.MessageArchive != nil {
+		cfg = *mgr.config.MessageArchive
+		cfg.GuildIDs = slices.Clone(cfg.GuildIDs)
+	}
+	cfg.Dir = strings.TrimSpace(cfg.Dir)
+	return cfg, cfg.Dir != ""
+}
+
+// Presence retorna a configuração de rotação de status (valor zero se ausente).
+func (mgr *ConfigManager) Presence() PresenceConfig {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.config == nil || mgr.config.Presence == nil {
+		return PresenceConfig{}
+	}
+	p := *mgr.config.Presence
+	p.Statuses = append([]string(nil), p.Statuses...)
+	return p
+}
+
+// RefreshConcurrency retorna o limite de guilds processadas em paralelo nos refreshes (mínimo 1).
+func (mgr *ConfigManager) RefreshConcurrency() int {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.config == nil || mgr.config.RefreshConcurrency <= 0 {
+		return DefaultRefreshConcurrency
+	}
+	return mgr.config.RefreshConcurrency
+}
+
+// MaxInFlightCommands retorna o limite de interações executando ao mesmo tempo (0: sem limite).
+func (mgr *ConfigManager) MaxInFlightCommands() int {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	switch {
+	case mgr.config == nil || mgr.config.MaxInFlightCommands == 0:
+		return DefaultMaxInFlightCommands
+	case mgr.config.MaxInFlightCommands < 0:
+		return 0
+	}
+	return mgr.config.MaxInFlightCommands
+}
+
+// UnknownCommandMessage retorna a resposta configurada para comandos desconhecidos ("" se não houver).
+func (mgr *ConfigManager) UnknownCommandMessage() string {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.config == nil {
+		return ""
+	}
+	return strings.TrimSpace(mgr.config.UnknownCommandMessage)
+}
+
+// ShutdownTimeout retorna o tempo máximo do shutdown gracioso. A variável de ambiente
+// ShutdownTimeoutEnv tem precedência sobre shutdown_timeout; sem nenhum dos dois, retorna
+// DefaultShutdownTimeout. Valores que não são durações positivas resultam em erro.
+func (mgr *ConfigManager) ShutdownTimeout() (time.Duration, error) {
+	raw, source := strings.TrimSpace(os.Getenv(ShutdownTimeoutEnv)), ShutdownTimeoutEnv
+	if raw == "" {
+		mgr.mu.RLock()
+		if mgr.config != nil {
+			raw = strings.TrimSpace(mgr.config.ShutdownTimeout)
 		}
+		mgr.mu.RUnlock()
+		source = "shutdown_timeout"
 	}
-	mgr.mu.Lock()
-	defer mgr.mu.Unlock()
+	if raw == "" {
+		return DefaultShutdownTimeout, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", source, raw, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be positive", source, raw)
+	}
+	return d, nil
+}
 
-	gcfg := mgr.GuildConfig(guildID)
-	if gcfg == nil {
-		return fmt.Errorf("guild not found")
+// GuildAvailabilityTimeout retorna quanto o startup espera as guilds indisponíveis. A variável
+// de ambiente GuildAvailabilityTimeoutEnv tem precedência sobre guild_availability_timeout; sem
+// nenhum dos dois, retorna DefaultGuildAvailabilityTimeout. Zero desativa a espera; durações
+// negativas ou inválidas resultam em erro.
+func (mgr *ConfigManager) GuildAvailabilityTimeout() (time.Duration, error) {
+	raw, source := strings.TrimSpace(os.Getenv(GuildAvailabilityTimeoutEnv)), GuildAvailabilityTimeoutEnv
+	if raw == "" {
+		mgr.mu.RLock()
+		if mgr.config != nil {
+			raw = strings.TrimSpace(mgr.config.GuildAvailabilityTimeout)
+		}
+		mgr.mu.RUnlock()
+		source = "guild_availability_timeout"
+	}
+	if raw == "" {
+		return DefaultGuildAvailabilityTimeout, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", source, raw, err)
 	}
-	gcfg.RolesCacheTTL = ttl
-	return mgr.SaveConfig()
+	if d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative", source, raw)
+	}
+	return d, nil
 }
 
 // GetRolesCacheTTL obtém o TTL do cache de roles configurado (string original, ex.: "5m").
 func (mgr *ConfigManager) GetRolesCacheTTL(guildID string) string {
-	gcfg := mgr.GuildConfig(guildID)
-	if gcfg == nil {
+	gcfg, ok := mgr.GuildConfig(guildID)
+	if !ok {
 		return ""
 	}
 	return gcfg.RolesCacheTTL
 }
 
+// Tipos de evento de notificação usados em GuildConfig.NotificationMentions.
+const (
+	NotificationEventAvatarChange  = "avatar_change"
+	NotificationEventMemberJoin    = "member_join"
+	NotificationEventMemberLeave   = "member_leave"
+	NotificationEventMessageEdit   = "message_edit"
+	NotificationEventMessageDelete = "message_delete"
+	NotificationEventRoleUpdate    = "role_update"
+	NotificationEventAutomod       = "automod"
+
+	// MentionNone desativa explicitamente a menção de um tipo de evento.
+	MentionNone = "none"
+)
+
+// MentionRoleFor retorna o cargo a ser mencionado para o tipo de evento, ou "" quando
+// o evento não deve mencionar ninguém (ausente ou MentionNone).
+func (gc *GuildConfig) MentionRoleFor(eventType string) string {
+	if gc == nil || len(gc.NotificationMentions) == 0 {
+		return ""
+	}
+	role := gc.NotificationMentions[eventType]
+	if role == MentionNone {
+		return ""
+	}
+	return role
+}
+
+// ShouldLogMessageChannel decide se mensagens de um canal devem ser registradas.
+// channelIDs deve conter o canal e seus ancestrais (ex.: thread -> canal pai), e
+// categoryID a categoria do canal (vazio se não houver). Com allowlist definida,
+// apenas canais listados (ou threads deles) são registrados; caso contrário a
+// denylist de canais e categorias é aplicada.
+func (gc *GuildConfig) ShouldLogMessageChannel(channelIDs []string, categoryID string) bool {
+	if gc == nil {
+		return true
+	}
+	if len(gc.MessageLogAllowedChannels) > 0 {
+		for _, id := range channelIDs {
+			if containsString(gc.MessageLogAllowedChannels, id) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, id := range channelIDs {
+		if containsString(gc.MessageLogIgnoredChannels, id) {
+			return false
+		}
+	}
+	if categoryID != "" && containsString(gc.MessageLogIgnoredCategories, categoryID) {
+		return false
+	}
+	return true
+}
+
+// IsAutomodExemptChannel informa se algum dos canais (canal e ancestrais, ex.: thread -> pai) está isento de automod.
+func (gc *GuildConfig) IsAutomodExemptChannel(channelIDs []string) bool {
+	if gc == nil {
+		return false
+	}
+	for _, id := range channelIDs {
+		if containsString(gc.AutomodExemptChannels, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAutomodExemptRole informa se algum dos cargos do membro está isento de automod.
+func (gc *GuildConfig) HasAutomodExemptRole(roles []string) bool {
+	if gc == nil {
+		return false
+	}
+	for _, id := range roles {
+		if containsString(gc.AutomodExemptRoles, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// AutomodActionCooldownDuration retorna o cooldown de ações de automod por usuário (0 = desativado).
+func (gc *GuildConfig) AutomodActionCooldownDuration() time.Duration {
+	if gc == nil || gc.AutomodActionCooldown == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(gc.AutomodActionCooldown)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// AutomodSpamDryRun informa se a detecção de spam só simula as ações (dry-run da guild ou da detecção).
+func (gc *GuildConfig) AutomodSpamDryRun() bool {
+	return gc != nil && (gc.AutomodDryRun || (gc.AutomodSpam != nil && gc.AutomodSpam.DryRun))
+}
+
+// AutomodLinksDryRun informa se a varredura de links e anexos só simula as ações.
+func (gc *GuildConfig) AutomodLinksDryRun() bool {
+	return gc != nil && (gc.AutomodDryRun || (gc.AutomodLinks != nil && gc.AutomodLinks.DryRun))
+}
+
+// AutomodShadowDelete informa se as detecções locais removem em silêncio (shadow-delete) em vez de
+// aplicar a ação configurada. Use sobre a configuração efetiva do canal (AutomodForChannel).
+func (gc *GuildConfig) AutomodShadowDelete() bool {
+	return gc != nil && gc.AutomodShadow
+}
+
+// AutomodChannelOverride ajusta o automod num canal (e nas threads dele). Precedência: o
+// override do canal vence as regras da guild; numa thread, o override da própria thread vence
+// o do canal pai. Spam e Links, se definidos, substituem por inteiro o bloco da guild nesse
+// canal (ex.: {"enabled": false} desliga a detecção; limites menores a endurecem). As listas
+// extras são somadas às do bloco efetivo, e AllowedDomains continua vencendo BlockedDomains.
+type AutomodChannelOverride struct {
+	ChannelID string             `json:"channel_id"`
+	Spam      *AutomodSpamConfig `json:"automod_spam,omitempty"`
+	Links     *AutomodLinkConfig `json:"automod_links,omitempty"`
+	Shadow    *bool              `json:"shadow,omitempty"` // Substitui automod_shadow da guild nesse canal
+
+	AllowedDomains    []string `json:"allowed_domains,omitempty"`    // Somados aos domínios permitidos
+	BlockedDomains    []string `json:"blocked_domains,omitempty"`    // Somados aos domínios bloqueados
+	BlockedExtensions []string `json:"blocked_extensions,omitempty"` // Somadas às extensões bloqueadas
+}
+
+// AutomodForChannel retorna uma cópia da configuração com automod_spam e automod_links efetivos
+// no canal, aplicando o override mais específico de channelIDs (canal e ancestrais, ex.:
+// thread -> pai). Sem override, a configuração da guild é retornada como está.
+func (gc *GuildConfig) AutomodForChannel(channelIDs []string) GuildConfig {
+	if gc == nil {
+		return GuildConfig{}
+	}
+	out := *gc
+	var override *AutomodChannelOverride
+	for _, id := range channelIDs {
+		for i := range gc.AutomodChannelOverrides {
+			if gc.AutomodChannelOverrides[i].ChannelID == id {
+				override = &gc.AutomodChannelOverrides[i]
+				break
+			}
+		}
+		if override != nil {
+			break
+		}
+	}
+	if override == nil {
+		return out
+	}
+	if override.Spam != nil {
+		out.AutomodSpam = override.Spam
+	}
+	if override.Links != nil {
+		out.AutomodLinks = override.Links
+	}
+	if override.Shadow != nil {
+		out.AutomodShadow = *override.Shadow
+	}
+	if len(override.AllowedDomains) > 0 || len(override.BlockedDomains) > 0 || len(override.BlockedExtensions) > 0 {
+		// Cópia para não alterar o bloco compartilhado com a guild
+		links := AutomodLinkConfig{}
+		if out.AutomodLinks != nil {
+			links = *out.AutomodLinks
+		}
+		links.AllowedDomains = append(append([]string(nil), links.AllowedDomains...), override.AllowedDomains...)
+		links.BlockedDomains = append(append([]string(nil), links.BlockedDomains...), override.BlockedDomains...)
+		links.BlockedExtensions = append(append([]string(nil), links.BlockedExtensions...), override.BlockedExtensions...)
+		out.AutomodLinks = &links
+	}
+	return out
+}
+
+// AutomodSpamConfig configura a detecção de spam por taxa do AutomodService.
+type AutomodSpamConfig struct {
+	Enabled         bool   `json:"enabled"`
+	MaxMessages     int    `json:"max_messages,omitempty"`     // Mensagens do mesmo usuário na janela (padrão: DefaultSpamMaxMessages)
+	MaxDuplicates   int    `json:"max_duplicates,omitempty"`   // Mensagens com o mesmo conteúdo na janela (padrão: DefaultSpamMaxDuplicates)
+	Window          string `json:"window,omitempty"`           // Janela deslizante, ex.: "10s" (padrão: DefaultSpamWindow)
+	Action          string `json:"action,omitempty"`           // SpamActionDelete, SpamActionTimeout ou SpamActionDeleteTimeout (padrão)
+	TimeoutDuration string `json:"timeout_duration,omitempty"` // Duração do timeout, ex.: "10m" (padrão: DefaultSpamTimeout)
+	DryRun          bool   `json:"dry_run,omitempty"`          // Só simula as ações (ver GuildConfig.AutomodDryRun)
+}
+
+// Ações e limites padrão da detecção de spam.
+const (
+	SpamActionDelete        = "delete"
+	SpamActionTimeout       = "timeout"
+	SpamActionDeleteTimeout = "delete_timeout"
+
+	DefaultSpamMaxMessages   = 8
+	DefaultSpamMaxDuplicates = 4
+	DefaultSpamWindow        = 10 * time.Second
+	DefaultSpamTimeout       = 5 * time.Minute
+)
+
+// Limits resolve os limites efetivos, aplicando os padrões a valores ausentes ou inválidos.
+func (c *AutomodSpamConfig) Limits() (maxMessages, maxDuplicates int, window, timeout time.Duration) {
+	maxMessages, maxDuplicates = DefaultSpamMaxMessages, DefaultSpamMaxDuplicates
+	window, timeout = DefaultSpamWindow, DefaultSpamTimeout
+	if c == nil {
+		return
+	}
+	if c.MaxMessages > 0 {
+		maxMessages = c.MaxMessages
+	}
+	if c.MaxDuplicates > 0 {
+		maxDuplicates = c.MaxDuplicates
+	}
+	if d, err := time.ParseDuration(c.Window); err == nil && d > 0 {
+		window = d
+	}
+	if d, err := time.ParseDuration(c.TimeoutDuration); err == nil && d > 0 {
+		timeout = d
+	}
+	return
+}
+
+// SpamAction retorna a ação configurada (padrão: SpamActionDeleteTimeout).
+func (c *AutomodSpamConfig) SpamAction() string {
+	if c == nil {
+		return SpamActionDeleteTimeout
+	}
+	switch c.Action {
+	case SpamActionDelete, SpamActionTimeout, SpamActionDeleteTimeout:
+		return c.Action
+	default:
+		return SpamActionDeleteTimeout
+	}
+}
+
+// AutomodLinkConfig configura a varredura de links e anexos do AutomodService.
+//
+// Padrões de domínio: "example.com" casa o domínio e subdomínios; padrões com "*" usam glob
+// sobre o host (ex.: "*.ru", "discord-gift*"); padrões com "/" casam como prefixo de host+caminho
+// (ex.: "bit.ly/scam").
+type AutomodLinkConfig struct {
+	Enabled           bool     `json:"enabled"`
+	BlockedDomains    []string `json:"blocked_domains,omitempty"`
+	AllowedDomains    []string `json:"allowed_domains,omitempty"`      // Têm precedência sobre o bloqueio e o modo de contas novas
+	BlockedExtensions []string `json:"blocked_extensions,omitempty"`   // Extensões de anexos, ex.: ".exe", "scr"
+	NewAccountAge     string   `json:"new_account_link_age,omitempty"` // Se definido (ex.: "24h"), links de membros que entraram há menos tempo são sinalizados
+	Action            string   `json:"action,omitempty"`               // LinkActionDelete (padrão), LinkActionFlag ou LinkActionDeleteTimeout
+	NewAccountAction  string   `json:"new_account_action,omitempty"`   // Ação para o modo de contas novas (padrão: LinkActionFlag)
+	TimeoutDuration   string   `json:"timeout_duration,omitempty"`     // Duração do timeout (padrão: DefaultSpamTimeout)
+	DryRun            bool     `json:"dry_run,omitempty"`              // Só simula as ações (ver GuildConfig.AutomodDryRun)
+}
+
+// AutomodDMConfig configura a DM enviada ao usuário quando o automod age sobre ele.
+//
+// Template aceita os placeholders {user}, {guild}, {rule}, {action}, {matched}, {channel} e {appeal};
+// vazio usa DefaultAutomodDMTemplate.
+type AutomodDMConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Template string `json:"template,omitempty"`
+	Appeal   string `json:"appeal,omitempty"`   // Instruções de recurso, inseridas em {appeal}
+	Cooldown string `json:"cooldown,omitempty"` // Intervalo mínimo entre DMs ao mesmo usuário, ex.: "10m" (padrão: DefaultAutomodDMCooldown)
+}
+
+// DefaultAutomodDMTemplate é a mensagem padrão das DMs de automod.
+const DefaultAutomodDMTemplate = "Your message in **{guild}** was moderated.\nRule: {rule}\nAction: {action}\n{appeal}"
+
+// DefaultAutomodDMCooldown é o intervalo padrão entre DMs de automod ao mesmo usuário.
+const DefaultAutomodDMCooldown = 10 * time.Minute
+
+// CooldownDuration retorna o intervalo efetivo entre DMs ao mesmo usuário.
+func (c *AutomodDMConfig) CooldownDuration() time.Duration {
+	if d, err := time.ParseDuration(c.Cooldown); err == nil && d >= 0 {
+		return d
+	}
+	return DefaultAutomodDMCooldown
+}
+
+// AutomodEscalationConfig escala os timeouts do automod: a n-ésima ofensa com timeout de um membro
+// usa a duração base da regra multiplicada por 2^(n-1), até MaxTimeout. Sem ofensas por ResetAfter,
+// o nível volta a zero (EscalationDecayReset) ou desce um degrau por janela (EscalationDecayStep).
+type AutomodEscalationConfig struct {
+	Enabled    bool   `json:"enabled"`
+	ResetAfter string `json:"reset_after,omitempty"` // Janela sem ofensas para o decaimento, ex.: "720h" (padrão: DefaultEscalationResetAfter)
+	Decay      string `json:"decay,omitempty"`       // EscalationDecayReset (padrão) ou EscalationDecayStep
+	MaxTimeout string `json:"max_timeout,omitempty"` // Teto do timeout escalado (padrão e máximo: MaxEscalationTimeout)
+}
+
+// Modos de decaimento e limites do escalonamento de automod.
+const (
+	EscalationDecayReset = "reset"
+	EscalationDecayStep  = "step"
+
+	DefaultEscalationResetAfter = 30 * 24 * time.Hour
+	// MaxEscalationTimeout é o maior timeout aceito pelo Discord (28 dias).
+	MaxEscalationTimeout = 28 * 24 * time.Hour
+)
+
+// ResetAfterDuration retorna a janela sem ofensas que dispara o decaimento.
+func (c *AutomodEscalationConfig) ResetAfterDuration() time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.ResetAfter); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultEscalationResetAfter
+}
+
+// DecayLevel aplica o decaimento ao nível gravado, dada a última ofensa e o momento da avaliação.
+func (c *AutomodEscalationConfig) DecayLevel(level int, lastOffense, now time.Time) int {
+	if level <= 0 || lastOffense.IsZero() {
+		return 0
+	}
+	windows := int(now.Sub(lastOffense) / c.ResetAfterDuration())
+	if windows <= 0 {
+		return level
+	}
+	if c != nil && c.Decay == EscalationDecayStep {
+		return max(level-windows, 0)
+	}
+	return 0
+}
+
+// Timeout retorna a duração do timeout no nível dado (1 = primeira ofensa) a partir da duração base.
+func (c *AutomodEscalationConfig) Timeout(base time.Duration, level int) time.Duration {
+	limit := MaxEscalationTimeout
+	if c != nil {
+		if d, err := time.ParseDuration(c.MaxTimeout); err == nil && d > 0 && d < limit {
+			limit = d
+		}
+	}
+	d := base
+	for i := 1; i < level && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+// GreeterConfig configura as mensagens de boas-vindas e despedida de uma guild.
+//
+// Templates aceitam {mention}, {username}, {guild} e {member_count}; vazios usam
+// DefaultGreeterWelcomeTemplate e DefaultGreeterLeaveTemplate. Quando mais de BurstLimit membros
+// entram em BurstWindow (ex.: um raid), as boas-vindas no canal são agrupadas numa mensagem por
+// janela e as por DM são suprimidas, para não esbarrar nos rate limits.
+type GreeterConfig struct {
+	Welcome     *GreeterMessage `json:"welcome,omitempty"`
+	Leave       *GreeterMessage `json:"leave,omitempty"`
+	BurstLimit  int             `json:"burst_limit,omitempty"`  // Entradas na janela antes de agrupar (padrão: DefaultGreeterBurstLimit)
+	BurstWindow string          `json:"burst_window,omitempty"` // Janela de contagem e de envio dos lotes, ex.: "30s" (padrão: DefaultGreeterBurstWindow)
+}
+
+// GreeterMessage é uma mensagem do greeter e o destino dela.
+type GreeterMessage struct {
+	Enabled   bool   `json:"enabled"`
+	ChannelID string `json:"channel_id,omitempty"` // Canal (ou thread) onde a mensagem é postada
+	DM        bool   `json:"dm,omitempty"`         // Envia por DM ao membro em vez do canal (só boas-vindas)
+	Template  string `json:"template,omitempty"`
+}
+
+// Padrões do greeter.
+const (
+	DefaultGreeterWelcomeTemplate = "Welcome to **{guild}**, {mention}! You are member #{member_count}."
+	DefaultGreeterLeaveTemplate   = "**{username}** left the server. We now have {member_count} members."
+	DefaultGreeterBurstLimit      = 5
+	DefaultGreeterBurstWindow     = 30 * time.Second
+)
+
+// Burst retorna o limite de entradas e a janela efetivos do agrupamento.
+func (c *GreeterConfig) Burst() (limit int, window time.Duration) {
+	limit, window = DefaultGreeterBurstLimit, DefaultGreeterBurstWindow
+	if c == nil {
+		return
+	}
+	if c.BurstLimit > 0 {
+		limit = c.BurstLimit
+	}
+	if d, err := time.ParseDuration(c.BurstWindow); err == nil && d > 0 {
+		window = d
+	}
+	return
+}
+
+// AvatarRefreshConfig restringe o refresh de avatares aos membros ativos: quem mandou uma mensagem
+// (ainda no store) ou entrou na guild dentro de ActiveWindow. Os demais são pulados até voltarem a
+// aparecer; trocas vistas por eventos (USER_UPDATE, GUILD_MEMBER_UPDATE) continuam registradas.
+type AvatarRefreshConfig struct {
+	ActiveOnly   bool   `json:"active_only"`
+	ActiveWindow string `json:"active_window,omitempty"` // ex.: "72h" (padrão: DefaultAvatarRefreshActiveWindow)
+}
+
+// DefaultAvatarRefreshActiveWindow é a janela padrão de atividade do refresh restrito.
+const DefaultAvatarRefreshActiveWindow = 7 * 24 * time.Hour
+
+// Window retorna a janela de atividade efetiva.
+func (c *AvatarRefreshConfig) Window() time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.ActiveWindow); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultAvatarRefreshActiveWindow
+}
+
+// Modos de AvatarNotificationConfig.
+const (
+	AvatarNotifyImmediate = "immediate"
+	AvatarNotifyDigest    = "digest"
+)
+
+// Intervalo do resumo de avatares: padrão e mínimo aceito.
+const (
+	DefaultAvatarDigestInterval = 15 * time.Minute
+	MinAvatarDigestInterval     = time.Minute
+)
+
+// AvatarNotificationConfig escolhe como as trocas de avatar chegam ao canal de log: uma notificação
+// por troca ("immediate", padrão) ou um resumo a cada Interval ("digest").
+type AvatarNotificationConfig struct {
+	Mode     string `json:"mode,omitempty"`     // "immediate" ou "digest"
+	Interval string `json:"interval,omitempty"` // ex.: "15m" (padrão: DefaultAvatarDigestInterval)
+}
+
+// Digest informa se as trocas de avatar devem ser agrupadas num resumo.
+func (c *AvatarNotificationConfig) Digest() bool {
+	return c != nil && c.Mode == AvatarNotifyDigest
+}
+
+// DigestInterval retorna o intervalo efetivo do resumo.
+func (c *AvatarNotificationConfig) DigestInterval() time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.Interval); err == nil && d >= MinAvatarDigestInterval {
+			return d
+		}
+	}
+	return DefaultAvatarDigestInterval
+}
+
+// AutoroleConfig configura os cargos atribuídos a quem entra na guild.
+type AutoroleConfig struct {
+	Enabled       bool     `json:"enabled"`
+	RoleIDs       []string `json:"role_ids"`
+	Delay         string   `json:"delay,omitempty"`           // Espera antes de atribuir, ex.: "10m" (vazio: na entrada)
+	MinAccountAge string   `json:"min_account_age,omitempty"` // Contas mais novas que isso não recebem os cargos, ex.: "72h"
+}
+
+// DelayDuration retorna a espera antes da atribuição (0 = imediata).
+func (c *AutoroleConfig) DelayDuration() time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.Delay); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// MinAccountAgeDuration retorna a idade mínima da conta para receber os cargos (0 = sem exigência).
+func (c *AutoroleConfig) MinAccountAgeDuration() time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.MinAccountAge); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// ActivityReportConfig configura o relatório periódico de atividade de uma guild.
+type ActivityReportConfig struct {
+	Enabled   bool   `json:"enabled"`
+	ChannelID string `json:"channel_id"`       // Canal (ou thread) onde o relatório é postado
+	Period    string `json:"period,omitempty"` // Intervalo entre relatórios e período coberto, ex.: "24h" (padrão: DefaultActivityReportPeriod)
+}
+
+// Limites do relatório de atividade.
+const (
+	DefaultActivityReportPeriod = 7 * 24 * time.Hour
+	MinActivityReportPeriod     = time.Hour
+)
+
+// PeriodDuration retorna o período efetivo do relatório.
+func (c *ActivityReportConfig) PeriodDuration() time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.Period); err == nil && d >= MinActivityReportPeriod {
+			return d
+		}
+	}
+	return DefaultActivityReportPeriod
+}
+
+// AttachmentArchiveConfig controla o arquivamento dos bytes dos anexos das mensagens registradas.
+// Anexos maiores que MaxBytes (ou que estourariam MaxTotalBytes na mesma mensagem) ficam só com os metadados.
+type AttachmentArchiveConfig struct {
+	Enabled       bool `json:"enabled"`
+	MaxBytes      int  `json:"max_bytes,omitempty"`       // Por anexo (padrão: DefaultAttachmentArchiveMaxBytes)
+	MaxTotalBytes int  `json:"max_total_bytes,omitempty"` // Por mensagem (padrão: DefaultAttachmentArchiveMaxTotalBytes)
+}
+
+// Limites padrão do arquivamento de anexos.
+const (
+	DefaultAttachmentArchiveMaxBytes      = 8 << 20
+	DefaultAttachmentArchiveMaxTotalBytes = 25 << 20
+)
+
+// Limits retorna os limites efetivos por anexo e por mensagem.
+func (c *AttachmentArchiveConfig) Limits() (perAttachment, perMessage int) {
+	perAttachment, perMessage = c.MaxBytes, c.MaxTotalBytes
+	if perAttachment <= 0 {
+		perAttachment = DefaultAttachmentArchiveMaxBytes
+	}
+	if perMessage <= 0 {
+		perMessage = DefaultAttachmentArchiveMaxTotalBytes
+	}
+	return perAttachment, perMessage
+}
+
+// MessagePrivacyConfig controla o modo privacidade do armazenamento de mensagens. Com Enabled, o
+// conteúdo nunca é gravado (nem em message_attachments/message_embeds ou automod_actions) e
+// Metadata escolhe quais metadados continuam guardados: nil (campo ausente) guarda todos os
+// MessageMetadataFields, [] não guarda nenhum.
+type MessagePrivacyConfig struct {
+	Enabled  bool     `json:"enabled"`
+	Metadata []string `json:"metadata"`
+}
+
+// Metadados de mensagens configuráveis no modo privacidade.
+const (
+	MessageMetadataAuthor      = "author"      // author_id (nome e avatar nunca são gravados em modo privacidade)
+	MessageMetadataChannel     = "channel"     // channel_id
+	MessageMetadataTimestamp   = "timestamp"   // cached_at exato; sem ele, truncado à hora
+	MessageMetadataLength      = "length"      // original_length (tamanho do texto em runas)
+	MessageMetadataAttachments = "attachments" // attachment_count
+)
+
+// MessageMetadataFields lista os metadados aceitos em message_privacy.metadata.
+var MessageMetadataFields = []string{
+	MessageMetadataAuthor,
+	MessageMetadataChannel,
+	MessageMetadataTimestamp,
+	MessageMetadataLength,
+	MessageMetadataAttachments,
+}
+
+// PrivacyModeEnabled reporta se as mensagens da guild são gravadas sem conteúdo.
+func (gc *GuildConfig) PrivacyModeEnabled() bool {
+	return gc != nil && gc.MessagePrivacy != nil && gc.MessagePrivacy.Enabled
+}
+
+// Retains reporta se o metadado field é guardado no modo privacidade.
+func (c *MessagePrivacyConfig) Retains(field string) bool {
+	return c.Metadata == nil || slices.Contains(c.Metadata, field)
+}
+
+// Ações da varredura de links.
+const (
+	LinkActionFlag          = "flag"
+	LinkActionDelete        = "delete"
+	LinkActionDeleteTimeout = "delete_timeout"
+)
+
+// LinkAction retorna a ação efetiva para links/anexos bloqueados (padrão: LinkActionDelete).
+func (c *AutomodLinkConfig) LinkAction() string {
+	return normalizeLinkAction(c.Action, LinkActionDelete)
+}
+
+// NewAccountLinkAction retorna a ação efetiva para links de contas novas (padrão: LinkActionFlag).
+func (c *AutomodLinkConfig) NewAccountLinkAction() string {
+	return normalizeLinkAction(c.NewAccountAction, LinkActionFlag)
+}
+
+// NewAccountWindow retorna a idade mínima de membro para postar links sem sinalização (0 = desativado).
+func (c *AutomodLinkConfig) NewAccountWindow() time.Duration {
+	d, err := time.ParseDuration(c.NewAccountAge)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// Timeout retorna a duração do timeout aplicado por LinkActionDeleteTimeout.
+func (c *AutomodLinkConfig) Timeout() time.Duration {
+	if d, err := time.ParseDuration(c.TimeoutDuration); err == nil && d > 0 {
+		return d
+	}
+	return DefaultSpamTimeout
+}
+
+func normalizeLinkAction(action, fallback string) string {
+	switch action {
+	case LinkActionFlag, LinkActionDelete, LinkActionDeleteTimeout:
+		return action
+	default:
+		return fallback
+	}
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
 // FindListByName searches for a list by its name in LooseLists.
 func (gc *GuildConfig) FindListByName(name string) *List {
 	for _, rule := range gc.LooseLists {
@@ -0,0 +1,159 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// DefaultConfigWatchInterval is the default interval between config file checks.
+const DefaultConfigWatchInterval = 5 * time.Second
+
+// configStamp identifies a version of the config file on disk.
+type configStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s configStamp) equal(o configStamp) bool {
+	return s.size == o.size && s.modTime.Equal(o.modTime)
+}
+
+func statConfig(path string) (configStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return configStamp{}, false
+	}
+	return configStamp{modTime: info.ModTime(), size: info.Size()}, true
+}
+
+// recordStamp remembers the current file version so the watcher ignores the bot's own writes.
+func (mgr *ConfigManager) recordStamp() {
+	stamp, _ := statConfig(mgr.configFilePath)
+	mgr.stampMu.Lock()
+	mgr.stamp = stamp
+	mgr.stampMu.Unlock()
+}
+
+// OnReload registers fn to be called with the new config after every successful reload from
+// disk (ReloadConfig or Watch). It returns a func that removes the listener.
+func (mgr *ConfigManager) OnReload(fn func(*BotConfig)) func() {
+	if fn == nil {
+		return func() {}
+	}
+	mgr.reloadMu.Lock()
+	if mgr.reloadHooks == nil {
+		mgr.reloadHooks = make(map[int]func(*BotConfig))
+	}
+	id := mgr.nextReloadHook
+	mgr.nextReloadHook++
+	mgr.reloadHooks[id] = fn
+	mgr.reloadMu.Unlock()
+	return func() {
+		mgr.reloadMu.Lock()
+		delete(mgr.reloadHooks, id)
+		mgr.reloadMu.Unlock()
+	}
+}
+
+// ReloadConfig re-reads the config file and, if every guild is valid, swaps the in-memory
+// config and calls the OnReload listeners. An invalid config is rejected and the current one
+// stays in effect.
+//
+// The read and the swap happen under mgr.mu, as in LoadConfig: a concurrent UpdateGuild or
+// SaveConfig either finishes before the read (and is seen by it) or waits for the swap.
+// Listeners get a deep copy that later writes don't change.
+func (mgr *ConfigManager) ReloadConfig() error {
+	mgr.mu.Lock()
+	loaded := &BotConfig{Guilds: []GuildConfig{}}
+	if err := mgr.jsonManager.Load(loaded); err != nil {
+		mgr.mu.Unlock()
+		return fmt.Errorf("read %s: %w", mgr.configFilePath, err)
+	}
+	for i := range loaded.Guilds {
+		if err := loaded.Guilds[i].Validate(); err != nil {
+			mgr.mu.Unlock()
+			return fmt.Errorf("guild %s: %w", loaded.Guilds[i].GuildID, err)
+		}
+	}
+	if _, err := normalizeOperatorIDs(loaded.BotOperators); err != nil {
+		mgr.mu.Unlock()
+		return err
+	}
+	snapshot, err := cloneBotConfig(loaded)
+	if err != nil {
+		mgr.mu.Unlock()
+		return fmt.Errorf("copy config: %w", err)
+	}
+	mgr.config = loaded
+	mgr.rebuildGuildIndexLocked()
+	mgr.recordStamp()
+	mgr.mu.Unlock()
+
+	mgr.reloadMu.Lock()
+	hooks := make([]func(*BotConfig), 0, len(mgr.reloadHooks))
+	for _, fn := range mgr.reloadHooks {
+		hooks = append(hooks, fn)
+	}
+	mgr.reloadMu.Unlock()
+
+	log.Info().Applicationf("🔄 Configuration reloaded from %s (%d guilds)", mgr.configFilePath, len(snapshot.Guilds))
+	for _, fn := range hooks {
+		fn(snapshot)
+	}
+	return nil
+}
+
+// cloneBotConfig deep-copies the config, like cloneGuildConfig.
+func cloneBotConfig(cfg *BotConfig) (*BotConfig, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := &BotConfig{}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Watch checks the config file every interval (default: DefaultConfigWatchInterval) and calls
+// ReloadConfig when it changes externally; the bot's own writes (SaveConfig) are ignored. It
+// returns a func that stops the watcher.
+func (mgr *ConfigManager) Watch(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultConfigWatchInterval
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			current, ok := statConfig(mgr.configFilePath)
+			if !ok {
+				continue
+			}
+			mgr.stampMu.Lock()
+			changed := !current.equal(mgr.stamp)
+			mgr.stamp = current
+			mgr.stampMu.Unlock()
+			if !changed {
+				continue
+			}
+			if err := mgr.ReloadConfig(); err != nil {
+				log.Warn().Applicationf("Configuration change ignored (keeping the current config): %v", err)
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -80,19 +80,32 @@ func Error() *ErrorLogger {
 // --- Fluent API Finalizers ---
 
 func (cl *CategorizedLogger) Applicationf(format string, v ...interface{}) {
-	cl.log(cl.logger.application, format, v...)
+	cl.log(CategoryApplication, format, v...)
 }
 
 func (cl *CategorizedLogger) Discordf(format string, v ...interface{}) {
-	cl.log(cl.logger.discord, format, v...)
+	cl.log(CategoryDiscord, format, v...)
 }
 
 func (cl *CategorizedLogger) Databasef(format string, v ...interface{}) {
-	cl.log(cl.logger.database, format, v...)
+	cl.log(CategoryDatabase, format, v...)
 }
 
-func (cl *CategorizedLogger) log(target *stdlog.Logger, format string, v ...interface{}) {
+func (cl *CategorizedLogger) log(cat Category, format string, v ...interface{}) {
+	var target *stdlog.Logger
+	if cl.logger != nil {
+		target = cl.logger.target(cat)
+	}
+	// Amostragem antes de formatar: mensagens descartadas não custam um Sprintf
+	if !sampled(cat, target) {
+		return
+	}
+	level := "INFO"
+	if cl.level == WarnLevel {
+		level = "WARN"
+	}
 	if cl.logger == nil {
+		recordRecent(level, cat.String(), fmt.Sprintf(format, v...))
 		stdlog.Printf(format, v...)
 		return
 	}
@@ -100,28 +113,32 @@ func (cl *CategorizedLogger) log(target *stdlog.Logger, format string, v ...inte
 	if cl.level == WarnLevel {
 		msg = "WARN: " + msg
 	}
-	cl.logger.writeTo(target, msg, v...)
+	cl.logger.writeTo(target, level, cat.String(), msg, v...)
 }
 
 func (el *ErrorLogger) Errorf(format string, v ...interface{}) {
 	if el.logger == nil {
+		recordRecent("ERROR", "error", fmt.Sprintf(format, v...))
 		stdlog.Printf("ERROR: "+format, v...)
 		return
 	}
-	el.logger.writeTo(el.logger.error, "ERROR: "+format, v...)
+	el.logger.writeTo(el.logger.error, "ERROR", "error", "ERROR: "+format, v...)
 }
 
 func (el *ErrorLogger) Fatalf(format string, v ...interface{}) {
 	if el.logger == nil {
 		stdlog.Fatalf("FATAL: "+format, v...)
 	}
-	el.logger.writeTo(el.logger.error, "FATAL: "+format, v...)
+	el.logger.writeTo(el.logger.error, "FATAL", "error", "FATAL: "+format, v...)
 	os.Exit(1)
 }
 
 // --- Initialization & Helpers ---
 
 func getDefaultLogDir() string {
+	if util.IsDevMode() {
+		return filepath.Dir(util.GetLogFilePath())
+	}
 	if home, err := os.UserHomeDir(); err == nil && home != "" {
 		return filepath.Join(home, ".log", util.EffectiveBotName())
 	}
@@ -160,11 +177,29 @@ func SetupLogger() error {
 	}
 	GlobalLogger = globalLogger
 	globalLogger.Info().Applicationf("logger initialized at %s", time.Now().Format(time.RFC3339Nano))
+	if problem := configureRecentFromEnv(); problem != "" {
+		globalLogger.Warn().Applicationf("%s", problem)
+	}
+	for _, problem := range configureSamplingFromEnv() {
+		globalLogger.Warn().Applicationf("%s", problem)
+	}
 	return nil
 }
 
-func (l *Logger) writeTo(target *stdlog.Logger, format string, v ...interface{}) {
+func (l *Logger) target(cat Category) *stdlog.Logger {
+	switch cat {
+	case CategoryDiscord:
+		return l.discord
+	case CategoryDatabase:
+		return l.database
+	default:
+		return l.application
+	}
+}
+
+func (l *Logger) writeTo(target *stdlog.Logger, level, category, format string, v ...interface{}) {
 	message := fmt.Sprintf(format, v...)
+	recordRecent(level, category, message)
 	if target == nil {
 		stdlog.Printf("%s\n", message)
 		return
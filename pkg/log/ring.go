@@ -0,0 +1,149 @@
+package log
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRecentLines is the default capacity of the in-memory buffer of recent log lines.
+const DefaultRecentLines = 500
+
+// RecentLinesEnv overrides the buffer capacity at SetupLogger; 0 disables the buffer.
+const RecentLinesEnv = "DISCORDCORE_LOG_BUFFER"
+
+// Entry is a log line kept in the recent-lines buffer.
+type Entry struct {
+	Time     time.Time
+	Level    string // INFO, WARN, ERROR ou FATAL
+	Category string // application, discord, database ou error
+	Message  string
+}
+
+// ringBuffer guarda as últimas linhas de log (sobrescreve as mais antigas).
+type ringBuffer struct {
+	mu    sync.Mutex
+	lines []Entry
+	next  int
+	full  bool
+}
+
+var (
+	recentMu sync.RWMutex
+	recent   = newRingBuffer(DefaultRecentLines)
+)
+
+func newRingBuffer(size int) *ringBuffer {
+	if size <= 0 {
+		return nil
+	}
+	return &ringBuffer{lines: make([]Entry, size)}
+}
+
+func (r *ringBuffer) add(e Entry) {
+	r.mu.Lock()
+	r.lines[r.next] = e
+	r.next++
+	if r.next == len(r.lines) {
+		r.next = 0
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// snapshot returns the buffered lines, oldest first.
+func (r *ringBuffer) snapshot() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Entry(nil), r.lines[:r.next]...)
+	}
+	out := make([]Entry, 0, len(r.lines))
+	out = append(out, r.lines[r.next:]...)
+	return append(out, r.lines[:r.next]...)
+}
+
+// SetRecentLines resizes the recent-lines buffer (discarding its content); 0 disables it.
+func SetRecentLines(size int) {
+	recentMu.Lock()
+	recent = newRingBuffer(size)
+	recentMu.Unlock()
+}
+
+// recordRecent copia a linha para o buffer, se ativo.
+func recordRecent(level, category, message string) {
+	recentMu.RLock()
+	r := recent
+	recentMu.RUnlock()
+	if r != nil {
+		r.add(Entry{Time: time.Now(), Level: level, Category: category, Message: message})
+	}
+}
+
+// Recent returns up to limit of the most recent buffered lines matching level and category
+// (empty matches any), oldest first. Messages are passed through Redact.
+func Recent(level, category string, limit int) []Entry {
+	recentMu.RLock()
+	r := recent
+	recentMu.RUnlock()
+	if r == nil || limit <= 0 {
+		return nil
+	}
+	all := r.snapshot()
+	var out []Entry
+	for i := len(all) - 1; i >= 0 && len(out) < limit; i-- {
+		e := all[i]
+		if level != "" && !strings.EqualFold(e.Level, level) {
+			continue
+		}
+		if category != "" && !strings.EqualFold(e.Category, category) {
+			continue
+		}
+		e.Message = Redact(e.Message)
+		out = append(out, e)
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+var redactPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Tokens de bot/usuário do Discord
+	{regexp.MustCompile(`[MNO][A-Za-z\d_-]{23,27}\.[A-Za-z\d_-]{6}\.[A-Za-z\d_-]{27,40}`), "[REDACTED_TOKEN]"},
+	// Cabeçalhos de autorização
+	{regexp.MustCompile(`(?i)\b(Bot|Bearer)\s+[A-Za-z\d._-]{20,}`), "$1 [REDACTED]"},
+	// URLs de webhook carregam o token no caminho
+	{regexp.MustCompile(`(?i)(discord(?:app)?\.com/api/(?:v\d+/)?webhooks/\d+/)[A-Za-z\d_-]+`), "${1}[REDACTED]"},
+	// pares chave=valor / chave: valor sensíveis
+	{regexp.MustCompile(`(?i)\b(token|password|passwd|secret|api[_-]?key|authorization)(\s*[=:]\s*)("[^"]*"|\S+)`), "$1$2[REDACTED]"},
+}
+
+// Redact masks secrets (Discord tokens, authorization headers, webhook tokens and
+// token/password/secret key-value pairs) in a log line before it leaves the host.
+func Redact(s string) string {
+	for _, p := range redactPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+// configureRecentFromEnv applies RecentLinesEnv, returning a problem description if invalid.
+func configureRecentFromEnv() string {
+	raw := strings.TrimSpace(os.Getenv(RecentLinesEnv))
+	if raw == "" {
+		return ""
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return "invalid " + RecentLinesEnv + " value " + strconv.Quote(raw) + "; keeping the default"
+	}
+	SetRecentLines(n)
+	return ""
+}
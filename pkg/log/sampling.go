@@ -0,0 +1,169 @@
+package log
+
+import (
+	"fmt"
+	stdlog "log"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Category identifies a log stream that can be sampled.
+type Category int
+
+const (
+	CategoryApplication Category = iota
+	CategoryDiscord
+	CategoryDatabase
+	categoryCount
+)
+
+// SamplingEnv configures sampling at SetupLogger, as a comma-separated list of
+// "category:threshold:rate" entries (category is application, discord or database),
+// e.g. "discord:200:10" logs 1 in 10 Discord events once they exceed 200/sec.
+const SamplingEnv = "DISCORDCORE_LOG_SAMPLING"
+
+// DefaultSamplingReportInterval is how often the number of suppressed messages is reported.
+const DefaultSamplingReportInterval = time.Minute
+
+// SamplingConfig limits a category under load: within each one-second window the first
+// Threshold messages are logged, and past that only 1 in Rate. Errors are never sampled.
+type SamplingConfig struct {
+	Threshold      int           // mensagens/segundo registradas integralmente
+	Rate           int           // acima do limite, registra 1 a cada Rate (<= 1 desativa)
+	ReportInterval time.Duration // intervalo do resumo de suprimidas (padrão DefaultSamplingReportInterval)
+}
+
+// sampler keeps the per-category counters. Decisions are a couple of atomic operations and
+// happen before the message is formatted, so dropped messages cost almost nothing.
+type sampler struct {
+	cfg        SamplingConfig
+	window     atomic.Int64 // segundo (unix) da janela atual
+	count      atomic.Int64 // mensagens vistas na janela atual
+	suppressed atomic.Int64 // suprimidas desde o último resumo
+	lastReport atomic.Int64 // unix nano do último resumo
+}
+
+var samplers [categoryCount]atomic.Pointer[sampler]
+
+// SetSampling enables (or replaces) sampling for a category. A config with Rate <= 1 or
+// Threshold < 0 disables it; sampling is off by default.
+func SetSampling(cat Category, cfg SamplingConfig) {
+	if cat < 0 || cat >= categoryCount {
+		return
+	}
+	if cfg.Rate <= 1 || cfg.Threshold < 0 {
+		samplers[cat].Store(nil)
+		return
+	}
+	if cfg.ReportInterval <= 0 {
+		cfg.ReportInterval = DefaultSamplingReportInterval
+	}
+	s := &sampler{cfg: cfg}
+	s.lastReport.Store(time.Now().UnixNano())
+	samplers[cat].Store(s)
+}
+
+// DisableSampling turns sampling off for a category.
+func DisableSampling(cat Category) {
+	SetSampling(cat, SamplingConfig{})
+}
+
+// allow decides whether a message should be written and returns the number of suppressed
+// messages to report (0 when there is nothing to report yet).
+func (s *sampler) allow(now time.Time) (bool, int64) {
+	sec := now.Unix()
+	if w := s.window.Load(); w != sec && s.window.CompareAndSwap(w, sec) {
+		s.count.Store(0)
+	}
+	n := s.count.Add(1)
+	if n <= int64(s.cfg.Threshold) || n%int64(s.cfg.Rate) == 0 {
+		return true, s.takeReport(now)
+	}
+	s.suppressed.Add(1)
+	return false, 0
+}
+
+// takeReport returns the suppressed count once per ReportInterval (only one caller wins).
+func (s *sampler) takeReport(now time.Time) int64 {
+	last := s.lastReport.Load()
+	if now.UnixNano()-last < int64(s.cfg.ReportInterval) || s.suppressed.Load() == 0 {
+		return 0
+	}
+	if !s.lastReport.CompareAndSwap(last, now.UnixNano()) {
+		return 0
+	}
+	return s.suppressed.Swap(0)
+}
+
+// sampled aplica a amostragem da categoria; sem configuração, sempre registra.
+func sampled(cat Category, target *stdlog.Logger) bool {
+	s := samplers[cat].Load()
+	if s == nil {
+		return true
+	}
+	ok, report := s.allow(time.Now())
+	if report > 0 {
+		msg := fmt.Sprintf("log sampling: suppressed %d %s messages in the last %s", report, cat, s.cfg.ReportInterval)
+		if target != nil {
+			target.Printf("%s", msg)
+		} else {
+			stdlog.Printf("%s\n", msg)
+		}
+	}
+	return ok
+}
+
+func (c Category) String() string {
+	switch c {
+	case CategoryApplication:
+		return "application"
+	case CategoryDiscord:
+		return "discord"
+	case CategoryDatabase:
+		return "database"
+	default:
+		return "unknown"
+	}
+}
+
+// parseCategory maps a SamplingEnv category name.
+func parseCategory(name string) (Category, bool) {
+	for c := Category(0); c < categoryCount; c++ {
+		if strings.EqualFold(name, c.String()) {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// configureSamplingFromEnv applies SamplingEnv; malformed entries are reported and skipped.
+func configureSamplingFromEnv() []string {
+	raw := strings.TrimSpace(os.Getenv(SamplingEnv))
+	if raw == "" {
+		return nil
+	}
+	var problems []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			problems = append(problems, fmt.Sprintf("invalid %s entry %q: expected category:threshold:rate", SamplingEnv, entry))
+			continue
+		}
+		cat, ok := parseCategory(strings.TrimSpace(parts[0]))
+		threshold, errT := strconv.Atoi(strings.TrimSpace(parts[1]))
+		rate, errR := strconv.Atoi(strings.TrimSpace(parts[2]))
+		if !ok || errT != nil || errR != nil || threshold < 0 || rate < 1 {
+			problems = append(problems, fmt.Sprintf("invalid %s entry %q", SamplingEnv, entry))
+			continue
+		}
+		SetSampling(cat, SamplingConfig{Threshold: threshold, Rate: rate})
+	}
+	return problems
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 )
 
@@ -319,6 +320,13 @@ type ServiceWrapper struct {
 	wrappedStart func() error
 	wrappedStop  func() error
 	wrappedCheck func() bool
+
+	// Optional hot reload (Reloadable); nil means the wrapped service has nothing to reload
+	wrappedReload func(cfg *files.BotConfig) error
+
+	// Lifecycle timeouts (0 = use the manager default)
+	startTimeout time.Duration
+	stopTimeout  time.Duration
 }
 
 // NewServiceWrapper creates a wrapper for existing services
@@ -377,6 +385,35 @@ func NewServiceWrapper(
 	return wrapper
 }
 
+// SetStartTimeout overrides how long the manager waits for this service to start.
+func (sw *ServiceWrapper) SetStartTimeout(d time.Duration) {
+	sw.startTimeout = d
+}
+
+// SetStopTimeout overrides how long the manager waits for this service to stop.
+func (sw *ServiceWrapper) SetStopTimeout(d time.Duration) {
+	sw.stopTimeout = d
+}
+
+// SetReloadFunc sets the function called by Reload when the configuration changes.
+func (sw *ServiceWrapper) SetReloadFunc(fn func(cfg *files.BotConfig) error) {
+	sw.wrappedReload = fn
+}
+
+// Reload implements Reloadable. Without a reload function it is a no-op.
+func (sw *ServiceWrapper) Reload(cfg *files.BotConfig) error {
+	if sw.wrappedReload == nil {
+		return nil
+	}
+	return sw.wrappedReload(cfg)
+}
+
+// StartTimeout implements LifecycleTimeouts.
+func (sw *ServiceWrapper) StartTimeout() time.Duration { return sw.startTimeout }
+
+// StopTimeout implements LifecycleTimeouts.
+func (sw *ServiceWrapper) StopTimeout() time.Duration { return sw.stopTimeout }
+
 // ManagedService provides a higher-level service implementation with automatic lifecycle management
 type ManagedService struct {
 	*BaseService
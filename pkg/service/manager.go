@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 )
 
@@ -32,6 +34,7 @@ const (
 	TypeCommands   ServiceType = "commands"
 	TypeCache      ServiceType = "cache"
 	TypeNotifier   ServiceType = "notifier"
+	TypeScheduler  ServiceType = "scheduler"
 )
 
 // ServicePriority determines startup/shutdown order (higher number = higher priority)
@@ -91,6 +94,28 @@ type Service interface {
 	Stats() ServiceStats
 }
 
+// LifecycleTimeouts can be implemented by services that need start/stop timeouts
+// different from the manager defaults. A zero duration means "use the default".
+type LifecycleTimeouts interface {
+	StartTimeout() time.Duration
+	StopTimeout() time.Duration
+}
+
+// Reloadable is implemented by services that can apply a new configuration without a full
+// Stop/Start (which would lose in-flight state). Reload should swap any state derived from the
+// configuration atomically. Returning ErrReloadRequiresRestart asks the manager to restart
+// the service instead.
+type Reloadable interface {
+	Reload(cfg *files.BotConfig) error
+}
+
+// ErrReloadRequiresRestart is returned by Reload when the new configuration can only be applied
+// by restarting the service.
+var ErrReloadRequiresRestart = stderrors.New("service reload requires restart")
+
+// ErrLifecycleTimeout is returned (wrapped) when a service exceeds its start/stop timeout.
+var ErrLifecycleTimeout = stderrors.New("service lifecycle timeout")
+
 // ServiceInfo holds metadata about a registered service
 type ServiceInfo struct {
 	Service       Service              `json:"-"`
@@ -118,6 +143,7 @@ type ServiceManager struct {
 	healthStopOnce sync.Once
 
 	// Configuration
+	startTimeout    time.Duration
 	shutdownTimeout time.Duration
 	healthInterval  time.Duration
 	maxRestarts     int
@@ -136,6 +162,7 @@ func NewServiceManager(errorHandler *errors.ErrorHandler) *ServiceManager {
 		cancel:          cancel,
 		healthStop:      make(chan struct{}),
 		errorHandler:    errorHandler,
+		startTimeout:    30 * time.Second,
 		shutdownTimeout: 30 * time.Second,
 		healthInterval:  1 * time.Minute,
 		maxRestarts:     3,
@@ -273,13 +300,16 @@ func (sm *ServiceManager) StartService(name string) error {
 	}
 
 	// Start the service
-	ctx, cancel := context.WithTimeout(sm.ctx, 30*time.Second)
+	timeout := sm.serviceStartTimeout(info.Service)
+	ctx, cancel := context.WithTimeout(sm.ctx, timeout)
 	defer cancel()
 
 	log.Info().Applicationf("service %s: Starting service...", name)
 
-	err := sm.errorHandler.HandleWithRetry(ctx, "start_service", name, func() error {
-		return info.Service.Start(ctx)
+	err := sm.runWithTimeout(ctx, name, "start", timeout, func() error {
+		return sm.errorHandler.HandleWithRetry(ctx, "start_service", name, func() error {
+			return info.Service.Start(ctx)
+		})
 	})
 
 	sm.mu.Lock()
@@ -333,12 +363,15 @@ func (sm *ServiceManager) StopService(name string) error {
 	}
 
 	// Stop the service
-	ctx, cancel := context.WithTimeout(context.Background(), sm.shutdownTimeout)
+	timeout := sm.serviceStopTimeout(info.Service)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	log.Info().Applicationf("service %s: Stopping service...", name)
 
-	err := info.Service.Stop(ctx)
+	err := sm.runWithTimeout(ctx, name, "stop", timeout, func() error {
+		return info.Service.Stop(ctx)
+	})
 
 	sm.mu.Lock()
 	if err != nil {
@@ -368,6 +401,59 @@ func (sm *ServiceManager) StopService(name string) error {
 	return nil
 }
 
+// SetStartTimeout sets the default timeout applied to each service Start.
+func (sm *ServiceManager) SetStartTimeout(d time.Duration) {
+	if d > 0 {
+		sm.startTimeout = d
+	}
+}
+
+// SetShutdownTimeout sets the default timeout applied to each service Stop.
+func (sm *ServiceManager) SetShutdownTimeout(d time.Duration) {
+	if d > 0 {
+		sm.shutdownTimeout = d
+	}
+}
+
+func (sm *ServiceManager) serviceStartTimeout(svc Service) time.Duration {
+	if lt, ok := svc.(LifecycleTimeouts); ok {
+		if d := lt.StartTimeout(); d > 0 {
+			return d
+		}
+	}
+	return sm.startTimeout
+}
+
+func (sm *ServiceManager) serviceStopTimeout(svc Service) time.Duration {
+	if lt, ok := svc.(LifecycleTimeouts); ok {
+		if d := lt.StopTimeout(); d > 0 {
+			return d
+		}
+	}
+	return sm.shutdownTimeout
+}
+
+// runWithTimeout runs fn in its own goroutine and returns when it finishes or ctx expires.
+// On timeout the goroutine is abandoned (it cannot be forcibly stopped) and a warning is logged,
+// so a single hung service cannot wedge the manager's lifecycle.
+func (sm *ServiceManager) runWithTimeout(ctx context.Context, name, op string, timeout time.Duration, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if ctx.Err() != context.DeadlineExceeded {
+			return ctx.Err()
+		}
+		log.Warn().Applicationf("service %s: %s exceeded timeout of %s; abandoning %s goroutine", name, op, timeout, op)
+		return fmt.Errorf("service '%s' %s timed out after %s: %w", name, op, timeout, ErrLifecycleTimeout)
+	}
+}
+
 // RestartService restarts a specific service
 func (sm *ServiceManager) RestartService(name string) error {
 	log.Info().Applicationf("service %s: Restarting service...", name)
@@ -387,6 +473,43 @@ func (sm *ServiceManager) RestartService(name string) error {
 	return sm.StartService(name)
 }
 
+// ReloadAll applies a new configuration to every running service, in start order. Services
+// implementing Reloadable are reloaded (or restarted when they return ErrReloadRequiresRestart);
+// the others read the configuration at use time and are left alone.
+func (sm *ServiceManager) ReloadAll(cfg *files.BotConfig) error {
+	order, err := sm.calculateStartOrder()
+	if err != nil {
+		return fmt.Errorf("failed to calculate reload order: %w", err)
+	}
+
+	var reloadErrors []error
+	for _, name := range order {
+		sm.mu.RLock()
+		info := sm.services[name]
+		running := info.State == StateRunning
+		sm.mu.RUnlock()
+		r, ok := info.Service.(Reloadable)
+		if !running || !ok {
+			continue
+		}
+		err := r.Reload(cfg)
+		if stderrors.Is(err, ErrReloadRequiresRestart) {
+			err = sm.RestartService(name)
+		}
+		if err != nil {
+			log.Error().Errorf("Failed to reload service: service=%s error=%v", name, err)
+			reloadErrors = append(reloadErrors, fmt.Errorf("failed to reload service '%s': %w", name, err))
+			continue
+		}
+		log.Info().Applicationf("service %s: Configuration reloaded", name)
+	}
+
+	if len(reloadErrors) > 0 {
+		return fmt.Errorf("failed to reload services: %v", reloadErrors)
+	}
+	return nil
+}
+
 // GetServiceInfo returns information about a specific service
 func (sm *ServiceManager) GetServiceInfo(name string) (*ServiceInfo, error) {
 	sm.mu.RLock()
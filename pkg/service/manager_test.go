@@ -0,0 +1,122 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+)
+
+// blockingService returns a wrapper whose start or stop blocks until release is closed.
+func blockingService(name string, blockStart, blockStop bool, release <-chan struct{}) *ServiceWrapper {
+	wait := func() error {
+		<-release
+		return nil
+	}
+	var start, stop func() error
+	if blockStart {
+		start = wait
+	}
+	if blockStop {
+		stop = wait
+	}
+	return NewServiceWrapper(name, TypeMonitoring, PriorityNormal, nil, start, stop, nil)
+}
+
+func newTestManager(t *testing.T, services ...Service) *ServiceManager {
+	t.Helper()
+	sm := NewServiceManager(errors.NewErrorHandler())
+	for _, svc := range services {
+		if err := sm.Register(svc); err != nil {
+			t.Fatalf("register %s: %v", svc.Name(), err)
+		}
+	}
+	return sm
+}
+
+func TestStartAllTimesOutSlowService(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	slow := blockingService("slow", true, false, release)
+	slow.SetStartTimeout(50 * time.Millisecond)
+	fast := blockingService("fast", false, false, release)
+	sm := newTestManager(t, slow, fast)
+
+	begin := time.Now()
+	err := sm.StartAll()
+	if err == nil {
+		t.Fatal("StartAll succeeded with a hung service")
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Errorf("StartAll took %s, want it bounded by the start timeout", elapsed)
+	}
+	if !strings.Contains(err.Error(), "'slow'") || !strings.Contains(err.Error(), ErrLifecycleTimeout.Error()) {
+		t.Errorf("error %q does not name the slow service and the timeout", err)
+	}
+	if strings.Contains(err.Error(), "'fast'") {
+		t.Errorf("error %q blames the fast service", err)
+	}
+
+	info, err := sm.GetServiceInfo("slow")
+	if err != nil {
+		t.Fatalf("service info: %v", err)
+	}
+	if info.State != StateError {
+		t.Errorf("slow service state = %s, want %s", info.State, StateError)
+	}
+	// StartAll rolls back the services that did start
+	if fast.IsRunning() {
+		t.Error("fast service left running after a failed StartAll")
+	}
+}
+
+func TestStartAllUsesManagerDefaultTimeout(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	sm := newTestManager(t, blockingService("slow", true, false, release))
+	sm.SetStartTimeout(50 * time.Millisecond)
+	done := make(chan error, 1)
+	go func() { done <- sm.StartAll() }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "'slow'") {
+			t.Errorf("StartAll error = %v, want a timeout naming slow", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartAll is still blocked on the slow service")
+	}
+}
+
+func TestStopAllTimesOutSlowService(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	slow := blockingService("slow", false, true, release)
+	slow.SetStopTimeout(50 * time.Millisecond)
+	other := blockingService("other", false, false, release)
+	sm := newTestManager(t, slow, other)
+	if err := sm.StartAll(); err != nil {
+		t.Fatalf("StartAll: %v", err)
+	}
+
+	begin := time.Now()
+	err := sm.StopAll()
+	if err == nil || !strings.Contains(err.Error(), "'slow'") {
+		t.Fatalf("StopAll error = %v, want a timeout naming slow", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Errorf("StopAll took %s, want it bounded by the stop timeout", elapsed)
+	}
+	for _, name := range []string{"slow", "other"} {
+		info, err := sm.GetServiceInfo(name)
+		if err != nil {
+			t.Fatalf("service info: %v", err)
+		}
+		if info.State != StateStopped {
+			t.Errorf("%s state = %s, want %s", name, info.State, StateStopped)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChannelMessageCount is the number of stored messages of a channel over a period.
+type ChannelMessageCount struct {
+	ChannelID string
+	Count     int64
+}
+
+// MessageCountsByChannel counts the stored messages of a guild cached within [since, until),
+// per channel, busiest first. Only messages still in the store (not yet expired) are counted;
+// a zero until means "now".
+func (s *Store) MessageCountsByChannel(guildID string, since, until time.Time) ([]ChannelMessageCount, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	rows, err := s.dbFor(guildID).Query(
+		`SELECT channel_id, COUNT(*) AS total
+         FROM messages
+         WHERE guild_id=? AND cached_at >= ? AND cached_at < ?
+         GROUP BY channel_id
+         ORDER BY total DESC, channel_id ASC`,
+		guildID, since.UTC(), until.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ChannelMessageCount
+	for rows.Next() {
+		var c ChannelMessageCount
+		if err := rows.Scan(&c.ChannelID, &c.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// CountMemberJoins counts the members whose latest recorded join falls within [since, until);
+// a zero until means "now".
+func (s *Store) CountMemberJoins(guildID string, since, until time.Time) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	var n int64
+	err := s.dbFor(guildID).QueryRow(
+		`SELECT COUNT(*) FROM member_joins WHERE guild_id=? AND joined_at >= ? AND joined_at < ?`,
+		guildID, since.UTC(), until.UTC(),
+	).Scan(&n)
+	return n, err
+}
+
+// ActiveMemberIDs returns the users of a guild with recent activity: an author of a message
+// cached since the given time (only messages still in the store count) or a member whose latest
+// recorded join is since then.
+func (s *Store) ActiveMemberIDs(guildID string, since time.Time) (map[string]struct{}, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	rows, err := s.dbFor(guildID).Query(
+		`SELECT author_id FROM messages WHERE guild_id=? AND cached_at >= ? AND author_id <> ''
+         UNION
+         SELECT user_id FROM member_joins WHERE guild_id=? AND joined_at >= ?`,
+		guildID, since.UTC(), guildID, since.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]struct{})
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = struct{}{}
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestActivityCountsRespectUntil(t *testing.T) {
+	s := newTestStore(t, Options{})
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 7)
+
+	for _, at := range []time.Time{since, since.Add(time.Hour), until, until.Add(time.Hour)} {
+		if err := s.RecordLeave("g1", "u1", at); err != nil {
+			t.Fatalf("record leave: %v", err)
+		}
+		if _, err := s.RecordAutomodAction(AutomodAction{GuildID: "g1", UserID: "u1", Action: "timeout", CreatedAt: at}); err != nil {
+			t.Fatalf("record automod action: %v", err)
+		}
+	}
+	if err := s.RecordJoin("g1", "u1", since.Add(time.Minute)); err != nil {
+		t.Fatalf("record join: %v", err)
+	}
+
+	leaves, err := s.CountMembershipEvents("g1", MembershipLeave, since, until)
+	if err != nil {
+		t.Fatalf("count leaves: %v", err)
+	}
+	if leaves != 2 {
+		t.Errorf("leaves = %d, want 2", leaves)
+	}
+	counts, err := s.AutomodActionCountsBetween("g1", since, until)
+	if err != nil {
+		t.Fatalf("automod counts: %v", err)
+	}
+	if counts["timeout"] != 2 {
+		t.Errorf("timeouts = %d, want 2", counts["timeout"])
+	}
+}
+
+func TestTopEmojisBetween(t *testing.T) {
+	s := newTestStore(t, Options{})
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 7)
+
+	in := []EmojiUse{{ID: "111", Name: "party", Animated: true}, {ID: "222", Name: "smile"}}
+	for _, at := range []time.Time{since, until.Add(-time.Second)} {
+		if err := s.IncrementEmojiUsage("g1", in, at); err != nil {
+			t.Fatalf("increment: %v", err)
+		}
+	}
+	if err := s.IncrementEmojiUsage("g1", in[:1], until); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+
+	top, err := s.TopEmojisBetween(context.Background(), "g1", since, until, 10)
+	if err != nil {
+		t.Fatalf("top emojis: %v", err)
+	}
+	if len(top) != 2 {
+		t.Fatalf("got %d entries, want 2", len(top))
+	}
+	want := map[string]struct {
+		count  int64
+		markup string
+	}{
+		"111": {2, "<a:party:111>"},
+		"222": {2, "<:smile:222>"},
+	}
+	for _, u := range top {
+		w := want[u.ID]
+		if u.Count != w.count {
+			t.Errorf("%s count = %d, want %d", u.ID, u.Count, w.count)
+		}
+		if got := u.MessageFormat(); got != w.markup {
+			t.Errorf("%s markup = %q, want %q", u.ID, got, w.markup)
+		}
+	}
+}
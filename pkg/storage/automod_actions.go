@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AutomodAction is a persisted record of a single automod action (native or custom).
+type AutomodAction struct {
+	ID        int64
+	GuildID   string
+	UserID    string
+	ChannelID string
+	RuleID    string
+	Matched   string // keyword/content that triggered the rule, when available
+	Action    string // ex.: "block_message", "send_alert", "timeout"
+	Content   string // message content (bounded), used to restore false positives
+	Simulated bool   // dry-run action: recorded but not taken
+	CreatedAt time.Time
+}
+
+// RecordAutomodAction appends an automod action to the audit table and returns its ID.
+func (s *Store) RecordAutomodAction(a AutomodAction) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if a.GuildID == "" {
+		return 0, nil
+	}
+	var id int64
+	err := s.retryWrite("RecordAutomodAction", func() error {
+		var err error
+		id, err = s.insertAutomodAction(context.Background(), s.dbFor(a.GuildID), a)
+		return err
+	})
+	return id, err
+}
+
+// insertAutomodAction is the statement shared by RecordAutomodAction and StoreTx.
+func (s *Store) insertAutomodAction(ctx context.Context, q sqlExecutor, a AutomodAction) (int64, error) {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	content, _, _ := s.ClampContent(a.Content)
+	res, err := q.ExecContext(ctx,
+		`INSERT INTO automod_actions (guild_id, user_id, channel_id, rule_id, matched, action, content, simulated, created_at)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.GuildID, a.UserID, a.ChannelID, a.RuleID, a.Matched, a.Action, content, a.Simulated, a.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// GetAutomodAction returns a single automod action by ID (nil if not found).
+func (s *Store) GetAutomodAction(guildID string, id int64) (*AutomodAction, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	var a AutomodAction
+	err := s.dbFor(guildID).QueryRow(
+		`SELECT id, guild_id, user_id, channel_id, rule_id, matched, action, content, simulated, created_at
+         FROM automod_actions WHERE guild_id=? AND id=?`,
+		guildID, id,
+	).Scan(&a.ID, &a.GuildID, &a.UserID, &a.ChannelID, &a.RuleID, &a.Matched, &a.Action, &a.Content, &a.Simulated, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetAutomodActions returns the automod actions recorded for a guild since the given time, newest first.
+func (s *Store) GetAutomodActions(guildID string, since time.Time) ([]AutomodAction, error) {
+	return s.GetAutomodActionsContext(context.Background(), guildID, since)
+}
+
+// GetAutomodActionsContext is GetAutomodActions with a context that cancels the query.
+func (s *Store) GetAutomodActionsContext(ctx context.Context, guildID string, since time.Time) ([]AutomodAction, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	return s.listAutomodActions(ctx, guildID, since, nil, 0)
+}
+
+// AutomodActionsPage is the paginated variant of GetAutomodActions (newest first).
+func (s *Store) AutomodActionsPage(guildID string, since time.Time, page PageRequest) (Page[AutomodAction], error) {
+	if s.db == nil {
+		return Page[AutomodAction]{}, fmt.Errorf("store not initialized")
+	}
+	cursor, err := decodePageCursor(page, true)
+	if err != nil {
+		return Page[AutomodAction]{}, err
+	}
+	limit := page.limit()
+	items, err := s.listAutomodActions(context.Background(), guildID, since, cursor, limit+1)
+	if err != nil {
+		return Page[AutomodAction]{}, err
+	}
+	return newPage(items, limit, func(a AutomodAction) Cursor { return intCursor(a.CreatedAt, a.ID) }), nil
+}
+
+// listAutomodActions lists actions after cursor (nil: from the newest); limit <= 0 means no limit.
+func (s *Store) listAutomodActions(ctx context.Context, guildID string, since time.Time, cursor *keysetCursor, limit int) ([]AutomodAction, error) {
+	query := `SELECT id, guild_id, user_id, channel_id, rule_id, matched, action, content, simulated, created_at
+         FROM automod_actions
+         WHERE guild_id=? AND created_at >= ?`
+	args := []any{guildID, since.UTC()}
+	clause, cursorArgs := cursor.after("created_at", "id", true)
+	query += clause + ` ORDER BY created_at DESC, id DESC`
+	args = append(args, cursorArgs...)
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.dbFor(guildID).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []AutomodAction
+	for rows.Next() {
+		var a AutomodAction
+		if err := rows.Scan(&a.ID, &a.GuildID, &a.UserID, &a.ChannelID, &a.RuleID, &a.Matched, &a.Action, &a.Content, &a.Simulated, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// AutomodActionCounts aggregates executed automod actions per action type for a guild since the
+// given time. Simulated (dry-run) actions are not counted.
+func (s *Store) AutomodActionCounts(guildID string, since time.Time) (map[string]int, error) {
+	return s.AutomodActionCountsBetween(guildID, since, time.Time{})
+}
+
+// AutomodActionCountsBetween is AutomodActionCounts limited to [since, until); a zero until
+// means "now".
+func (s *Store) AutomodActionCountsBetween(guildID string, since, until time.Time) (map[string]int, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	rows, err := s.dbFor(guildID).Query(
+		`SELECT action, COUNT(*) FROM automod_actions
+         WHERE guild_id=? AND created_at >= ? AND created_at < ? AND simulated=0
+         GROUP BY action`,
+		guildID, since.UTC(), until.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var action string
+		var n int
+		if err := rows.Scan(&action, &n); err != nil {
+			return nil, err
+		}
+		counts[action] = n
+	}
+	return counts, rows.Err()
+}
+
+// VerdictFalsePositive marks an automod action reported as a false positive by a moderator.
+const VerdictFalsePositive = "false_positive"
+
+// RuleFeedbackStat aggregates moderator feedback for a single automod rule.
+type RuleFeedbackStat struct {
+	RuleID         string
+	Actions        int     // actions recorded for the rule in the period
+	FalsePositives int     // actions marked as false positives
+	Rate           float64 // FalsePositives / Actions (0 without actions)
+}
+
+// RecordAutomodFeedback stores a moderator verdict for an automod action. Each action accepts a
+// single verdict; it returns false when the action had already been reviewed.
+func (s *Store) RecordAutomodFeedback(a AutomodAction, moderatorID, verdict string) (bool, error) {
+	if s.db == nil {
+		return false, fmt.Errorf("store not initialized")
+	}
+	res, err := s.execWrite(s.dbFor(a.GuildID), "RecordAutomodFeedback",
+		`INSERT OR IGNORE INTO automod_feedback (guild_id, action_id, rule_id, moderator_id, verdict, created_at)
+         VALUES (?, ?, ?, ?, ?, ?)`,
+		a.GuildID, a.ID, a.RuleID, moderatorID, verdict, time.Now().UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// RuleFeedbackStats returns, per rule, how many actions were recorded since the given time and
+// how many of them were marked as false positives. Simulated actions are included, so rules in
+// dry-run can be tuned with the same feedback.
+func (s *Store) RuleFeedbackStats(guildID string, since time.Time) ([]RuleFeedbackStat, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	rows, err := s.dbFor(guildID).Query(
+		`SELECT a.rule_id, COUNT(*), COUNT(f.id)
+         FROM automod_actions a
+         LEFT JOIN automod_feedback f
+           ON f.guild_id = a.guild_id AND f.action_id = a.id AND f.verdict = ?
+         WHERE a.guild_id=? AND a.created_at >= ?
+         GROUP BY a.rule_id
+         ORDER BY COUNT(f.id) DESC, a.rule_id`,
+		VerdictFalsePositive, guildID, since.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []RuleFeedbackStat
+	for rows.Next() {
+		var st RuleFeedbackStat
+		if err := rows.Scan(&st.RuleID, &st.Actions, &st.FalsePositives); err != nil {
+			return nil, err
+		}
+		if st.Actions > 0 {
+			st.Rate = float64(st.FalsePositives) / float64(st.Actions)
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
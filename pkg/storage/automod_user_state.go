@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AutomodUserState is the per-user automod state of a guild: the last action taken on the
+// user (used by the action cooldown) and the escalation level with the time of the last offense
+// that raised it. EscalationLevel is the stored value, before any decay.
+type AutomodUserState struct {
+	GuildID         string
+	UserID          string
+	LastActionAt    time.Time
+	LastRule        string
+	LastAction      string
+	EscalationLevel int
+	LastOffenseAt   time.Time // zero if the user was never escalated
+}
+
+// GetAutomodUserState returns the automod state of a member (nil if automod never acted on them).
+func (s *Store) GetAutomodUserState(guildID, userID string) (*AutomodUserState, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	return getAutomodUserState(context.Background(), s.dbFor(guildID), guildID, userID)
+}
+
+// getAutomodUserState is the query shared by GetAutomodUserState and StoreTx.
+func getAutomodUserState(ctx context.Context, q sqlExecutor, guildID, userID string) (*AutomodUserState, error) {
+	st := AutomodUserState{GuildID: guildID, UserID: userID}
+	var lastOffense sql.NullTime
+	err := q.QueryRowContext(ctx,
+		`SELECT last_action_at, last_rule, last_action, escalation_level, last_offense_at
+         FROM automod_user_state WHERE guild_id=? AND user_id=?`,
+		guildID, userID,
+	).Scan(&st.LastActionAt, &st.LastRule, &st.LastAction, &st.EscalationLevel, &lastOffense)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lastOffense.Valid {
+		st.LastOffenseAt = lastOffense.Time
+	}
+	return &st, nil
+}
+
+// MarkAutomodAction records that automod acted on a member at the given time.
+func (s *Store) MarkAutomodAction(guildID, userID, rule, action string, at time.Time) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || userID == "" {
+		return nil
+	}
+	return s.retryWrite("MarkAutomodAction", func() error {
+		return markAutomodAction(context.Background(), s.dbFor(guildID), guildID, userID, rule, action, at)
+	})
+}
+
+// markAutomodAction is the statement shared by MarkAutomodAction and StoreTx.
+func markAutomodAction(ctx context.Context, q sqlExecutor, guildID, userID, rule, action string, at time.Time) error {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	_, err := q.ExecContext(ctx,
+		`INSERT INTO automod_user_state (guild_id, user_id, last_action_at, last_rule, last_action)
+         VALUES (?, ?, ?, ?, ?)
+         ON CONFLICT(guild_id, user_id) DO UPDATE SET
+           last_action_at=excluded.last_action_at,
+           last_rule=excluded.last_rule,
+           last_action=excluded.last_action`,
+		guildID, userID, at.UTC(), rule, action,
+	)
+	return err
+}
+
+// RecordAutomodOffense raises the escalation level of a member and returns the new level. decay
+// receives the stored level and last offense time and returns the level still in effect at the
+// time of this offense (so forgiveness is applied lazily, at evaluation); nil keeps the level.
+func (s *Store) RecordAutomodOffense(guildID, userID string, at time.Time, decay func(level int, lastOffense time.Time) int) (int, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || userID == "" {
+		return 0, nil
+	}
+	var level int
+	err := s.retryWrite("RecordAutomodOffense", func() error {
+		var err error
+		level, err = recordAutomodOffense(context.Background(), s.dbFor(guildID), guildID, userID, at, decay)
+		return err
+	})
+	return level, err
+}
+
+// recordAutomodOffense is the read-modify-write shared by RecordAutomodOffense and StoreTx.
+func recordAutomodOffense(ctx context.Context, q sqlExecutor, guildID, userID string, at time.Time, decay func(level int, lastOffense time.Time) int) (int, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	st, err := getAutomodUserState(ctx, q, guildID, userID)
+	if err != nil {
+		return 0, err
+	}
+	level := 0
+	if st != nil {
+		level = st.EscalationLevel
+		if decay != nil {
+			level = decay(level, st.LastOffenseAt)
+		}
+	}
+	level++
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO automod_user_state (guild_id, user_id, last_action_at, escalation_level, last_offense_at)
+         VALUES (?, ?, ?, ?, ?)
+         ON CONFLICT(guild_id, user_id) DO UPDATE SET
+           escalation_level=excluded.escalation_level,
+           last_offense_at=excluded.last_offense_at`,
+		guildID, userID, at.UTC(), level, at.UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return level, nil
+}
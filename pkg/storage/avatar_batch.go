@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DefaultAvatarBatchSize bounds each refresh transaction so a large guild doesn't hold the
+// write lock for the whole member list.
+const DefaultAvatarBatchSize = 500
+
+// AvatarUpsert is one member's avatar as observed by a bulk refresh.
+type AvatarUpsert struct {
+	UserID    string
+	Hash      string
+	UpdatedAt time.Time // zero uses the time of the call
+}
+
+func (s *Store) avatarBatchSize() int {
+	if s.opts.AvatarBatchSize > 0 {
+		return s.opts.AvatarBatchSize
+	}
+	return DefaultAvatarBatchSize
+}
+
+// UpsertAvatarsBatch writes the current avatar of many members of a guild, AvatarBatchSize
+// records per transaction. Like UpsertAvatar, a history row is appended only when a stored
+// hash actually changed. inserted counts members seen for the first time and updated counts
+// hash changes (history rows written); unchanged records only refresh updated_at.
+// Event-driven single changes should keep using UpsertAvatar.
+func (s *Store) UpsertAvatarsBatch(guildID string, records []AvatarUpsert) (inserted, updated int, err error) {
+	if s.db == nil {
+		return 0, 0, fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || len(records) == 0 {
+		return 0, 0, nil
+	}
+	now := time.Now().UTC()
+	db := s.dbFor(guildID)
+	size := s.avatarBatchSize()
+	for start := 0; start < len(records); start += size {
+		end := start + size
+		if end > len(records) {
+			end = len(records)
+		}
+		chunk := records[start:end]
+		var ins, upd int
+		err := s.retryWrite("UpsertAvatarsBatch", func() error {
+			var err error
+			ins, upd, err = upsertAvatarsTx(db, guildID, chunk, now)
+			return err
+		})
+		if err != nil {
+			return inserted, updated, err
+		}
+		inserted += ins
+		updated += upd
+	}
+	return inserted, updated, nil
+}
+
+// upsertAvatarsTx writes one chunk in a transaction, preparing its three statements once and
+// reusing them for every record.
+func upsertAvatarsTx(db *sql.DB, guildID string, records []AvatarUpsert, now time.Time) (inserted, updated int, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	selectCur, err := tx.Prepare(`SELECT avatar_hash FROM avatars_current WHERE guild_id=? AND user_id=?`)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer selectCur.Close()
+	insertHistory, err := tx.Prepare(
+		`INSERT INTO avatars_history (guild_id, user_id, old_hash, new_hash, changed_at)
+         VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer insertHistory.Close()
+	upsertCur, err := tx.Prepare(
+		`INSERT INTO avatars_current (guild_id, user_id, avatar_hash, updated_at)
+         VALUES (?, ?, ?, ?)
+         ON CONFLICT(guild_id, user_id) DO UPDATE SET
+           avatar_hash=excluded.avatar_hash,
+           updated_at=excluded.updated_at`)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer upsertCur.Close()
+
+	for _, r := range records {
+		if r.UserID == "" {
+			continue
+		}
+		at := r.UpdatedAt
+		if at.IsZero() {
+			at = now
+		}
+		var curHash string
+		err := selectCur.QueryRow(guildID, r.UserID).Scan(&curHash)
+		switch {
+		case err == sql.ErrNoRows:
+			inserted++
+		case err != nil:
+			return 0, 0, err
+		case curHash != r.Hash:
+			if _, err := insertHistory.Exec(guildID, r.UserID, curHash, r.Hash, at); err != nil {
+				return 0, 0, err
+			}
+			updated++
+		}
+		if _, err := upsertCur.Exec(guildID, r.UserID, r.Hash, at); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return inserted, updated, nil
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const avatarBenchMembers = 5000
+
+func avatarBenchRecords(round int) []AvatarUpsert {
+	records := make([]AvatarUpsert, avatarBenchMembers)
+	for i := range records {
+		records[i] = AvatarUpsert{UserID: "u" + strconv.Itoa(i), Hash: "h" + strconv.Itoa(round) + "-" + strconv.Itoa(i)}
+	}
+	return records
+}
+
+func benchStore(b *testing.B) *Store {
+	b.Helper()
+	s := NewStoreWithOptions(filepath.Join(b.TempDir(), "bench.db"), Options{})
+	if err := s.Init(); err != nil {
+		b.Fatalf("init store: %v", err)
+	}
+	b.Cleanup(func() { s.Close() })
+	return s
+}
+
+// Each round changes every member's hash, so both paths write the current row and a history row.
+func BenchmarkUpsertAvatarsBatch(b *testing.B) {
+	s := benchStore(b)
+	round := 0
+	for b.Loop() {
+		if _, _, err := s.UpsertAvatarsBatch("g1", avatarBenchRecords(round)); err != nil {
+			b.Fatalf("batch: %v", err)
+		}
+		round++
+	}
+}
+
+func BenchmarkUpsertAvatarLoop(b *testing.B) {
+	s := benchStore(b)
+	round := 0
+	for b.Loop() {
+		now := time.Now()
+		for _, r := range avatarBenchRecords(round) {
+			if _, _, err := s.UpsertAvatar("g1", r.UserID, r.Hash, now); err != nil {
+				b.Fatalf("upsert: %v", err)
+			}
+		}
+		round++
+	}
+}
+
+func TestUpsertAvatarsBatchCounts(t *testing.T) {
+	s := newTestStore(t, Options{AvatarBatchSize: 7})
+	records := avatarBenchRecords(0)[:20]
+	ins, upd, err := s.UpsertAvatarsBatch("g1", records)
+	if err != nil || ins != 20 || upd != 0 {
+		t.Fatalf("first batch = (%d, %d, %v), want (20, 0, nil)", ins, upd, err)
+	}
+	records[3].Hash = "changed"
+	ins, upd, err = s.UpsertAvatarsBatch("g1", records)
+	if err != nil || ins != 0 || upd != 1 {
+		t.Fatalf("second batch = (%d, %d, %v), want (0, 1, nil)", ins, upd, err)
+	}
+}
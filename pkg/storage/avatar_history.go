@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AvatarRecord is one avatar change recorded in avatars_history.
+type AvatarRecord struct {
+	ID        int64
+	GuildID   string
+	UserID    string
+	OldHash   string
+	NewHash   string
+	ChangedAt time.Time
+}
+
+// GetAvatarHistory returns the avatar changes of a member, newest first; limit <= 0 means no
+// limit. Rows are only written when the hash actually changes (UpsertAvatar and
+// UpsertAvatarsBatch), so silent refreshes with the same avatar don't show up here.
+func (s *Store) GetAvatarHistory(guildID, userID string, limit int) ([]AvatarRecord, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	return s.listAvatarHistory(guildID, userID, nil, limit)
+}
+
+// AvatarHistoryPage is the paginated variant of GetAvatarHistory (newest first).
+func (s *Store) AvatarHistoryPage(guildID, userID string, page PageRequest) (Page[AvatarRecord], error) {
+	if s.db == nil {
+		return Page[AvatarRecord]{}, fmt.Errorf("store not initialized")
+	}
+	cursor, err := decodePageCursor(page, true)
+	if err != nil {
+		return Page[AvatarRecord]{}, err
+	}
+	limit := page.limit()
+	items, err := s.listAvatarHistory(guildID, userID, cursor, limit+1)
+	if err != nil {
+		return Page[AvatarRecord]{}, err
+	}
+	return newPage(items, limit, func(r AvatarRecord) Cursor { return intCursor(r.ChangedAt, r.ID) }), nil
+}
+
+// listAvatarHistory lists avatar changes after cursor (nil: from the newest); limit <= 0 means no limit.
+func (s *Store) listAvatarHistory(guildID, userID string, cursor *keysetCursor, limit int) ([]AvatarRecord, error) {
+	query := `SELECT id, old_hash, new_hash, changed_at FROM avatars_history
+         WHERE guild_id=? AND user_id=?`
+	args := []any{guildID, userID}
+	clause, cursorArgs := cursor.after("changed_at", "id", true)
+	query += clause + ` ORDER BY changed_at DESC, id DESC`
+	args = append(args, cursorArgs...)
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.dbFor(guildID).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []AvatarRecord
+	for rows.Next() {
+		var oldHash, newHash sql.NullString
+		rec := AvatarRecord{GuildID: guildID, UserID: userID}
+		if err := rows.Scan(&rec.ID, &oldHash, &newHash, &rec.ChangedAt); err != nil {
+			return nil, err
+		}
+		rec.OldHash, rec.NewHash = oldHash.String, newHash.String
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+// CountAvatarChanges returns how many times a member changed avatar since the given time
+// (e.g. "changed avatar N times this week").
+func (s *Store) CountAvatarChanges(guildID, userID string, since time.Time) (int, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	var n int
+	err := s.dbFor(guildID).QueryRow(
+		`SELECT COUNT(*) FROM avatars_history WHERE guild_id=? AND user_id=? AND changed_at >= ?`,
+		guildID, userID, since.UTC(),
+	).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Backup writes a consistent snapshot of the store to destPath with VACUUM INTO, while the
+// bot keeps running (writers are only paused for the duration of the copy). The snapshot is
+// written to a temporary file next to destPath, checked (it must open and pass
+// PRAGMA quick_check) and only then renamed over destPath, so destPath always holds either
+// the previous backup or a complete new one. In a sharded store each shard is backed up to
+// the matching "<dest>.shard<N>.db" file. Queued message writes are flushed first.
+func (s *Store) Backup(destPath string) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if destPath == "" {
+		return fmt.Errorf("backup path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+	s.FlushWrites()
+	if err := backupDB(s.db, destPath); err != nil {
+		return err
+	}
+	for i, shard := range s.shards {
+		if err := backupDB(shard, shardPath(destPath, i)); err != nil {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func backupDB(db *sql.DB, destPath string) error {
+	tmp := destPath + ".tmp"
+	// VACUUM INTO refuses to overwrite; a leftover from an interrupted backup is garbage
+	_ = os.Remove(tmp)
+	if _, err := db.Exec(`VACUUM INTO ?`, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("backup: %w", err)
+	}
+	if err := verifyDatabase(tmp); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("backup verification: %w", err)
+	}
+	if err := os.Rename(tmp, destPath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("backup: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the database at destPath with the backup at srcPath (and, for a sharded
+// backup, each "<src>.shard<N>.db" with the matching destination shard). The backup is checked
+// before anything is touched, copied to a temporary file and renamed into place; destPath's
+// stale -wal/-shm files are removed so SQLite doesn't replay them over the restored copy.
+// No store may have destPath open while it runs: stop the bot first.
+func Restore(srcPath, destPath string) error {
+	if srcPath == "" || destPath == "" {
+		return fmt.Errorf("restore paths must not be empty")
+	}
+	pairs := [][2]string{{srcPath, destPath}}
+	for i := 0; ; i++ {
+		src := shardPath(srcPath, i)
+		if _, err := os.Stat(src); err != nil {
+			break
+		}
+		pairs = append(pairs, [2]string{src, shardPath(destPath, i)})
+	}
+	// Verify everything first, so a bad shard doesn't leave a half-restored set
+	for _, p := range pairs {
+		if err := verifyDatabase(p[0]); err != nil {
+			return fmt.Errorf("restore %s: %w", p[0], err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return fmt.Errorf("create database directory: %w", err)
+	}
+	for _, p := range pairs {
+		if err := restoreFile(p[0], p[1]); err != nil {
+			return fmt.Errorf("restore %s: %w", p[0], err)
+		}
+	}
+	return nil
+}
+
+func restoreFile(src, dest string) error {
+	tmp := dest + ".restore.tmp"
+	if err := copyFileSync(src, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dest + suffix); err != nil && !os.IsNotExist(err) {
+			_ = os.Remove(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// copyFileSync copies src to dest and fsyncs it.
+func copyFileSync(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// verifyDatabase checks that path is an SQLite database that opens and passes PRAGMA quick_check.
+func verifyDatabase(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	var result string
+	if err := db.QueryRow(`PRAGMA quick_check`).Scan(&result); err != nil {
+		return err
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed: %s", result)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CommandUsageCount is the aggregated usage of a slash command over a period.
+type CommandUsageCount struct {
+	Command string // caminho completo, ex.: "admin emoji-stats"
+	Count   int64
+	Errors  int64 // invocations whose handler returned an error
+}
+
+// IncrementCommandUsage adds one invocation of command to the (guild, command, day) counter.
+// Only counters are stored, never the individual invocations. guildID is empty for DMs.
+func (s *Store) IncrementCommandUsage(guildID, command string, failed bool, at time.Time) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if command == "" {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	errs := 0
+	if failed {
+		errs = 1
+	}
+	_, err := s.execWrite(s.dbFor(guildID), "IncrementCommandUsage",
+		`INSERT INTO command_usage (guild_id, command, day, count, errors)
+         VALUES (?, ?, ?, 1, ?)
+         ON CONFLICT(guild_id, command, day) DO UPDATE SET
+           count=count+1,
+           errors=errors+excluded.errors`,
+		guildID, command, at.UTC().Format(emojiUsageDay), errs,
+	)
+	return err
+}
+
+// CommandUsage returns the invocation counts per command of a guild since the given time
+// (day granularity, UTC), most used first.
+func (s *Store) CommandUsage(guildID string, since time.Time) ([]CommandUsageCount, error) {
+	return s.CommandUsageContext(context.Background(), guildID, since)
+}
+
+// CommandUsageContext is CommandUsage with a context that cancels the query.
+func (s *Store) CommandUsageContext(ctx context.Context, guildID string, since time.Time) ([]CommandUsageCount, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	rows, err := s.dbFor(guildID).QueryContext(ctx,
+		`SELECT command, SUM(count) AS total, SUM(errors)
+         FROM command_usage
+         WHERE guild_id=? AND day >= ?
+         GROUP BY command
+         ORDER BY total DESC, command ASC`,
+		guildID, since.UTC().Format(emojiUsageDay),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CommandUsageCount
+	for rows.Next() {
+		var u CommandUsageCount
+		if err := rows.Scan(&u.Command, &u.Count, &u.Errors); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// SizeBytes returns the on-disk size of the store: every database file (primary plus shards)
+// together with its -wal file. In-memory databases count page_count * page_size.
+func (s *Store) SizeBytes() (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	var total int64
+	for _, db := range s.allDBs() {
+		n, err := databaseSize(db)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// Compact runs VACUUM on every database file to give the space freed by deletes (expiry,
+// PruneMessagesOlderThan, purges) back to the filesystem, then truncates the WAL, logging
+// the size before and after.
+//
+// VACUUM rewrites the whole file and holds its write lock until done, so it must not overlap
+// with writes: Compact flushes queued message writes and then blocks every store write that
+// goes through the store's write path until it finishes (they wait instead of failing with
+// SQLITE_BUSY). Run it in a low-traffic window; it needs free disk space about the size of
+// the database.
+func (s *Store) Compact() error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	// Before the lock: the flush goes through the write path, which waits on it
+	s.FlushWrites()
+	s.maintMu.Lock()
+	defer s.maintMu.Unlock()
+
+	started := time.Now()
+	before, err := s.SizeBytes()
+	if err != nil {
+		return fmt.Errorf("compact: %w", err)
+	}
+	for i, db := range s.allDBs() {
+		if _, err := db.Exec(`VACUUM`); err != nil {
+			return fmt.Errorf("compact database %d: %w", i, err)
+		}
+		// In WAL mode VACUUM goes through the -wal file; the checkpoint reclaims it too
+		if _, err := db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+			return fmt.Errorf("checkpoint database %d: %w", i, err)
+		}
+	}
+	after, err := s.SizeBytes()
+	if err != nil {
+		return fmt.Errorf("compact: %w", err)
+	}
+	log.Info().Databasef("Store compacted in %s: %s -> %s (reclaimed %s)",
+		time.Since(started).Round(time.Millisecond), formatSize(before), formatSize(after), formatSize(before-after))
+	return nil
+}
+
+// allDBs returns the primary database followed by the shards.
+func (s *Store) allDBs() []*sql.DB {
+	return append([]*sql.DB{s.db}, s.shards...)
+}
+
+// databaseSize stats the main file of db and its -wal file, falling back to the page count for
+// in-memory databases.
+func databaseSize(db *sql.DB) (int64, error) {
+	path, err := databaseFile(db)
+	if err != nil {
+		return 0, err
+	}
+	if path == "" {
+		return pageBytes(db)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	if wal, err := os.Stat(path + "-wal"); err == nil {
+		size += wal.Size()
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+	return size, nil
+}
+
+// databaseFile returns the path of db's main file ("" for in-memory databases).
+func databaseFile(db *sql.DB) (string, error) {
+	rows, err := db.Query(`PRAGMA database_list`)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var seq int
+		var name, file string
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			return "", err
+		}
+		if name == "main" {
+			return file, nil
+		}
+	}
+	return "", rows.Err()
+}
+
+func pageBytes(db *sql.DB) (int64, error) {
+	var pages, pageSize int64
+	if err := db.QueryRow(`PRAGMA page_count`).Scan(&pages); err != nil {
+		return 0, err
+	}
+	if err := db.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return pages * pageSize, nil
+}
+
+// formatSize formats a byte count for logs (e.g. "12.3 MiB").
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
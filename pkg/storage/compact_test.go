@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"os"
+	"testing"
+)
+
+func onDiskSize(t *testing.T, s *Store) (total, wal int64) {
+	t.Helper()
+	paths := []string{s.dbPath}
+	for i := range s.opts.ShardCount {
+		paths = append(paths, shardPath(s.dbPath, i))
+	}
+	for _, p := range paths {
+		for _, f := range []string{p, p + "-wal"} {
+			info, err := os.Stat(f)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("stat %s: %v", f, err)
+			}
+			total += info.Size()
+			if f != p {
+				wal += info.Size()
+			}
+		}
+	}
+	return total, wal
+}
+
+func TestSizeBytesIncludesWAL(t *testing.T) {
+	s := newTestStore(t, Options{ShardCount: 2})
+	insertExpiredMessages(t, s, "g1", 50)
+	insertExpiredMessages(t, s, "g2", 50)
+	s.FlushWrites()
+
+	want, wal := onDiskSize(t, s)
+	if wal == 0 {
+		t.Fatal("expected data in the -wal files")
+	}
+	got, err := s.SizeBytes()
+	if err != nil {
+		t.Fatalf("size: %v", err)
+	}
+	if got != want {
+		t.Fatalf("SizeBytes = %d, want %d (database plus -wal files)", got, want)
+	}
+
+	if err := s.Compact(); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if _, wal := onDiskSize(t, s); wal != 0 {
+		t.Fatalf("-wal files hold %d bytes after Compact, want 0", wal)
+	}
+}
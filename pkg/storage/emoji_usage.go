@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Kinds of custom expressions tracked in emoji_usage.
+const (
+	EmojiKindEmoji   = "emoji"
+	EmojiKindSticker = "sticker"
+)
+
+// emojiUsageDay is the per-day bucket key (UTC).
+const emojiUsageDay = "2006-01-02"
+
+// EmojiUse is one custom emoji or sticker seen in a message.
+type EmojiUse struct {
+	ID       string
+	Name     string
+	Kind     string // EmojiKindEmoji ou EmojiKindSticker
+	Animated bool   // animated custom emoji (<a:name:id>)
+}
+
+// EmojiUsage is the aggregated usage of a custom emoji or sticker over a period.
+type EmojiUsage struct {
+	ID       string
+	Name     string
+	Kind     string
+	Animated bool
+	Count    int64
+}
+
+// MessageFormat returns the markup that renders a custom emoji in a message ("<:name:id>", or
+// "<a:name:id>" for animated ones). Stickers cannot be rendered inline and return their name.
+func (u EmojiUsage) MessageFormat() string {
+	switch {
+	case u.Kind == EmojiKindSticker:
+		return u.Name
+	case u.Animated:
+		return "<a:" + u.Name + ":" + u.ID + ">"
+	default:
+		return "<:" + u.Name + ":" + u.ID + ">"
+	}
+}
+
+// IncrementEmojiUsage adds one use of each entry to the (guild, emoji, day) counters.
+// Only counters are stored, never the individual events.
+func (s *Store) IncrementEmojiUsage(guildID string, uses []EmojiUse, at time.Time) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || len(uses) == 0 {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	day := at.UTC().Format(emojiUsageDay)
+	db := s.dbFor(guildID)
+	return s.retryWrite("IncrementEmojiUsage", func() error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+		for _, u := range uses {
+			if u.ID == "" {
+				continue
+			}
+			kind := u.Kind
+			if kind == "" {
+				kind = EmojiKindEmoji
+			}
+			if _, err := tx.Exec(
+				`INSERT INTO emoji_usage (guild_id, emoji_id, name, kind, animated, day, count)
+                 VALUES (?, ?, ?, ?, ?, ?, 1)
+                 ON CONFLICT(guild_id, emoji_id, day) DO UPDATE SET
+                   count=count+1,
+                   name=excluded.name,
+                   animated=excluded.animated`,
+				guildID, u.ID, u.Name, kind, u.Animated, day,
+			); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// TopEmojis returns the most used custom emojis and stickers of a guild since the given
+// time (day granularity, UTC), most used first. limit <= 0 defaults to 10.
+func (s *Store) TopEmojis(guildID string, since time.Time, limit int) ([]EmojiUsage, error) {
+	return s.TopEmojisContext(context.Background(), guildID, since, limit)
+}
+
+// TopEmojisContext is TopEmojis with a context that cancels the query.
+func (s *Store) TopEmojisContext(ctx context.Context, guildID string, since time.Time, limit int) ([]EmojiUsage, error) {
+	return s.TopEmojisBetween(ctx, guildID, since, time.Time{}, limit)
+}
+
+// TopEmojisBetween is TopEmojisContext limited to [since, until): days are UTC, so the day of
+// since and the day of the last instant before until are included. A zero until means "now".
+func (s *Store) TopEmojisBetween(ctx context.Context, guildID string, since, until time.Time, limit int) ([]EmojiUsage, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	rows, err := s.dbFor(guildID).QueryContext(ctx,
+		`SELECT emoji_id, MAX(name), kind, MAX(animated), SUM(count) AS total
+         FROM emoji_usage
+         WHERE guild_id=? AND day >= ? AND day <= ?
+         GROUP BY emoji_id, kind
+         ORDER BY total DESC, emoji_id ASC
+         LIMIT ?`,
+		guildID, since.UTC().Format(emojiUsageDay), until.Add(-time.Nanosecond).UTC().Format(emojiUsageDay), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []EmojiUsage
+	for rows.Next() {
+		var u EmojiUsage
+		if err := rows.Scan(&u.ID, &u.Name, &u.Kind, &u.Animated, &u.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// guildScopedTables lists every table keyed by guild_id (all routed by dbFor).
+var guildScopedTables = []string{
+	"messages",
+	"member_joins",
+	"membership_events",
+	"avatars_current",
+	"avatars_history",
+	"guild_meta",
+	"roles_current",
+	"automod_actions",
+	"automod_feedback",
+	"reactions",
+	"voice_sessions",
+	"emoji_usage",
+	"automod_user_state",
+	"command_usage",
+	"message_attachments",
+	"message_embeds",
+}
+
+// PurgeGuild deletes every row stored for a guild, in one transaction, and returns how
+// many rows were removed. The guild's message archives in Options.MessageArchiveDir are
+// deleted too. Used when the bot leaves a guild with the purge policy.
+func (s *Store) PurgeGuild(guildID string) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if guildID == "" {
+		return 0, nil
+	}
+	// Pending writes of the guild would come back after the purge
+	s.FlushWrites()
+	db := s.dbFor(guildID)
+	var total int64
+	err := s.retryWrite("PurgeGuild", func() error {
+		total = 0
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+		for _, table := range guildScopedTables {
+			res, err := tx.Exec(`DELETE FROM `+table+` WHERE guild_id=?`, guildID)
+			if err != nil {
+				return fmt.Errorf("purge %s: %w", table, err)
+			}
+			if n, err := res.RowsAffected(); err == nil {
+				total += n
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return total, err
+	}
+	n, err := s.purgeGuildScheduledTasks(guildID)
+	if err != nil {
+		return total + n, err
+	}
+	return total + n, s.purgeGuildArchives(guildID)
+}
+
+// purgeGuildArchives removes the "messages_<guild>_*.jsonl.gz" files written by ArchiveGuildMessages.
+func (s *Store) purgeGuildArchives(guildID string) error {
+	if s.opts.MessageArchiveDir == "" {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(s.opts.MessageArchiveDir, "messages_"+guildID+"_*.jsonl.gz"))
+	if err != nil {
+		return fmt.Errorf("purge archives: %w", err)
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("purge archive %s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// DefaultPingTimeout bounds Ping when the caller's context has no deadline.
+const DefaultPingTimeout = 5 * time.Second
+
+// Ping verifies that every database file is reachable and writable: it runs a trivial
+// query and takes (then releases) a write lock with BEGIN IMMEDIATE, without changing data.
+// When ctx has no deadline, DefaultPingTimeout applies so a hung database cannot hang the probe.
+func (s *Store) Ping(ctx context.Context) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultPingTimeout)
+		defer cancel()
+	}
+	dbs := []*sql.DB{s.db}
+	dbs = append(dbs, s.shards...)
+	for i, db := range dbs {
+		if err := pingDB(ctx, db); err != nil {
+			if i == 0 {
+				return fmt.Errorf("ping store: %w", err)
+			}
+			return fmt.Errorf("ping store shard %d: %w", i-1, err)
+		}
+	}
+	return nil
+}
+
+func pingDB(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var one int
+	if err := conn.QueryRowContext(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	// ROLLBACK ignores ctx: if it expired here, the connection would go back to the pool holding
+	// the write lock and every later write would get SQLITE_BUSY
+	if _, err := conn.ExecContext(context.WithoutCancel(ctx), `ROLLBACK`); err != nil {
+		// Discard the connection instead of returning it to the pool with the transaction open
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		return fmt.Errorf("rollback: %w", err)
+	}
+	return nil
+}
+
+// selfTestKey is the runtime_meta row used by ProbeWrite.
+const selfTestKey = "self_test"
+
+// ProbeWrite writes a row to the primary database and reads it back, proving the store
+// actually persists data (Ping only takes and releases a write lock).
+func (s *Store) ProbeWrite(ctx context.Context) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultPingTimeout)
+		defer cancel()
+	}
+	want := time.Now().UTC().Truncate(time.Millisecond)
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO runtime_meta (key, ts) VALUES (?, ?)
+         ON CONFLICT(key) DO UPDATE SET ts=excluded.ts`,
+		selfTestKey, want,
+	); err != nil {
+		return fmt.Errorf("write probe row: %w", err)
+	}
+	var got time.Time
+	if err := s.db.QueryRowContext(ctx, `SELECT ts FROM runtime_meta WHERE key=?`, selfTestKey).Scan(&got); err != nil {
+		return fmt.Errorf("read probe row: %w", err)
+	}
+	if !got.Equal(want) {
+		return fmt.Errorf("probe row mismatch: wrote %s, read %s", want.Format(time.RFC3339Nano), got.Format(time.RFC3339Nano))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tipos de MembershipEvent.
+const (
+	MembershipJoin  = "join"
+	MembershipLeave = "leave"
+)
+
+// MembershipEvent is one join or leave of a member, as recorded by RecordJoin/RecordLeave.
+// Unlike member_joins (which keeps only the earliest join), every event is kept, so rejoins
+// show up in the timeline.
+type MembershipEvent struct {
+	ID      int64
+	GuildID string
+	UserID  string
+	Type    string // MembershipJoin or MembershipLeave
+	At      time.Time
+}
+
+// RecordJoin appends a join event for a member (zero at uses now).
+func (s *Store) RecordJoin(guildID, userID string, at time.Time) error {
+	return s.recordMembershipEvent("RecordJoin", guildID, userID, MembershipJoin, at)
+}
+
+// RecordLeave appends a leave event for a member (zero at uses now).
+func (s *Store) RecordLeave(guildID, userID string, at time.Time) error {
+	return s.recordMembershipEvent("RecordLeave", guildID, userID, MembershipLeave, at)
+}
+
+func (s *Store) recordMembershipEvent(op, guildID, userID, event string, at time.Time) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || userID == "" {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	_, err := s.execWrite(s.dbFor(guildID), op,
+		`INSERT INTO membership_events (guild_id, user_id, event, at) VALUES (?, ?, ?, ?)`,
+		guildID, userID, event, at.UTC(),
+	)
+	return err
+}
+
+// GetMembershipEvents returns the join/leave timeline of a member, oldest first.
+func (s *Store) GetMembershipEvents(guildID, userID string) ([]MembershipEvent, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	return s.listMembershipEvents(guildID, userID, nil, 0)
+}
+
+// MembershipEventsPage is the paginated variant of GetMembershipEvents (oldest first).
+func (s *Store) MembershipEventsPage(guildID, userID string, page PageRequest) (Page[MembershipEvent], error) {
+	if s.db == nil {
+		return Page[MembershipEvent]{}, fmt.Errorf("store not initialized")
+	}
+	cursor, err := decodePageCursor(page, true)
+	if err != nil {
+		return Page[MembershipEvent]{}, err
+	}
+	limit := page.limit()
+	items, err := s.listMembershipEvents(guildID, userID, cursor, limit+1)
+	if err != nil {
+		return Page[MembershipEvent]{}, err
+	}
+	return newPage(items, limit, func(ev MembershipEvent) Cursor { return intCursor(ev.At, ev.ID) }), nil
+}
+
+// listMembershipEvents lists events after cursor (nil: from the oldest); limit <= 0 means no limit.
+func (s *Store) listMembershipEvents(guildID, userID string, cursor *keysetCursor, limit int) ([]MembershipEvent, error) {
+	query := `SELECT id, event, at FROM membership_events WHERE guild_id=? AND user_id=?`
+	args := []any{guildID, userID}
+	clause, cursorArgs := cursor.after("at", "id", false)
+	query += clause + ` ORDER BY at ASC, id ASC`
+	args = append(args, cursorArgs...)
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.dbFor(guildID).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []MembershipEvent
+	for rows.Next() {
+		ev := MembershipEvent{GuildID: guildID, UserID: userID}
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.At); err != nil {
+			return nil, err
+		}
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
+
+// CountMembershipEvents counts the events of one type (MembershipJoin or MembershipLeave) of a
+// guild within [since, until); a zero until means "now". Rejoins and repeated leaves count each time.
+func (s *Store) CountMembershipEvents(guildID, event string, since, until time.Time) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	var n int64
+	err := s.dbFor(guildID).QueryRow(
+		`SELECT COUNT(*) FROM membership_events WHERE guild_id=? AND event=? AND at >= ? AND at < ?`,
+		guildID, event, since.UTC(), until.UTC(),
+	).Scan(&n)
+	return n, err
+}
+
+// CleanupObsoleteMembershipEvents removes join/leave events older than retentionDays
+func (s *Store) CleanupObsoleteMembershipEvents(retentionDays int) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if retentionDays <= 0 {
+		retentionDays = 90 // default: keep 3 months of membership history
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
+	return s.execAllShards(`DELETE FROM membership_events WHERE at < ?`, cutoff)
+}
@@ -0,0 +1,470 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// DefaultArchiveImportTTL is how long re-imported messages stay in the store when
+// ImportMessageArchive is called without an explicit duration.
+const DefaultArchiveImportTTL = 24 * time.Hour
+
+// archiveTimeLayout encodes the date range in archive file names.
+const archiveTimeLayout = "20060102T150405Z"
+
+// MessageArchive describes an archive file written by ArchiveGuildMessages.
+type MessageArchive struct {
+	Path    string
+	GuildID string
+	From    time.Time // cached_at of the oldest archived message
+	To      time.Time // cached_at of the newest archived message
+	Count   int
+}
+
+// archivedMessage is one line of an archive file. The field names are the file format: keep
+// them stable so older archives can still be imported.
+type archivedMessage struct {
+	GuildID         string                `json:"guild_id"`
+	MessageID       string                `json:"message_id"`
+	ChannelID       string                `json:"channel_id,omitempty"`
+	AuthorID        string                `json:"author_id,omitempty"`
+	AuthorUsername  string                `json:"author_username,omitempty"`
+	AuthorAvatar    string                `json:"author_avatar,omitempty"`
+	Content         string                `json:"content"`
+	CachedAt        time.Time             `json:"cached_at"`
+	ExpiresAt       *time.Time            `json:"expires_at,omitempty"`
+	Truncated       bool                  `json:"truncated,omitempty"`
+	OriginalLength  int                   `json:"original_length,omitempty"`
+	ContentOmitted  bool                  `json:"content_omitted,omitempty"`
+	AttachmentCount int                   `json:"attachment_count,omitempty"`
+	Attachments     []archivedAttachment  `json:"attachments,omitempty"`
+	Embeds          []MessageEmbedSummary `json:"embeds,omitempty"`
+}
+
+type archivedAttachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	URL         string `json:"url,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	Data        []byte `json:"data,omitempty"` // bytes archived by the store, if any
+}
+
+// ArchiveExpiredMessages archives the expired messages of every guild (or only of
+// Options.MessageArchiveGuilds) into Options.MessageArchiveDir and deletes them from the store.
+// It is called by CleanupExpiredMessages when the directory is set. Guilds that fail are skipped
+// (their messages stay in the store) and reported together in the returned error.
+func (s *Store) ArchiveExpiredMessages() ([]MessageArchive, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	if s.opts.MessageArchiveDir == "" {
+		return nil, fmt.Errorf("message archive dir not configured")
+	}
+	archives, _, err := s.archiveExpiredMessages(time.Now().UTC())
+	return archives, err
+}
+
+// messageSelection is the condition (besides the guild) that picks the messages to archive.
+type messageSelection struct {
+	where string
+	args  []any
+}
+
+// expiredBefore selects the messages whose expiry passed at t.
+func expiredBefore(t time.Time) messageSelection {
+	return messageSelection{where: `expires_at IS NOT NULL AND expires_at <= ?`, args: []any{t}}
+}
+
+// cachedBefore selects the messages cached before t, whatever their expiry.
+func cachedBefore(t time.Time) messageSelection {
+	return messageSelection{where: `cached_at < ?`, args: []any{t}}
+}
+
+// archiveExpiredMessages archives every guild's expired messages.
+func (s *Store) archiveExpiredMessages(now time.Time) (archives []MessageArchive, failed []string, err error) {
+	return s.archiveMessages("", expiredBefore(now))
+}
+
+// archiveMessages archives the selected messages of guildID, or of every guild when empty,
+// honouring Options.MessageArchiveGuilds. A guild that fails to archive is logged and skipped, so
+// the others still are; its ID is returned in failed so the caller keeps its messages for the
+// next run instead of deleting them unarchived.
+func (s *Store) archiveMessages(guildID string, sel messageSelection) (archives []MessageArchive, failed []string, err error) {
+	guilds := []string{guildID}
+	if guildID == "" {
+		guilds = nil
+		for _, db := range s.guildDBs() {
+			ids, err := selectedMessageGuilds(db, sel)
+			if err != nil {
+				return nil, nil, err
+			}
+			guilds = append(guilds, ids...)
+		}
+	}
+	var errs []error
+	for _, guildID := range guilds {
+		if len(s.opts.MessageArchiveGuilds) > 0 && !slices.Contains(s.opts.MessageArchiveGuilds, guildID) {
+			continue
+		}
+		archive, err := s.archiveGuildMessages(guildID, s.opts.MessageArchiveDir, sel)
+		if err != nil {
+			log.Warn().Databasef("Failed to archive messages of guild %s; keeping them for the next run: %v", guildID, err)
+			failed = append(failed, guildID)
+			errs = append(errs, fmt.Errorf("archive guild %s: %w", guildID, err))
+			continue
+		}
+		if archive.Count > 0 {
+			log.Info().Databasef("🗄️ Archived %d messages of guild %s to %s", archive.Count, guildID, archive.Path)
+			archives = append(archives, archive)
+		}
+	}
+	return archives, failed, errors.Join(errs...)
+}
+
+func selectedMessageGuilds(db *sql.DB, sel messageSelection) ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT guild_id FROM messages WHERE `+sel.where, sel.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// archivePageSize is how many messages ArchiveGuildMessages reads per query.
+const archivePageSize = 500
+
+// ArchiveGuildMessages writes the messages of a guild that expired up to before (with their
+// attachment and embed metadata and archived attachment bytes) to a gzip-compressed JSONL file
+// in dir, named "messages_<guild>_<from>_<to>.jsonl.gz" after the cached_at range, and then
+// deletes them from the store in one transaction. Messages are streamed to the file page by
+// page, so only their IDs are kept in memory. The file is complete on disk before anything is
+// deleted; on error nothing is deleted. Count is 0 (and no file is written) when there is
+// nothing to archive.
+func (s *Store) ArchiveGuildMessages(guildID, dir string, before time.Time) (MessageArchive, error) {
+	if s.db == nil {
+		return MessageArchive{}, fmt.Errorf("store not initialized")
+	}
+	return s.archiveGuildMessages(guildID, dir, expiredBefore(before.UTC()))
+}
+
+// archiveGuildMessages is ArchiveGuildMessages for any selection of messages.
+func (s *Store) archiveGuildMessages(guildID, dir string, sel messageSelection) (MessageArchive, error) {
+	archive := MessageArchive{GuildID: guildID}
+	// Queued writes must land first, otherwise they would recreate archived messages
+	s.FlushWrites()
+
+	var w *archiveWriter
+	defer func() {
+		if w != nil {
+			w.abort() // no-op after commit
+		}
+	}()
+	var ids []string
+	err := s.forEachSelectedMessage(guildID, sel, func(rec MessageRecord) error {
+		if w == nil {
+			var err error
+			if w, err = newArchiveWriter(dir); err != nil {
+				return err
+			}
+			archive.From = rec.CachedAt
+		}
+		line, err := s.archiveLine(rec)
+		if err != nil {
+			return err
+		}
+		if err := w.encode(line); err != nil {
+			return err
+		}
+		archive.To = rec.CachedAt
+		archive.Count++
+		ids = append(ids, rec.MessageID)
+		return nil
+	})
+	if err != nil || w == nil {
+		return archive, err
+	}
+	archive.Path = archivePath(dir, guildID, archive.From, archive.To)
+	if err := w.commit(archive.Path); err != nil {
+		return archive, err
+	}
+
+	if err := s.deleteArchivedMessages(guildID, ids); err != nil {
+		return archive, fmt.Errorf("delete archived messages (archive kept at %s): %w", archive.Path, err)
+	}
+	return archive, nil
+}
+
+// forEachSelectedMessage streams the selected messages of a guild, oldest first. Each page is
+// read in full before fn runs, so fn may query the store without holding a cursor open.
+func (s *Store) forEachSelectedMessage(guildID string, sel messageSelection, fn func(MessageRecord) error) error {
+	db := s.dbFor(guildID)
+	var last *MessageRecord
+	for {
+		query := `SELECT guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at, content_truncated, original_length, content_omitted, attachment_count
+         FROM messages
+         WHERE guild_id=? AND ` + sel.where
+		args := append([]any{guildID}, sel.args...)
+		if last != nil {
+			query += ` AND (cached_at > ? OR (cached_at = ? AND message_id > ?))`
+			args = append(args, last.CachedAt, last.CachedAt, last.MessageID)
+		}
+		query += ` ORDER BY cached_at ASC, message_id ASC LIMIT ?`
+		page, err := scanMessagePage(db, query, append(args, archivePageSize)...)
+		if err != nil {
+			return err
+		}
+		for _, rec := range page {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		if len(page) < archivePageSize {
+			return nil
+		}
+		last = &page[len(page)-1]
+	}
+}
+
+func scanMessagePage(db *sql.DB, query string, args ...any) ([]MessageRecord, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var page []MessageRecord
+	for rows.Next() {
+		var rec MessageRecord
+		var expires sql.NullTime
+		if err := rows.Scan(&rec.GuildID, &rec.MessageID, &rec.ChannelID, &rec.AuthorID, &rec.AuthorUsername, &rec.AuthorAvatar,
+			&rec.Content, &rec.CachedAt, &expires, &rec.Truncated, &rec.OriginalLength, &rec.ContentOmitted, &rec.AttachmentCount); err != nil {
+			return nil, err
+		}
+		if expires.Valid {
+			rec.ExpiresAt, rec.HasExpiry = expires.Time, true
+		}
+		page = append(page, rec)
+	}
+	return page, rows.Err()
+}
+
+// archiveWriter encodes archive lines as gzip-compressed JSONL into a temporary file in the
+// archive dir, renamed to its final name by commit.
+type archiveWriter struct {
+	f   *os.File
+	buf *bufio.Writer
+	gz  *gzip.Writer
+	enc *json.Encoder
+}
+
+func newArchiveWriter(dir string) (*archiveWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create archive dir: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".messages-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("create archive file: %w", err)
+	}
+	buf := bufio.NewWriter(f)
+	gz := gzip.NewWriter(buf)
+	return &archiveWriter{f: f, buf: buf, gz: gz, enc: json.NewEncoder(gz)}, nil
+}
+
+func (w *archiveWriter) encode(line archivedMessage) error {
+	if err := w.enc.Encode(line); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return nil
+}
+
+// commit flushes and syncs the file and renames it to path.
+func (w *archiveWriter) commit(path string) error {
+	if err := w.gz.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := w.f.Sync(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("close archive file: %w", err)
+	}
+	if err := os.Rename(w.f.Name(), path); err != nil {
+		return fmt.Errorf("rename archive file: %w", err)
+	}
+	return nil
+}
+
+// abort closes and removes the temporary file; after commit it only fails silently.
+func (w *archiveWriter) abort() {
+	_ = w.f.Close()
+	_ = os.Remove(w.f.Name())
+}
+
+func (s *Store) archiveLine(rec MessageRecord) (archivedMessage, error) {
+	line := archivedMessage{
+		GuildID:         rec.GuildID,
+		MessageID:       rec.MessageID,
+		ChannelID:       rec.ChannelID,
+		AuthorID:        rec.AuthorID,
+		AuthorUsername:  rec.AuthorUsername,
+		AuthorAvatar:    rec.AuthorAvatar,
+		Content:         rec.Content,
+		CachedAt:        rec.CachedAt.UTC(),
+		Truncated:       rec.Truncated,
+		OriginalLength:  rec.OriginalLength,
+		ContentOmitted:  rec.ContentOmitted,
+		AttachmentCount: rec.AttachmentCount,
+	}
+	if rec.HasExpiry {
+		expires := rec.ExpiresAt.UTC()
+		line.ExpiresAt = &expires
+	}
+	attachments, embeds, err := s.GetMessageMedia(rec.GuildID, rec.MessageID)
+	if err != nil {
+		return line, fmt.Errorf("load media of message %s: %w", rec.MessageID, err)
+	}
+	line.Embeds = embeds
+	for _, a := range attachments {
+		out := archivedAttachment{ID: a.ID, Filename: a.Filename, URL: a.URL, ContentType: a.ContentType, Size: a.Size}
+		if a.Archived {
+			if out.Data, _, err = s.GetAttachmentData(rec.GuildID, rec.MessageID, a.ID); err != nil {
+				return line, fmt.Errorf("load attachment %s: %w", a.ID, err)
+			}
+		}
+		line.Attachments = append(line.Attachments, out)
+	}
+	return line, nil
+}
+
+// archivePath names an archive after the guild and the date range, adding a counter if a file
+// with the same range already exists.
+func archivePath(dir, guildID string, from, to time.Time) string {
+	base := fmt.Sprintf("messages_%s_%s_%s", guildID, from.UTC().Format(archiveTimeLayout), to.UTC().Format(archiveTimeLayout))
+	path := filepath.Join(dir, base+".jsonl.gz")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.jsonl.gz", base, i))
+	}
+}
+
+// deleteArchivedMessages removes the archived messages and their media in one transaction.
+func (s *Store) deleteArchivedMessages(guildID string, messageIDs []string) error {
+	db := s.dbFor(guildID)
+	return s.retryWrite("ArchiveGuildMessages", func() error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+		for _, messageID := range messageIDs {
+			for _, query := range []string{
+				`DELETE FROM messages WHERE guild_id=? AND message_id=?`,
+				`DELETE FROM message_attachments WHERE guild_id=? AND message_id=?`,
+				`DELETE FROM message_embeds WHERE guild_id=? AND message_id=?`,
+			} {
+				if _, err := tx.Exec(query, guildID, messageID); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// ImportMessageArchive loads an archive written by ArchiveGuildMessages back into the store for
+// investigation. The messages get a new expiry keepFor from now (DefaultArchiveImportTTL when
+// keepFor <= 0), so the regular cleanup archives/removes them again afterwards; messages still
+// in the store are overwritten. Returns how many messages were imported.
+func (s *Store) ImportMessageArchive(ctx context.Context, path string, keepFor time.Duration) (int, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if keepFor <= 0 {
+		keepFor = DefaultArchiveImportTTL
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return 0, fmt.Errorf("read archive %s: %w", path, err)
+	}
+	defer gz.Close()
+
+	expires := time.Now().Add(keepFor)
+	dec := json.NewDecoder(gz)
+	imported := 0
+	for dec.More() {
+		if err := ctx.Err(); err != nil {
+			return imported, err
+		}
+		var line archivedMessage
+		if err := dec.Decode(&line); err != nil {
+			return imported, fmt.Errorf("read archive %s (line %d): %w", path, imported+1, err)
+		}
+		rec := MessageRecord{
+			GuildID:         line.GuildID,
+			MessageID:       line.MessageID,
+			ChannelID:       line.ChannelID,
+			AuthorID:        line.AuthorID,
+			AuthorUsername:  line.AuthorUsername,
+			AuthorAvatar:    line.AuthorAvatar,
+			Content:         line.Content,
+			CachedAt:        line.CachedAt,
+			ExpiresAt:       expires,
+			HasExpiry:       true,
+			ContentOmitted:  line.ContentOmitted,
+			OriginalLength:  line.OriginalLength,
+			AttachmentCount: line.AttachmentCount,
+		}
+		args := s.upsertMessageArgs(rec)
+		if line.Truncated {
+			// Already cut (with TruncationMarker) when first stored: keep it as is instead of clamping again
+			args[6], args[9], args[10] = line.Content, true, line.OriginalLength
+		}
+		if _, err := s.execWrite(s.dbFor(rec.GuildID), "ImportMessageArchive", upsertMessageSQL, args...); err != nil {
+			return imported, fmt.Errorf("import message %s: %w", rec.MessageID, err)
+		}
+		attachments := make([]MessageAttachment, 0, len(line.Attachments))
+		for _, a := range line.Attachments {
+			attachments = append(attachments, MessageAttachment{ID: a.ID, Filename: a.Filename, URL: a.URL, ContentType: a.ContentType, Size: a.Size, Data: a.Data})
+		}
+		embeds := line.Embeds
+		if embeds == nil {
+			embeds = []MessageEmbedSummary{}
+		}
+		if err := s.SaveMessageMedia(rec.GuildID, rec.MessageID, attachments, embeds); err != nil {
+			return imported, fmt.Errorf("import media of message %s: %w", rec.MessageID, err)
+		}
+		imported++
+	}
+	return imported, nil
+}
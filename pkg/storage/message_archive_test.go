@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, opts Options) *Store {
+	t.Helper()
+	s := NewStoreWithOptions(filepath.Join(t.TempDir(), "test.db"), opts)
+	if err := s.Init(); err != nil {
+		t.Fatalf("init store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func insertExpiredMessages(t *testing.T, s *Store, guildID string, n int) {
+	t.Helper()
+	base := time.Now().UTC().Add(-2 * time.Hour)
+	for i := range n {
+		rec := MessageRecord{
+			GuildID:   guildID,
+			MessageID: guildID + "-" + strconv.Itoa(i),
+			ChannelID: "c1",
+			AuthorID:  "u1",
+			Content:   "message " + strconv.Itoa(i),
+			CachedAt:  base.Add(time.Duration(i) * time.Second),
+			ExpiresAt: base.Add(time.Hour),
+			HasExpiry: true,
+		}
+		if err := s.UpsertMessage(rec); err != nil {
+			t.Fatalf("upsert message: %v", err)
+		}
+	}
+}
+
+func countArchiveLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	sc := bufio.NewScanner(gz)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("scan archive: %v", err)
+	}
+	return n
+}
+
+func countMessages(t *testing.T, s *Store, guildID string) int {
+	t.Helper()
+	var n int
+	if err := s.dbFor(guildID).QueryRow(`SELECT COUNT(*) FROM messages WHERE guild_id=?`, guildID).Scan(&n); err != nil {
+		t.Fatalf("count messages: %v", err)
+	}
+	return n
+}
+
+func TestArchiveGuildMessagesStreamsAcrossPages(t *testing.T) {
+	s := newTestStore(t, Options{})
+	total := archivePageSize*2 + 7
+	insertExpiredMessages(t, s, "g1", total)
+	if err := s.SaveMessageMedia("g1", "g1-3", []MessageAttachment{{ID: "a1", Filename: "a.png", Data: []byte("png")}}, nil); err != nil {
+		t.Fatalf("save media: %v", err)
+	}
+
+	dir := t.TempDir()
+	archive, err := s.ArchiveGuildMessages("g1", dir, time.Now())
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if archive.Count != total {
+		t.Fatalf("archived %d messages, want %d", archive.Count, total)
+	}
+	if got := countArchiveLines(t, archive.Path); got != total {
+		t.Fatalf("archive has %d lines, want %d", got, total)
+	}
+	if n := countMessages(t, s, "g1"); n != 0 {
+		t.Fatalf("%d messages left in the store", n)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, ".messages-*.tmp")); len(tmp) > 0 {
+		t.Fatalf("temporary files left behind: %v", tmp)
+	}
+
+	imported, err := s.ImportMessageArchive(t.Context(), archive.Path, time.Hour)
+	if err != nil || imported != total {
+		t.Fatalf("import = %d, %v; want %d", imported, err, total)
+	}
+	data, _, err := s.GetAttachmentData("g1", "g1-3", "a1")
+	if err != nil || string(data) != "png" {
+		t.Fatalf("attachment after import = %q, %v", data, err)
+	}
+}
+
+func TestCleanupExpiredMessagesSkipsFailedGuild(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestStore(t, Options{MessageArchiveDir: dir})
+	insertExpiredMessages(t, s, "good", 3)
+	insertExpiredMessages(t, s, "bad", 3)
+	// A row that cannot be scanned makes the archive of "bad" fail.
+	if _, err := s.dbFor("bad").Exec(`UPDATE messages SET cached_at='not a time' WHERE guild_id='bad'`); err != nil {
+		t.Fatalf("corrupt row: %v", err)
+	}
+
+	if err := s.CleanupExpiredMessages(); err == nil {
+		t.Fatal("expected the failed guild to be reported")
+	}
+	if n := countMessages(t, s, "good"); n != 0 {
+		t.Fatalf("good guild kept %d messages, want 0", n)
+	}
+	if n := countMessages(t, s, "bad"); n != 3 {
+		t.Fatalf("bad guild kept %d messages, want 3 (unarchived messages must not be deleted)", n)
+	}
+	archives, _ := filepath.Glob(filepath.Join(dir, "messages_good_*.jsonl.gz"))
+	if len(archives) != 1 {
+		t.Fatalf("archives of the good guild = %v, want 1", archives)
+	}
+}
+
+func TestPurgeGuildRemovesArchives(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestStore(t, Options{MessageArchiveDir: dir})
+	insertExpiredMessages(t, s, "1", 2)
+	insertExpiredMessages(t, s, "12", 2)
+	if _, err := s.ArchiveExpiredMessages(); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	if _, err := s.PurgeGuild("1"); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if left, _ := filepath.Glob(filepath.Join(dir, "messages_1_*.jsonl.gz")); len(left) != 0 {
+		t.Fatalf("archives of the purged guild left: %v", left)
+	}
+	if kept, _ := filepath.Glob(filepath.Join(dir, "messages_12_*.jsonl.gz")); len(kept) != 1 {
+		t.Fatalf("archives of another guild = %v, want 1 kept", kept)
+	}
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MessageAttachment is the metadata of a file attached to a stored message. Data holds the
+// archived bytes only when they were requested (GetAttachmentData); Archived reports whether
+// the store has them.
+type MessageAttachment struct {
+	ID          string
+	Filename    string
+	URL         string
+	ContentType string
+	Size        int
+	Archived    bool
+	Data        []byte
+}
+
+// MessageEmbedSummary is a compact view of an embed of a stored message.
+type MessageEmbedSummary struct {
+	Type        string
+	Title       string
+	Description string
+	URL         string
+}
+
+// SaveMessageMedia replaces the attachment and embed metadata of a message (e.g. after an edit
+// or a link unfurl). A nil slice leaves that kind untouched (partial updates); an empty one
+// clears it. Archived bytes of attachments still present are kept; attachments with Data are
+// stored with their bytes. The caller enforces size limits.
+func (s *Store) SaveMessageMedia(guildID, messageID string, attachments []MessageAttachment, embeds []MessageEmbedSummary) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || messageID == "" || (attachments == nil && embeds == nil) {
+		return nil
+	}
+	db := s.dbFor(guildID)
+	return s.retryWrite("SaveMessageMedia", func() error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+		now := time.Now().UTC()
+		if attachments != nil {
+			// Attachments no longer on the message are removed; the rest keep their archived bytes
+			keep := make([]any, 0, len(attachments)+2)
+			keep = append(keep, guildID, messageID)
+			for _, a := range attachments {
+				keep = append(keep, a.ID)
+			}
+			removeQuery := `DELETE FROM message_attachments WHERE guild_id=? AND message_id=?`
+			if len(attachments) > 0 {
+				removeQuery += ` AND attachment_id NOT IN (?` + strings.Repeat(`, ?`, len(attachments)-1) + `)`
+			}
+			if _, err := tx.Exec(removeQuery, keep...); err != nil {
+				return err
+			}
+			for _, a := range attachments {
+				var data any
+				if len(a.Data) > 0 {
+					data = a.Data
+				}
+				if _, err := tx.Exec(
+					`INSERT INTO message_attachments (guild_id, message_id, attachment_id, filename, url, content_type, size, data, stored_at)
+                 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+                 ON CONFLICT(guild_id, message_id, attachment_id) DO UPDATE SET
+                   filename=excluded.filename,
+                   url=excluded.url,
+                   content_type=excluded.content_type,
+                   size=excluded.size,
+                   data=COALESCE(excluded.data, message_attachments.data)`,
+					guildID, messageID, a.ID, a.Filename, a.URL, a.ContentType, a.Size, data, now,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		if embeds != nil {
+			if _, err := tx.Exec(`DELETE FROM message_embeds WHERE guild_id=? AND message_id=?`, guildID, messageID); err != nil {
+				return err
+			}
+			for i, e := range embeds {
+				if _, err := tx.Exec(
+					`INSERT INTO message_embeds (guild_id, message_id, position, type, title, description, url, stored_at)
+                 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+					guildID, messageID, i, e.Type, e.Title, e.Description, e.URL, now,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// ArchiveAttachmentData stores the bytes of an attachment already saved by SaveMessageMedia.
+func (s *Store) ArchiveAttachmentData(guildID, messageID, attachmentID string, data []byte) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	_, err := s.execWrite(s.dbFor(guildID), "ArchiveAttachmentData",
+		`UPDATE message_attachments SET data=? WHERE guild_id=? AND message_id=? AND attachment_id=?`,
+		data, guildID, messageID, attachmentID,
+	)
+	return err
+}
+
+// GetMessageMedia returns the attachment metadata (without bytes) and embed summaries of a message.
+func (s *Store) GetMessageMedia(guildID, messageID string) ([]MessageAttachment, []MessageEmbedSummary, error) {
+	return s.GetMessageMediaContext(context.Background(), guildID, messageID)
+}
+
+// GetMessageMediaContext is GetMessageMedia with a context that cancels the queries.
+func (s *Store) GetMessageMediaContext(ctx context.Context, guildID, messageID string) ([]MessageAttachment, []MessageEmbedSummary, error) {
+	if s.db == nil {
+		return nil, nil, fmt.Errorf("store not initialized")
+	}
+	db := s.dbFor(guildID)
+	rows, err := db.QueryContext(ctx,
+		`SELECT attachment_id, filename, url, content_type, size, data IS NOT NULL
+         FROM message_attachments WHERE guild_id=? AND message_id=? ORDER BY rowid`,
+		guildID, messageID,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	var attachments []MessageAttachment
+	for rows.Next() {
+		var a MessageAttachment
+		if err := rows.Scan(&a.ID, &a.Filename, &a.URL, &a.ContentType, &a.Size, &a.Archived); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, nil, err
+	}
+	rows.Close()
+
+	rows, err = db.QueryContext(ctx,
+		`SELECT type, title, description, url FROM message_embeds WHERE guild_id=? AND message_id=? ORDER BY position`,
+		guildID, messageID,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var embeds []MessageEmbedSummary
+	for rows.Next() {
+		var e MessageEmbedSummary
+		if err := rows.Scan(&e.Type, &e.Title, &e.Description, &e.URL); err != nil {
+			return nil, nil, err
+		}
+		embeds = append(embeds, e)
+	}
+	return attachments, embeds, rows.Err()
+}
+
+// GetAttachmentData returns the archived bytes of an attachment (ok=false when not archived).
+func (s *Store) GetAttachmentData(guildID, messageID, attachmentID string) ([]byte, bool, error) {
+	if s.db == nil {
+		return nil, false, fmt.Errorf("store not initialized")
+	}
+	var data []byte
+	err := s.dbFor(guildID).QueryRow(
+		`SELECT data FROM message_attachments WHERE guild_id=? AND message_id=? AND attachment_id=? AND data IS NOT NULL`,
+		guildID, messageID, attachmentID,
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// deleteMessageMedia removes the media rows of a message.
+func (s *Store) deleteMessageMedia(db *sql.DB, guildID, messageID string) error {
+	if _, err := db.Exec(`DELETE FROM message_attachments WHERE guild_id=? AND message_id=?`, guildID, messageID); err != nil {
+		return err
+	}
+	_, err := db.Exec(`DELETE FROM message_embeds WHERE guild_id=? AND message_id=?`, guildID, messageID)
+	return err
+}
+
+// cleanupOrphanMessageMedia removes media rows whose message is gone (expired or deleted).
+func (s *Store) cleanupOrphanMessageMedia() error {
+	for _, table := range []string{"message_attachments", "message_embeds"} {
+		if _, err := s.execAllShards(`DELETE FROM ` + table + ` WHERE NOT EXISTS (
+            SELECT 1 FROM messages m WHERE m.guild_id=` + table + `.guild_id AND m.message_id=` + table + `.message_id)`); err != nil {
+			return fmt.Errorf("cleanup %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ForEachGuildMessageInRange streams stored messages of a guild cached within [since, until),
+// oldest first, calling fn for each row. channelID filters to a single channel when non-empty;
+// a zero until means "now"; limit <= 0 means no limit. Iteration stops at the first error from fn.
+func (s *Store) ForEachGuildMessageInRange(guildID, channelID string, since, until time.Time, limit int, fn func(MessageRecord) error) error {
+	return s.ForEachGuildMessageInRangeContext(context.Background(), guildID, channelID, since, until, limit, fn)
+}
+
+// ForEachGuildMessageInRangeContext is ForEachGuildMessageInRange with a context: cancelling it
+// stops the iteration and returns the context's error.
+func (s *Store) ForEachGuildMessageInRangeContext(ctx context.Context, guildID, channelID string, since, until time.Time, limit int, fn func(MessageRecord) error) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	return s.forEachGuildMessage(ctx, guildID, channelID, since, until, nil, limit, fn)
+}
+
+// GuildMessagesPage is the paginated variant of GuildMessagesInRange (oldest first), with media.
+func (s *Store) GuildMessagesPage(guildID, channelID string, since, until time.Time, page PageRequest) (Page[MessageRecord], error) {
+	if s.db == nil {
+		return Page[MessageRecord]{}, fmt.Errorf("store not initialized")
+	}
+	cursor, err := decodePageCursor(page, false)
+	if err != nil {
+		return Page[MessageRecord]{}, err
+	}
+	limit := page.limit()
+	var items []MessageRecord
+	err = s.forEachGuildMessage(context.Background(), guildID, channelID, since, until, cursor, limit+1, func(rec MessageRecord) error {
+		items = append(items, rec)
+		return nil
+	})
+	if err != nil {
+		return Page[MessageRecord]{}, err
+	}
+	out := newPage(items, limit, func(rec MessageRecord) Cursor { return Cursor{Time: rec.CachedAt, ID: rec.MessageID} })
+	for i := range out.Items {
+		s.loadMessageMedia(context.Background(), &out.Items[i])
+	}
+	return out, nil
+}
+
+// forEachGuildMessage streams messages after cursor (nil: from the oldest) in (cached_at, message_id) order.
+func (s *Store) forEachGuildMessage(ctx context.Context, guildID, channelID string, since, until time.Time, cursor *keysetCursor, limit int, fn func(MessageRecord) error) error {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	query := `SELECT guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at, content_truncated, original_length, content_omitted, attachment_count
+         FROM messages
+         WHERE guild_id=? AND cached_at >= ? AND cached_at < ?`
+	args := []any{guildID, since.UTC(), until.UTC()}
+	if channelID != "" {
+		query += ` AND channel_id=?`
+		args = append(args, channelID)
+	}
+	clause, cursorArgs := cursor.after("cached_at", "message_id", false)
+	query += clause + ` ORDER BY cached_at ASC, message_id ASC`
+	args = append(args, cursorArgs...)
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.dbFor(guildID).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rec MessageRecord
+		var expires sql.NullTime
+		if err := rows.Scan(
+			&rec.GuildID,
+			&rec.MessageID,
+			&rec.ChannelID,
+			&rec.AuthorID,
+			&rec.AuthorUsername,
+			&rec.AuthorAvatar,
+			&rec.Content,
+			&rec.CachedAt,
+			&expires,
+			&rec.Truncated,
+			&rec.OriginalLength,
+			&rec.ContentOmitted,
+			&rec.AttachmentCount,
+		); err != nil {
+			return err
+		}
+		if expires.Valid {
+			rec.HasExpiry = true
+			rec.ExpiresAt = expires.Time
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GuildMessagesInRange returns stored messages of a guild cached within [since, until), oldest first,
+// with their attachment and embed metadata. See ForEachGuildMessageInRange for the meaning of
+// channelID, until and limit (the streaming variant doesn't load media).
+func (s *Store) GuildMessagesInRange(guildID, channelID string, since, until time.Time, limit int) ([]MessageRecord, error) {
+	return s.GuildMessagesInRangeContext(context.Background(), guildID, channelID, since, until, limit)
+}
+
+// GuildMessagesInRangeContext is GuildMessagesInRange with a context that cancels the queries.
+func (s *Store) GuildMessagesInRangeContext(ctx context.Context, guildID, channelID string, since, until time.Time, limit int) ([]MessageRecord, error) {
+	var out []MessageRecord
+	err := s.ForEachGuildMessageInRangeContext(ctx, guildID, channelID, since, until, limit, func(rec MessageRecord) error {
+		out = append(out, rec)
+		return nil
+	})
+	if err != nil {
+		return out, err
+	}
+	// Load media after iterating so no query runs with the cursor open
+	for i := range out {
+		s.loadMessageMedia(ctx, &out[i])
+	}
+	return out, nil
+}
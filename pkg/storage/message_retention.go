@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PruneMessagesOlderThan deletes every message cached more than d ago (cached_at < now-d),
+// together with its attachment/embed metadata, and returns how many messages were removed.
+// Unlike CleanupExpiredMessages it ignores expires_at, so it bounds the table size whatever
+// TTL the messages were stored with. When Options.MessageArchiveDir is set the messages are
+// archived first, like in CleanupExpiredMessages: a guild that fails to archive keeps its
+// messages (and the failure is returned) while the others are pruned. Each database is pruned
+// in a single transaction (one per shard in a sharded store); concurrent upserts just wait for
+// it (or it is retried on lock contention).
+func (s *Store) PruneMessagesOlderThan(d time.Duration) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("retention must be positive, got %s", d)
+	}
+	return s.pruneOlderThan("", d, s.guildDBs())
+}
+
+// PruneGuildMessagesOlderThan is PruneMessagesOlderThan restricted to one guild, for guilds
+// with their own retention policy.
+func (s *Store) PruneGuildMessagesOlderThan(guildID string, d time.Duration) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("retention must be positive, got %s", d)
+	}
+	if guildID == "" {
+		return 0, nil
+	}
+	return s.pruneOlderThan(guildID, d, []*sql.DB{s.dbFor(guildID)})
+}
+
+// pruneOlderThan archives (when configured) and deletes the messages of guildID, or of every
+// guild when empty, cached more than d ago.
+func (s *Store) pruneOlderThan(guildID string, d time.Duration, dbs []*sql.DB) (int64, error) {
+	// Queued writes must land before the cutoff, otherwise they would recreate pruned messages
+	s.FlushWrites()
+	cutoff := time.Now().UTC().Add(-d)
+
+	var total int64
+	var failed []string
+	var archiveErr error
+	if s.opts.MessageArchiveDir != "" {
+		var archives []MessageArchive
+		archives, failed, archiveErr = s.archiveMessages(guildID, cachedBefore(cutoff))
+		for _, a := range archives {
+			total += int64(a.Count)
+		}
+		// Nothing left to prune when every selected guild failed
+		if archiveErr != nil && (len(failed) == 0 || guildID != "") {
+			return total, fmt.Errorf("archive messages: %w", archiveErr)
+		}
+	}
+	for _, db := range dbs {
+		n, err := s.pruneMessages(db, guildID, cutoff, failed)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	if archiveErr != nil {
+		return total, fmt.Errorf("archive messages: %w", archiveErr)
+	}
+	return total, nil
+}
+
+// pruneMessages deletes, in one transaction on db, the messages (of guildID, or of every guild
+// when empty, except the skipped ones) cached before cutoff and their media rows.
+func (s *Store) pruneMessages(db *sql.DB, guildID string, cutoff time.Time, skip []string) (int64, error) {
+	filter := func(alias string) string {
+		cond := alias + `cached_at < ?`
+		if guildID != "" {
+			cond = alias + `guild_id=? AND ` + cond
+		}
+		if len(skip) > 0 {
+			cond += ` AND ` + alias + `guild_id NOT IN (?` + strings.Repeat(`, ?`, len(skip)-1) + `)`
+		}
+		return cond
+	}
+	args := []any{cutoff}
+	if guildID != "" {
+		args = []any{guildID, cutoff}
+	}
+	for _, id := range skip {
+		args = append(args, id)
+	}
+	var removed int64
+	err := s.retryWrite("PruneMessages", func() error {
+		removed = 0
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+		for _, table := range []string{"message_attachments", "message_embeds"} {
+			if _, err := tx.Exec(`DELETE FROM `+table+` WHERE EXISTS (
+                SELECT 1 FROM messages m WHERE m.guild_id=`+table+`.guild_id AND m.message_id=`+table+`.message_id AND `+filter("m.")+`)`, args...); err != nil {
+				return fmt.Errorf("prune %s: %w", table, err)
+			}
+		}
+		res, err := tx.Exec(`DELETE FROM messages WHERE `+filter(""), args...)
+		if err != nil {
+			return fmt.Errorf("prune messages: %w", err)
+		}
+		if removed, err = res.RowsAffected(); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	return removed, err
+}
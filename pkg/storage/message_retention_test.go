@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func insertCachedMessage(t *testing.T, s *Store, guildID, messageID string, cachedAt time.Time) {
+	t.Helper()
+	rec := MessageRecord{GuildID: guildID, MessageID: messageID, ChannelID: "c1", AuthorID: "u1", Content: "content of " + messageID, CachedAt: cachedAt}
+	if err := s.UpsertMessage(rec); err != nil {
+		t.Fatalf("upsert message: %v", err)
+	}
+}
+
+func TestPruneMessagesOlderThanArchivesFirst(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestStore(t, Options{MessageArchiveDir: dir})
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	insertCachedMessage(t, s, "g1", "old1", old)
+	insertCachedMessage(t, s, "g1", "old2", old.Add(time.Second))
+	insertCachedMessage(t, s, "g1", "recent", time.Now().UTC())
+	if err := s.SaveMessageMedia("g1", "old1", []MessageAttachment{{ID: "a1", Filename: "a.png", Data: []byte("png")}}, nil); err != nil {
+		t.Fatalf("save media: %v", err)
+	}
+
+	n, err := s.PruneMessagesOlderThan(24 * time.Hour)
+	if err != nil || n != 2 {
+		t.Fatalf("prune = %d, %v; want 2", n, err)
+	}
+	if got := countMessages(t, s, "g1"); got != 1 {
+		t.Fatalf("%d messages left, want only the recent one", got)
+	}
+	archives, _ := filepath.Glob(filepath.Join(dir, "messages_g1_*.jsonl.gz"))
+	if len(archives) != 1 {
+		t.Fatalf("archives = %v, want 1", archives)
+	}
+
+	imported, err := s.ImportMessageArchive(t.Context(), archives[0], time.Hour)
+	if err != nil || imported != 2 {
+		t.Fatalf("import = %d, %v; want 2", imported, err)
+	}
+	rec, err := s.GetMessage("g1", "old2")
+	if err != nil || rec == nil || rec.Content != "content of old2" {
+		t.Fatalf("message after import = %+v, %v", rec, err)
+	}
+	data, _, err := s.GetAttachmentData("g1", "old1", "a1")
+	if err != nil || string(data) != "png" {
+		t.Fatalf("attachment after import = %q, %v", data, err)
+	}
+}
+
+func TestPruneMessagesOlderThanSkipsFailedGuild(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestStore(t, Options{MessageArchiveDir: dir})
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	insertCachedMessage(t, s, "good", "m1", old)
+	insertCachedMessage(t, s, "bad", "m1", old)
+	// A row that cannot be scanned makes the archive of "bad" fail.
+	if _, err := s.dbFor("bad").Exec(`UPDATE messages SET content=NULL WHERE guild_id='bad'`); err != nil {
+		t.Fatalf("corrupt row: %v", err)
+	}
+
+	if _, err := s.PruneMessagesOlderThan(24 * time.Hour); err == nil {
+		t.Fatal("expected the failed guild to be reported")
+	}
+	if got := countMessages(t, s, "good"); got != 0 {
+		t.Fatalf("good guild kept %d messages, want 0", got)
+	}
+	if got := countMessages(t, s, "bad"); got != 1 {
+		t.Fatalf("bad guild kept %d messages, want 1 (unarchived messages must not be deleted)", got)
+	}
+	if _, err := s.PruneGuildMessagesOlderThan("bad", 24*time.Hour); err == nil {
+		t.Fatal("expected the guild prune to report the failed archive")
+	}
+	if got := countMessages(t, s, "bad"); got != 1 {
+		t.Fatalf("bad guild kept %d messages after the guild prune, want 1", got)
+	}
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// DefaultSearchLimit is the number of results returned by SearchMessages when limit <= 0.
+const DefaultSearchLimit = 50
+
+// ErrSearchUnavailable is returned by SearchMessages when the full-text index could not be
+// created, typically because the SQLite build lacks the FTS5 extension.
+var ErrSearchUnavailable = errors.New("message search unavailable")
+
+// messages_fts is an external-content FTS5 index over messages.content: it stores only the
+// index and reads the text from messages by rowid. The triggers keep it in sync with every
+// insert, update and delete on messages (including expiry cleanup, pruning and guild purge).
+const createMessageSearch = `
+CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+  content,
+  content=messages,
+  content_rowid=rowid,
+  tokenize="unicode61 remove_diacritics 2"
+);
+CREATE TRIGGER IF NOT EXISTS messages_fts_ai AFTER INSERT ON messages BEGIN
+  INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_fts_ad AFTER DELETE ON messages BEGIN
+  INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_fts_au AFTER UPDATE OF content ON messages BEGIN
+  INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+  INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+END;`
+
+// ensureMessageSearch creates the full-text index and its triggers on db. When the index is
+// new and messages already has rows (database created before the index existed), it is
+// rebuilt from the table once.
+func ensureMessageSearch(db *sql.DB, ns *namespaceRewriter) error {
+	var existing int
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?`, ns.tableName("messages_fts"),
+	).Scan(&existing); err != nil {
+		return err
+	}
+	if _, err := db.Exec(createMessageSearch); err != nil {
+		return err
+	}
+	if existing == 0 {
+		if _, err := db.Exec(`INSERT INTO messages_fts(messages_fts) VALUES ('rebuild')`); err != nil {
+			return fmt.Errorf("build message search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// initMessageSearch sets up the full-text index on every guild database. Failing to do so
+// doesn't fail Init: the store works without it and SearchMessages reports why.
+func (s *Store) initMessageSearch() {
+	for _, db := range s.guildDBs() {
+		if err := ensureMessageSearch(db, s.ns); err != nil {
+			s.searchErr = fmt.Errorf("%w (SQLite build without FTS5?): %v", ErrSearchUnavailable, err)
+			log.Warn().Databasef("Message full-text search disabled: %v", err)
+			return
+		}
+	}
+}
+
+// SearchMessages returns the non-expired messages of a guild whose content matches query,
+// most relevant first (FTS5 bm25), with their attachment and embed metadata. Every word of
+// query must appear (in any order, ignoring case and accents); a query wrapped in double
+// quotes matches the exact phrase. limit <= 0 uses DefaultSearchLimit. Returns
+// ErrSearchUnavailable when the index could not be created.
+func (s *Store) SearchMessages(guildID, query string, limit int) ([]MessageRecord, error) {
+	return s.SearchMessagesContext(context.Background(), guildID, query, limit)
+}
+
+// SearchMessagesContext is SearchMessages with a context that cancels the query.
+func (s *Store) SearchMessagesContext(ctx context.Context, guildID, query string, limit int) ([]MessageRecord, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	if s.searchErr != nil {
+		return nil, s.searchErr
+	}
+	match := ftsMatchQuery(query)
+	if guildID == "" || match == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	// Messages still queued would only reach the index later
+	s.FlushWrites()
+
+	rows, err := s.dbFor(guildID).QueryContext(ctx,
+		`SELECT m.guild_id, m.message_id, m.channel_id, m.author_id, m.author_username, m.author_avatar, m.content, m.cached_at, m.expires_at, m.content_truncated, m.original_length, m.content_omitted, m.attachment_count
+         FROM messages_fts
+         JOIN messages m ON m.rowid = messages_fts.rowid
+         WHERE messages_fts MATCH ? AND m.guild_id=? AND (m.expires_at IS NULL OR m.expires_at > CURRENT_TIMESTAMP)
+         ORDER BY messages_fts.rank
+         LIMIT ?`,
+		match, guildID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var out []MessageRecord
+	for rows.Next() {
+		var rec MessageRecord
+		var expires sql.NullTime
+		if err := rows.Scan(
+			&rec.GuildID,
+			&rec.MessageID,
+			&rec.ChannelID,
+			&rec.AuthorID,
+			&rec.AuthorUsername,
+			&rec.AuthorAvatar,
+			&rec.Content,
+			&rec.CachedAt,
+			&expires,
+			&rec.Truncated,
+			&rec.OriginalLength,
+			&rec.ContentOmitted,
+			&rec.AttachmentCount,
+		); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if expires.Valid {
+			rec.HasExpiry = true
+			rec.ExpiresAt = expires.Time
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+	// Load media after iterating so no query runs with the cursor open
+	for i := range out {
+		s.loadMessageMedia(ctx, &out[i])
+	}
+	return out, nil
+}
+
+// ftsMatchQuery turns free text into an FTS5 query: each word becomes a quoted term (so
+// operators and punctuation in user input can't cause syntax errors), combined with AND.
+// Text wrapped in double quotes becomes a single phrase.
+func ftsMatchQuery(query string) string {
+	query = strings.TrimSpace(query)
+	quote := func(term string) string {
+		return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	if len(query) >= 2 && strings.HasPrefix(query, `"`) && strings.HasSuffix(query, `"`) {
+		if phrase := strings.TrimSpace(query[1 : len(query)-1]); phrase != "" {
+			return quote(phrase)
+		}
+		return ""
+	}
+	terms := strings.Fields(query)
+	for i, t := range terms {
+		terms[i] = quote(t)
+	}
+	return strings.Join(terms, " ")
+}
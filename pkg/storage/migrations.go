@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+// Migration is a registered schema change (see RegisterMigration).
+type Migration struct {
+	Version int
+	Up      func(tx *sql.Tx) error
+}
+
+var (
+	migrationsMu sync.Mutex
+	migrations   = map[int]func(tx *sql.Tx) error{}
+)
+
+// RegisterMigration adds a schema change applied by Store.Migrate (and so by Init) to every
+// database that hasn't recorded version yet, in ascending version order. Each migration runs
+// in its own transaction together with the schema_version row that records it; if up fails,
+// nothing of it is kept and Migrate stops there. The tables created by Init are the baseline,
+// so migrations only describe changes on top of them.
+//
+// Meant to be called from init() of the package that owns the change, before the store is
+// opened. Downstream bots should use versions from 1000 up to stay clear of the library's.
+// Statements are namespaced like the store's own (Options.Namespace), but only for the
+// library's tables. It panics if version is not positive, up is nil or version is taken.
+func RegisterMigration(version int, up func(*sql.Tx) error) {
+	if version <= 0 {
+		panic(fmt.Sprintf("storage: migration version must be positive, got %d", version))
+	}
+	if up == nil {
+		panic(fmt.Sprintf("storage: migration %d is nil", version))
+	}
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	if _, dup := migrations[version]; dup {
+		panic(fmt.Sprintf("storage: migration %d already registered", version))
+	}
+	migrations[version] = up
+}
+
+// registeredMigrations returns the registered migrations in ascending version order.
+func registeredMigrations() []Migration {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	out := make([]Migration, 0, len(migrations))
+	for v, up := range migrations {
+		out = append(out, Migration{Version: v, Up: up})
+	}
+	slices.SortFunc(out, func(a, b Migration) int { return a.Version - b.Version })
+	return out
+}
+
+// Migrate applies the registered migrations not yet recorded in schema_version, on the primary
+// database and on every shard. Init calls it; calling it again is a no-op unless migrations
+// were registered since.
+func (s *Store) Migrate() error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	pending := registeredMigrations()
+	if len(pending) == 0 {
+		return nil
+	}
+	dbs := s.allDBs()
+	for i, db := range dbs {
+		applied, err := appliedMigrations(db)
+		if err != nil {
+			return fmt.Errorf("read schema version: %w", err)
+		}
+		for _, m := range pending {
+			if applied[m.Version] {
+				continue
+			}
+			if err := s.retryWrite("Migrate", func() error { return applyMigration(db, m) }); err != nil {
+				return fmt.Errorf("migration %d: %w", m.Version, err)
+			}
+			if len(dbs) > 1 {
+				log.Info().Databasef("Applied schema migration %d (database %d/%d)", m.Version, i+1, len(dbs))
+			} else {
+				log.Info().Databasef("Applied schema migration %d", m.Version)
+			}
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the highest migration version applied to the primary database (0 when none).
+func (s *Store) SchemaVersion() (int, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	var v sql.NullInt64
+	if err := s.db.QueryRow(`SELECT MAX(version) FROM schema_version`).Scan(&v); err != nil {
+		return 0, err
+	}
+	return int(v.Int64), nil
+}
+
+func appliedMigrations(db *sql.DB) (map[int]bool, error) {
+	rows, err := db.Query(`SELECT version FROM schema_version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+func applyMigration(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err := m.Up(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version, applied_at) VALUES (?, ?)`, m.Version, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// NamespaceEnv names the environment variable read by NamespaceFromEnv.
+const NamespaceEnv = "DISCORDCORE_DB_NAMESPACE"
+
+var namespacePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// NamespaceFromEnv returns the store namespace configured in NamespaceEnv (empty when unset).
+func NamespaceFromEnv() string {
+	return strings.ToLower(strings.TrimSpace(os.Getenv(NamespaceEnv)))
+}
+
+// validateNamespace accepts the empty namespace (no prefixing) or a short lowercase identifier.
+func validateNamespace(ns string) error {
+	if ns == "" {
+		return nil
+	}
+	// "idx_" would collide with the index prefix and "sqlite_" is reserved by SQLite
+	if namespacePattern.MatchString(ns) && ns != "idx" && !strings.HasPrefix(ns, "idx_") && ns != "sqlite" && !strings.HasPrefix(ns, "sqlite_") {
+		return nil
+	}
+	return fmt.Errorf("invalid store namespace %q: use lowercase letters, digits and underscores, starting with a letter (max 32)", ns)
+}
+
+// namespacedTables lists every table created by ensureSchema. Identifiers with these names
+// (and index names starting with "idx_") are prefixed by the namespace rewriter.
+var namespacedTables = map[string]bool{
+	"messages":            true,
+	"member_joins":        true,
+	"membership_events":   true,
+	"avatars_current":     true,
+	"avatars_history":     true,
+	"guild_meta":          true,
+	"runtime_meta":        true,
+	"roles_current":       true,
+	"persistent_cache":    true,
+	"automod_actions":     true,
+	"reactions":           true,
+	"automod_feedback":    true,
+	"voice_sessions":      true,
+	"emoji_usage":         true,
+	"command_usage":       true,
+	"automod_user_state":  true,
+	"scheduled_tasks":     true,
+	"message_attachments": true,
+	"message_embeds":      true,
+	"schema_version":      true,
+	// Full-text index over messages and the triggers that keep it in sync (message_search.go);
+	// FTS5 names its shadow tables after the physical name, so they follow the prefix.
+	"messages_fts":    true,
+	"messages_fts_ai": true,
+	"messages_fts_ad": true,
+	"messages_fts_au": true,
+}
+
+// namespaceRewriter prefixes table and index identifiers in SQL text. String literals,
+// quoted identifiers and column references (identifiers after a '.') are left untouched.
+type namespaceRewriter struct {
+	prefix string
+	cache  sync.Map // query -> rewritten query
+}
+
+func newNamespaceRewriter(ns string) *namespaceRewriter {
+	return &namespaceRewriter{prefix: ns + "_"}
+}
+
+// tableName returns the physical name of a table in this namespace.
+func (r *namespaceRewriter) tableName(table string) string {
+	if r == nil {
+		return table
+	}
+	return r.prefix + table
+}
+
+func (r *namespaceRewriter) rewrite(query string) string {
+	if cached, ok := r.cache.Load(query); ok {
+		return cached.(string)
+	}
+	var b strings.Builder
+	b.Grow(len(query) + 64)
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Literal or quoted identifier: copy through the closing quote (doubled quotes escape)
+			j := i + 1
+			for j < len(query) {
+				if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if j < len(query) {
+				j++
+			}
+			b.WriteString(query[i:j])
+			i = j
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			j := strings.IndexByte(query[i:], '\n')
+			if j < 0 {
+				j = len(query) - i
+			}
+			b.WriteString(query[i : i+j])
+			i += j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(query) && isIdentPart(query[j]) {
+				j++
+			}
+			word := query[i:j]
+			lower := strings.ToLower(word)
+			afterDot := i > 0 && query[i-1] == '.'
+			if !afterDot && (namespacedTables[lower] || strings.HasPrefix(lower, "idx_")) {
+				b.WriteString(r.prefix)
+			}
+			b.WriteString(word)
+			i = j
+		case c >= '0' && c <= '9':
+			// Numbers (e.g. 1e3) are not identifiers
+			j := i + 1
+			for j < len(query) && isIdentPart(query[j]) {
+				j++
+			}
+			b.WriteString(query[i:j])
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	out := b.String()
+	r.cache.Store(query, out)
+	return out
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// openNamespacedDB opens dsn through a connector that rewrites every statement with rw,
+// so all store queries (including schema creation) are namespaced in the query layer.
+func openNamespacedDB(dsn string, rw *namespaceRewriter) (*sql.DB, error) {
+	probe, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := probe.Driver()
+	_ = probe.Close()
+	return sql.OpenDB(&namespacedConnector{dsn: dsn, drv: drv, rw: rw}), nil
+}
+
+type namespacedConnector struct {
+	dsn string
+	drv driver.Driver
+	rw  *namespaceRewriter
+}
+
+func (c *namespacedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.drv.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &namespacedConn{Conn: conn, rw: c.rw}, nil
+}
+
+func (c *namespacedConnector) Driver() driver.Driver { return c.drv }
+
+// namespacedConn rewrites queries before delegating to the SQLite connection. Optional
+// driver interfaces the connection doesn't implement fall back to database/sql defaults.
+type namespacedConn struct {
+	driver.Conn
+	rw *namespaceRewriter
+}
+
+func (c *namespacedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(c.rw.rewrite(query))
+}
+
+func (c *namespacedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, c.rw.rewrite(query))
+	}
+	return c.Prepare(query)
+}
+
+func (c *namespacedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // fallback required by the driver.Conn contract
+}
+
+func (c *namespacedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, c.rw.rewrite(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *namespacedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, c.rw.rewrite(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *namespacedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *namespacedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *namespacedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *namespacedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
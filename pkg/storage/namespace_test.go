@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValidateNamespace(t *testing.T) {
+	for ns, ok := range map[string]bool{
+		"":                                  true,
+		"dev":                               true,
+		"dev_2":                             true,
+		"Dev":                               false,
+		"2dev":                              false,
+		"dev-a":                             false,
+		"idx":                               false,
+		"idx_dev":                           false,
+		"sqlite_x":                          false,
+		"a23456789012345678901234567890123": false,
+	} {
+		if err := validateNamespace(ns); (err == nil) != ok {
+			t.Errorf("validateNamespace(%q) = %v, want ok=%v", ns, err, ok)
+		}
+	}
+}
+
+func TestNamespaceRewrite(t *testing.T) {
+	rw := newNamespaceRewriter("dev")
+	for _, tc := range []struct{ in, want string }{
+		{`SELECT * FROM messages WHERE guild_id=?`, `SELECT * FROM dev_messages WHERE guild_id=?`},
+		{`CREATE INDEX IF NOT EXISTS idx_messages_gid ON messages(guild_id)`, `CREATE INDEX IF NOT EXISTS dev_idx_messages_gid ON dev_messages(guild_id)`},
+		{`SELECT m.content FROM messages m JOIN message_embeds e ON e.message_id = m.message_id`, `SELECT m.content FROM dev_messages m JOIN dev_message_embeds e ON e.message_id = m.message_id`},
+		{`SELECT 'messages', "messages", x.messages FROM reactions x`, `SELECT 'messages', "messages", x.messages FROM dev_reactions x`},
+		{"DELETE FROM reactions -- not messages\nWHERE id=1e3", "DELETE FROM dev_reactions -- not messages\nWHERE id=1e3"},
+		{`SELECT 'it''s messages' FROM voice_sessions`, `SELECT 'it''s messages' FROM dev_voice_sessions`},
+	} {
+		if got := rw.rewrite(tc.in); got != tc.want {
+			t.Errorf("rewrite(%q)\n got %q\nwant %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestNamespacesShareOneFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.db")
+	stores := map[string]*Store{}
+	for _, ns := range []string{"", "dev", "prod"} {
+		s := NewStoreWithOptions(path, Options{Namespace: ns})
+		if err := s.Init(); err != nil {
+			t.Fatalf("init namespace %q: %v", ns, err)
+		}
+		t.Cleanup(func() { s.Close() })
+		stores[ns] = s
+	}
+
+	// Same guild, same user: each namespace keeps its own rows
+	at := time.Now().Add(-time.Minute)
+	for ns, s := range stores {
+		for range len(ns) + 1 {
+			if err := s.RecordJoin("g1", "u1", at); err != nil {
+				t.Fatalf("record join in %q: %v", ns, err)
+			}
+		}
+		if err := s.UpsertMessage(MessageRecord{GuildID: "g1", MessageID: "m1", ChannelID: "c1", AuthorID: "u1", Content: "from " + ns, CachedAt: at}); err != nil {
+			t.Fatalf("upsert message in %q: %v", ns, err)
+		}
+	}
+	for ns, s := range stores {
+		events, err := s.GetMembershipEvents("g1", "u1")
+		if err != nil {
+			t.Fatalf("events in %q: %v", ns, err)
+		}
+		if len(events) != len(ns)+1 {
+			t.Errorf("namespace %q sees %d events, want %d", ns, len(events), len(ns)+1)
+		}
+		msg, err := s.GetMessage("g1", "m1")
+		if err != nil || msg == nil {
+			t.Fatalf("message in %q: %v", ns, err)
+		}
+		if msg.Content != "from "+ns {
+			t.Errorf("namespace %q reads %q", ns, msg.Content)
+		}
+	}
+
+	// Purging a guild in one namespace leaves the others intact
+	if _, err := stores["dev"].PurgeGuild("g1"); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if msg, _ := stores["dev"].GetMessage("g1", "m1"); msg != nil {
+		t.Error("purged namespace still has the message")
+	}
+	if msg, _ := stores["prod"].GetMessage("g1", "m1"); msg == nil {
+		t.Error("purge in dev removed the prod message")
+	}
+
+	var tables int
+	if err := stores[""].db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name IN ('messages', 'dev_messages', 'prod_messages')`).Scan(&tables); err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	if tables != 3 {
+		t.Errorf("found %d messages tables, want 3", tables)
+	}
+}
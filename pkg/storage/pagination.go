@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page size limits of the paginated listings.
+const (
+	DefaultPageSize = 25
+	MaxPageSize     = 500
+)
+
+// ErrInvalidCursor is returned by the paginated list methods when the cursor wasn't produced
+// by this store (or is corrupted).
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Page is one page of a paginated list query. NextCursor is empty on the last page; otherwise
+// passing it back in PageRequest.Cursor returns the following page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// PageRequest selects a page: an empty Cursor starts from the beginning, and Limit <= 0 means
+// DefaultPageSize (capped at MaxPageSize).
+type PageRequest struct {
+	Cursor string
+	Limit  int
+}
+
+func (p PageRequest) limit() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageSize
+	case p.Limit > MaxPageSize:
+		return MaxPageSize
+	default:
+		return p.Limit
+	}
+}
+
+// Cursor is the decoded position of a page: the sort key (timestamp plus a unique id as the
+// tie-breaker) of the last item returned. Pages continue strictly after it, so rows inserted
+// concurrently don't shift or repeat items the way OFFSET does.
+type Cursor struct {
+	Time time.Time
+	ID   string
+}
+
+// EncodeCursor turns a position into the opaque string handed to callers.
+func EncodeCursor(c Cursor) string {
+	raw := "v1|" + strconv.FormatInt(c.Time.UnixNano(), 10) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[0] != "v1" || parts[2] == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{Time: time.Unix(0, nanos).UTC(), ID: parts[2]}, nil
+}
+
+func intCursor(t time.Time, id int64) Cursor {
+	return Cursor{Time: t, ID: strconv.FormatInt(id, 10)}
+}
+
+// decodePageCursor decodes the cursor of a request (nil when starting from the beginning).
+// numericID requires an integer id, for tables keyed by an AUTOINCREMENT column.
+func decodePageCursor(p PageRequest, numericID bool) (*keysetCursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+	c, err := DecodeCursor(p.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	kc := &keysetCursor{at: c.Time, id: c.ID}
+	if numericID {
+		id, err := strconv.ParseInt(c.ID, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		kc.id = id
+	}
+	return kc, nil
+}
+
+// keysetCursor is a decoded cursor ready to be bound in a query.
+type keysetCursor struct {
+	at time.Time
+	id any
+}
+
+// after returns the WHERE fragment (prefixed with AND) selecting the rows that come strictly
+// after the cursor in the (timeCol, idCol) order; desc selects a newest-first listing.
+func (c *keysetCursor) after(timeCol, idCol string, desc bool) (string, []any) {
+	if c == nil {
+		return "", nil
+	}
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	at := c.at.UTC()
+	return ` AND (` + timeCol + ` ` + op + ` ? OR (` + timeCol + ` = ? AND ` + idCol + ` ` + op + ` ?))`, []any{at, at, c.id}
+}
+
+// newPage builds a page from up to limit+1 rows: the extra row only signals that there is a
+// next page, whose cursor points at the last item kept.
+func newPage[T any](items []T, limit int, cursorOf func(T) Cursor) Page[T] {
+	if len(items) <= limit {
+		return Page[T]{Items: items}
+	}
+	items = items[:limit]
+	return Page[T]{Items: items, NextCursor: EncodeCursor(cursorOf(items[limit-1]))}
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// collectPages walks every page of a paginated listing and returns all items in order.
+func collectPages[T any](t *testing.T, limit int, fetch func(PageRequest) (Page[T], error)) []T {
+	t.Helper()
+	var all []T
+	req := PageRequest{Limit: limit}
+	for range 100 {
+		page, err := fetch(req)
+		if err != nil {
+			t.Fatalf("fetch page: %v", err)
+		}
+		if len(page.Items) > limit {
+			t.Fatalf("page has %d items, limit is %d", len(page.Items), limit)
+		}
+		all = append(all, page.Items...)
+		if page.NextCursor == "" {
+			return all
+		}
+		req.Cursor = page.NextCursor
+	}
+	t.Fatal("pagination did not terminate")
+	return nil
+}
+
+func TestAvatarHistoryPage(t *testing.T) {
+	s := newTestStore(t, Options{})
+	base := time.Now().UTC().Add(-time.Hour)
+	for i := range 7 {
+		// Pairs of changes share a timestamp so the id tie-breaker is exercised.
+		at := base.Add(time.Duration(i/2) * time.Minute)
+		if _, _, err := s.UpsertAvatar("g1", "u1", "hash"+strconv.Itoa(i), at); err != nil {
+			t.Fatalf("upsert avatar: %v", err)
+		}
+	}
+
+	want, err := s.GetAvatarHistory("g1", "u1", 0)
+	if err != nil {
+		t.Fatalf("get avatar history: %v", err)
+	}
+	got := collectPages(t, 3, func(req PageRequest) (Page[AvatarRecord], error) {
+		return s.AvatarHistoryPage("g1", "u1", req)
+	})
+	if len(want) == 0 || len(got) != len(want) {
+		t.Fatalf("got %d records over all pages, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID || got[i].NewHash != want[i].NewHash {
+			t.Errorf("record %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := s.AvatarHistoryPage("g1", "u1", PageRequest{Cursor: "garbage"}); err != ErrInvalidCursor {
+		t.Errorf("bad cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestMembershipEventsPage(t *testing.T) {
+	s := newTestStore(t, Options{})
+	base := time.Now().UTC().Add(-time.Hour)
+	for i := range 6 {
+		at := base.Add(time.Duration(i/2) * time.Minute)
+		record := s.RecordJoin
+		if i%2 == 1 {
+			record = s.RecordLeave
+		}
+		if err := record("g1", "u1", at); err != nil {
+			t.Fatalf("record event: %v", err)
+		}
+	}
+	if err := s.RecordJoin("g1", "u2", base); err != nil {
+		t.Fatalf("record event: %v", err)
+	}
+
+	got := collectPages(t, 4, func(req PageRequest) (Page[MembershipEvent], error) {
+		return s.MembershipEventsPage("g1", "u1", req)
+	})
+	if len(got) != 6 {
+		t.Fatalf("got %d events over all pages, want 6", len(got))
+	}
+	for i, ev := range got {
+		want := MembershipJoin
+		if i%2 == 1 {
+			want = MembershipLeave
+		}
+		if ev.Type != want {
+			t.Errorf("event %d = %s, want %s", i, ev.Type, want)
+		}
+		if i > 0 && ev.ID <= got[i-1].ID {
+			t.Errorf("event %d out of order: id %d after %d", i, ev.ID, got[i-1].ID)
+		}
+	}
+}
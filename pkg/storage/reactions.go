@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reaction actions persisted in the reactions table.
+const (
+	ReactionAdd    = "add"
+	ReactionRemove = "remove"
+)
+
+// ReactionEvent is a persisted reaction add/remove on a message.
+type ReactionEvent struct {
+	ID        int64
+	GuildID   string
+	ChannelID string
+	MessageID string
+	UserID    string
+	Emoji     string // unicode name, or "name:id" for custom emojis
+	Action    string // ReactionAdd ou ReactionRemove
+	CreatedAt time.Time
+}
+
+// RecordReaction appends a reaction add/remove event.
+func (s *Store) RecordReaction(r ReactionEvent) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if r.GuildID == "" || r.MessageID == "" {
+		return nil
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	_, err := s.execWrite(s.dbFor(r.GuildID), "RecordReaction",
+		`INSERT INTO reactions (guild_id, channel_id, message_id, user_id, emoji, action, created_at)
+         VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		r.GuildID, r.ChannelID, r.MessageID, r.UserID, r.Emoji, r.Action, r.CreatedAt.UTC(),
+	)
+	return err
+}
+
+// GetReactions returns the reaction events recorded for a message, oldest first.
+func (s *Store) GetReactions(guildID, messageID string) ([]ReactionEvent, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	return s.listReactions(guildID, messageID, nil, 0)
+}
+
+// ReactionsPage is the paginated variant of GetReactions (oldest first).
+func (s *Store) ReactionsPage(guildID, messageID string, page PageRequest) (Page[ReactionEvent], error) {
+	if s.db == nil {
+		return Page[ReactionEvent]{}, fmt.Errorf("store not initialized")
+	}
+	cursor, err := decodePageCursor(page, true)
+	if err != nil {
+		return Page[ReactionEvent]{}, err
+	}
+	limit := page.limit()
+	items, err := s.listReactions(guildID, messageID, cursor, limit+1)
+	if err != nil {
+		return Page[ReactionEvent]{}, err
+	}
+	return newPage(items, limit, func(r ReactionEvent) Cursor { return intCursor(r.CreatedAt, r.ID) }), nil
+}
+
+// listReactions lists reaction events after cursor (nil: from the oldest); limit <= 0 means no limit.
+func (s *Store) listReactions(guildID, messageID string, cursor *keysetCursor, limit int) ([]ReactionEvent, error) {
+	query := `SELECT id, guild_id, channel_id, message_id, user_id, emoji, action, created_at
+         FROM reactions
+         WHERE guild_id=? AND message_id=?`
+	args := []any{guildID, messageID}
+	clause, cursorArgs := cursor.after("created_at", "id", false)
+	query += clause + ` ORDER BY created_at ASC, id ASC`
+	args = append(args, cursorArgs...)
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.dbFor(guildID).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ReactionEvent
+	for rows.Next() {
+		var r ReactionEvent
+		if err := rows.Scan(&r.ID, &r.GuildID, &r.ChannelID, &r.MessageID, &r.UserID, &r.Emoji, &r.Action, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// CleanupObsoleteReactions removes reaction events older than retentionDays
+func (s *Store) CleanupObsoleteReactions(retentionDays int) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if retentionDays <= 0 {
+		retentionDays = 30 // default: keep 30 days of reaction events
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
+	return s.execAllShards(`DELETE FROM reactions WHERE created_at < ?`, cutoff)
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"modernc.org/sqlite"
+)
+
+const (
+	// DefaultWriteRetries is how many times a write is retried after SQLITE_BUSY/SQLITE_LOCKED.
+	DefaultWriteRetries = 3
+	// writeRetryBaseDelay is the first backoff; it doubles on each attempt.
+	writeRetryBaseDelay = 20 * time.Millisecond
+
+	// Primary SQLite result codes (extended codes carry them in the low byte).
+	sqliteBusy   = 5
+	sqliteLocked = 6
+)
+
+// isBusyError reports whether err is a transient lock contention error from SQLite.
+func isBusyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var se *sqlite.Error
+	if errors.As(err, &se) {
+		switch se.Code() & 0xff {
+		case sqliteBusy, sqliteLocked:
+			return true
+		}
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "SQLITE_BUSY") || strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked")
+}
+
+// writeRetries resolves Options.WriteRetries (0 = default, negative = disabled).
+func (s *Store) writeRetries() int {
+	switch {
+	case s.opts.WriteRetries < 0:
+		return 0
+	case s.opts.WriteRetries == 0:
+		return DefaultWriteRetries
+	default:
+		return s.opts.WriteRetries
+	}
+}
+
+// retryWrite runs fn and retries it with exponential backoff while it fails with a busy/locked
+// error. fn must be safe to re-run from scratch (a single statement or a whole transaction).
+// Only short writes go through here; long-running reads surface busy errors directly.
+func (s *Store) retryWrite(op string, fn func() error) error {
+	retries := s.writeRetries()
+	delay := writeRetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := s.guardWrite(fn)
+		if err == nil || attempt >= retries || !isBusyError(err) {
+			if err != nil && attempt > 0 && isBusyError(err) {
+				log.Error().Errorf("Store write %s still busy after %d retries: %v", op, attempt, err)
+			}
+			return err
+		}
+		log.Warn().Applicationf("Store write %s hit lock contention (attempt %d/%d), retrying in %s: %v", op, attempt+1, retries, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// guardWrite runs fn holding maintMu for reading, so Compact (which holds it for writing while
+// VACUUM runs) waits for it. Every write must go through here, usually via retryWrite; the
+// deferred unlock keeps a panicking fn (e.g. a WithTx callback) from leaving the lock held.
+func (s *Store) guardWrite(fn func() error) error {
+	s.maintMu.RLock()
+	defer s.maintMu.RUnlock()
+	return fn()
+}
+
+// execWrite executes a single write statement through retryWrite.
+func (s *Store) execWrite(db *sql.DB, op, query string, args ...any) (sql.Result, error) {
+	return s.execWriteContext(context.Background(), db, op, query, args...)
+}
+
+// execWriteContext is execWrite with a context; a cancelled context is not retried.
+func (s *Store) execWriteContext(ctx context.Context, db *sql.DB, op, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := s.retryWrite(op, func() error {
+		var err error
+		res, err = db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// holdWriteLock takes the write lock of the database at path from a separate connection and
+// returns a function that releases it.
+func holdWriteLock(t *testing.T, path string) (release func()) {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open locker: %v", err)
+	}
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("locker conn: %v", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), `BEGIN IMMEDIATE`); err != nil {
+		t.Fatalf("take write lock: %v", err)
+	}
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			_, _ = conn.ExecContext(context.Background(), `ROLLBACK`)
+			_ = conn.Close()
+			_ = db.Close()
+		})
+	}
+	t.Cleanup(release)
+	return release
+}
+
+// busyStore opens a store that fails at once on a held lock (no busy_timeout), so contention
+// reaches retryWrite instead of being absorbed by SQLite.
+func busyStore(t *testing.T, path string, retries int) *Store {
+	t.Helper()
+	s := NewStoreWithOptions(path, Options{BusyTimeout: -1, WriteRetries: retries})
+	if err := s.Init(); err != nil {
+		t.Fatalf("init store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestRetryWriteSurvivesTransientBusy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+	s := busyStore(t, path, DefaultWriteRetries)
+	release := holdWriteLock(t, path)
+	// Released during the backoff (20ms, 40ms, 80ms), while the writers are retrying.
+	time.AfterFunc(50*time.Millisecond, release)
+
+	const writers = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := range writers {
+		wg.Go(func() {
+			errs <- s.RecordJoin("g1", "u"+strconv.Itoa(w), time.Now())
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("write failed despite retries: %v", err)
+		}
+	}
+	n, err := s.CountMembershipEvents("g1", MembershipJoin, time.Time{}, time.Time{})
+	if err != nil || n != writers {
+		t.Errorf("stored %d events (err %v), want %d", n, err, writers)
+	}
+}
+
+func TestRetryWriteSurfacesPersistentBusy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+	for _, retries := range []int{-1, 2} {
+		s := busyStore(t, path, retries)
+		release := holdWriteLock(t, path)
+		err := s.RecordJoin("g1", "u1", time.Now())
+		release()
+		if !isBusyError(err) {
+			t.Errorf("retries=%d: error = %v, want a busy error", retries, err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scheduled task states.
+const (
+	ScheduledTaskPending = "pending"
+	ScheduledTaskDone    = "done"
+	ScheduledTaskFailed  = "failed"
+)
+
+// ScheduledTask is a durable task persisted in scheduled_tasks and run by task.Dispatcher
+// once DueAt has passed. Interval > 0 makes it recurring.
+type ScheduledTask struct {
+	ID        int64
+	Type      string
+	GuildID   string // optional; used by guild purges and listings
+	Payload   string // free-form JSON, decoded by the type's handler
+	DueAt     time.Time
+	Interval  time.Duration
+	Status    string
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
+
+// ScheduleTask persists a pending task and returns its ID.
+func (s *Store) ScheduleTask(t ScheduledTask) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if t.Type == "" {
+		return 0, fmt.Errorf("scheduled task type is empty")
+	}
+	if t.DueAt.IsZero() {
+		t.DueAt = time.Now()
+	}
+	if t.Payload == "" {
+		t.Payload = "null"
+	}
+	now := time.Now().UTC()
+	res, err := s.execWrite(s.db, "ScheduleTask",
+		`INSERT INTO scheduled_tasks (type, guild_id, payload, due_at, interval_seconds, status, attempts, last_error, created_at, updated_at)
+         VALUES (?, ?, ?, ?, ?, ?, 0, '', ?, ?)`,
+		t.Type, t.GuildID, t.Payload, t.DueAt.UTC(), int64(t.Interval/time.Second), ScheduledTaskPending, now, now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// DueScheduledTasks returns up to limit pending tasks whose due time is at or before now, oldest first.
+// When types is non-empty only tasks of those types are returned, so due tasks nobody handles do not
+// fill the batch and starve the rest.
+func (s *Store) DueScheduledTasks(now time.Time, limit int, types ...string) ([]ScheduledTask, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	where := `WHERE status=? AND due_at <= ?`
+	args := []any{ScheduledTaskPending, now.UTC()}
+	if len(types) > 0 {
+		where += ` AND type IN (?` + strings.Repeat(`, ?`, len(types)-1) + `)`
+		for _, t := range types {
+			args = append(args, t)
+		}
+	}
+	return s.queryScheduledTasks(where+` ORDER BY due_at ASC, id ASC LIMIT ?`, append(args, limit)...)
+}
+
+// PendingScheduledTasks lists pending tasks for a guild (all guilds when guildID is empty), soonest first.
+func (s *Store) PendingScheduledTasks(guildID string) ([]ScheduledTask, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	return s.listPendingScheduledTasks(guildID, nil, 0)
+}
+
+// PendingScheduledTasksPage is the paginated variant of PendingScheduledTasks (soonest first).
+func (s *Store) PendingScheduledTasksPage(guildID string, page PageRequest) (Page[ScheduledTask], error) {
+	if s.db == nil {
+		return Page[ScheduledTask]{}, fmt.Errorf("store not initialized")
+	}
+	cursor, err := decodePageCursor(page, true)
+	if err != nil {
+		return Page[ScheduledTask]{}, err
+	}
+	limit := page.limit()
+	items, err := s.listPendingScheduledTasks(guildID, cursor, limit+1)
+	if err != nil {
+		return Page[ScheduledTask]{}, err
+	}
+	return newPage(items, limit, func(t ScheduledTask) Cursor { return intCursor(t.DueAt, t.ID) }), nil
+}
+
+// listPendingScheduledTasks lists pending tasks after cursor (nil: from the soonest); limit <= 0 means no limit.
+func (s *Store) listPendingScheduledTasks(guildID string, cursor *keysetCursor, limit int) ([]ScheduledTask, error) {
+	where := `WHERE status=?`
+	args := []any{ScheduledTaskPending}
+	if guildID != "" {
+		where += ` AND guild_id=?`
+		args = append(args, guildID)
+	}
+	clause, cursorArgs := cursor.after("due_at", "id", false)
+	where += clause + ` ORDER BY due_at ASC, id ASC`
+	args = append(args, cursorArgs...)
+	if limit > 0 {
+		where += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	return s.queryScheduledTasks(where, args...)
+}
+
+// GetScheduledTask returns a task by ID (nil if not found).
+func (s *Store) GetScheduledTask(id int64) (*ScheduledTask, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	tasks, err := s.queryScheduledTasks(`WHERE id=?`, id)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+// CompleteScheduledTask marks a one-off task as done.
+func (s *Store) CompleteScheduledTask(id int64) error {
+	return s.setScheduledTaskStatus("CompleteScheduledTask", id, ScheduledTaskDone, "")
+}
+
+// FailScheduledTask marks a task as permanently failed, keeping the last error.
+func (s *Store) FailScheduledTask(id int64, lastErr string) error {
+	return s.setScheduledTaskStatus("FailScheduledTask", id, ScheduledTaskFailed, lastErr)
+}
+
+// RescheduleTask keeps a task pending with a new due time. attempts is stored as given
+// (0 after a successful recurring run, incremented by the caller after a failure).
+func (s *Store) RescheduleTask(id int64, dueAt time.Time, attempts int, lastErr string) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	_, err := s.execWrite(s.db, "RescheduleTask",
+		`UPDATE scheduled_tasks SET due_at=?, attempts=?, last_error=?, status=?, updated_at=? WHERE id=?`,
+		dueAt.UTC(), attempts, lastErr, ScheduledTaskPending, time.Now().UTC(), id,
+	)
+	return err
+}
+
+// CancelScheduledTask deletes a pending task. Returns false when it did not exist or already ran.
+func (s *Store) CancelScheduledTask(id int64) (bool, error) {
+	if s.db == nil {
+		return false, fmt.Errorf("store not initialized")
+	}
+	res, err := s.execWrite(s.db, "CancelScheduledTask",
+		`DELETE FROM scheduled_tasks WHERE id=? AND status=?`, id, ScheduledTaskPending)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CleanupScheduledTasks removes done/failed tasks last updated before cutoff.
+func (s *Store) CleanupScheduledTasks(cutoff time.Time) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	res, err := s.execWrite(s.db, "CleanupScheduledTasks",
+		`DELETE FROM scheduled_tasks WHERE status IN (?, ?) AND updated_at < ?`,
+		ScheduledTaskDone, ScheduledTaskFailed, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *Store) setScheduledTaskStatus(op string, id int64, status, lastErr string) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	_, err := s.execWrite(s.db, op,
+		`UPDATE scheduled_tasks SET status=?, last_error=?, updated_at=? WHERE id=?`,
+		status, lastErr, time.Now().UTC(), id,
+	)
+	return err
+}
+
+func (s *Store) queryScheduledTasks(where string, args ...any) ([]ScheduledTask, error) {
+	rows, err := s.db.Query(
+		`SELECT id, type, guild_id, payload, due_at, interval_seconds, status, attempts, last_error, created_at
+         FROM scheduled_tasks `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ScheduledTask
+	for rows.Next() {
+		var t ScheduledTask
+		var intervalSeconds int64
+		if err := rows.Scan(&t.ID, &t.Type, &t.GuildID, &t.Payload, &t.DueAt, &intervalSeconds, &t.Status, &t.Attempts, &t.LastError, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		t.Interval = time.Duration(intervalSeconds) * time.Second
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// purgeGuildScheduledTasks removes a guild's scheduled tasks (they live in the primary database).
+func (s *Store) purgeGuildScheduledTasks(guildID string) (int64, error) {
+	res, err := s.execWrite(s.db, "PurgeGuildScheduledTasks", `DELETE FROM scheduled_tasks WHERE guild_id=?`, guildID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
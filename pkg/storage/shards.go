@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// dbFor returns the database holding guild-scoped rows for guildID.
+// In single-file mode it is always the primary database.
+func (s *Store) dbFor(guildID string) *sql.DB {
+	if len(s.shards) == 0 {
+		return s.db
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(guildID))
+	return s.shards[int(h.Sum32()%uint32(len(s.shards)))]
+}
+
+// guildDBs returns every database that may hold guild-scoped rows (for fan-out queries).
+func (s *Store) guildDBs() []*sql.DB {
+	if len(s.shards) == 0 {
+		return []*sql.DB{s.db}
+	}
+	return s.shards
+}
+
+// execAllShards runs a statement against every guild database and sums the affected rows.
+func (s *Store) execAllShards(query string, args ...any) (int64, error) {
+	var total int64
+	for _, db := range s.guildDBs() {
+		res, err := s.execWrite(db, "execAllShards", query, args...)
+		if err != nil {
+			return total, err
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			total += n
+		}
+	}
+	return total, nil
+}
+
+// shardPath derives the file name of shard i from the primary database path.
+func shardPath(dbPath string, i int) string {
+	if isMemoryPath(dbPath) {
+		return dbPath
+	}
+	base := strings.TrimSuffix(dbPath, ".db")
+	return fmt.Sprintf("%s.shard%d.db", base, i)
+}
@@ -1,29 +1,157 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/small-frappuccino/discordcore/pkg/util"
 	_ "modernc.org/sqlite"
 )
 
 // Store wraps an embedded SQLite database for durable caching of messages,
 // avatar hashes (current and history), guild metadata (e.g., bot_since) and member joins.
 // It uses modernc.org/sqlite for CGO-less builds.
+//
+// By default everything lives in a single file. With Options.ShardCount > 1, guild-scoped
+// tables are partitioned across extra files routed by guild ID (see Options).
 type Store struct {
 	dbPath string
 	db     *sql.DB
+	opts   Options
+
+	// shards holds the per-guild partitions when sharding is enabled; nil in single-file mode.
+	shards []*sql.DB
+
+	// wbuf queues message upserts when Options.WriteBufferSize > 0; nil otherwise (and after
+	// Close, so later upserts hit the closed database and fail instead of being queued).
+	wbuf atomic.Pointer[writeBuffer]
+
+	// ns prefixes table and index names when Options.Namespace is set; nil otherwise.
+	ns *namespaceRewriter
+
+	// searchErr is set when the full-text index couldn't be created (see SearchMessages).
+	searchErr error
+
+	// maintMu keeps writes out while Compact runs: every write holds it for reading (guardWrite,
+	// usually through retryWrite/execWrite), Compact for writing.
+	maintMu sync.RWMutex
+}
+
+// Options configures optional Store behaviour. The zero value keeps the single-file layout.
+type Options struct {
+	// ShardCount > 1 partitions guild-scoped tables (messages, avatars, joins, roles, guild_meta,
+	// automod_actions/feedback, reactions, voice_sessions, emoji_usage) across ShardCount
+	// files named "<db>.shard<N>.db", chosen by a stable hash of the guild ID. Writes for
+	// different guilds then contend on different file locks. Global tables (runtime_meta,
+	// persistent_cache) stay in the primary file. The value must not change once data
+	// has been written, otherwise guilds are routed to a different shard.
+	ShardCount int
+
+	// MaxContentLength caps the stored message content, in runes. Longer content is cut
+	// and suffixed with TruncationMarker, and the record is flagged as truncated with its
+	// original length. 0 uses DefaultMaxContentLength; a negative value disables the cap.
+	MaxContentLength int
+
+	// WriteBufferSize > 0 makes UpsertMessage asynchronous: records are queued on a buffer of
+	// this size and written in batches by a background goroutine, so event handlers don't
+	// wait on disk I/O. Durability tradeoff: queued writes are lost if the process crashes
+	// (at most WriteBufferSize records / WriteFlushInterval of activity); Close flushes them
+	// on a clean shutdown. Reads and deletes of a message still observe its queued writes.
+	WriteBufferSize int
+	// WriteFlushInterval is the maximum time a queued write waits (default DefaultWriteFlushInterval).
+	WriteFlushInterval time.Duration
+	// WriteBatchSize is the number of records written per transaction (default DefaultWriteBatchSize).
+	WriteBatchSize int
+	// WriteBufferDropWhenFull drops writes (with a logged warning) when the buffer is full
+	// instead of blocking the caller until there is room.
+	WriteBufferDropWhenFull bool
+
+	// WriteRetries is how many times upserts/inserts are retried with a short exponential
+	// backoff when SQLite reports SQLITE_BUSY/SQLITE_LOCKED despite busy_timeout.
+	// 0 uses DefaultWriteRetries; a negative value disables retries.
+	WriteRetries int
+
+	// AvatarBatchSize is the number of avatars written per transaction by UpsertAvatarsBatch
+	// (the silent refresh path). 0 uses DefaultAvatarBatchSize.
+	AvatarBatchSize int
+
+	// Namespace lets several bot environments share one database file: every table and index
+	// is prefixed with "<namespace>_" (e.g. "dev_messages"). The prefix is applied to each
+	// statement in the query layer, so no query can reach another namespace's data. Empty
+	// (the default) keeps the unprefixed names. Lowercase letters, digits and underscores.
+	Namespace string
+
+	// MessageArchiveDir, when set, makes CleanupExpiredMessages export the expired messages to
+	// gzip-compressed JSONL files in this directory (ArchiveExpiredMessages) before deleting
+	// them; a guild whose archive fails keeps its messages. MessageArchiveGuilds restricts
+	// archival to those guilds (empty: all); expired messages of other guilds are just deleted.
+	MessageArchiveDir    string
+	MessageArchiveGuilds []string
+
+	// JournalMode is the SQLite journal mode ("WAL", "DELETE", "TRUNCATE", "PERSIST", "MEMORY"
+	// or "OFF"). Empty uses DefaultJournalMode: with WAL, readers don't block the writer and
+	// vice versa, which avoids most "database is locked" errors under concurrent writes.
+	JournalMode string
+	// BusyTimeout is how long a connection waits for a lock held by another one before failing
+	// with SQLITE_BUSY. 0 uses DefaultBusyTimeout; a negative value fails immediately.
+	BusyTimeout time.Duration
 }
 
+const (
+	// DefaultMaxContentLength is generous enough for any regular message (Discord allows
+	// up to 4000 characters with Nitro) while bounding spam walls and pasted logs.
+	DefaultMaxContentLength = 4000
+
+	// TruncationMarker is appended to content cut by the store.
+	TruncationMarker = "…[truncated]"
+
+	// DefaultJournalMode and DefaultBusyTimeout are the connection settings used when
+	// Options leaves them empty (the NewStore defaults).
+	DefaultJournalMode = "WAL"
+	DefaultBusyTimeout = 5 * time.Second
+)
+
+// journalModes lists the values accepted in Options.JournalMode.
+var journalModes = []string{"WAL", "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "OFF"}
+
 // NewStore creates a new Store pointing to dbPath. Call Init() before using it.
+// In development mode (util.IsDevMode) the store always runs in memory.
 func NewStore(dbPath string) *Store {
-	return &Store{dbPath: dbPath}
+	return NewStoreWithOptions(dbPath, Options{})
+}
+
+// NewStoreWithOptions creates a new Store with the given options. Call Init() before using it.
+func NewStoreWithOptions(dbPath string, opts Options) *Store {
+	if util.IsDevMode() {
+		dbPath = util.MemoryDBPath
+	}
+	return &Store{dbPath: dbPath, opts: opts}
+}
+
+// isMemoryPath reports whether dbPath selects an in-memory database.
+func isMemoryPath(dbPath string) bool {
+	return dbPath == util.MemoryDBPath
+}
+
+var memoryDBSeq atomic.Uint64
+
+// memoryDSN names a fresh shared-cache in-memory database, so every pooled connection
+// (and the write buffer goroutine) sees the same data. Each call yields a distinct database,
+// which keeps shards separate. The data lives until the last connection is closed.
+func memoryDSN() string {
+	return fmt.Sprintf("file:discordcore-mem-%d?mode=memory&cache=shared", memoryDBSeq.Add(1))
 }
 
 // Init opens the SQLite database, configures pragmas, and ensures the schema exists.
+// In sharded mode every shard is opened and receives the same schema.
 func (s *Store) Init() error {
 	if s.db != nil {
 		return nil
@@ -31,49 +159,135 @@ func (s *Store) Init() error {
 	if s.dbPath == "" {
 		return fmt.Errorf("db path is empty")
 	}
-	if err := os.MkdirAll(filepath.Dir(s.dbPath), 0o755); err != nil {
-		return fmt.Errorf("failed to create db directory: %w", err)
+	if err := validateNamespace(s.opts.Namespace); err != nil {
+		return err
+	}
+	if mode := strings.ToUpper(strings.TrimSpace(s.opts.JournalMode)); mode != "" && !slices.Contains(journalModes, mode) {
+		return fmt.Errorf("invalid journal mode %q: use one of %s", s.opts.JournalMode, strings.Join(journalModes, ", "))
+	}
+	if s.opts.Namespace != "" {
+		s.ns = newNamespaceRewriter(s.opts.Namespace)
+	}
+	if !isMemoryPath(s.dbPath) {
+		if err := os.MkdirAll(filepath.Dir(s.dbPath), 0o755); err != nil {
+			return fmt.Errorf("failed to create db directory: %w", err)
+		}
 	}
 
-	db, err := sql.Open("sqlite", s.dbPath)
+	db, err := openDB(s.dbPath, s.opts, s.ns)
 	if err != nil {
-		return fmt.Errorf("open sqlite: %w", err)
+		return err
 	}
 
-	// Pragmas for durability and concurrency
-	if _, err := db.Exec(`PRAGMA journal_mode=WAL;`); err != nil {
-		_ = db.Close()
-		return fmt.Errorf("set WAL: %w", err)
+	if s.opts.ShardCount > 1 {
+		shards := make([]*sql.DB, 0, s.opts.ShardCount)
+		for i := 0; i < s.opts.ShardCount; i++ {
+			shard, err := openDB(shardPath(s.dbPath, i), s.opts, s.ns)
+			if err != nil {
+				for _, open := range shards {
+					_ = open.Close()
+				}
+				_ = db.Close()
+				return fmt.Errorf("open shard %d: %w", i, err)
+			}
+			shards = append(shards, shard)
+		}
+		s.shards = shards
 	}
-	if _, err := db.Exec(`PRAGMA foreign_keys=ON;`); err != nil {
-		_ = db.Close()
-		return fmt.Errorf("enable FKs: %w", err)
+
+	s.db = db
+	if err := s.Migrate(); err != nil {
+		_ = s.Close()
+		s.db, s.shards = nil, nil
+		return err
 	}
-	if _, err := db.Exec(`PRAGMA busy_timeout=5000;`); err != nil {
-		_ = db.Close()
-		return fmt.Errorf("set busy_timeout: %w", err)
+	s.initMessageSearch()
+	if s.opts.WriteBufferSize > 0 {
+		s.wbuf.Store(newWriteBuffer(s))
 	}
-	if _, err := db.Exec(`PRAGMA synchronous=NORMAL;`); err != nil {
+	return nil
+}
+
+// openDB opens a single SQLite file, applies pragmas and ensures the schema. A non-nil ns
+// routes every statement through the namespace rewriter.
+func openDB(path string, opts Options, ns *namespaceRewriter) (*sql.DB, error) {
+	dsn := path
+	if isMemoryPath(path) {
+		dsn = memoryDSN()
+	}
+	dsn = withPragmas(dsn, opts)
+	var db *sql.DB
+	var err error
+	if ns != nil {
+		db, err = openNamespacedDB(dsn, ns)
+	} else {
+		db, err = sql.Open("sqlite", dsn)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	// The pragmas run when a connection is opened; ping to surface errors here
+	if err := db.Ping(); err != nil {
 		_ = db.Close()
-		return fmt.Errorf("set synchronous: %w", err)
+		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
 
 	// Schema creation
-	if err := ensureSchema(db); err != nil {
+	if err := ensureSchema(db, ns); err != nil {
 		_ = db.Close()
-		return err
+		return nil, err
 	}
+	return db, nil
+}
 
-	s.db = db
-	return nil
+// withPragmas adds the connection pragmas to dsn. They go in the DSN (not a one-off Exec) so
+// every pooled connection gets them: busy_timeout, foreign_keys and synchronous are
+// per-connection settings, and a connection without busy_timeout fails with SQLITE_BUSY at once.
+// Transactions begin IMMEDIATE (_txlock): the store only opens transactions to write, and a
+// deferred one that reads first cannot upgrade to a write lock once another connection has
+// committed, which fails with SQLITE_BUSY without waiting for busy_timeout.
+func withPragmas(dsn string, opts Options) string {
+	mode := strings.ToUpper(strings.TrimSpace(opts.JournalMode))
+	if mode == "" {
+		mode = DefaultJournalMode
+	}
+	timeout := opts.BusyTimeout
+	if timeout == 0 {
+		timeout = DefaultBusyTimeout
+	}
+	pragmas := []string{
+		// busy_timeout first, so switching the journal mode also waits for other connections
+		fmt.Sprintf("busy_timeout(%d)", max(timeout, 0).Milliseconds()),
+		"journal_mode(" + mode + ")",
+		"foreign_keys(1)",
+		"synchronous(NORMAL)",
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_txlock=immediate&_pragma=" + strings.Join(pragmas, "&_pragma=")
 }
 
-// Close closes the underlying database.
+// Close closes the underlying database(s).
 func (s *Store) Close() error {
 	if s.db == nil {
 		return nil
 	}
-	return s.db.Close()
+	// Write out buffered messages before closing the databases
+	if wb := s.wbuf.Swap(nil); wb != nil {
+		wb.close()
+	}
+	var firstErr error
+	for _, shard := range s.shards {
+		if err := shard.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := s.db.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
 }
 
 // MessageRecord represents a cached Discord message snapshot for edit/delete notifications.
@@ -88,23 +302,40 @@ type MessageRecord struct {
 	CachedAt       time.Time
 	ExpiresAt      time.Time
 	HasExpiry      bool
+
+	// Truncated reports whether Content was cut to the store's MaxContentLength;
+	// OriginalLength then holds the full length in runes.
+	Truncated      bool
+	OriginalLength int
+
+	// ContentOmitted marks a record saved in privacy mode: Content is empty and OriginalLength,
+	// if set, was supplied by the caller. AttachmentCount is the number of attachments of the
+	// message (also kept when the attachment metadata itself is not stored).
+	ContentOmitted  bool
+	AttachmentCount int
+
+	// Attachments and Embeds are filled by the read methods from message_attachments and
+	// message_embeds (saved separately with SaveMessageMedia); UpsertMessage ignores them.
+	Attachments []MessageAttachment
+	Embeds      []MessageEmbedSummary
 }
 
-// UpsertMessage inserts or updates a message record (write-through).
-func (s *Store) UpsertMessage(m MessageRecord) error {
-	if s.db == nil {
-		return fmt.Errorf("store not initialized")
+// ClampContent applies the store's content length limit, returning the (possibly cut)
+// content, whether it was truncated and the original length in runes.
+func (s *Store) ClampContent(content string) (string, bool, int) {
+	limit := s.opts.MaxContentLength
+	if limit == 0 {
+		limit = DefaultMaxContentLength
 	}
-
-	var expires any
-	if m.HasExpiry {
-		expires = m.ExpiresAt.UTC()
-	} else {
-		expires = nil
+	runes := []rune(content)
+	if limit < 0 || len(runes) <= limit {
+		return content, false, len(runes)
 	}
-	_, err := s.db.Exec(
-		`INSERT INTO messages (guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at)
-         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	return string(runes[:limit]) + TruncationMarker, true, len(runes)
+}
+
+const upsertMessageSQL = `INSERT INTO messages (guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at, content_truncated, original_length, content_omitted, attachment_count)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
          ON CONFLICT(guild_id, message_id) DO UPDATE SET
            channel_id=excluded.channel_id,
            author_id=excluded.author_id,
@@ -112,20 +343,59 @@ func (s *Store) UpsertMessage(m MessageRecord) error {
            author_avatar=excluded.author_avatar,
            content=excluded.content,
            cached_at=excluded.cached_at,
-           expires_at=excluded.expires_at`,
-		m.GuildID, m.MessageID, m.ChannelID, m.AuthorID, m.AuthorUsername, m.AuthorAvatar, m.Content, m.CachedAt.UTC(), expires,
-	)
+           expires_at=excluded.expires_at,
+           content_truncated=excluded.content_truncated,
+           original_length=excluded.original_length,
+           content_omitted=excluded.content_omitted,
+           attachment_count=excluded.attachment_count`
+
+// UpsertMessage inserts or updates a message record. It is write-through unless the write
+// buffer is enabled (Options.WriteBufferSize), in which case the record is queued.
+func (s *Store) UpsertMessage(m MessageRecord) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if wb := s.wbuf.Load(); wb != nil {
+		return wb.enqueue(m)
+	}
+	_, err := s.execWrite(s.dbFor(m.GuildID), "UpsertMessage", upsertMessageSQL, s.upsertMessageArgs(m)...)
 	return err
 }
 
+// upsertMessageArgs builds the upsertMessageSQL arguments for a record.
+// Truncation is applied centrally here; callers always pass the full content.
+func (s *Store) upsertMessageArgs(m MessageRecord) []any {
+	var expires any
+	if m.HasExpiry {
+		expires = m.ExpiresAt.UTC()
+	}
+	content, truncated, origLen := s.ClampContent(m.Content)
+	if m.ContentOmitted {
+		content, truncated, origLen = "", false, m.OriginalLength
+	}
+	return []any{m.GuildID, m.MessageID, m.ChannelID, m.AuthorID, m.AuthorUsername, m.AuthorAvatar, content, m.CachedAt.UTC(), expires, truncated, origLen, m.ContentOmitted, m.AttachmentCount}
+}
+
 // GetMessage returns a non-expired message if present; nil if not found or expired.
 func (s *Store) GetMessage(guildID, messageID string) (*MessageRecord, error) {
 	if s.db == nil {
 		return nil, fmt.Errorf("store not initialized")
 	}
+	if wb := s.wbuf.Load(); wb != nil {
+		if m, ok := wb.lookup(guildID, messageID); ok {
+			if m.HasExpiry && !m.ExpiresAt.After(time.Now()) {
+				return nil, nil
+			}
+			if !m.ContentOmitted {
+				m.Content, m.Truncated, m.OriginalLength = s.ClampContent(m.Content)
+			}
+			s.loadMessageMedia(context.Background(), &m)
+			return &m, nil
+		}
+	}
 
-	row := s.db.QueryRow(
-		`SELECT guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at
+	row := s.dbFor(guildID).QueryRow(
+		`SELECT guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at, content_truncated, original_length, content_omitted, attachment_count
          FROM messages
          WHERE guild_id=? AND message_id=? AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)`,
 		guildID, messageID,
@@ -143,6 +413,10 @@ func (s *Store) GetMessage(guildID, messageID string) (*MessageRecord, error) {
 		&rec.Content,
 		&rec.CachedAt,
 		&expires,
+		&rec.Truncated,
+		&rec.OriginalLength,
+		&rec.ContentOmitted,
+		&rec.AttachmentCount,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -153,25 +427,73 @@ func (s *Store) GetMessage(guildID, messageID string) (*MessageRecord, error) {
 		rec.HasExpiry = true
 		rec.ExpiresAt = expires.Time
 	}
+	s.loadMessageMedia(context.Background(), &rec)
 	return &rec, nil
 }
 
+// loadMessageMedia fills the attachment/embed metadata of a record (best effort: a failure
+// leaves them empty, the message itself is still returned).
+func (s *Store) loadMessageMedia(ctx context.Context, rec *MessageRecord) {
+	attachments, embeds, err := s.GetMessageMediaContext(ctx, rec.GuildID, rec.MessageID)
+	if err == nil {
+		rec.Attachments, rec.Embeds = attachments, embeds
+	}
+}
+
 // DeleteMessage removes a message record (no error if absent).
 func (s *Store) DeleteMessage(guildID, messageID string) error {
 	if s.db == nil {
 		return fmt.Errorf("store not initialized")
 	}
-	_, err := s.db.Exec(`DELETE FROM messages WHERE guild_id=? AND message_id=?`, guildID, messageID)
-	return err
+	// Queued writes for the message must land before the delete, otherwise they would recreate it
+	s.FlushWrites()
+	db := s.dbFor(guildID)
+	return s.retryWrite("DeleteMessage", func() error {
+		if _, err := db.Exec(`DELETE FROM messages WHERE guild_id=? AND message_id=?`, guildID, messageID); err != nil {
+			return err
+		}
+		return s.deleteMessageMedia(db, guildID, messageID)
+	})
 }
 
-// CleanupExpiredMessages deletes all expired messages.
+// CleanupExpiredMessages deletes all expired messages, archiving them first when
+// Options.MessageArchiveDir is set. The messages of a guild that fails to archive are kept (and
+// the failure returned) while the other guilds are archived and cleaned up as usual.
 func (s *Store) CleanupExpiredMessages() error {
 	if s.db == nil {
 		return fmt.Errorf("store not initialized")
 	}
-	_, err := s.db.Exec(`DELETE FROM messages WHERE expires_at IS NOT NULL AND expires_at <= CURRENT_TIMESTAMP`)
-	return err
+	if s.opts.MessageArchiveDir != "" {
+		// Same cutoff for archive and delete, so nothing expiring in between is deleted unarchived
+		cutoff := time.Now().UTC()
+		_, failed, archiveErr := s.archiveExpiredMessages(cutoff)
+		if archiveErr != nil && len(failed) == 0 {
+			return fmt.Errorf("archive expired messages: %w", archiveErr)
+		}
+		// Guilds that failed to archive keep their expired messages until a later cleanup
+		query := `DELETE FROM messages WHERE expires_at IS NOT NULL AND expires_at <= ?`
+		args := []any{cutoff}
+		if len(failed) > 0 {
+			query += ` AND guild_id NOT IN (?` + strings.Repeat(`, ?`, len(failed)-1) + `)`
+			for _, id := range failed {
+				args = append(args, id)
+			}
+		}
+		if _, err := s.execAllShards(query, args...); err != nil {
+			return err
+		}
+		if err := s.cleanupOrphanMessageMedia(); err != nil {
+			return err
+		}
+		if archiveErr != nil {
+			return fmt.Errorf("archive expired messages: %w", archiveErr)
+		}
+		return nil
+	}
+	if _, err := s.execAllShards(`DELETE FROM messages WHERE expires_at IS NOT NULL AND expires_at <= CURRENT_TIMESTAMP`); err != nil {
+		return err
+	}
+	return s.cleanupOrphanMessageMedia()
 }
 
 // CleanupObsoleteMemberJoins removes member join records for users who left guilds (older than retentionDays)
@@ -183,11 +505,7 @@ func (s *Store) CleanupObsoleteMemberJoins(retentionDays int) (int64, error) {
 		retentionDays = 90 // default: keep 90 days of history
 	}
 	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
-	result, err := s.db.Exec(`DELETE FROM member_joins WHERE joined_at < ?`, cutoff)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
+	return s.execAllShards(`DELETE FROM member_joins WHERE joined_at < ?`, cutoff)
 }
 
 // CleanupObsoleteMemberRoles removes role records older than retentionDays
@@ -199,11 +517,7 @@ func (s *Store) CleanupObsoleteMemberRoles(retentionDays int) (int64, error) {
 		retentionDays = 30 // default: keep 30 days of role history
 	}
 	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
-	result, err := s.db.Exec(`DELETE FROM roles_current WHERE updated_at < ?`, cutoff)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
+	return s.execAllShards(`DELETE FROM roles_current WHERE updated_at < ?`, cutoff)
 }
 
 // CleanupObsoleteAvatars removes avatar records older than retentionDays
@@ -215,11 +529,7 @@ func (s *Store) CleanupObsoleteAvatars(retentionDays int) (int64, error) {
 		retentionDays = 180 // default: keep 6 months of avatar history
 	}
 	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
-	result, err := s.db.Exec(`DELETE FROM avatars_history WHERE changed_at < ?`, cutoff)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
+	return s.execAllShards(`DELETE FROM avatars_history WHERE changed_at < ?`, cutoff)
 }
 
 // CleanupAllObsoleteData performs cleanup of all obsolete data with default retention periods
@@ -228,10 +538,9 @@ func (s *Store) CleanupAllObsoleteData() error {
 		return fmt.Errorf("store not initialized")
 	}
 
-	// Cleanup expired messages
-	if err := s.CleanupExpiredMessages(); err != nil {
-		return fmt.Errorf("cleanup messages: %w", err)
-	}
+	// Cleanup expired messages. A guild that failed to archive is reported at the end, after
+	// the other cleanups ran.
+	messagesErr := s.CleanupExpiredMessages()
 
 	// Cleanup expired cache entries
 	if err := s.CleanupExpiredCacheEntries(); err != nil {
@@ -243,6 +552,11 @@ func (s *Store) CleanupAllObsoleteData() error {
 		return fmt.Errorf("cleanup member joins: %w", err)
 	}
 
+	// Cleanup obsolete join/leave events (90 days)
+	if _, err := s.CleanupObsoleteMembershipEvents(90); err != nil {
+		return fmt.Errorf("cleanup membership events: %w", err)
+	}
+
 	// Cleanup obsolete member roles (30 days)
 	if _, err := s.CleanupObsoleteMemberRoles(30); err != nil {
 		return fmt.Errorf("cleanup member roles: %w", err)
@@ -253,6 +567,19 @@ func (s *Store) CleanupAllObsoleteData() error {
 		return fmt.Errorf("cleanup avatars: %w", err)
 	}
 
+	// Cleanup obsolete reaction events (30 days)
+	if _, err := s.CleanupObsoleteReactions(30); err != nil {
+		return fmt.Errorf("cleanup reactions: %w", err)
+	}
+
+	// Cleanup obsolete voice sessions (90 days)
+	if _, err := s.CleanupObsoleteVoiceSessions(90); err != nil {
+		return fmt.Errorf("cleanup voice sessions: %w", err)
+	}
+
+	if messagesErr != nil {
+		return fmt.Errorf("cleanup messages: %w", messagesErr)
+	}
 	return nil
 }
 
@@ -264,7 +591,7 @@ func (s *Store) UpsertMemberJoin(guildID, userID string, joinedAt time.Time) err
 	if guildID == "" || userID == "" || joinedAt.IsZero() {
 		return nil
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWrite(s.dbFor(guildID), "UpsertMemberJoin",
 		`INSERT INTO member_joins (guild_id, user_id, joined_at)
          VALUES (?, ?, ?)
          ON CONFLICT(guild_id, user_id) DO UPDATE SET
@@ -282,7 +609,7 @@ func (s *Store) GetMemberJoin(guildID, userID string) (time.Time, bool, error) {
 	if s.db == nil {
 		return time.Time{}, false, fmt.Errorf("store not initialized")
 	}
-	row := s.db.QueryRow(`SELECT joined_at FROM member_joins WHERE guild_id=? AND user_id=?`, guildID, userID)
+	row := s.dbFor(guildID).QueryRow(`SELECT joined_at FROM member_joins WHERE guild_id=? AND user_id=?`, guildID, userID)
 	var jt time.Time
 	if err := row.Scan(&jt); err != nil {
 		if err == sql.ErrNoRows {
@@ -293,10 +620,57 @@ func (s *Store) GetMemberJoin(guildID, userID string) (time.Time, bool, error) {
 	return jt, true, nil
 }
 
+// RecordAutoroleAssignment stores the roles autorole gave a member (comma-separated in
+// member_joins.autorole_ids). Without a join row yet, at is also used as the join time.
+func (s *Store) RecordAutoroleAssignment(guildID, userID string, roleIDs []string, at time.Time) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || userID == "" || len(roleIDs) == 0 {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	_, err := s.execWrite(s.dbFor(guildID), "RecordAutoroleAssignment",
+		`INSERT INTO member_joins (guild_id, user_id, joined_at, autorole_ids, autorole_at)
+         VALUES (?, ?, ?, ?, ?)
+         ON CONFLICT(guild_id, user_id) DO UPDATE SET
+           autorole_ids=excluded.autorole_ids,
+           autorole_at=excluded.autorole_at`,
+		guildID, userID, at.UTC(), strings.Join(roleIDs, ","), at.UTC(),
+	)
+	return err
+}
+
+// GetAutoroleAssignment returns the roles autorole gave a member and when (nil roles if none).
+func (s *Store) GetAutoroleAssignment(guildID, userID string) ([]string, time.Time, error) {
+	if s.db == nil {
+		return nil, time.Time{}, fmt.Errorf("store not initialized")
+	}
+	var ids string
+	var at sql.NullTime
+	err := s.dbFor(guildID).QueryRow(
+		`SELECT autorole_ids, autorole_at FROM member_joins WHERE guild_id=? AND user_id=?`, guildID, userID,
+	).Scan(&ids, &at)
+	if err == sql.ErrNoRows || (err == nil && ids == "") {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return strings.Split(ids, ","), at.Time, nil
+}
+
 // UpsertAvatar sets the current avatar hash for a member in a guild.
 // If the hash changed, it records a row in avatars_history.
 // Returns (changed, oldHash, err).
 func (s *Store) UpsertAvatar(guildID, userID, newHash string, updatedAt time.Time) (bool, string, error) {
+	return s.UpsertAvatarContext(context.Background(), guildID, userID, newHash, updatedAt)
+}
+
+// UpsertAvatarContext is UpsertAvatar with a context that cancels the transaction.
+func (s *Store) UpsertAvatarContext(ctx context.Context, guildID, userID, newHash string, updatedAt time.Time) (bool, string, error) {
 	if s.db == nil {
 		return false, "", fmt.Errorf("store not initialized")
 	}
@@ -307,7 +681,18 @@ func (s *Store) UpsertAvatar(guildID, userID, newHash string, updatedAt time.Tim
 		updatedAt = time.Now().UTC()
 	}
 
-	tx, err := s.db.Begin()
+	var changed bool
+	var oldHash string
+	err := s.retryWrite("UpsertAvatar", func() error {
+		var err error
+		changed, oldHash, err = s.upsertAvatarTx(ctx, s.dbFor(guildID), guildID, userID, newHash, updatedAt)
+		return err
+	})
+	return changed, oldHash, err
+}
+
+func (s *Store) upsertAvatarTx(ctx context.Context, db *sql.DB, guildID, userID, newHash string, updatedAt time.Time) (bool, string, error) {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return false, "", err
 	}
@@ -318,7 +703,7 @@ func (s *Store) UpsertAvatar(guildID, userID, newHash string, updatedAt time.Tim
 	// Read current
 	var curHash string
 	var hasCur bool
-	if err := tx.QueryRow(
+	if err := tx.QueryRowContext(ctx,
 		`SELECT avatar_hash FROM avatars_current WHERE guild_id=? AND user_id=?`,
 		guildID, userID,
 	).Scan(&curHash); err != nil {
@@ -332,7 +717,7 @@ func (s *Store) UpsertAvatar(guildID, userID, newHash string, updatedAt time.Tim
 	changed := !hasCur || curHash != newHash
 	if changed && hasCur {
 		// Record history
-		if _, err := tx.Exec(
+		if _, err := tx.ExecContext(ctx,
 			`INSERT INTO avatars_history (guild_id, user_id, old_hash, new_hash, changed_at)
              VALUES (?, ?, ?, ?, ?)`,
 			guildID, userID, curHash, newHash, updatedAt,
@@ -342,7 +727,7 @@ func (s *Store) UpsertAvatar(guildID, userID, newHash string, updatedAt time.Tim
 	}
 
 	// Upsert current
-	if _, err := tx.Exec(
+	if _, err := tx.ExecContext(ctx,
 		`INSERT INTO avatars_current (guild_id, user_id, avatar_hash, updated_at)
          VALUES (?, ?, ?, ?)
          ON CONFLICT(guild_id, user_id) DO UPDATE SET
@@ -361,10 +746,15 @@ func (s *Store) UpsertAvatar(guildID, userID, newHash string, updatedAt time.Tim
 
 // GetAvatar returns the current avatar hash for a user in a guild, if any.
 func (s *Store) GetAvatar(guildID, userID string) (hash string, updatedAt time.Time, ok bool, err error) {
+	return s.GetAvatarContext(context.Background(), guildID, userID)
+}
+
+// GetAvatarContext is GetAvatar with a context that cancels the query.
+func (s *Store) GetAvatarContext(ctx context.Context, guildID, userID string) (hash string, updatedAt time.Time, ok bool, err error) {
 	if s.db == nil {
 		return "", time.Time{}, false, fmt.Errorf("store not initialized")
 	}
-	row := s.db.QueryRow(
+	row := s.dbFor(guildID).QueryRowContext(ctx,
 		`SELECT avatar_hash, updated_at FROM avatars_current WHERE guild_id=? AND user_id=?`,
 		guildID, userID,
 	)
@@ -390,7 +780,7 @@ func (s *Store) SetBotSince(guildID string, t time.Time) error {
 	if t.IsZero() {
 		t = time.Now().UTC()
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWrite(s.dbFor(guildID), "SetBotSince",
 		`INSERT INTO guild_meta (guild_id, bot_since)
          VALUES (?, ?)
          ON CONFLICT(guild_id) DO UPDATE SET
@@ -408,7 +798,7 @@ func (s *Store) GetBotSince(guildID string) (time.Time, bool, error) {
 	if s.db == nil {
 		return time.Time{}, false, fmt.Errorf("store not initialized")
 	}
-	row := s.db.QueryRow(`SELECT bot_since FROM guild_meta WHERE guild_id=?`, guildID)
+	row := s.dbFor(guildID).QueryRow(`SELECT bot_since FROM guild_meta WHERE guild_id=?`, guildID)
 	var t sql.NullTime
 	if err := row.Scan(&t); err != nil {
 		if err == sql.ErrNoRows {
@@ -424,13 +814,18 @@ func (s *Store) GetBotSince(guildID string) (time.Time, bool, error) {
 
 // SetHeartbeat records the last-known "bot is running" timestamp.
 func (s *Store) SetHeartbeat(t time.Time) error {
+	return s.SetHeartbeatContext(context.Background(), t)
+}
+
+// SetHeartbeatContext is SetHeartbeat with a context that cancels the write.
+func (s *Store) SetHeartbeatContext(ctx context.Context, t time.Time) error {
 	if s.db == nil {
 		return fmt.Errorf("store not initialized")
 	}
 	if t.IsZero() {
 		t = time.Now().UTC()
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWriteContext(ctx, s.db, "SetHeartbeat",
 		`INSERT INTO runtime_meta (key, ts) VALUES (?, ?)
          ON CONFLICT(key) DO UPDATE SET ts=excluded.ts`,
 		"heartbeat", t.UTC(),
@@ -440,10 +835,15 @@ func (s *Store) SetHeartbeat(t time.Time) error {
 
 // GetHeartbeat returns the last recorded heartbeat timestamp, if any.
 func (s *Store) GetHeartbeat() (time.Time, bool, error) {
+	return s.GetHeartbeatContext(context.Background())
+}
+
+// GetHeartbeatContext is GetHeartbeat with a context that cancels the query.
+func (s *Store) GetHeartbeatContext(ctx context.Context) (time.Time, bool, error) {
 	if s.db == nil {
 		return time.Time{}, false, fmt.Errorf("store not initialized")
 	}
-	row := s.db.QueryRow(`SELECT ts FROM runtime_meta WHERE key=?`, "heartbeat")
+	row := s.db.QueryRowContext(ctx, `SELECT ts FROM runtime_meta WHERE key=?`, "heartbeat")
 	var ts time.Time
 	if err := row.Scan(&ts); err != nil {
 		if err == sql.ErrNoRows {
@@ -462,7 +862,7 @@ func (s *Store) SetLastEvent(t time.Time) error {
 	if t.IsZero() {
 		t = time.Now().UTC()
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWrite(s.db, "SetLastEvent",
 		`INSERT INTO runtime_meta (key, ts) VALUES (?, ?)
          ON CONFLICT(key) DO UPDATE SET ts=excluded.ts`,
 		"last_event", t.UTC(),
@@ -494,7 +894,7 @@ func (s *Store) SetGuildOwnerID(guildID, ownerID string) error {
 	if guildID == "" || ownerID == "" {
 		return nil
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWrite(s.dbFor(guildID), "SetGuildOwnerID",
 		`INSERT INTO guild_meta (guild_id, owner_id)
          VALUES (?, ?)
          ON CONFLICT(guild_id) DO UPDATE SET
@@ -509,7 +909,7 @@ func (s *Store) GetGuildOwnerID(guildID string) (string, bool, error) {
 	if s.db == nil {
 		return "", false, fmt.Errorf("store not initialized")
 	}
-	row := s.db.QueryRow(`SELECT owner_id FROM guild_meta WHERE guild_id=?`, guildID)
+	row := s.dbFor(guildID).QueryRow(`SELECT owner_id FROM guild_meta WHERE guild_id=?`, guildID)
 	var owner sql.NullString
 	if err := row.Scan(&owner); err != nil {
 		if err == sql.ErrNoRows {
@@ -535,7 +935,13 @@ func (s *Store) UpsertMemberRoles(guildID, userID string, roles []string, update
 		updatedAt = time.Now().UTC()
 	}
 
-	tx, err := s.db.Begin()
+	return s.retryWrite("UpsertMemberRoles", func() error {
+		return upsertMemberRolesTx(s.dbFor(guildID), guildID, userID, roles, updatedAt)
+	})
+}
+
+func upsertMemberRolesTx(db *sql.DB, guildID, userID string, roles []string, updatedAt time.Time) error {
+	tx, err := db.Begin()
 	if err != nil {
 		return err
 	}
@@ -565,7 +971,7 @@ func (s *Store) GetMemberRoles(guildID, userID string) ([]string, error) {
 	if s.db == nil {
 		return nil, fmt.Errorf("store not initialized")
 	}
-	rows, err := s.db.Query(`SELECT role_id FROM roles_current WHERE guild_id=? AND user_id=?`, guildID, userID)
+	rows, err := s.dbFor(guildID).Query(`SELECT role_id FROM roles_current WHERE guild_id=? AND user_id=?`, guildID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -616,7 +1022,7 @@ func (s *Store) DiffMemberRoles(guildID, userID string, current []string) (added
 }
 
 // ensureSchema creates required tables and indexes if they don't exist.
-func ensureSchema(db *sql.DB) error {
+func ensureSchema(db *sql.DB, ns *namespaceRewriter) error {
 	const createMessages = `
 CREATE TABLE IF NOT EXISTS messages (
   guild_id        TEXT NOT NULL,
@@ -628,18 +1034,36 @@ CREATE TABLE IF NOT EXISTS messages (
   content         TEXT,
   cached_at       TIMESTAMP NOT NULL,
   expires_at      TIMESTAMP,
+  content_truncated INTEGER NOT NULL DEFAULT 0,
+  original_length   INTEGER NOT NULL DEFAULT 0,
+  content_omitted   INTEGER NOT NULL DEFAULT 0,
+  attachment_count  INTEGER NOT NULL DEFAULT 0,
   PRIMARY KEY (guild_id, message_id)
 );
-CREATE INDEX IF NOT EXISTS idx_messages_expires ON messages(expires_at);`
+CREATE INDEX IF NOT EXISTS idx_messages_expires ON messages(expires_at);
+CREATE INDEX IF NOT EXISTS idx_messages_guild_cached ON messages(guild_id, cached_at);`
 
 	const createMemberJoins = `
 CREATE TABLE IF NOT EXISTS member_joins (
   guild_id   TEXT NOT NULL,
   user_id    TEXT NOT NULL,
   joined_at  TIMESTAMP NOT NULL,
+  autorole_ids TEXT NOT NULL DEFAULT '',
+  autorole_at  TIMESTAMP,
   PRIMARY KEY (guild_id, user_id)
 );`
 
+	const createMembershipEvents = `
+CREATE TABLE IF NOT EXISTS membership_events (
+  id       INTEGER PRIMARY KEY AUTOINCREMENT,
+  guild_id TEXT NOT NULL,
+  user_id  TEXT NOT NULL,
+  event    TEXT NOT NULL,
+  at       TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_membership_events_gid_uid ON membership_events(guild_id, user_id, at);
+CREATE INDEX IF NOT EXISTS idx_membership_events_at ON membership_events(at);`
+
 	const createAvatarsCurrent = `
 CREATE TABLE IF NOT EXISTS avatars_current (
   guild_id    TEXT NOT NULL,
@@ -695,24 +1119,220 @@ CREATE TABLE IF NOT EXISTS persistent_cache (
 CREATE INDEX IF NOT EXISTS idx_persistent_cache_type ON persistent_cache(cache_type);
 CREATE INDEX IF NOT EXISTS idx_persistent_cache_expires ON persistent_cache(expires_at);`
 
+	const createAutomodActions = `
+CREATE TABLE IF NOT EXISTS automod_actions (
+  id         INTEGER PRIMARY KEY AUTOINCREMENT,
+  guild_id   TEXT NOT NULL,
+  user_id    TEXT NOT NULL DEFAULT '',
+  channel_id TEXT NOT NULL DEFAULT '',
+  rule_id    TEXT NOT NULL DEFAULT '',
+  matched    TEXT NOT NULL DEFAULT '',
+  action     TEXT NOT NULL,
+  content    TEXT NOT NULL DEFAULT '',
+  simulated  INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_automod_actions_gid_created ON automod_actions(guild_id, created_at);`
+
+	const createReactions = `
+CREATE TABLE IF NOT EXISTS reactions (
+  id         INTEGER PRIMARY KEY AUTOINCREMENT,
+  guild_id   TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  message_id TEXT NOT NULL,
+  user_id    TEXT NOT NULL,
+  emoji      TEXT NOT NULL,
+  action     TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_reactions_gid_mid ON reactions(guild_id, message_id);
+CREATE INDEX IF NOT EXISTS idx_reactions_created ON reactions(created_at);`
+
+	const createAutomodFeedback = `
+CREATE TABLE IF NOT EXISTS automod_feedback (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT,
+  guild_id     TEXT NOT NULL,
+  action_id    INTEGER NOT NULL,
+  rule_id      TEXT NOT NULL DEFAULT '',
+  moderator_id TEXT NOT NULL,
+  verdict      TEXT NOT NULL,
+  created_at   TIMESTAMP NOT NULL,
+  UNIQUE (guild_id, action_id)
+);
+CREATE INDEX IF NOT EXISTS idx_automod_feedback_gid_rule ON automod_feedback(guild_id, rule_id);`
+
+	const createVoiceSessions = `
+CREATE TABLE IF NOT EXISTS voice_sessions (
+  id               INTEGER PRIMARY KEY AUTOINCREMENT,
+  guild_id         TEXT NOT NULL,
+  user_id          TEXT NOT NULL,
+  channel_id       TEXT NOT NULL,
+  joined_at        TIMESTAMP NOT NULL,
+  left_at          TIMESTAMP,
+  duration_seconds INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_voice_sessions_gid_uid ON voice_sessions(guild_id, user_id, joined_at);
+CREATE INDEX IF NOT EXISTS idx_voice_sessions_open ON voice_sessions(left_at) WHERE left_at IS NULL;`
+
+	const createEmojiUsage = `
+CREATE TABLE IF NOT EXISTS emoji_usage (
+  guild_id TEXT NOT NULL,
+  emoji_id TEXT NOT NULL,
+  name     TEXT NOT NULL DEFAULT '',
+  kind     TEXT NOT NULL,
+  animated INTEGER NOT NULL DEFAULT 0,
+  day      TEXT NOT NULL,
+  count    INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (guild_id, emoji_id, day)
+);
+CREATE INDEX IF NOT EXISTS idx_emoji_usage_gid_day ON emoji_usage(guild_id, day);`
+
+	const createCommandUsage = `
+CREATE TABLE IF NOT EXISTS command_usage (
+  guild_id TEXT NOT NULL,
+  command  TEXT NOT NULL,
+  day      TEXT NOT NULL,
+  count    INTEGER NOT NULL DEFAULT 0,
+  errors   INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (guild_id, command, day)
+);
+CREATE INDEX IF NOT EXISTS idx_command_usage_gid_day ON command_usage(guild_id, day);`
+
+	const createAutomodUserState = `
+CREATE TABLE IF NOT EXISTS automod_user_state (
+  guild_id       TEXT NOT NULL,
+  user_id        TEXT NOT NULL,
+  last_action_at TIMESTAMP NOT NULL,
+  last_rule      TEXT NOT NULL DEFAULT '',
+  last_action    TEXT NOT NULL DEFAULT '',
+  escalation_level INTEGER NOT NULL DEFAULT 0,
+  last_offense_at  TIMESTAMP,
+  PRIMARY KEY (guild_id, user_id)
+);`
+
+	const createScheduledTasks = `
+CREATE TABLE IF NOT EXISTS scheduled_tasks (
+  id               INTEGER PRIMARY KEY AUTOINCREMENT,
+  type             TEXT NOT NULL,
+  guild_id         TEXT NOT NULL DEFAULT '',
+  payload          TEXT NOT NULL DEFAULT 'null',
+  due_at           TIMESTAMP NOT NULL,
+  interval_seconds INTEGER NOT NULL DEFAULT 0,
+  status           TEXT NOT NULL,
+  attempts         INTEGER NOT NULL DEFAULT 0,
+  last_error       TEXT NOT NULL DEFAULT '',
+  created_at       TIMESTAMP NOT NULL,
+  updated_at       TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_scheduled_tasks_due ON scheduled_tasks(status, due_at);
+CREATE INDEX IF NOT EXISTS idx_scheduled_tasks_gid ON scheduled_tasks(guild_id);`
+
+	const createMessageMedia = `
+CREATE TABLE IF NOT EXISTS message_attachments (
+  guild_id      TEXT NOT NULL,
+  message_id    TEXT NOT NULL,
+  attachment_id TEXT NOT NULL,
+  filename      TEXT NOT NULL DEFAULT '',
+  url           TEXT NOT NULL DEFAULT '',
+  content_type  TEXT NOT NULL DEFAULT '',
+  size          INTEGER NOT NULL DEFAULT 0,
+  data          BLOB,
+  stored_at     TIMESTAMP NOT NULL,
+  PRIMARY KEY (guild_id, message_id, attachment_id)
+);
+CREATE TABLE IF NOT EXISTS message_embeds (
+  guild_id    TEXT NOT NULL,
+  message_id  TEXT NOT NULL,
+  position    INTEGER NOT NULL,
+  type        TEXT NOT NULL DEFAULT '',
+  title       TEXT NOT NULL DEFAULT '',
+  description TEXT NOT NULL DEFAULT '',
+  url         TEXT NOT NULL DEFAULT '',
+  stored_at   TIMESTAMP NOT NULL,
+  PRIMARY KEY (guild_id, message_id, position)
+);`
+
+	// Migrations applied by Store.Migrate (migrations.go)
+	const createSchemaVersion = `
+CREATE TABLE IF NOT EXISTS schema_version (
+  version    INTEGER PRIMARY KEY,
+  applied_at TIMESTAMP NOT NULL
+);`
+
+	// New tables must also be listed in namespacedTables (namespace.go)
 	stmts := []string{
 		createMessages,
 		createMemberJoins,
+		createMembershipEvents,
 		createAvatarsCurrent,
 		createAvatarsHistory,
 		createGuildMeta,
 		createRuntimeMeta,
 		createRolesCurrent,
 		createPersistentCache,
+		createAutomodActions,
+		createReactions,
+		createAutomodFeedback,
+		createVoiceSessions,
+		createEmojiUsage,
+		createCommandUsage,
+		createAutomodUserState,
+		createScheduledTasks,
+		createMessageMedia,
+		createSchemaVersion,
 	}
 	for _, sqlText := range stmts {
 		if _, err := db.Exec(sqlText); err != nil {
 			return fmt.Errorf("create schema: %w", err)
 		}
 	}
+
+	// Columns added after the initial release; CREATE TABLE IF NOT EXISTS doesn't add them to old databases.
+	addedColumns := []struct{ table, column, decl string }{
+		{"messages", "content_truncated", "INTEGER NOT NULL DEFAULT 0"},
+		{"messages", "original_length", "INTEGER NOT NULL DEFAULT 0"},
+		{"messages", "content_omitted", "INTEGER NOT NULL DEFAULT 0"},
+		{"messages", "attachment_count", "INTEGER NOT NULL DEFAULT 0"},
+		{"automod_actions", "content", "TEXT NOT NULL DEFAULT ''"},
+		{"automod_actions", "simulated", "INTEGER NOT NULL DEFAULT 0"},
+		{"automod_user_state", "escalation_level", "INTEGER NOT NULL DEFAULT 0"},
+		{"member_joins", "autorole_ids", "TEXT NOT NULL DEFAULT ''"},
+		{"member_joins", "autorole_at", "TIMESTAMP"},
+		{"automod_user_state", "last_offense_at", "TIMESTAMP"},
+		{"emoji_usage", "animated", "INTEGER NOT NULL DEFAULT 0"},
+	}
+	for _, c := range addedColumns {
+		if err := ensureColumn(db, ns.tableName(c.table), c.column, c.decl); err != nil {
+			return fmt.Errorf("add column %s.%s: %w", c.table, c.column, err)
+		}
+	}
 	return nil
 }
 
+// ensureColumn adds a column to an existing table when it is missing. table is the physical
+// (already namespaced) name, since pragma_table_info receives it as a bound value.
+func ensureColumn(db *sql.DB, table, column, decl string) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+	return err
+}
+
 // Persistent Cache Methods
 
 // UpsertCacheEntry saves a cache entry to persistent storage
@@ -723,7 +1343,7 @@ func (s *Store) UpsertCacheEntry(key, cacheType, data string, expiresAt time.Tim
 	if key == "" || cacheType == "" || data == "" {
 		return nil
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWrite(s.db, "UpsertCacheEntry",
 		`INSERT INTO persistent_cache (cache_key, cache_type, data, expires_at, cached_at)
          VALUES (?, ?, ?, ?, ?)
          ON CONFLICT(cache_key) DO UPDATE SET
@@ -801,7 +1421,7 @@ func (s *Store) DeleteCacheEntry(key string) error {
 	if s.db == nil {
 		return fmt.Errorf("store not initialized")
 	}
-	_, err := s.db.Exec(`DELETE FROM persistent_cache WHERE cache_key=?`, key)
+	_, err := s.execWrite(s.db, "DeleteCacheEntry", `DELETE FROM persistent_cache WHERE cache_key=?`, key)
 	return err
 }
 
@@ -810,7 +1430,7 @@ func (s *Store) CleanupExpiredCacheEntries() error {
 	if s.db == nil {
 		return fmt.Errorf("store not initialized")
 	}
-	_, err := s.db.Exec(`DELETE FROM persistent_cache WHERE expires_at <= ?`, time.Now().UTC())
+	_, err := s.execWrite(s.db, "CleanupExpiredCacheEntries", `DELETE FROM persistent_cache WHERE expires_at <= ?`, time.Now().UTC())
 	return err
 }
 
@@ -822,7 +1442,7 @@ func (s *Store) DeleteCacheEntriesByPrefix(prefix string) error {
 	if prefix == "" {
 		return nil
 	}
-	_, err := s.db.Exec(`DELETE FROM persistent_cache WHERE cache_key LIKE ?`, prefix+"%")
+	_, err := s.execWrite(s.db, "DeleteCacheEntriesByPrefix", `DELETE FROM persistent_cache WHERE cache_key LIKE ?`, prefix+"%")
 	return err
 }
 
@@ -834,7 +1454,7 @@ func (s *Store) DeleteCacheEntriesByTypeAndPrefix(cacheType, keyPrefix string) e
 	if cacheType == "" || keyPrefix == "" {
 		return nil
 	}
-	_, err := s.db.Exec(`DELETE FROM persistent_cache WHERE cache_type=? AND cache_key LIKE ?`, cacheType, keyPrefix+"%")
+	_, err := s.execWrite(s.db, "DeleteCacheEntriesByTypeAndPrefix", `DELETE FROM persistent_cache WHERE cache_type=? AND cache_key LIKE ?`, cacheType, keyPrefix+"%")
 	return err
 }
 
@@ -843,7 +1463,7 @@ func (s *Store) GetAllMemberJoins(guildID string) (map[string]time.Time, error)
 	if s.db == nil {
 		return nil, fmt.Errorf("store not initialized")
 	}
-	rows, err := s.db.Query(`SELECT user_id, joined_at FROM member_joins WHERE guild_id=?`, guildID)
+	rows, err := s.dbFor(guildID).Query(`SELECT user_id, joined_at FROM member_joins WHERE guild_id=?`, guildID)
 	if err != nil {
 		return nil, err
 	}
@@ -866,7 +1486,7 @@ func (s *Store) GetAllGuildMemberRoles(guildID string) (map[string][]string, err
 	if s.db == nil {
 		return nil, fmt.Errorf("store not initialized")
 	}
-	rows, err := s.db.Query(`SELECT user_id, role_id FROM roles_current WHERE guild_id=?`, guildID)
+	rows, err := s.dbFor(guildID).Query(`SELECT user_id, role_id FROM roles_current WHERE guild_id=?`, guildID)
 	if err != nil {
 		return nil, err
 	}
@@ -891,7 +1511,7 @@ func (s *Store) TouchMemberJoin(guildID, userID string) error {
 	if guildID == "" || userID == "" {
 		return nil
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWrite(s.dbFor(guildID), "TouchMemberJoin",
 		`UPDATE member_joins SET joined_at=? WHERE guild_id=? AND user_id=?`,
 		time.Now().UTC(), guildID, userID,
 	)
@@ -906,7 +1526,7 @@ func (s *Store) TouchMemberRoles(guildID, userID string) error {
 	if guildID == "" || userID == "" {
 		return nil
 	}
-	_, err := s.db.Exec(
+	_, err := s.execWrite(s.dbFor(guildID), "TouchMemberRoles",
 		`UPDATE roles_current SET updated_at=? WHERE guild_id=? AND user_id=?`,
 		time.Now().UTC(), guildID, userID,
 	)
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentWritesFromTwoConnections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.db")
+	// Retries off: any SQLITE_BUSY must surface, so only WAL and busy_timeout can prevent it.
+	opts := Options{WriteRetries: -1}
+	stores := make([]*Store, 2)
+	for i := range stores {
+		stores[i] = NewStoreWithOptions(path, opts)
+		if err := stores[i].Init(); err != nil {
+			t.Fatalf("init store %d: %v", i, err)
+		}
+		t.Cleanup(func() { stores[i].Close() })
+	}
+
+	var mode string
+	if err := stores[0].db.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil {
+		t.Fatalf("read journal mode: %v", err)
+	}
+	if !strings.EqualFold(mode, DefaultJournalMode) {
+		t.Fatalf("journal_mode = %q, want %s", mode, DefaultJournalMode)
+	}
+
+	// Batches of 100 avatars keep each write transaction open long enough to overlap.
+	const writers, writesEach, batch = 4, 5, 100
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*writesEach)
+	for w := range writers {
+		wg.Go(func() {
+			s := stores[w%len(stores)]
+			for i := range writesEach {
+				records := make([]AvatarUpsert, batch)
+				for j := range records {
+					records[j] = AvatarUpsert{UserID: "u" + strconv.Itoa(w) + "-" + strconv.Itoa(j), Hash: "h" + strconv.Itoa(i)}
+				}
+				if _, _, err := s.UpsertAvatarsBatch("g1", records); err != nil {
+					errs <- err
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent write failed: %v", err)
+	}
+
+	var n int
+	if err := stores[1].db.QueryRow(`SELECT COUNT(*) FROM avatars_history WHERE guild_id='g1'`).Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	// The first batch of each writer inserts; every later one changes all hashes.
+	if want := writers * (writesEach - 1) * batch; n != want {
+		t.Errorf("stored %d history rows, want %d", n, want)
+	}
+}
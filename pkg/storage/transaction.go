@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCrossShardTx is returned by StoreTx methods called for a guild whose rows live in another
+// database than the one the transaction is bound to (sharded stores only).
+var ErrCrossShardTx = errors.New("transaction spans more than one guild database")
+
+// sqlExecutor is the subset of *sql.DB and *sql.Tx used by statements shared between the Store
+// methods and StoreTx.
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// StoreTx exposes the store writes that can be grouped atomically with WithTx. The methods have
+// the same semantics as their Store counterparts.
+type StoreTx interface {
+	RecordAutomodAction(a AutomodAction) (int64, error)
+	MarkAutomodAction(guildID, userID, rule, action string, at time.Time) error
+	RecordAutomodOffense(guildID, userID string, at time.Time, decay func(level int, lastOffense time.Time) int) (int, error)
+	GetAutomodUserState(guildID, userID string) (*AutomodUserState, error)
+}
+
+// WithTx runs fn inside a transaction: it is committed when fn returns nil and rolled back when
+// fn returns an error (or panics). The transaction is opened on the first call and bound to the
+// database of that guild; in a sharded store, calls for a guild in another database fail with
+// ErrCrossShardTx. On SQLite lock contention the whole transaction is retried, so fn may run
+// more than once and should only perform store writes.
+func (s *Store) WithTx(ctx context.Context, fn func(tx StoreTx) error) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	return s.retryWrite("WithTx", func() error {
+		tx := &storeTx{store: s, ctx: ctx}
+		return tx.run(fn)
+	})
+}
+
+// storeTx implements StoreTx; the underlying *sql.Tx is opened lazily by the first call.
+type storeTx struct {
+	store *Store
+	ctx   context.Context
+	db    *sql.DB
+	tx    *sql.Tx
+}
+
+func (t *storeTx) run(fn func(tx StoreTx) error) (err error) {
+	defer func() {
+		if t.tx == nil {
+			return
+		}
+		if p := recover(); p != nil {
+			_ = t.tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = t.tx.Rollback()
+		}
+	}()
+	if err = fn(t); err != nil {
+		return err
+	}
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+// forGuild returns the transaction for guildID, opening it on the first call.
+func (t *storeTx) forGuild(guildID string) (*sql.Tx, error) {
+	db := t.store.dbFor(guildID)
+	if t.tx == nil {
+		tx, err := db.BeginTx(t.ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		t.db, t.tx = db, tx
+		return tx, nil
+	}
+	if db != t.db {
+		return nil, fmt.Errorf("%w: guild %s", ErrCrossShardTx, guildID)
+	}
+	return t.tx, nil
+}
+
+func (t *storeTx) RecordAutomodAction(a AutomodAction) (int64, error) {
+	if a.GuildID == "" {
+		return 0, nil
+	}
+	tx, err := t.forGuild(a.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return t.store.insertAutomodAction(t.ctx, tx, a)
+}
+
+func (t *storeTx) MarkAutomodAction(guildID, userID, rule, action string, at time.Time) error {
+	if guildID == "" || userID == "" {
+		return nil
+	}
+	tx, err := t.forGuild(guildID)
+	if err != nil {
+		return err
+	}
+	return markAutomodAction(t.ctx, tx, guildID, userID, rule, action, at)
+}
+
+func (t *storeTx) RecordAutomodOffense(guildID, userID string, at time.Time, decay func(level int, lastOffense time.Time) int) (int, error) {
+	if guildID == "" || userID == "" {
+		return 0, nil
+	}
+	tx, err := t.forGuild(guildID)
+	if err != nil {
+		return 0, err
+	}
+	return recordAutomodOffense(t.ctx, tx, guildID, userID, at, decay)
+}
+
+func (t *storeTx) GetAutomodUserState(guildID, userID string) (*AutomodUserState, error) {
+	tx, err := t.forGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return getAutomodUserState(t.ctx, tx, guildID, userID)
+}
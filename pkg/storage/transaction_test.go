@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func countAutomodActions(t *testing.T, s *Store, guildID string) int {
+	t.Helper()
+	actions, err := s.GetAutomodActions(guildID, time.Time{})
+	if err != nil {
+		t.Fatalf("list automod actions: %v", err)
+	}
+	return len(actions)
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	s := newTestStore(t, Options{})
+	boom := errors.New("boom")
+	now := time.Now()
+
+	err := s.WithTx(context.Background(), func(tx StoreTx) error {
+		if _, err := tx.RecordAutomodAction(AutomodAction{GuildID: "g1", UserID: "u1", Action: "timeout"}); err != nil {
+			return err
+		}
+		if err := tx.MarkAutomodAction("g1", "u1", "r1", "timeout", now); err != nil {
+			return err
+		}
+		if _, err := tx.RecordAutomodOffense("g1", "u1", now, nil); err != nil {
+			return err
+		}
+		// Reads inside the transaction see its uncommitted writes.
+		st, err := tx.GetAutomodUserState("g1", "u1")
+		if err != nil {
+			return err
+		}
+		if st == nil || st.LastAction != "timeout" {
+			t.Errorf("state inside tx = %+v, want last action timeout", st)
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("WithTx error = %v, want boom", err)
+	}
+
+	if n := countAutomodActions(t, s, "g1"); n != 0 {
+		t.Errorf("%d automod actions persisted after rollback, want 0", n)
+	}
+	st, err := s.GetAutomodUserState("g1", "u1")
+	if err != nil {
+		t.Fatalf("get user state: %v", err)
+	}
+	if st != nil {
+		t.Errorf("user state persisted after rollback: %+v", st)
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	s := newTestStore(t, Options{})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("WithTx swallowed the panic")
+			}
+		}()
+		_ = s.WithTx(context.Background(), func(tx StoreTx) error {
+			if _, err := tx.RecordAutomodAction(AutomodAction{GuildID: "g1", UserID: "u1", Action: "timeout"}); err != nil {
+				return err
+			}
+			panic("mid-transaction failure")
+		})
+	}()
+	if n := countAutomodActions(t, s, "g1"); n != 0 {
+		t.Errorf("%d automod actions persisted after panic, want 0", n)
+	}
+	// The connection was released: later writes are not stuck behind the lock.
+	if _, err := s.RecordAutomodAction(AutomodAction{GuildID: "g1", UserID: "u1", Action: "warn"}); err != nil {
+		t.Fatalf("write after panic: %v", err)
+	}
+}
+
+func TestWithTxCommits(t *testing.T) {
+	s := newTestStore(t, Options{})
+	now := time.Now()
+	err := s.WithTx(context.Background(), func(tx StoreTx) error {
+		if _, err := tx.RecordAutomodAction(AutomodAction{GuildID: "g1", UserID: "u1", Action: "timeout"}); err != nil {
+			return err
+		}
+		return tx.MarkAutomodAction("g1", "u1", "r1", "timeout", now)
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if n := countAutomodActions(t, s, "g1"); n != 1 {
+		t.Errorf("%d automod actions after commit, want 1", n)
+	}
+	st, err := s.GetAutomodUserState("g1", "u1")
+	if err != nil || st == nil || st.LastRule != "r1" {
+		t.Errorf("user state after commit = %+v, %v; want rule r1", st, err)
+	}
+}
+
+func TestWithTxRejectsCrossShardWrites(t *testing.T) {
+	s := newTestStore(t, Options{ShardCount: 4})
+	other := ""
+	for i := range 100 {
+		if g := "g" + strconv.Itoa(i); s.dbFor(g) != s.dbFor("g0") {
+			other = g
+			break
+		}
+	}
+	if other == "" {
+		t.Fatal("no guild found on another shard")
+	}
+
+	err := s.WithTx(context.Background(), func(tx StoreTx) error {
+		if _, err := tx.RecordAutomodAction(AutomodAction{GuildID: "g0", UserID: "u1", Action: "timeout"}); err != nil {
+			return err
+		}
+		_, err := tx.RecordAutomodAction(AutomodAction{GuildID: other, UserID: "u1", Action: "timeout"})
+		return err
+	})
+	if !errors.Is(err, ErrCrossShardTx) {
+		t.Fatalf("WithTx error = %v, want ErrCrossShardTx", err)
+	}
+	if n := countAutomodActions(t, s, "g0"); n != 0 {
+		t.Errorf("%d automod actions persisted on the first shard, want 0", n)
+	}
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// VoiceSession is a persisted stay of a user in a voice channel.
+// LeftAt is zero and Duration is 0 while the session is still open.
+type VoiceSession struct {
+	ID        int64
+	GuildID   string
+	UserID    string
+	ChannelID string
+	JoinedAt  time.Time
+	LeftAt    time.Time
+	Duration  time.Duration
+}
+
+// Open reports whether the session has not been closed yet.
+func (v VoiceSession) Open() bool { return v.LeftAt.IsZero() }
+
+// StartVoiceSession opens a voice session for a user in a channel.
+// Callers moving a user between channels should EndVoiceSession first.
+func (s *Store) StartVoiceSession(guildID, userID, channelID string, joinedAt time.Time) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || userID == "" || channelID == "" {
+		return nil
+	}
+	_, err := s.execWrite(s.dbFor(guildID), "StartVoiceSession",
+		`INSERT INTO voice_sessions (guild_id, user_id, channel_id, joined_at) VALUES (?, ?, ?, ?)`,
+		guildID, userID, channelID, joinedAt.UTC(),
+	)
+	return err
+}
+
+// EndVoiceSession closes the user's open voice sessions in a guild, computing their duration.
+// Returns the number of sessions closed.
+func (s *Store) EndVoiceSession(guildID, userID string, leftAt time.Time) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if guildID == "" || userID == "" {
+		return 0, nil
+	}
+	return s.closeOpenVoiceSessions(s.dbFor(guildID), `guild_id=? AND user_id=?`, []any{guildID, userID}, leftAt)
+}
+
+// CloseOpenVoiceSessions closes every open voice session across all guilds at the given time.
+// Used on shutdown and on startup to settle sessions left open by an unclean stop.
+func (s *Store) CloseOpenVoiceSessions(at time.Time) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	var total int64
+	for _, db := range s.guildDBs() {
+		n, err := s.closeOpenVoiceSessions(db, `1=1`, nil, at)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// GetVoiceSessions returns the voice sessions of a user in a guild that started since the given time, oldest first.
+func (s *Store) GetVoiceSessions(guildID, userID string, since time.Time) ([]VoiceSession, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+	return s.listVoiceSessions(guildID, userID, since, nil, 0)
+}
+
+// VoiceSessionsPage is the paginated variant of GetVoiceSessions (oldest first).
+func (s *Store) VoiceSessionsPage(guildID, userID string, since time.Time, page PageRequest) (Page[VoiceSession], error) {
+	if s.db == nil {
+		return Page[VoiceSession]{}, fmt.Errorf("store not initialized")
+	}
+	cursor, err := decodePageCursor(page, true)
+	if err != nil {
+		return Page[VoiceSession]{}, err
+	}
+	limit := page.limit()
+	items, err := s.listVoiceSessions(guildID, userID, since, cursor, limit+1)
+	if err != nil {
+		return Page[VoiceSession]{}, err
+	}
+	return newPage(items, limit, func(v VoiceSession) Cursor { return intCursor(v.JoinedAt, v.ID) }), nil
+}
+
+// listVoiceSessions lists sessions after cursor (nil: from the oldest); limit <= 0 means no limit.
+func (s *Store) listVoiceSessions(guildID, userID string, since time.Time, cursor *keysetCursor, limit int) ([]VoiceSession, error) {
+	query := `SELECT id, guild_id, user_id, channel_id, joined_at, left_at, duration_seconds
+         FROM voice_sessions
+         WHERE guild_id=? AND user_id=? AND joined_at >= ?`
+	args := []any{guildID, userID, since.UTC()}
+	clause, cursorArgs := cursor.after("joined_at", "id", false)
+	query += clause + ` ORDER BY joined_at ASC, id ASC`
+	args = append(args, cursorArgs...)
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.dbFor(guildID).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []VoiceSession
+	for rows.Next() {
+		var v VoiceSession
+		var leftAt sql.NullTime
+		var seconds int64
+		if err := rows.Scan(&v.ID, &v.GuildID, &v.UserID, &v.ChannelID, &v.JoinedAt, &leftAt, &seconds); err != nil {
+			return nil, err
+		}
+		if leftAt.Valid {
+			v.LeftAt = leftAt.Time
+		}
+		v.Duration = time.Duration(seconds) * time.Second
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// CleanupObsoleteVoiceSessions removes closed voice sessions older than retentionDays
+func (s *Store) CleanupObsoleteVoiceSessions(retentionDays int) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("store not initialized")
+	}
+	if retentionDays <= 0 {
+		retentionDays = 90 // default: keep 3 months of voice activity
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays)
+	return s.execAllShards(`DELETE FROM voice_sessions WHERE left_at IS NOT NULL AND joined_at < ?`, cutoff)
+}
+
+// closeOpenVoiceSessions closes the open sessions matching where. The duration is computed
+// in Go because timestamps are stored in the driver's text format, not as epoch values.
+func (s *Store) closeOpenVoiceSessions(db *sql.DB, where string, args []any, at time.Time) (int64, error) {
+	rows, err := db.Query(`SELECT id, joined_at FROM voice_sessions WHERE left_at IS NULL AND `+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	type openSession struct {
+		id       int64
+		joinedAt time.Time
+	}
+	var open []openSession
+	for rows.Next() {
+		var o openSession
+		if err := rows.Scan(&o.id, &o.joinedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		open = append(open, o)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+
+	var closed int64
+	for _, o := range open {
+		d := at.Sub(o.joinedAt)
+		if d < 0 {
+			d = 0
+		}
+		if _, err := s.execWrite(db, "CloseVoiceSessions",
+			`UPDATE voice_sessions SET left_at=?, duration_seconds=? WHERE id=? AND left_at IS NULL`,
+			at.UTC(), int64(d/time.Second), o.id,
+		); err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
+}
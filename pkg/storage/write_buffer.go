@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+)
+
+const (
+	// DefaultWriteFlushInterval bounds how long a buffered message waits before being written.
+	DefaultWriteFlushInterval = time.Second
+	// DefaultWriteBatchSize is the number of buffered messages written per transaction.
+	DefaultWriteBatchSize = 100
+)
+
+// writeBuffer decouples message upserts from disk I/O: records are queued on a bounded
+// channel and written in batches (one transaction per database) by a background goroutine.
+type writeBuffer struct {
+	store    *Store
+	ch       chan MessageRecord
+	flushReq chan chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	dropFull bool
+	dropped  uint64
+
+	// closeMu keeps enqueue and close apart: enqueue holds it for reading while it sends, close
+	// takes it for writing to set closed, so no record lands on ch after the final drain.
+	closeMu  sync.RWMutex
+	closed   bool
+	stopOnce sync.Once
+
+	// pending keeps the latest queued record per message so GetMessage sees writes that
+	// have not been flushed yet (e.g. an edit right after the create).
+	mu      sync.Mutex
+	pending map[string]MessageRecord
+}
+
+func newWriteBuffer(s *Store) *writeBuffer {
+	wb := &writeBuffer{
+		store:    s,
+		ch:       make(chan MessageRecord, s.opts.WriteBufferSize),
+		flushReq: make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		dropFull: s.opts.WriteBufferDropWhenFull,
+		pending:  make(map[string]MessageRecord),
+	}
+	go wb.loop()
+	return wb
+}
+
+func pendingKey(guildID, messageID string) string {
+	return guildID + ":" + messageID
+}
+
+// errStoreClosing is returned by enqueue once close has started.
+var errStoreClosing = fmt.Errorf("store is closing")
+
+// enqueue queues a record, blocking while the buffer is full unless drop mode is enabled.
+// It fails once the buffer is closing instead of dropping the record silently.
+func (wb *writeBuffer) enqueue(m MessageRecord) error {
+	wb.closeMu.RLock()
+	defer wb.closeMu.RUnlock()
+	if wb.closed {
+		return errStoreClosing
+	}
+
+	key := pendingKey(m.GuildID, m.MessageID)
+	wb.mu.Lock()
+	wb.pending[key] = m
+	wb.mu.Unlock()
+
+	if !wb.dropFull {
+		select {
+		case wb.ch <- m:
+			return nil
+		case <-wb.stop:
+			wb.forget(m)
+			return errStoreClosing
+		}
+	}
+	select {
+	case wb.ch <- m:
+		return nil
+	default:
+		wb.forget(m)
+		if n := atomic.AddUint64(&wb.dropped, 1); n == 1 || n%1000 == 0 {
+			log.Warn().Applicationf("Storage write buffer full, dropped message write (guild=%s, message=%s, total dropped=%d)", m.GuildID, m.MessageID, n)
+		}
+		return fmt.Errorf("write buffer full")
+	}
+}
+
+// lookup returns the queued (not yet flushed) record for a message, if any.
+func (wb *writeBuffer) lookup(guildID, messageID string) (MessageRecord, bool) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	m, ok := wb.pending[pendingKey(guildID, messageID)]
+	return m, ok
+}
+
+// forget drops the pending entry if it still refers to m (a newer write may have replaced it).
+func (wb *writeBuffer) forget(m MessageRecord) {
+	key := pendingKey(m.GuildID, m.MessageID)
+	wb.mu.Lock()
+	if cur, ok := wb.pending[key]; ok && sameQueuedRecord(cur, m) {
+		delete(wb.pending, key)
+	}
+	wb.mu.Unlock()
+}
+
+// sameQueuedRecord compares the persisted fields of two records (media is never queued).
+func sameQueuedRecord(a, b MessageRecord) bool {
+	a.Attachments, a.Embeds, b.Attachments, b.Embeds = nil, nil, nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// flush blocks until every record queued so far has been written.
+func (wb *writeBuffer) flush() {
+	ack := make(chan struct{})
+	select {
+	case wb.flushReq <- ack:
+		<-ack
+	case <-wb.done:
+	}
+}
+
+// close stops the loop after writing everything still queued.
+func (wb *writeBuffer) close() {
+	// stop first: it releases enqueues blocked on a full buffer, which hold closeMu
+	wb.stopOnce.Do(func() { close(wb.stop) })
+	wb.closeMu.Lock()
+	wb.closed = true
+	wb.closeMu.Unlock()
+	<-wb.done
+
+	// Records sent while the loop was draining for stop; no enqueue can add more now
+	var rest []MessageRecord
+	for len(wb.ch) > 0 {
+		rest = append(rest, <-wb.ch)
+	}
+	if len(rest) > 0 {
+		if err := wb.store.upsertMessagesNow(rest); err != nil {
+			log.Error().Errorf("Failed to flush %d buffered messages on close: %v", len(rest), err)
+		}
+		for _, m := range rest {
+			wb.forget(m)
+		}
+	}
+}
+
+func (wb *writeBuffer) loop() {
+	defer close(wb.done)
+	interval := wb.store.opts.WriteFlushInterval
+	if interval <= 0 {
+		interval = DefaultWriteFlushInterval
+	}
+	batchSize := wb.store.opts.WriteBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultWriteBatchSize
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	batch := make([]MessageRecord, 0, batchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := wb.store.upsertMessagesNow(batch); err != nil {
+			log.Error().Errorf("Failed to flush %d buffered messages: %v", len(batch), err)
+		}
+		for _, m := range batch {
+			wb.forget(m)
+		}
+		batch = batch[:0]
+	}
+	drain := func() {
+		for {
+			select {
+			case m := <-wb.ch:
+				batch = append(batch, m)
+				if len(batch) >= batchSize {
+					write()
+				}
+			default:
+				write()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case m := <-wb.ch:
+			batch = append(batch, m)
+			if len(batch) >= batchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case ack := <-wb.flushReq:
+			drain()
+			close(ack)
+		case <-wb.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// UpsertMessages writes several message records, using one transaction per database.
+// Unlike UpsertMessage it always writes synchronously, even when the write buffer is enabled.
+func (s *Store) UpsertMessages(records []MessageRecord) error {
+	if s.db == nil {
+		return fmt.Errorf("store not initialized")
+	}
+	return s.upsertMessagesNow(records)
+}
+
+// FlushWrites blocks until all buffered message writes are on disk. It is a no-op when
+// the write buffer is disabled.
+func (s *Store) FlushWrites() {
+	if wb := s.wbuf.Load(); wb != nil {
+		wb.flush()
+	}
+}
+
+func (s *Store) upsertMessagesNow(records []MessageRecord) error {
+	byDB := make(map[*sql.DB][]MessageRecord)
+	var order []*sql.DB
+	for _, m := range records {
+		db := s.dbFor(m.GuildID)
+		if _, ok := byDB[db]; !ok {
+			order = append(order, db)
+		}
+		byDB[db] = append(byDB[db], m)
+	}
+	for _, db := range order {
+		batch := byDB[db]
+		if err := s.retryWrite("UpsertMessages", func() error { return s.upsertMessagesTx(db, batch) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) upsertMessagesTx(db *sql.DB, records []MessageRecord) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(upsertMessageSQL)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, m := range records {
+		if _, err := stmt.Exec(s.upsertMessageArgs(m)...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
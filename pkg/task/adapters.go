@@ -19,7 +19,7 @@ type NotificationSender interface {
 	SendMemberLeaveNotification(channelID string, member *discordgo.GuildMemberRemove, serverTime time.Duration, botTime time.Duration) error
 	SendMessageEditNotification(channelID string, original *CachedMessage, edited *discordgo.MessageUpdate) error
 	SendMessageDeleteNotification(channelID string, deleted *CachedMessage, deletedBy string) error
-	SendAutomodActionNotification(channelID string, event *discordgo.AutoModerationActionExecution) error
+	SendAutomodActionNotification(channelID string, event *discordgo.AutoModerationActionExecution, actionID int64, simulated []string) error
 }
 
 // CachedMessage is a minimal snapshot of a Discord message used for notifications.
@@ -30,6 +30,14 @@ type CachedMessage struct {
 	ChannelID string
 	GuildID   string
 	Timestamp time.Time
+
+	// Truncated indicates the stored content was cut by the store; OriginalLength is the full length in runes.
+	Truncated      bool
+	OriginalLength int
+
+	// Attachments and Embeds are the media metadata stored with the message.
+	Attachments []storage.MessageAttachment
+	Embeds      []storage.MessageEmbedSummary
 }
 
 const (
@@ -76,6 +84,8 @@ type MessageDeletePayload struct {
 type AutomodActionPayload struct {
 	ChannelID string
 	Event     *discordgo.AutoModerationActionExecution
+	ActionID  int64    // stored action ID (0 if not recorded); enables the feedback buttons
+	Simulated []string // dry-run actions not taken; marks the notification "[DRY RUN]"
 }
 
 // AvatarChangePayload holds information to process an avatar change.
@@ -226,8 +236,9 @@ func (a *NotificationAdapters) EnqueueMessageDelete(channelID string, deleted *C
 	})
 }
 
-// EnqueueAutomodAction enqueues an automod action notification.
-func (a *NotificationAdapters) EnqueueAutomodAction(channelID string, event *discordgo.AutoModerationActionExecution) error {
+// EnqueueAutomodAction enqueues an automod action notification. simulated lists the actions a
+// dry-run rule would have taken (empty for executed actions).
+func (a *NotificationAdapters) EnqueueAutomodAction(channelID string, event *discordgo.AutoModerationActionExecution, actionID int64, simulated []string) error {
 	if event == nil {
 		return nil
 	}
@@ -237,6 +248,8 @@ func (a *NotificationAdapters) EnqueueAutomodAction(channelID string, event *dis
 		Payload: AutomodActionPayload{
 			ChannelID: channelID,
 			Event:     event,
+			ActionID:  actionID,
+			Simulated: simulated,
 		},
 		Options: TaskOptions{
 			GroupKey:       group,
@@ -354,7 +367,7 @@ func (a *NotificationAdapters) handleSendAutomodAction(ctx context.Context, payl
 	if !ok || p.Event == nil {
 		return fmt.Errorf("invalid payload for %s", TaskTypeSendAutomodAction)
 	}
-	return a.Notifier.SendAutomodActionNotification(p.ChannelID, p.Event)
+	return a.Notifier.SendAutomodActionNotification(p.ChannelID, p.Event, p.ActionID, p.Simulated)
 }
 
 func (a *NotificationAdapters) handleProcessAvatarChange(ctx context.Context, payload any) error {
@@ -395,8 +408,8 @@ func (a *NotificationAdapters) handleProcessAvatarChange(ctx context.Context, pa
 	}
 
 	// Find destination channel
-	gcfg := a.Config.GuildConfig(p.GuildID)
-	if gcfg == nil {
+	gcfg, ok := a.Config.GuildConfig(p.GuildID)
+	if !ok {
 		// No configuration; update avatar and exit (avoid retries)
 		_, _, _ = a.Store.UpsertAvatar(p.GuildID, p.UserID, p.NewAvatar, time.Now())
 		log.Info().Applicationf("No guild config found; skipping avatar notification; guildID=%s userID=%s", p.GuildID, p.UserID)
@@ -0,0 +1,241 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/errors"
+	"github.com/small-frappuccino/discordcore/pkg/errutil"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+// DurableHandler runs a scheduled task. The JSON payload is in t.Payload (see DecodePayload).
+// Returning an error retries the task with backoff.
+type DurableHandler func(ctx context.Context, t storage.ScheduledTask) error
+
+// Dispatcher defaults.
+const (
+	DefaultDurablePollInterval = 30 * time.Second
+	DefaultDurableMaxAttempts  = 5
+	DefaultDurableBatchSize    = 100
+	durableRetryBase           = 30 * time.Second
+	durableRetryMax            = 30 * time.Minute
+	durableRetention           = 7 * 24 * time.Hour
+)
+
+// Dispatcher runs tasks persisted in scheduled_tasks, unlike the in-memory TaskRouter schedules.
+// On start and every PollInterval it loads the due tasks and calls the handler for their type;
+// one-off tasks are completed and recurring ones rescheduled. Delivery is at-least-once: a task
+// interrupted by a crash runs again on the next start.
+type Dispatcher struct {
+	store        *storage.Store
+	PollInterval time.Duration
+	MaxAttempts  int
+
+	mu       sync.RWMutex
+	handlers map[string]DurableHandler
+
+	runMu  sync.Mutex // serializes RunDue (tick vs manual call)
+	stop   chan struct{}
+	done   chan struct{}
+	cancel context.CancelFunc
+}
+
+// NewDispatcher creates a Dispatcher on store with the default intervals.
+func NewDispatcher(store *storage.Store) *Dispatcher {
+	return &Dispatcher{
+		store:        store,
+		PollInterval: DefaultDurablePollInterval,
+		MaxAttempts:  DefaultDurableMaxAttempts,
+		handlers:     make(map[string]DurableHandler),
+	}
+}
+
+// RegisterHandler sets the handler for a task type. Due tasks without a handler stay pending.
+func (d *Dispatcher) RegisterHandler(taskType string, handler DurableHandler) {
+	if taskType == "" || handler == nil {
+		return
+	}
+	d.mu.Lock()
+	d.handlers[taskType] = handler
+	d.mu.Unlock()
+}
+
+// Schedule persists a one-off task due at dueAt; payload is encoded as JSON.
+func (d *Dispatcher) Schedule(taskType, guildID string, payload any, dueAt time.Time) (int64, error) {
+	return d.schedule(taskType, guildID, payload, dueAt, 0)
+}
+
+// ScheduleRecurring persists a task that runs at firstDue and then every interval (at least 1s).
+func (d *Dispatcher) ScheduleRecurring(taskType, guildID string, payload any, firstDue time.Time, interval time.Duration) (int64, error) {
+	if interval < time.Second {
+		return 0, fmt.Errorf("recurring interval must be at least 1s")
+	}
+	return d.schedule(taskType, guildID, payload, firstDue, interval)
+}
+
+// Cancel deletes a pending task.
+func (d *Dispatcher) Cancel(id int64) (bool, error) {
+	return d.store.CancelScheduledTask(id)
+}
+
+func (d *Dispatcher) schedule(taskType, guildID string, payload any, dueAt time.Time, interval time.Duration) (int64, error) {
+	if d.store == nil {
+		return 0, fmt.Errorf("scheduled tasks require a store")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("encode payload: %w", err)
+	}
+	return d.store.ScheduleTask(storage.ScheduledTask{
+		Type:     taskType,
+		GuildID:  guildID,
+		Payload:  string(data),
+		DueAt:    dueAt,
+		Interval: interval,
+	})
+}
+
+// DecodePayload decodes a task's JSON payload into v.
+func DecodePayload(t storage.ScheduledTask, v any) error {
+	if err := json.Unmarshal([]byte(t.Payload), v); err != nil {
+		return fmt.Errorf("decode payload of task %d (%s): %w", t.ID, t.Type, err)
+	}
+	return nil
+}
+
+// Start runs the due tasks now and then every PollInterval until Stop.
+func (d *Dispatcher) Start() error {
+	if d.store == nil {
+		return fmt.Errorf("scheduled tasks require a store")
+	}
+	if d.stop != nil {
+		return nil
+	}
+	interval := d.PollInterval
+	if interval <= 0 {
+		interval = DefaultDurablePollInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stop, d.done, d.cancel = make(chan struct{}), make(chan struct{}), cancel
+
+	go func(stop, done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		lastCleanup := time.Time{}
+		for {
+			if _, err := d.RunDue(ctx); err != nil {
+				log.Error().Errorf("Scheduled tasks: failed to load due tasks: %v", err)
+			}
+			if time.Since(lastCleanup) > time.Hour {
+				lastCleanup = time.Now()
+				if _, err := d.store.CleanupScheduledTasks(time.Now().Add(-durableRetention)); err != nil {
+					log.Warn().Applicationf("Scheduled tasks: cleanup failed: %v", err)
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}(d.stop, d.done)
+
+	log.Info().Applicationf("⏰ Scheduled task dispatcher started (poll every %s)", interval)
+	return nil
+}
+
+// Stop ends the loop, cancels running handlers and waits for them.
+func (d *Dispatcher) Stop() error {
+	if d.stop == nil {
+		return nil
+	}
+	close(d.stop)
+	d.cancel()
+	<-d.done
+	d.stop, d.done, d.cancel = nil, nil, nil
+	return nil
+}
+
+// RunDue runs the due tasks now and returns how many were processed, successfully or not.
+// Tasks without a registered handler are skipped and don't count towards the batch.
+func (d *Dispatcher) RunDue(ctx context.Context) (int, error) {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+
+	d.mu.RLock()
+	handlers := maps.Clone(d.handlers)
+	d.mu.RUnlock()
+	if len(handlers) == 0 {
+		return 0, nil
+	}
+	// Filter by registered type in the query so unhandled tasks don't fill the batch.
+	due, err := d.store.DueScheduledTasks(time.Now(), DefaultDurableBatchSize, slices.Collect(maps.Keys(handlers))...)
+	if err != nil {
+		return 0, err
+	}
+	processed := 0
+	for _, t := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		handler := handlers[t.Type]
+		if handler == nil {
+			continue
+		}
+		processed++
+		d.finish(t, d.invoke(ctx, handler, t))
+	}
+	return processed, nil
+}
+
+// invoke runs the handler, turning a panic into a task failure.
+func (d *Dispatcher) invoke(ctx context.Context, handler DurableHandler, t storage.ScheduledTask) (err error) {
+	defer errutil.RecoverPanic(errors.CategoryService, "durable task "+t.Type, func(r any) {
+		err = fmt.Errorf("panic: %v", r)
+	})
+	return handler(ctx, t)
+}
+
+// finish stores the outcome: done, rescheduled (recurring or retry) or failed.
+func (d *Dispatcher) finish(t storage.ScheduledTask, runErr error) {
+	now := time.Now()
+	var err error
+	switch {
+	case runErr == nil && t.Interval > 0:
+		next := t.DueAt.Add(t.Interval)
+		for !next.After(now) { // runs missed while the bot was down are skipped
+			next = next.Add(t.Interval)
+		}
+		err = d.store.RescheduleTask(t.ID, next, 0, "")
+	case runErr == nil:
+		err = d.store.CompleteScheduledTask(t.ID)
+	default:
+		attempts := t.Attempts + 1
+		maxAttempts := d.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = DefaultDurableMaxAttempts
+		}
+		if attempts >= maxAttempts {
+			log.Error().Errorf("Scheduled task %d (%s) failed permanently after %d attempts: %v", t.ID, t.Type, attempts, runErr)
+			err = d.store.FailScheduledTask(t.ID, runErr.Error())
+			break
+		}
+		delay := durableRetryBase << (attempts - 1)
+		if delay > durableRetryMax || delay <= 0 {
+			delay = durableRetryMax
+		}
+		log.Warn().Applicationf("Scheduled task %d (%s) failed (attempt %d/%d), retrying in %s: %v", t.ID, t.Type, attempts, maxAttempts, delay, runErr)
+		err = d.store.RescheduleTask(t.ID, now.Add(delay), attempts, runErr.Error())
+	}
+	if err != nil {
+		log.Error().Errorf("Scheduled task %d (%s): failed to record result: %v", t.ID, t.Type, err)
+	}
+}
@@ -0,0 +1,69 @@
+package task
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/storage"
+)
+
+func newTestStore(t *testing.T) *storage.Store {
+	t.Helper()
+	store := storage.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("init store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestRunDueSkipsUnhandledTypesInQuery(t *testing.T) {
+	store := newTestStore(t)
+	d := NewDispatcher(store)
+	past := time.Now().Add(-time.Hour)
+
+	// A full batch of older due tasks with no handler must not hide the handled one.
+	for range DefaultDurableBatchSize + 10 {
+		if _, err := d.Schedule("orphan", "g1", nil, past); err != nil {
+			t.Fatalf("schedule orphan: %v", err)
+		}
+	}
+	if _, err := d.Schedule("handled", "g1", map[string]string{"k": "v"}, past.Add(time.Minute)); err != nil {
+		t.Fatalf("schedule handled: %v", err)
+	}
+
+	ran := 0
+	d.RegisterHandler("handled", func(ctx context.Context, task storage.ScheduledTask) error {
+		ran++
+		return nil
+	})
+
+	processed, err := d.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+	if processed != 1 || ran != 1 {
+		t.Fatalf("processed=%d ran=%d, want 1 and 1", processed, ran)
+	}
+	pending, err := store.PendingScheduledTasks("g1")
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(pending) != DefaultDurableBatchSize+10 {
+		t.Fatalf("pending=%d, want the %d orphan tasks", len(pending), DefaultDurableBatchSize+10)
+	}
+}
+
+func TestRunDueWithoutHandlers(t *testing.T) {
+	store := newTestStore(t)
+	d := NewDispatcher(store)
+	if _, err := d.Schedule("orphan", "g1", nil, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	processed, err := d.RunDue(context.Background())
+	if err != nil || processed != 0 {
+		t.Fatalf("RunDue = %d, %v; want 0, nil", processed, err)
+	}
+}
@@ -257,6 +257,22 @@ func (tr *TaskRouter) Close() {
 	})
 }
 
+// Shutdown closes the router like Close but gives up waiting once ctx is done, returning
+// ctx.Err(). Tasks still running at that point are abandoned.
+func (tr *TaskRouter) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		tr.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Stats provides a snapshot with counts useful for debugging/monitoring.
 type Stats struct {
 	GroupsCount     int
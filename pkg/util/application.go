@@ -118,12 +118,18 @@ func EffectiveBotName() string {
 // GetApplicationSupportPath (Linux-only) returns the base path for configuration files.
 // New layout: ~/.config/[BotName]
 func GetApplicationSupportPath(_ string) string {
+	if IsDevMode() {
+		return filepath.Join(DevTempDir(), "config")
+	}
 	return filepath.Join(homeDir(), ".config", EffectiveBotName())
 }
 
 // GetApplicationCachesPath (Linux-only) returns the base path for cache data.
 // New layout: ~/.cache/[BotName]
 func GetApplicationCachesPath() string {
+	if IsDevMode() {
+		return filepath.Join(DevTempDir(), "cache")
+	}
 	return filepath.Join(homeDir(), ".cache", EffectiveBotName())
 }
 
@@ -141,19 +147,30 @@ func LegacyMigrationCacheFilePath() string {
 
 // GetMessageDBPath returns the SQLite DB path for message persistence.
 // New Linux layout: ~/.cache/[BotName]/messages/messages.db
+// In development mode it returns MemoryDBPath.
 func GetMessageDBPath() string {
+	if IsDevMode() {
+		return MemoryDBPath
+	}
 	return filepath.Join(ApplicationCachesPath, "messages", "messages.db")
 }
 
 // GetSettingsFilePath returns the path for the primary settings JSON.
 // Layout (explicit): ~/.config/[BotName]/preferences/settings.json
+// In development mode it returns DISCORDCORE_DEV_CONFIG or ./settings.dev.json.
 func GetSettingsFilePath() string {
+	if IsDevMode() {
+		return devSettingsFilePath()
+	}
 	return filepath.Join(ApplicationSupportPath, "preferences", "settings.json")
 }
 
 // GetLogFilePath returns the path to the main log file.
 // New Linux layout: ~/.log/[BotName]/discordcore.log
 func GetLogFilePath() string {
+	if IsDevMode() {
+		return filepath.Join(DevTempDir(), "log", "discordcore.log")
+	}
 	return filepath.Join(homeDir(), ".log", EffectiveBotName(), "discordcore.log")
 }
 
@@ -161,7 +178,7 @@ func GetLogFilePath() string {
 // Safe to call multiple times.
 func EnsureCacheDirs() error {
 	dirs := []string{
-		filepath.Dir(GetMessageDBPath()),
+		filepath.Join(ApplicationCachesPath, "messages"),
 	}
 	for _, d := range dirs {
 		if err := os.MkdirAll(d, 0o755); err != nil {
@@ -219,8 +236,8 @@ func sanitizeName(s string) string {
 // It is safe to call multiple times.
 func EnsureCacheInitialized() error {
 	dirs := []string{
-		filepath.Dir(GetMessageDBPath()),               // messages db directory
-		filepath.Join(ApplicationCachesPath, "avatar"), // avatar cache (even if now migrated to sqlite; kept for future artifacts)
+		filepath.Join(ApplicationCachesPath, "messages"), // messages db directory
+		filepath.Join(ApplicationCachesPath, "avatar"),   // avatar cache (even if now migrated to sqlite; kept for future artifacts)
 	}
 
 	for _, d := range dirs {
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DevModeEnv enables development mode when set to "1" (or "true").
+	DevModeEnv = "DISCORDCORE_DEV"
+	// DevConfigEnv overrides the settings file used in development mode.
+	DevConfigEnv = "DISCORDCORE_DEV_CONFIG"
+	// DefaultDevConfigFile is the settings file used in development mode, relative to the working directory.
+	DefaultDevConfigFile = "settings.dev.json"
+	// MemoryDBPath is the store path used in development mode.
+	MemoryDBPath = ":memory:"
+)
+
+// IsDevMode reports whether development mode is enabled via DISCORDCORE_DEV.
+//
+// In development mode nothing is written under $HOME:
+//   - the SQLite store runs in memory (GetMessageDBPath returns MemoryDBPath);
+//   - settings are read from DISCORDCORE_DEV_CONFIG or ./settings.dev.json;
+//   - tokens come from the environment or ./.env, never from $HOME/.local/bin/.env;
+//   - cache, config and log directories live under a per-process temp directory,
+//     removed by CleanupDevMode.
+func IsDevMode() bool {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv(DevModeEnv)))
+	return v == "1" || v == "true"
+}
+
+// DevTempDir returns the per-process temp directory used in development mode.
+// The directory is created on demand by EnsureCacheDirs/EnsureCacheInitialized.
+func DevTempDir() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("discordcore-dev-%d", os.Getpid()))
+}
+
+// CleanupDevMode removes the development temp directory. No-op outside development mode.
+func CleanupDevMode() error {
+	if !IsDevMode() {
+		return nil
+	}
+	return os.RemoveAll(DevTempDir())
+}
+
+func devSettingsFilePath() string {
+	if p := strings.TrimSpace(os.Getenv(DevConfigEnv)); p != "" {
+		return p
+	}
+	return DefaultDevConfigFile
+}
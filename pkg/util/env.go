@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +17,8 @@ import (
 //   - It attempts to load the single file "$HOME/.local/bin/.env" (if it exists).
 //   - After loading that file (if present), it checks whether the environment variable
 //     named by tokenEnvName is set and returns its value.
+//   - At every step the secret may also come from a file named by tokenEnvName+"_FILE"
+//     (Docker/Kubernetes secrets convention); see LoadSecret.
 //
 // Returns the value of the environment variable when found, or a non-nil error if the
 // variable remains unset after the fallback attempt. Errors are descriptive to help callers
@@ -24,9 +27,22 @@ import (
 // Callers should pass the exact environment variable name they expect (for example
 // "ALICE_BOT_DEVELOPMENT_TOKEN" or a repo-specific token name).
 func LoadEnvWithLocalBinFallback(tokenEnvName string) (string, error) {
-	// First, honor already-set environment variable
-	if v := os.Getenv(tokenEnvName); v != "" {
-		return v, nil
+	// First, honor already-set environment variable (or its _FILE variant)
+	if v, ok, err := lookupSecret(tokenEnvName); ok || err != nil {
+		return v, err
+	}
+
+	// Development mode never reads from $HOME; only a local ./.env is considered
+	if IsDevMode() {
+		if info, statErr := os.Stat(".env"); statErr == nil && !info.IsDir() {
+			if loadErr := godotenv.Load(".env"); loadErr != nil {
+				return "", fmt.Errorf("failed to load local env file .env: %v", loadErr)
+			}
+			if v, ok, err := lookupSecret(tokenEnvName); ok || err != nil {
+				return v, err
+			}
+		}
+		return "", fmt.Errorf("environment variable %q not set (development mode: $HOME/.local/bin/.env is not searched)", tokenEnvName)
 	}
 
 	// Determine fallback path: $HOME/.local/bin/.env
@@ -42,8 +58,8 @@ func LoadEnvWithLocalBinFallback(tokenEnvName string) (string, error) {
 			return "", fmt.Errorf("failed to load fallback env file %s: %v", envPath, loadErr)
 		}
 		// Check variable after loading fallback
-		if v := os.Getenv(tokenEnvName); v != "" {
-			return v, nil
+		if v, ok, err := lookupSecret(tokenEnvName); ok || err != nil {
+			return v, err
 		}
 		// Loaded fallback but variable still missing
 		return "", fmt.Errorf("environment variable %q not set after loading fallback file %s", tokenEnvName, envPath)
@@ -52,3 +68,41 @@ func LoadEnvWithLocalBinFallback(tokenEnvName string) (string, error) {
 	// Fallback file does not exist and env var not set
 	return "", fmt.Errorf("environment variable %q not set and fallback env file not found: %s", tokenEnvName, envPath)
 }
+
+// SecretFileSuffix is appended to a variable name to point at a file holding its value.
+const SecretFileSuffix = "_FILE"
+
+// LoadSecret returns the value of the environment variable name or, when it is unset, the
+// contents of the file whose path is in name+"_FILE" (the Docker/Kubernetes secrets
+// convention), with surrounding whitespace trimmed. The direct variable takes precedence.
+// It returns an error when neither is set, or when the file can't be read or is empty.
+func LoadSecret(name string) (string, error) {
+	v, ok, err := lookupSecret(name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("neither %s nor %s%s is set", name, name, SecretFileSuffix)
+	}
+	return v, nil
+}
+
+// lookupSecret implements LoadSecret, reporting ok=false (without error) when both are unset.
+func lookupSecret(name string) (string, bool, error) {
+	if v := os.Getenv(name); v != "" {
+		return v, true, nil
+	}
+	path := strings.TrimSpace(os.Getenv(name + SecretFileSuffix))
+	if path == "" {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("read %s%s: %w", name, SecretFileSuffix, err)
+	}
+	v := strings.TrimSpace(string(data))
+	if v == "" {
+		return "", false, fmt.Errorf("secret file %s (from %s%s) is empty", path, name, SecretFileSuffix)
+	}
+	return v, true, nil
+}
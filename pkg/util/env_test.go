@@ -0,0 +1,79 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSecretFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write secret file: %v", err)
+	}
+	return path
+}
+
+func TestLoadSecret(t *testing.T) {
+	const name = "DISCORDCORE_TEST_SECRET"
+	for _, tc := range []struct {
+		name    string
+		env     string
+		file    *string // contents of the NAME_FILE file; nil leaves NAME_FILE unset
+		path    string  // NAME_FILE pointing at a path that doesn't exist
+		want    string
+		wantErr string
+	}{
+		{name: "env only", env: "from-env", want: "from-env"},
+		{name: "file only", file: ptr("  from-file\n"), want: "from-file"},
+		{name: "env wins over file", env: "from-env", file: ptr("from-file"), want: "from-env"},
+		{name: "neither", wantErr: "neither"},
+		{name: "missing file", path: "/nonexistent/secret", wantErr: "read " + name + SecretFileSuffix},
+		{name: "empty file", file: ptr(" \n\t"), wantErr: "is empty"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(name, tc.env)
+			t.Setenv(name+SecretFileSuffix, "")
+			switch {
+			case tc.file != nil:
+				t.Setenv(name+SecretFileSuffix, writeSecretFile(t, *tc.file))
+			case tc.path != "":
+				t.Setenv(name+SecretFileSuffix, tc.path)
+			}
+
+			got, err := LoadSecret(name)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("LoadSecret error = %v, want it to mention %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSecret: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("LoadSecret = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvWithLocalBinFallbackReadsSecretFile(t *testing.T) {
+	const name = "DISCORDCORE_TEST_TOKEN"
+	t.Setenv(name, "")
+	t.Setenv(name+SecretFileSuffix, writeSecretFile(t, "file-token\n"))
+	// HOME has no .local/bin/.env: the secret file must be found before that fallback
+	t.Setenv("HOME", t.TempDir())
+
+	got, err := LoadEnvWithLocalBinFallback(name)
+	if err != nil {
+		t.Fatalf("LoadEnvWithLocalBinFallback: %v", err)
+	}
+	if got != "file-token" {
+		t.Errorf("token = %q, want file-token", got)
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvironmentEnv selects the deployment environment (dev, staging or prod).
+const EnvironmentEnv = "DISCORDCORE_ENV"
+
+// Deployment environments.
+const (
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
+)
+
+// CurrentEnvironment returns the deployment environment from DISCORDCORE_ENV, accepting the
+// long forms ("development", "production"). When unset or unknown it falls back to EnvDev in
+// development mode and EnvProd otherwise, so environment-scoped commands fail closed.
+func CurrentEnvironment() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvironmentEnv))) {
+	case "dev", "development":
+		return EnvDev
+	case "staging", "stage":
+		return EnvStaging
+	case "prod", "production":
+		return EnvProd
+	}
+	if IsDevMode() {
+		return EnvDev
+	}
+	return EnvProd
+}
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is used when a guild has no locale configured.
+const DefaultLocale = "en"
+
+// DisplayTimeLayout is the absolute date/time layout used in embeds.
+const DisplayTimeLayout = "January 2, 2006 at 3:04 PM MST"
+
+// NumberSeparators returns the digit-group and decimal separators for a locale
+// ("en", "pt-BR", "de", "fr"...). Unknown locales use the English convention.
+func NumberSeparators(locale string) (group, decimal string) {
+	lang := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		if lang == "de-ch" || lang == "de_ch" {
+			return "’", "."
+		}
+		lang = lang[:i]
+	}
+	switch lang {
+	case "pt", "es", "de", "it", "nl", "id", "tr", "da", "el", "ro", "hr", "sl", "vi":
+		return ".", ","
+	case "fr", "ru", "pl", "uk", "cs", "sk", "sv", "fi", "no", "nb", "hu", "bg", "lt", "lv", "et":
+		return " ", ","
+	default:
+		return ",", "."
+	}
+}
+
+// FormatInt groups the digits of n by thousands using the locale's separator.
+func FormatInt(n int64, locale string) string {
+	group, _ := NumberSeparators(locale)
+	return groupDigits(strconv.FormatInt(n, 10), group)
+}
+
+// FormatFloat formats f with the given number of decimals and the locale's separators.
+func FormatFloat(f float64, decimals int, locale string) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	group, decimal := NumberSeparators(locale)
+	s := strconv.FormatFloat(f, 'f', decimals, 64)
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	out := groupDigits(intPart, group)
+	if hasFrac {
+		out += decimal + frac
+	}
+	return out
+}
+
+// FormatNumber formats integer and float values with FormatInt/FormatFloat (two decimals)
+// and anything else with %v, so metric maps of mixed types render consistently.
+func FormatNumber(v any, locale string) string {
+	switch n := v.(type) {
+	case int:
+		return FormatInt(int64(n), locale)
+	case int32:
+		return FormatInt(int64(n), locale)
+	case int64:
+		return FormatInt(n, locale)
+	case uint:
+		return FormatInt(int64(n), locale)
+	case uint32:
+		return FormatInt(int64(n), locale)
+	case uint64:
+		if n > math.MaxInt64 {
+			return strconv.FormatUint(n, 10)
+		}
+		return FormatInt(int64(n), locale)
+	case float32:
+		return FormatFloat(float64(n), 2, locale)
+	case float64:
+		return FormatFloat(n, 2, locale)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func groupDigits(digits, sep string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= 3 || sep == "" {
+		return sign + digits
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > len(sign) {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// RelativeTime describes t relative to now with its largest unit ("2 hours ago",
+// "in 3 days", "just now").
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < 45*time.Second {
+		return "just now"
+	}
+	var n int64
+	var unit string
+	switch {
+	case d < time.Hour:
+		n, unit = int64(math.Round(d.Minutes())), "minute"
+	case d < 24*time.Hour:
+		n, unit = int64(math.Round(d.Hours())), "hour"
+	case d < 30*24*time.Hour:
+		n, unit = int64(d.Hours()/24), "day"
+	case d < 365*24*time.Hour:
+		n, unit = int64(d.Hours()/(24*30)), "month"
+	default:
+		n, unit = int64(d.Hours()/(24*365)), "year"
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	if future {
+		return fmt.Sprintf("in %d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
+
+// LoadTimezone resolves an IANA zone name ("America/Sao_Paulo"); empty or unknown names use UTC.
+func LoadTimezone(name string) *time.Location {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FormatDisplayTime renders t in the given timezone followed by a Discord relative timestamp,
+// e.g. "January 2, 2006 at 3:04 PM BRT (<t:1136239445:R>)". Discord renders "<t:...:R>" as
+// "2 hours ago" in the reader's language and keeps it current, unlike text baked in at send time.
+func FormatDisplayTime(t time.Time, timezone string) string {
+	return fmt.Sprintf("%s (<t:%d:R>)", t.In(LoadTimezone(timezone)).Format(DisplayTimeLayout), t.Unix())
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDisplayTimeUsesDiscordRelativeTimestamp(t *testing.T) {
+	ts := time.Date(2026, 10, 15, 8, 8, 0, 0, time.UTC)
+	got := FormatDisplayTime(ts, "America/Sao_Paulo")
+	want := "October 15, 2026 at 5:08 AM -03 (<t:1792051680:R>)"
+	if got != want {
+		t.Fatalf("FormatDisplayTime = %q, want %q", got, want)
+	}
+	if got := FormatDisplayTime(ts, ""); got != "October 15, 2026 at 8:08 AM UTC (<t:1792051680:R>)" {
+		t.Fatalf("FormatDisplayTime (UTC) = %q", got)
+	}
+}
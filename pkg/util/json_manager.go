@@ -72,13 +72,40 @@ func (m *JSONManager) Save(data interface{}) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	if err := os.WriteFile(m.filePath, fileData, 0644); err != nil {
+	if err := writeFileAtomic(m.filePath, fileData, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
 	return nil
 }
 
+// writeFileAtomic grava em um arquivo temporário no mesmo diretório, faz fsync e renomeia
+// sobre o destino, de modo que leitores (ou um crash) nunca vejam um JSON truncado.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op após o rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
 // safeJoin ensures that the joined path is within the base directory.
 func safeJoin(baseDir, relPath string) (string, error) {
 	cleanBase := filepath.Clean(baseDir)